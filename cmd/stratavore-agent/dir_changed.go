@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"go.uber.org/zap"
+)
+
+// watchDirChanged handles the daemon's notice that the project directory
+// was moved. Claude Code can't change its working directory, so the agent
+// only logs it; without a handler the signal would end the runner.
+func watchDirChanged(logger *zap.Logger) {
+	if len(dirChangedSignals) == 0 {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, dirChangedSignals...)
+	go func() {
+		for range ch {
+			logger.Warn("project directory was moved; restart this runner to work in the new location",
+				zap.String("old_path", projectPath))
+		}
+	}()
+}
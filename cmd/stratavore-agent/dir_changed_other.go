@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// dirChangedSignals tell the agent its project directory was moved
+var dirChangedSignals = []os.Signal{syscall.SIGUSR2}
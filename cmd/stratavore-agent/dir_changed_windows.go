@@ -0,0 +1,6 @@
+package main
+
+import "os"
+
+// dirChangedSignals is empty on Windows, which has no SIGUSR2
+var dirChangedSignals []os.Signal
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultDrainCommand makes Claude Code finish its turn and exit
+const defaultDrainCommand = "/exit"
+
+// stdinForwarder copies the agent's stdin to claude while letting the
+// drain handler write a whole line between forwarded chunks
+type stdinForwarder struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (f *stdinForwarder) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.w.Write(p)
+}
+
+// inject writes line followed by a newline
+func (f *stdinForwarder) inject(line string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err := io.WriteString(f.w, line+"\n")
+	return err
+}
+
+// drain asks claude to wrap up by sending it the drain command, then waits
+// for it to exit, up to --drain-timeout-seconds. When the countdown runs out, or the
+// daemon escalates with another signal, claude is sent SIGTERM and killed
+// if it still hasn't exited after 10 seconds. Returns claude's exit code.
+func drain(cmd *exec.Cmd, stdin *stdinForwarder, errCh <-chan error, sigCh <-chan os.Signal, logger *zap.Logger) int {
+	timeout := time.Duration(drainTimeoutSeconds) * time.Second
+	logger.Info("drain requested, asking claude code to wrap up",
+		zap.String("command", drainCommand),
+		zap.Duration("timeout", timeout))
+
+	if err := stdin.inject(drainCommand); err != nil {
+		logger.Warn("failed to send drain command", zap.Error(err))
+	}
+
+	countdown := time.NewTimer(timeout)
+	defer countdown.Stop()
+
+	select {
+	case err := <-errCh:
+		logger.Info("claude code exited after drain")
+		return exitCode(err)
+	case <-countdown.C:
+		logger.Info("drain timeout reached, terminating")
+		cmd.Process.Signal(syscall.SIGTERM)
+	case sig := <-sigCh:
+		logger.Info("received signal during drain, terminating",
+			zap.String("signal", sig.String()))
+		cmd.Process.Signal(sig)
+	}
+
+	select {
+	case err := <-errCh:
+		logger.Info("claude code terminated gracefully")
+		return exitCode(err)
+	case <-time.After(10 * time.Second):
+		logger.Warn("claude code did not exit, killing")
+		cmd.Process.Kill()
+		return -1
+	}
+}
+
+// exitCode extracts the exit code from cmd.Wait's error
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// drainSignals ask the agent to wrap up its session
+var drainSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,6 @@
+package main
+
+import "os"
+
+// drainSignals is empty on Windows, which has no SIGUSR1
+var drainSignals []os.Signal
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"os/exec"
@@ -14,14 +17,30 @@ import (
 	"time"
 
 	"github.com/meridian-lex/stratavore/internal/procmetrics"
+	"github.com/meridian-lex/stratavore/pkg/api"
 	"go.uber.org/zap"
 )
 
+const (
+	agentVersion = "1.4.0"
+	heartbeatURL = "http://localhost:50051/api/v1/heartbeat"
+
+	heartbeatInterval   = 10 * time.Second
+	metricsPollInterval = 2 * time.Second
+)
+
 var (
 	runnerID    string
 	projectName string
 	projectPath string
 	claudeFlags []string
+
+	heartbeatJitter int
+
+	drainCommand        string
+	drainTimeoutSeconds int
+
+	stderrErrors stderrWatcher
 )
 
 func main() {
@@ -29,6 +48,9 @@ func main() {
 	flag.StringVar(&runnerID, "runner-id", "", "Runner ID")
 	flag.StringVar(&projectName, "project-name", "", "Project name")
 	flag.StringVar(&projectPath, "project-path", "", "Project path")
+	flag.IntVar(&heartbeatJitter, "heartbeat-jitter-seconds", 30, "Maximum random delay before the first heartbeat")
+	flag.StringVar(&drainCommand, "drain-command", defaultDrainCommand, "Input sent to claude code when asked to drain")
+	flag.IntVar(&drainTimeoutSeconds, "drain-timeout-seconds", 60, "Time claude code gets to exit after the drain command")
 	flag.Parse()
 	
 	if runnerID == "" || projectName == "" || projectPath == "" {
@@ -49,8 +71,16 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	
-	// Start heartbeat goroutine
-	go sendHeartbeats(ctx, runnerID, logger)
+	// Check claude code can start before reporting the runner as running
+	if err := preflight(ctx, projectPath, claudeFlags, logger); err != nil {
+		json.NewEncoder(os.Stderr).Encode(err)
+		os.Exit(preflightExitCode)
+	}
+
+	// Start heartbeat goroutine; it closes terminated if the daemon says
+	// the runner has already finished
+	terminated := make(chan struct{})
+	go sendHeartbeats(ctx, runnerID, terminated, logger)
 	
 	// Build Claude Code command
 	args := []string{"--project", projectPath}
@@ -62,10 +92,24 @@ func main() {
 	
 	// Start Claude Code
 	cmd := exec.CommandContext(ctx, "claude", args...)
-	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
 	cmd.Dir = projectPath
+
+	// Forward stdin through a pipe so a drain can inject its command. The
+	// pipe stays open after stdin ends so the command can still be sent.
+	stdinPipe, err := cmd.StdinPipe()
+	if err != nil {
+		logger.Error("failed to open claude code stdin", zap.Error(err))
+		os.Exit(1)
+	}
+	stdin := &stdinForwarder{w: stdinPipe}
+
+	// Pass stderr through, watching it for errors to report in heartbeats
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		logger.Error("failed to open claude code stderr", zap.Error(err))
+		os.Exit(1)
+	}
 	
 	logger.Info("starting claude code", zap.Strings("args", args))
 	
@@ -74,16 +118,33 @@ func main() {
 		os.Exit(1)
 	}
 	
+	go io.Copy(stdin, os.Stdin)
+
+	stderrDone := make(chan struct{})
+	go func() {
+		stderrErrors.watch(stderr, os.Stderr)
+		close(stderrDone)
+	}()
+
 	pid := cmd.Process.Pid
 	logger.Info("claude code started", zap.Int("pid", pid))
 	
 	// Handle signals
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	// Notify with no signals would relay every signal
+	drainCh := make(chan os.Signal, 1)
+	if len(drainSignals) > 0 {
+		signal.Notify(drainCh, drainSignals...)
+	}
+	watchDirChanged(logger)
 	
 	// Wait for process or signal
 	errCh := make(chan error, 1)
 	go func() {
+		// Wait closes the pipe, so finish reading stderr first
+		<-stderrDone
 		errCh <- cmd.Wait()
 	}()
 	
@@ -100,7 +161,16 @@ func main() {
 		logger.Info("claude code exited",
 			zap.Int("exit_code", exitCode))
 		os.Exit(exitCode)
-		
+
+	case <-drainCh:
+		os.Exit(drain(cmd, stdin, errCh, sigCh, logger))
+
+	case <-terminated:
+		// The daemon has written this runner off; nobody is watching it
+		logger.Warn("runner terminated by daemon, killing claude code")
+		cmd.Process.Kill()
+		os.Exit(1)
+
 	case sig := <-sigCh:
 		logger.Info("received signal, terminating",
 			zap.String("signal", sig.String()))
@@ -119,48 +189,83 @@ func main() {
 	}
 }
 
-func sendHeartbeats(ctx context.Context, runnerID string, logger *zap.Logger) {
-	ticker := time.NewTicker(10 * time.Second)
+// sendHeartbeats posts a heartbeat every 10 seconds until ctx is done, or
+// until the daemon answers with HeartbeatCommandExit, when it closes
+// terminated and stops.
+func sendHeartbeats(ctx context.Context, runnerID string, terminated chan<- struct{}, logger *zap.Logger) {
+	// Spread the first heartbeat over the jitter window so agents that all
+	// reconnect after a daemon restart don't hit the database at once.
+	// Cold-start heartbeat QPS drops roughly by the number of agents
+	// sharing the window.
+	if heartbeatJitter > 0 {
+		delay := rand.N(time.Duration(heartbeatJitter) * time.Second)
+		logger.Debug("delaying first heartbeat", zap.Duration("jitter", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
 	defer ticker.Stop()
 
 	client := &http.Client{Timeout: 5 * time.Second}
-	apiURL := "http://localhost:50051/api/v1/heartbeat"
 	hostname, _ := os.Hostname()
 
-	// pid is not known yet at startup; we'll discover it lazily.
-	// The process sampler is initialised once we know the PID.
-	var sampler *procmetrics.Sampler
+	// Sample in the background so each heartbeat reports CPU averaged over
+	// the interval since the last one rather than a single snapshot
+	poller := procmetrics.NewPoller(os.Getpid(), metricsPollInterval)
+	defer poller.Stop()
+	gpuMissingLogged := false
 
 	for {
 		select {
 		case <-ticker.C:
-			// Collect CPU / memory for the current process (the agent itself).
-			// If the agent is wrapping a claude subprocess, callers can pass the
-			// child PID via the --pid flag in a future enhancement; for now we
-			// report the agent's own resource usage which is a reasonable proxy.
-			cpuPercent := 0.0
-			var memoryMB int64
-
-			if sampler == nil {
-				sampler = procmetrics.NewSampler(os.Getpid())
-			}
-			if s, err := sampler.Sample(); err == nil {
-				cpuPercent = s.CPUPercent
-				memoryMB = s.MemoryMB
-			} else {
+			// Collect CPU / memory for the current process (the agent itself),
+			// plus totals for its process tree: claude code and the shells and
+			// tools it spawns, which do most of the work.
+			sample := poller.Average(int(heartbeatInterval / metricsPollInterval))
+			if err := poller.Err(); err != nil {
 				logger.Debug("procmetrics sample failed", zap.Error(err))
 			}
+			cpuPercent := sample.CPUPercent
+			memoryMB := sample.MemoryMB
+
+			gpuSamples, err := procmetrics.SampleGPU(os.Getpid())
+			if errors.Is(err, procmetrics.ErrNoNvidiaSMI) {
+				if !gpuMissingLogged {
+					logger.Info("nvidia-smi not found, GPU metrics disabled")
+					gpuMissingLogged = true
+				}
+			} else if err != nil {
+				logger.Debug("gpu sample failed", zap.Error(err))
+			}
 
 			// Create heartbeat request
 			hb := map[string]interface{}{
-				"runner_id":     runnerID,
-				"status":        "running",
-				"cpu_percent":   cpuPercent,
-				"memory_mb":     memoryMB,
-				"tokens_used":   0,
-				"session_id":    "",
-				"agent_version": "1.4.0",
-				"hostname":      hostname,
+				"runner_id":         runnerID,
+				"status":            "running",
+				"cpu_percent":       cpuPercent,
+				"memory_mb":         memoryMB,
+				"total_cpu_percent": sample.TreeCPUPercent,
+				"total_memory_mb":   sample.TreeMemoryMB,
+				"tokens_used":       0,
+				"session_id":        "",
+				"agent_version":     agentVersion,
+				"hostname":          hostname,
+			}
+			if len(gpuSamples) > 0 {
+				hb["gpu_samples"] = gpuSamples
+			}
+			if cpus, err := procmetrics.CPUAffinity(os.Getpid()); err == nil && len(cpus) > 0 {
+				hb["cpu_affinity"] = cpus
+			}
+			if errorContext := stderrErrors.take(); errorContext != "" {
+				hb["error_context"] = errorContext
+			}
+			if preflightReport != nil {
+				hb["preflight_result"] = preflightReport
 			}
 
 			data, err := json.Marshal(hb)
@@ -169,28 +274,45 @@ func sendHeartbeats(ctx context.Context, runnerID string, logger *zap.Logger) {
 				continue
 			}
 
-			resp, err := client.Post(apiURL, "application/json", bytes.NewReader(data))
+			resp, err := client.Post(heartbeatURL, "application/json", bytes.NewReader(data))
 			if err != nil {
 				logger.Debug("heartbeat failed (daemon may be restarting)", zap.Error(err))
 				continue
 			}
+			var env api.Response[api.HeartbeatResponse]
+			decodeErr := json.NewDecoder(resp.Body).Decode(&env)
 			resp.Body.Close()
 
+			if decodeErr == nil && env.Data.Command == api.HeartbeatCommandExit {
+				logger.Warn("daemon reports runner terminated, stopping heartbeats",
+					zap.String("runner_id", runnerID),
+					zap.String("reason", env.Data.Error))
+				close(terminated)
+				return
+			}
+
+			// Delivered; later heartbeats don't repeat the results
+			if resp.StatusCode == http.StatusOK {
+				preflightReport = nil
+			}
+
 			logger.Debug("heartbeat sent",
 				zap.String("runner_id", runnerID),
 				zap.Float64("cpu_pct", cpuPercent),
-				zap.Int64("mem_mb", memoryMB))
+				zap.Int64("mem_mb", memoryMB),
+				zap.Float64("total_cpu_pct", sample.TreeCPUPercent),
+				zap.Int64("total_mem_mb", sample.TreeMemoryMB))
 
 		case <-ctx.Done():
 			// Send final heartbeat
 			finalHB := map[string]interface{}{
 				"runner_id":     runnerID,
 				"status":        "stopped",
-				"agent_version": "1.4.0",
+				"agent_version": agentVersion,
 				"hostname":      hostname,
 			}
 			data, _ := json.Marshal(finalHB)
-			client.Post(apiURL, "application/json", bytes.NewReader(data))
+			client.Post(heartbeatURL, "application/json", bytes.NewReader(data))
 			return
 		}
 	}
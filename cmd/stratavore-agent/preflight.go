@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Pre-flight thresholds below which the agent warns
+const (
+	minFreeDiskBytes = 1 << 30 // 1 GiB
+	minOpenFiles     = 1024
+)
+
+// preflightExitCode is the agent's exit code when a critical pre-flight
+// check fails, so the daemon can tell it apart from claude code failing
+const preflightExitCode = 2
+
+// errPreflightUnsupported marks a check this platform can't run
+var errPreflightUnsupported = errors.New("not supported on this platform")
+
+// preflightReport holds the pre-flight results until a heartbeat delivers
+// them to the daemon
+var preflightReport *types.PreflightResult
+
+// preflightError is returned by preflight when a critical check fails
+type preflightError struct {
+	Message     string                 `json:"error"`
+	RunnerID    string                 `json:"runner_id"`
+	ProjectPath string                 `json:"project_path"`
+	ClaudeFlags []string               `json:"claude_flags"`
+	Result      *types.PreflightResult `json:"preflight_result"`
+}
+
+// preflight checks that claude code can be started in projectPath. A
+// failed critical check (claude binary, project directory) returns a
+// *preflightError after reporting it to the daemon; the other checks only
+// log a warning. The results are kept in preflightReport for the first
+// heartbeat.
+func preflight(ctx context.Context, projectPath string, flags []string, logger *zap.Logger) error {
+	result := &types.PreflightResult{Passed: true}
+
+	add := func(name string, critical bool, err error) {
+		check := types.PreflightCheck{Name: name, Passed: err == nil, Critical: critical}
+		switch {
+		case err == nil:
+		case errors.Is(err, errPreflightUnsupported):
+			check.Passed = true
+			check.Message = "skipped: " + err.Error()
+			logger.Warn("pre-flight check skipped", zap.String("check", name), zap.Error(err))
+		case critical:
+			check.Message = err.Error()
+			result.Passed = false
+			logger.Error("pre-flight check failed", zap.String("check", name), zap.Error(err))
+		default:
+			check.Message = err.Error()
+			logger.Warn("pre-flight check failed", zap.String("check", name), zap.Error(err))
+		}
+		result.Checks = append(result.Checks, check)
+	}
+
+	add("claude_binary", true, checkClaudeBinary())
+	add("project_path", true, checkProjectPath(projectPath))
+	add("disk_space", false, checkDiskSpace(projectPath))
+	add("open_files_limit", false, checkOpenFilesLimit())
+
+	preflightReport = result
+	if result.Passed {
+		return nil
+	}
+
+	// The agent exits before its first heartbeat, so send the results now
+	sendPreflightFailure(ctx, result, logger)
+
+	return &preflightError{
+		Message:     "pre-flight checks failed",
+		RunnerID:    runnerID,
+		ProjectPath: projectPath,
+		ClaudeFlags: flags,
+		Result:      result,
+	}
+}
+
+func (e *preflightError) Error() string {
+	return e.Message
+}
+
+// checkClaudeBinary verifies claude is on PATH and executable
+func checkClaudeBinary() error {
+	// LookPath only returns files with an execute bit set
+	if _, err := exec.LookPath("claude"); err != nil {
+		return fmt.Errorf("claude not found in PATH: %w", err)
+	}
+	return nil
+}
+
+// checkProjectPath verifies the project directory exists and can be listed
+func checkProjectPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	if _, err := dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s is not readable: %w", path, err)
+	}
+	return nil
+}
+
+// checkDiskSpace warns when the project's filesystem is nearly full
+func checkDiskSpace(path string) error {
+	free, err := freeDiskBytes(path)
+	if err != nil {
+		return fmt.Errorf("check free disk space: %w", err)
+	}
+	if free < minFreeDiskBytes {
+		return fmt.Errorf("only %d MB free on the project's filesystem (want at least %d MB)",
+			free>>20, minFreeDiskBytes>>20)
+	}
+	return nil
+}
+
+// checkOpenFilesLimit warns when ulimit -n is too low for claude code's
+// file watchers
+func checkOpenFilesLimit() error {
+	limit, err := openFilesLimit()
+	if err != nil {
+		return fmt.Errorf("check open files limit: %w", err)
+	}
+	if limit < minOpenFiles {
+		return fmt.Errorf("open files limit is %d (want at least %d; raise it with ulimit -n)",
+			limit, minOpenFiles)
+	}
+	return nil
+}
+
+// sendPreflightFailure posts a final heartbeat carrying the pre-flight
+// results. Delivery is best effort; the exit code tells the daemon the
+// launch failed either way.
+func sendPreflightFailure(ctx context.Context, result *types.PreflightResult, logger *zap.Logger) {
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(map[string]interface{}{
+		"runner_id":        runnerID,
+		"status":           "failed",
+		"agent_version":    agentVersion,
+		"hostname":         hostname,
+		"preflight_result": result,
+	})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", heartbeatURL, bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Debug("failed to report pre-flight results", zap.Error(err))
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,11 @@
+package main
+
+// freeDiskBytes is not implemented on Windows; the check is skipped
+func freeDiskBytes(path string) (uint64, error) {
+	return 0, errPreflightUnsupported
+}
+
+// openFilesLimit has no Windows equivalent of ulimit -n
+func openFilesLimit() (uint64, error) {
+	return 0, errPreflightUnsupported
+}
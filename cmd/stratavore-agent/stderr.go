@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+)
+
+// stderrErrorPatterns are substrings of claude's stderr that point at a
+// resource problem worth surfacing to the daemon
+var stderrErrorPatterns = []string{
+	"permission denied",
+	"out of memory",
+	"no space left on device",
+}
+
+// maxErrorContextLen caps the stderr line sent with a heartbeat
+const maxErrorContextLen = 512
+
+// stderrWatcher remembers the latest stderr line matching
+// stderrErrorPatterns until the next heartbeat takes it
+type stderrWatcher struct {
+	mu   sync.Mutex
+	last string
+}
+
+// watch copies r to w while scanning its lines for error patterns. It
+// returns when r is exhausted.
+func (sw *stderrWatcher) watch(r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(io.TeeReader(r, w))
+	for scanner.Scan() {
+		line := scanner.Text()
+		lower := strings.ToLower(line)
+		for _, pattern := range stderrErrorPatterns {
+			if strings.Contains(lower, pattern) {
+				if len(line) > maxErrorContextLen {
+					line = line[:maxErrorContextLen]
+				}
+				sw.mu.Lock()
+				sw.last = line
+				sw.mu.Unlock()
+				break
+			}
+		}
+	}
+
+	// Keep draining after an over-long line so the child never blocks on
+	// a full pipe
+	io.Copy(w, r)
+}
+
+// take returns the latest matched line and clears it
+func (sw *stderrWatcher) take() string {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	line := sw.last
+	sw.last = ""
+	return line
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	activityCmd.Flags().Int("limit", 50, "Maximum number of events to show")
+	rootCmd.AddCommand(activityCmd)
+}
+
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show recent activity across all projects",
+	Long: `Show what happened recently across all projects: runners started, sessions
+ended and token budget warnings. The newest events are printed last.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		resp, err := apiClient.GetActivity(ctx, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Events) == 0 {
+			fmt.Println("No recent activity")
+			return
+		}
+
+		fmt.Printf("%-17s %-15s %-20s %-9s %s\n", "TIME", "EVENT", "PROJECT", "ID", "DESCRIPTION")
+		// The daemon returns newest first; print as a feed, oldest first
+		for i := len(resp.Events) - 1; i >= 0; i-- {
+			e := resp.Events[i]
+			fmt.Printf("%-17s %-15s %-20s %-9s %s\n",
+				formatSessionTime(e.Timestamp),
+				truncate(e.EventType, 15),
+				truncate(valueOrDash(e.ProjectName), 20),
+				valueOrDash(shortID(e.ActorID)),
+				e.Description)
+		}
+	},
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/internal/storage"
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// agentProcess describes a stratavore-agent process found on this machine
+type agentProcess struct {
+	PID         int
+	RunnerID    string
+	ProjectName string
+	State       string // running, stopped or zombie
+	StartedAt   time.Time
+	Args        []string
+}
+
+func init() {
+	agentStartCmd.Flags().String("runner-id", "", "Runner ID")
+	agentStartCmd.Flags().String("project", "", "Project name")
+	agentStartCmd.Flags().String("path", "", "Project path (default: current directory)")
+	agentStartCmd.MarkFlagRequired("runner-id")
+	agentStartCmd.MarkFlagRequired("project")
+
+	agentListCmd.Flags().Bool("kill-orphans", false, "Send SIGTERM to agents whose runner has terminated or no longer exists")
+
+	agentStopCmd.ValidArgsFunction = completeRunnerIDs
+
+	agentCmd.AddCommand(agentStartCmd)
+	agentCmd.AddCommand(agentListCmd)
+	agentCmd.AddCommand(agentStopCmd)
+	rootCmd.AddCommand(agentCmd)
+}
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Manage agent processes directly (bypasses the daemon)",
+	Long: `Manage stratavore-agent processes directly on this machine.
+
+These commands do not go through the daemon and are intended for
+debugging runner issues and emergency recovery.`,
+}
+
+var agentStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Run an agent in the foreground",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runnerID, _ := cmd.Flags().GetString("runner-id")
+		projectName, _ := cmd.Flags().GetString("project")
+		projectPath, _ := cmd.Flags().GetString("path")
+
+		if projectPath == "" {
+			cwd, _ := os.Getwd()
+			projectPath = cwd
+		}
+
+		agent := exec.Command(agentBinaryPath(),
+			"--runner-id", runnerID,
+			"--project-name", projectName,
+			"--project-path", projectPath,
+		)
+		agent.Stdin = os.Stdin
+		agent.Stdout = os.Stdout
+		agent.Stderr = os.Stderr
+
+		fmt.Fprintf(os.Stderr, "Starting agent for runner %s (project %s)\n", runnerID, projectName)
+
+		if err := agent.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				os.Exit(exitErr.ExitCode())
+			}
+			fmt.Fprintf(os.Stderr, "Error running agent: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var agentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agent processes running on this machine",
+	Long: `List stratavore-agent processes running on this machine along with
+their runner's status from the daemon. Agents whose runner has terminated,
+failed or no longer exists are marked as orphans; --kill-orphans sends them
+SIGTERM.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		killOrphans, _ := cmd.Flags().GetBool("kill-orphans")
+
+		agents, err := findAgentProcesses()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing agents: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(agents) == 0 {
+			fmt.Println("No agent processes running")
+			return
+		}
+
+		statuses, err := agentRunnerStatuses(agents)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not reach the daemon, runner status unknown: %v\n\n", err)
+		}
+
+		fmt.Printf("Agent Processes (%d):\n\n", len(agents))
+		fmt.Println("PID       RUNNER    PROJECT              RUNNER STATUS  PROCESS   UPTIME")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────")
+
+		var orphans []agentProcess
+		for _, a := range agents {
+			status := statuses[a.RunnerID]
+			if status == "" {
+				status = "unknown"
+			}
+
+			row := fmt.Sprintf("%-8d  %-8s  %-20s %-14s %-9s %s",
+				a.PID,
+				shortID(valueOrDash(a.RunnerID)),
+				truncate(valueOrDash(a.ProjectName), 20),
+				status,
+				a.State,
+				formatDuration(time.Since(a.StartedAt)))
+
+			if isOrphanStatus(status) {
+				orphans = append(orphans, a)
+				row = "\033[31m" + row + "  ← orphan\033[0m"
+			}
+			fmt.Println(row)
+		}
+
+		if len(orphans) == 0 {
+			return
+		}
+
+		fmt.Println()
+		if !killOrphans {
+			fmt.Printf("%d orphaned agent(s); run with --kill-orphans to stop them\n", len(orphans))
+			return
+		}
+
+		failed := false
+		for _, a := range orphans {
+			if err := terminateAgent(a.PID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error sending SIGTERM to pid %d: %v\n", a.PID, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("✓ Sent SIGTERM to orphaned agent pid %d (runner %s)\n", a.PID, shortID(a.RunnerID))
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// agentRunnerStatuses looks up each agent's runner status through the
+// daemon. Runners the daemon doesn't know about are reported as "missing".
+// On a connection error the statuses found so far are returned with it.
+func agentRunnerStatuses(agents []agentProcess) (map[string]string, error) {
+	apiClient := getAPIClient()
+	statuses := make(map[string]string)
+
+	for _, a := range agents {
+		if a.RunnerID == "" {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		resp, err := apiClient.GetRunner(ctx, a.RunnerID)
+		cancel()
+		if err != nil {
+			return statuses, err
+		}
+
+		switch {
+		case resp.Error == "":
+			statuses[a.RunnerID] = resp.Runner.Status
+		case strings.HasPrefix(resp.Error, "runner not found"):
+			statuses[a.RunnerID] = "missing"
+		}
+	}
+
+	return statuses, nil
+}
+
+// isOrphanStatus reports whether an agent whose runner has status should
+// no longer be running
+func isOrphanStatus(status string) bool {
+	switch types.RunnerStatus(status) {
+	case types.StatusTerminated, types.StatusFailed:
+		return true
+	}
+	return status == "missing"
+}
+
+// processStateName maps a ps/proc state letter to a readable name
+func processStateName(state string) string {
+	switch state {
+	case "Z":
+		return "zombie"
+	case "T", "t":
+		return "stopped"
+	default:
+		return "running"
+	}
+}
+
+var agentStopCmd = &cobra.Command{
+	Use:   "stop <runner-id>",
+	Short: "Send SIGTERM to the agent for a runner",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runnerID := args[0]
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		db, err := storage.NewPostgresClient(
+			ctx,
+			cfg.Database.PostgreSQL.GetConnectionString(),
+			5, 1, nil,
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		runner, err := db.GetRunner(ctx, runnerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		pid, err := strconv.Atoi(runner.RuntimeID)
+		if err != nil || pid <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: runner %s has no agent PID (runtime_id=%q)\n", runnerID, runner.RuntimeID)
+			os.Exit(1)
+		}
+
+		if err := terminateAgent(pid); err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending SIGTERM to pid %d: %v\n", pid, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Sent SIGTERM to agent pid %d (runner %s)\n", pid, runnerID)
+	},
+}
+
+// agentBinaryPath locates stratavore-agent next to this executable,
+// falling back to PATH lookup
+func agentBinaryPath() string {
+	exeName := "stratavore-agent"
+	if runtime.GOOS == "windows" {
+		exeName += ".exe"
+	}
+
+	if exePath, err := os.Executable(); err == nil {
+		candidate := filepath.Join(filepath.Dir(exePath), exeName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+
+	return exeName
+}
+
+// flagValue extracts the value of the --name flag from agent arguments.
+// The agent uses the standard flag package, so -name and = forms work too.
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		switch {
+		case (arg == "--"+name || arg == "-"+name) && i+1 < len(args):
+			return args[i+1]
+		case strings.HasPrefix(arg, "--"+name+"="):
+			return strings.TrimPrefix(arg, "--"+name+"=")
+		case strings.HasPrefix(arg, "-"+name+"="):
+			return strings.TrimPrefix(arg, "-"+name+"=")
+		}
+	}
+	return ""
+}
+
+// isAgentBinary reports whether argv0 names the stratavore-agent binary
+func isAgentBinary(argv0 string) bool {
+	base := filepath.Base(argv0)
+	return base == "stratavore-agent" || base == "stratavore-agent.exe"
+}
@@ -0,0 +1,116 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicksPerSecond is USER_HZ, the unit of /proc/<pid>/stat times. It is
+// 100 on every architecture Linux supports.
+const clockTicksPerSecond = 100
+
+// findAgentProcesses scans /proc for running stratavore-agent processes.
+func findAgentProcesses() ([]agentProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	var agents []agentProcess
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+
+		// Processes may exit between ReadDir and ReadFile; skip silently
+		raw, err := os.ReadFile(filepath.Join("/proc", e.Name(), "cmdline"))
+		if err != nil || len(raw) == 0 {
+			continue
+		}
+
+		args := strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00")
+		if !isAgentBinary(args[0]) {
+			continue
+		}
+
+		state, startTicks, err := readProcStat(pid)
+		if err != nil {
+			continue
+		}
+
+		agents = append(agents, agentProcess{
+			PID:         pid,
+			RunnerID:    flagValue(args[1:], "runner-id"),
+			ProjectName: flagValue(args[1:], "project-name"),
+			State:       processStateName(state),
+			StartedAt:   bootTime.Add(time.Duration(startTicks) * time.Second / clockTicksPerSecond),
+			Args:        args,
+		})
+	}
+
+	return agents, nil
+}
+
+// readProcStat returns the state letter and start time, in clock ticks
+// since boot, from /proc/<pid>/stat
+func readProcStat(pid int) (string, int64, error) {
+	raw, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return "", 0, err
+	}
+
+	// The command name in parentheses may contain spaces, so fields are
+	// counted from the last ')'
+	end := strings.LastIndexByte(string(raw), ')')
+	if end < 0 {
+		return "", 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	fields := strings.Fields(string(raw[end+1:]))
+	if len(fields) < 20 {
+		return "", 0, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+
+	// fields[0] is field 3 (state); starttime is field 22
+	startTicks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed stat for pid %d: %w", pid, err)
+	}
+	return fields[0], startTicks, nil
+}
+
+// readBootTime returns when the system booted, from /proc/stat
+func readBootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parse btime: %w", err)
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, fmt.Errorf("btime missing from /proc/stat")
+}
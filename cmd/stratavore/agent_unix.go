@@ -0,0 +1,77 @@
+//go:build unix && !linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// findAgentProcesses lists running stratavore-agent processes via `ps`.
+func findAgentProcesses() ([]agentProcess, error) {
+	out, err := exec.Command("ps", "-axo", "pid=,state=,etime=,args=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ps failed: %w", err)
+	}
+
+	now := time.Now()
+	var agents []agentProcess
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		args := fields[3:]
+		if !isAgentBinary(args[0]) {
+			continue
+		}
+
+		elapsed, err := parseElapsed(fields[2])
+		if err != nil {
+			continue
+		}
+
+		agents = append(agents, agentProcess{
+			PID:         pid,
+			RunnerID:    flagValue(args[1:], "runner-id"),
+			ProjectName: flagValue(args[1:], "project-name"),
+			State:       processStateName(fields[1][:1]),
+			StartedAt:   now.Add(-elapsed),
+			Args:        args,
+		})
+	}
+
+	return agents, nil
+}
+
+// parseElapsed parses ps's etime format, [[dd-]hh:]mm:ss
+func parseElapsed(s string) (time.Duration, error) {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil {
+			return 0, fmt.Errorf("invalid elapsed time %q", s)
+		}
+		days, s = n, rest
+	}
+
+	var secs int
+	for _, part := range strings.Split(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid elapsed time %q", s)
+		}
+		secs = secs*60 + n
+	}
+
+	return time.Duration(days)*24*time.Hour + time.Duration(secs)*time.Second, nil
+}
@@ -0,0 +1,17 @@
+package main
+
+import "errors"
+
+// errAgentsUnsupported is returned by the agent commands on Windows, which
+// has neither ps nor SIGTERM
+var errAgentsUnsupported = errors.New("managing agent processes is not supported on Windows; stop runners through the daemon")
+
+// findAgentProcesses is not supported on Windows
+func findAgentProcesses() ([]agentProcess, error) {
+	return nil, errAgentsUnsupported
+}
+
+// terminateAgent is not supported on Windows
+func terminateAgent(_ int) error {
+	return errAgentsUnsupported
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	authTokenCreateCmd.Flags().String("name", "", "Name to identify the token, e.g. \"CI token\"")
+	authTokenCreateCmd.Flags().StringSlice("scope", nil, "Comma-separated scopes, e.g. runners:read (default: your own scopes)")
+	authTokenCreateCmd.Flags().String("expires", "30d", "Token lifetime, e.g. 12h or 90d (at most 365d)")
+	authTokenCreateCmd.MarkFlagRequired("name")
+	authTokenCmd.AddCommand(authTokenCreateCmd)
+	authTokenCmd.AddCommand(authTokenListCmd)
+	authTokenCmd.AddCommand(authTokenRevokeCmd)
+	authCmd.AddCommand(authTokenCmd)
+	authRotateCmd.Flags().String("token", "", "Rotate this token and print its replacement instead of the saved login")
+	authCmd.AddCommand(authRotateCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage API authentication",
+}
+
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage long-lived API tokens for scripts and CI",
+}
+
+var authTokenCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API token",
+	Long: `Create a long-lived API token. The token is printed once and is not
+stored by the daemon or the CLI, so copy it now. Use it by setting the
+Authorization: Bearer header or the X-API-Key header.
+
+Requires an existing login; a token cannot carry scopes you don't hold.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		name, _ := cmd.Flags().GetString("name")
+		scope, _ := cmd.Flags().GetStringSlice("scope")
+		expires, _ := cmd.Flags().GetString("expires")
+
+		ttl, err := parseAge(expires)
+		if err != nil || ttl == 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid --expires %q\n", expires)
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.CreateAPIToken(ctx, &api.CreateAPITokenRequest{
+			Name:             name,
+			Scope:            scope,
+			ExpiresInSeconds: int64(ttl.Seconds()),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		t := resp.APIToken
+		fmt.Printf("✓ Created token %q (%s)\n", t.Name, t.ID)
+		fmt.Printf("  Scope:   %s\n", strings.Join(t.Scope, ","))
+		fmt.Printf("  Expires: %s\n\n", formatSessionTime(t.ExpiresAt))
+		fmt.Println("Copy the token now, it will not be shown again:")
+		fmt.Println()
+		fmt.Println(resp.Token)
+	},
+}
+
+var authTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active API tokens",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.ListAPITokens(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Tokens) == 0 {
+			fmt.Println("No active API tokens")
+			return
+		}
+
+		fmt.Printf("API Tokens (%d):\n\n", len(resp.Tokens))
+		fmt.Println("ID                                    NAME                 SCOPE                CREATED           LAST USED         EXPIRES")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────────")
+
+		for _, t := range resp.Tokens {
+			fmt.Printf("%-36s  %-20s %-20s %-16s  %-16s  %s\n",
+				t.ID,
+				truncate(t.Name, 20),
+				truncate(strings.Join(t.Scope, ","), 20),
+				formatSessionTime(t.CreatedAt),
+				formatSessionTime(t.LastUsedAt),
+				formatSessionTime(t.ExpiresAt))
+		}
+	},
+}
+
+var authTokenRevokeCmd = &cobra.Command{
+	Use:   "revoke <token-id>",
+	Short: "Revoke an API token",
+	Long: `Revoke an API token so the daemon rejects it immediately. Every daemon
+sharing the same Redis sees the revocation.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.RevokeAPIToken(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Revoked token %s\n", args[0])
+	},
+}
+
+var authRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Exchange a token for one with a refreshed expiry",
+	Long: `Exchange a valid token for a new one with the same subject and scope,
+valid for as long as the original was. The old token stops working at once.
+A token can't be rotated in the last 10% of its validity period; log in
+again or create a new API token instead.
+
+Without --token the saved login is rotated and the new token saved in its
+place.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" && loadToken() == "" {
+			fmt.Fprintln(os.Stderr, "Error: not logged in; run 'stratavore login' or pass --token")
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.RotateToken(ctx, token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if token != "" {
+			fmt.Println(resp.Token)
+			fmt.Fprintf(os.Stderr, "✓ Token rotated (expires %s); the old token no longer works\n", formatSessionTime(resp.ExpiresAt))
+			return
+		}
+
+		if err := saveToken(&storedToken{Token: resp.Token, ExpiresAt: resp.ExpiresAt}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Login token rotated (expires %s)\n", formatSessionTime(resp.ExpiresAt))
+	},
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// budgetBarWidth is the width of a fully used budget's bar
+const budgetBarWidth = 20
+
+func init() {
+	budgetHistoryCmd.Flags().Int("periods", 10, "Number of periods to show")
+	budgetCmd.AddCommand(budgetHistoryCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "View token budgets",
+}
+
+var budgetHistoryCmd = &cobra.Command{
+	Use:   "history [project]",
+	Short: "Show past token budget periods",
+	Long: `List a project's token budget periods, newest first, with how much of
+each period's limit was used. The current period is shown in bold. Without a
+project the global budget is shown.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		periods, _ := cmd.Flags().GetInt("periods")
+
+		scope, scopeID := "global", ""
+		if len(args) == 1 {
+			scope, scopeID = "project", args[0]
+		}
+
+		resp, err := apiClient.GetBudgetHistory(ctx, scope, scopeID, periods)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Periods) == 0 {
+			fmt.Println("No budget periods found")
+			return
+		}
+
+		fmt.Println("PERIOD                     LIMIT        USED         REMAINING    USED%")
+		fmt.Println("────────────────────────────────────────────────────────────────────────────────────────────")
+
+		for _, p := range resp.Periods {
+			remaining := p.LimitTokens - p.UsedTokens
+			if remaining < 0 {
+				remaining = 0
+			}
+
+			row := fmt.Sprintf("%-26s %-12s %-12s %-12s %4.0f%% %s",
+				formatBudgetPeriod(p),
+				formatNumber(p.LimitTokens),
+				formatNumber(p.UsedTokens),
+				formatNumber(remaining),
+				budgetPercent(p),
+				budgetBar(p))
+			if p.Current {
+				row = "\033[1m" + row + "\033[0m"
+			}
+			fmt.Println(row)
+		}
+	},
+}
+
+// formatBudgetPeriod renders a period as "YYYY-MM-DD HH:MM → HH:MM" for
+// hourly budgets and "YYYY-MM-DD → YYYY-MM-DD" otherwise
+func formatBudgetPeriod(p *api.BudgetPeriod) string {
+	start, _ := api.ParseTime(p.PeriodStart)
+	end, _ := api.ParseTime(p.PeriodEnd)
+	start, end = start.Local(), end.Local()
+
+	if p.PeriodGranularity == "hourly" {
+		return fmt.Sprintf("%s → %s", start.Format("2006-01-02 15:04"), end.Format("15:04"))
+	}
+	return fmt.Sprintf("%s → %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+}
+
+func budgetPercent(p *api.BudgetPeriod) float64 {
+	if p.LimitTokens <= 0 {
+		return 0
+	}
+	return float64(p.UsedTokens) * 100 / float64(p.LimitTokens)
+}
+
+// budgetBar draws the fraction of the limit used, capped at a full bar
+func budgetBar(p *api.BudgetPeriod) string {
+	width := int(budgetPercent(p) * budgetBarWidth / 100)
+	if width > budgetBarWidth {
+		width = budgetBarWidth
+	}
+	if width == 0 && p.UsedTokens > 0 {
+		width = 1
+	}
+	return strings.Repeat("█", width) + strings.Repeat("░", budgetBarWidth-width)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	cloneCmd.Flags().StringP("project", "p", "", "Launch the clone in a different project")
+	cloneCmd.Flags().StringSlice("flag", []string{}, "Additional flags to pass to Claude Code")
+	cloneCmd.Flags().Bool("resume-session", false, "Resume the source runner's conversation instead of starting a new one")
+	cloneCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	cloneCmd.ValidArgsFunction = completeRunnerIDs
+	rootCmd.AddCommand(cloneCmd)
+}
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <runner-id>",
+	Short: "Launch a new runner with an existing runner's configuration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		flags, _ := cmd.Flags().GetStringSlice("flag")
+		resumeSession, _ := cmd.Flags().GetBool("resume-session")
+
+		req := &api.CloneRunnerRequest{
+			SourceRunnerID: args[0],
+			NewProjectName: projectName,
+			Overrides:      &api.LaunchRunnerRequest{Flags: flags},
+			ResumeSession:  resumeSession,
+		}
+
+		fmt.Printf("🧬 Cloning runner %s...\n", args[0])
+
+		resp, err := apiClient.CloneRunner(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Runner started: %s\n", resp.Runner.ID)
+		fmt.Printf("  Status: %s\n", resp.Runner.Status)
+		fmt.Printf("  Project: %s\n", resp.Runner.ProjectName)
+		if resp.Runner.SessionID != "" {
+			fmt.Printf("  Session: %s\n", resp.Runner.SessionID)
+		}
+		fmt.Printf("\nUse 'stratavore watch %s' to monitor\n", resp.Runner.ProjectName)
+	},
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds dynamic completion lookups so that a missing or
+// slow daemon never hangs the shell.
+const completionTimeout = 500 * time.Millisecond
+
+func init() {
+	launchCmd.ValidArgsFunction = completeProjectNames
+	runnersCmd.ValidArgsFunction = completeProjectNames
+	watchCmd.ValidArgsFunction = completeProjectNames
+	projectDuplicateCmd.ValidArgsFunction = completeProjectNames
+	projectUpdateCmd.ValidArgsFunction = completeProjectNames
+	projectMoveCmd.ValidArgsFunction = completeProjectNames
+	budgetHistoryCmd.ValidArgsFunction = completeProjectNames
+	killCmd.ValidArgsFunction = completeRunnerIDs
+	attachCmd.ValidArgsFunction = completeRunnerIDs
+}
+
+// completeProjectNames suggests project names known to the daemon.
+// Returns no suggestions on error or timeout.
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	resp, err := getAPIClient().ListProjects(ctx, "", false)
+	if err != nil || resp.Error != "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range resp.Projects {
+		if strings.HasPrefix(p.Name, toComplete) {
+			names = append(names, p.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRunnerIDs suggests active runner IDs, annotated with their project.
+// Returns no suggestions on error or timeout.
+func completeRunnerIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	resp, err := getAPIClient().ListRunners(ctx, "")
+	if err != nil || resp.Error != "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, r := range resp.Runners {
+		if strings.HasPrefix(r.ID, toComplete) {
+			ids = append(ids, fmt.Sprintf("%s\t%s", r.ID, r.ProjectName))
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
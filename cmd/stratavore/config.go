@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	configEncryptCmd.Flags().String("recipient", "", "age public key (age1...)")
+	configEncryptCmd.Flags().String("input", "", "Plaintext config file")
+	configEncryptCmd.Flags().String("output", "", "Encrypted output file")
+	configEncryptCmd.MarkFlagRequired("recipient")
+	configEncryptCmd.MarkFlagRequired("input")
+	configEncryptCmd.MarkFlagRequired("output")
+
+	configDecryptCmd.Flags().String("identity", "", "age identity file (default: $STRATAVORE_IDENTITY_FILE or ~/.config/stratavore/identity)")
+	configDecryptCmd.Flags().String("input", "", "Encrypted config file")
+	configDecryptCmd.Flags().String("output", "", "Plaintext output file (default: stdout)")
+	configDecryptCmd.MarkFlagRequired("input")
+
+	configCmd.AddCommand(configEncryptCmd)
+	configCmd.AddCommand(configDecryptCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage configuration files",
+}
+
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt a config file with age",
+	Long: `Encrypt a config file to an age recipient so it can be committed safely.
+
+Name the output stratavore.yaml.age (or stratavore.age) and place it in a
+config search path; the daemon and CLI decrypt it automatically using
+$STRATAVORE_IDENTITY_FILE or ~/.config/stratavore/identity.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		recipient, _ := cmd.Flags().GetString("recipient")
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+
+		plaintext, err := os.ReadFile(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ciphertext, err := config.EncryptConfig(plaintext, recipient)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := os.WriteFile(output, ciphertext, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Encrypted %s → %s\n", input, output)
+	},
+}
+
+var configDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt an age-encrypted config file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		identity, _ := cmd.Flags().GetString("identity")
+		input, _ := cmd.Flags().GetString("input")
+		output, _ := cmd.Flags().GetString("output")
+
+		if identity == "" {
+			identity = config.DefaultIdentityPath()
+		}
+
+		ciphertext, err := os.ReadFile(input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		plaintext, err := config.DecryptConfig(ciphertext, identity)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if output == "" {
+			os.Stdout.Write(plaintext)
+			return
+		}
+
+		// Plaintext contains secrets; keep it owner-readable only
+		if err := os.WriteFile(output, plaintext, 0600); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(os.Stderr, "✓ Decrypted %s → %s\n", input, output)
+	},
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	configInitCmd.Flags().Bool("non-interactive", false, "Don't prompt; keep existing values and use defaults for the rest")
+	configInitCmd.Flags().String("output", "", "Config file to write (default: ~/.config/stratavore/stratavore.yaml)")
+	configCmd.AddCommand(configInitCmd)
+}
+
+// defaultHTTPPort matches the CLI's fallback when daemon.http_port is unset
+const defaultHTTPPort = 50049
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a starter config file interactively",
+	Long: `Prompt for database, daemon, logging and Telegram settings and write
+them to ~/.config/stratavore/stratavore.yaml. Press Enter to accept the value
+shown in brackets.
+
+If the file already exists you can overwrite it or merge into it; merging
+keeps every setting the wizard doesn't ask about. With --non-interactive
+nothing is prompted: an existing file is merged and defaults fill the rest.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		path, _ := cmd.Flags().GetString("output")
+		if path == "" {
+			path = config.UserConfigPath()
+		}
+
+		w := &configWizard{
+			in:          bufio.NewReader(os.Stdin),
+			interactive: !nonInteractive,
+		}
+
+		v := viper.New()
+		v.SetConfigType("yaml")
+
+		if _, err := os.Stat(path); err == nil {
+			choice := "merge"
+			if w.interactive {
+				choice = w.ask(fmt.Sprintf("%s exists. Overwrite, merge or abort? (o/m/a)", path), "m")
+			}
+			switch strings.ToLower(choice) {
+			case "o", "overwrite":
+			case "m", "merge":
+				v.SetConfigFile(path)
+				if err := v.ReadInConfig(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			default:
+				fmt.Println("Aborted")
+				return
+			}
+		}
+
+		defaults, err := config.DefaultConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		pg := defaults.Database.PostgreSQL
+
+		fmt.Println("PostgreSQL")
+		w.setString(v, "database.postgresql.host", "  Host", pg.Host)
+		w.setInt(v, "database.postgresql.port", "  Port", pg.Port)
+		w.setString(v, "database.postgresql.user", "  User", pg.User)
+		w.setString(v, "database.postgresql.password", "  Password", pg.Password)
+		w.setString(v, "database.postgresql.database", "  Database", pg.Database)
+
+		fmt.Println("Daemon")
+		w.setInt(v, "daemon.grpc_port", "  gRPC port", defaults.Daemon.Port_GRPC)
+		w.setInt(v, "daemon.http_port", "  HTTP port", defaultHTTPPort)
+
+		fmt.Println("Logging")
+		w.setString(v, "observability.log_level", "  Log level (debug, info, warn, error)", defaults.Observability.LogLevel)
+
+		fmt.Println("Telegram notifications (leave empty to disable)")
+		w.setString(v, "docker.telegram.token", "  Bot token", "")
+		w.setString(v, "docker.telegram.chat_id", "  Chat ID", "")
+
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: create config dir: %v\n", err)
+			os.Exit(1)
+		}
+
+		// The file holds the database password; keep it owner-readable only
+		if err := v.WriteConfigAs(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\n✓ Wrote %s\n", path)
+
+		if w.interactive && strings.HasPrefix(strings.ToLower(w.ask("Check the database connection now? (y/n)", "y")), "y") {
+			checkDatabaseConnection(v)
+		}
+	},
+}
+
+// configWizard reads answers from stdin, or returns each default unchanged
+// when not interactive
+type configWizard struct {
+	in          *bufio.Reader
+	interactive bool
+}
+
+// ask prints label with def in brackets and returns the trimmed answer, or
+// def for an empty answer
+func (w *configWizard) ask(label, def string) string {
+	if !w.interactive {
+		return def
+	}
+
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	// On EOF, ReadString returns what was read so far
+	line, _ := w.in.ReadString('\n')
+	if line = strings.TrimSpace(line); line == "" {
+		return def
+	}
+	return line
+}
+
+// setString prompts for key, defaulting to its current value in v or def
+func (w *configWizard) setString(v *viper.Viper, key, label, def string) {
+	if v.IsSet(key) {
+		def = v.GetString(key)
+	}
+	if answer := w.ask(label, def); answer != "" {
+		v.Set(key, answer)
+	}
+}
+
+// setInt is setString for integer settings, re-prompting on invalid input
+func (w *configWizard) setInt(v *viper.Viper, key, label string, def int) {
+	if v.IsSet(key) {
+		def = v.GetInt(key)
+	}
+	for {
+		n, err := strconv.Atoi(w.ask(label, strconv.Itoa(def)))
+		if err == nil {
+			v.Set(key, n)
+			return
+		}
+		fmt.Println("  Please enter a number")
+	}
+}
+
+// checkDatabaseConnection connects to PostgreSQL with the written settings
+func checkDatabaseConnection(v *viper.Viper) {
+	pg := config.PostgreSQLConfig{
+		Host:     v.GetString("database.postgresql.host"),
+		Port:     v.GetInt("database.postgresql.port"),
+		User:     v.GetString("database.postgresql.user"),
+		Password: v.GetString("database.postgresql.password"),
+		Database: v.GetString("database.postgresql.database"),
+		SSLMode:  "prefer",
+	}
+	if v.IsSet("database.postgresql.sslmode") {
+		pg.SSLMode = v.GetString("database.postgresql.sslmode")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, pg.GetConnectionString())
+	if err != nil {
+		fmt.Printf("❌ PostgreSQL: %v\n", err)
+		return
+	}
+	defer conn.Close(ctx)
+
+	if err := conn.Ping(ctx); err != nil {
+		fmt.Printf("❌ PostgreSQL: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ PostgreSQL: connected to %s:%d/%s\n", pg.Host, pg.Port, pg.Database)
+}
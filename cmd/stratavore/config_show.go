@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+func init() {
+	configShowCmd.Flags().String("file", "", "Config file to load instead of searching the default locations")
+	configShowCmd.Flags().String("format", "yaml", "Output format: yaml, json or env")
+	configCmd.AddCommand(configShowCmd)
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration the daemon and CLI would use: built-in defaults,
+overridden by the config file, overridden by STRATAVORE_* environment
+variables. Passwords, tokens and secrets are shown as [REDACTED].
+
+With --format env only settings that differ from the defaults are printed,
+as STRATAVORE_*=value lines.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		format, _ := cmd.Flags().GetString("format")
+
+		var cfg *config.Config
+		var err error
+		if file != "" {
+			cfg, err = config.LoadConfigFile(file)
+		} else {
+			cfg, err = config.LoadConfig()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// The source goes to stderr so the output stays parseable
+		if cfg.File() != "" {
+			fmt.Fprintf(os.Stderr, "# Config file: %s\n", cfg.File())
+		} else {
+			fmt.Fprintln(os.Stderr, "# Config file: none found, using defaults")
+		}
+
+		settings := config.RedactSettings(cfg.Settings())
+
+		switch format {
+		case "yaml":
+			out, err := yaml.Marshal(settings)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Stdout.Write(out)
+
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(settings); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+		case "env":
+			if err := printConfigEnv(cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (use yaml, json or env)\n", format)
+			os.Exit(1)
+		}
+	},
+}
+
+// printConfigEnv prints the settings that differ from the built-in
+// defaults as environment variable assignments
+func printConfigEnv(cfg *config.Config) error {
+	defaults, err := config.DefaultConfig()
+	if err != nil {
+		return err
+	}
+	defaultFlat := config.FlattenSettings(defaults.Settings())
+
+	flat := config.FlattenSettings(cfg.Settings())
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := flat[key]
+		if reflect.DeepEqual(value, defaultFlat[key]) {
+			continue
+		}
+		if config.IsSensitiveKey(key) {
+			value = config.RedactedValue
+		}
+		fmt.Printf("%s=%s\n", config.EnvName(key), config.EnvValue(value))
+	}
+	return nil
+}
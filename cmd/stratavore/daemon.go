@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// daemonProcessName is the executable name the stop command expects the
+// PID file to point at
+const daemonProcessName = "stratavored"
+
+// errDaemonNotRunning is returned when the PID file names a process that
+// no longer exists
+var errDaemonNotRunning = errors.New("daemon is not running")
+
+// daemonStopPollInterval is how often stop checks whether the daemon exited
+const daemonStopPollInterval = 250 * time.Millisecond
+
+// daemonKillGrace is how long stop waits for the daemon to exit after SIGKILL
+const daemonKillGrace = 5 * time.Second
+
+func init() {
+	daemonCmd.Flags().Int("timeout", 30, "Seconds to wait for the daemon to stop (stop)")
+	daemonCmd.Flags().Bool("force", false, "Send SIGKILL if the daemon hasn't stopped within --timeout (stop)")
+	daemonCmd.Flags().Bool("wait-for-port", false, "Also wait for the HTTP port to stop accepting connections (stop)")
+}
+
+// runDaemonStatus prints detailed daemon status and dependency health
+func runDaemonStatus() {
+	apiClient := getAPIClient()
+	ctx := context.Background()
+
+	resp, err := apiClient.GetStatus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Daemon: Not running (%v)\n", err)
+		fmt.Fprintf(os.Stderr, "   Start with: stratavored\n")
+		os.Exit(1)
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+
+	d := resp.Daemon
+	m := resp.Metrics
+
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println("  STRATAVORE DAEMON")
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println()
+	fmt.Printf("Status:      %s\n", boolToStatus(d.Healthy))
+	fmt.Printf("Version:     %s\n", d.Version)
+	fmt.Printf("Host:        %s\n", d.Hostname)
+	fmt.Printf("PID:         %d\n", d.PID)
+	fmt.Printf("Started:     %s\n", d.StartedAt)
+	fmt.Printf("Uptime:      %s\n", formatDuration(time.Duration(d.UptimeSeconds)*time.Second))
+	fmt.Printf("Goroutines:  %d\n", d.Goroutines)
+	fmt.Println()
+
+	ready, err := apiClient.GetReadiness(ctx)
+	if err != nil {
+		fmt.Printf("Connections: unavailable (%v)\n", err)
+	} else {
+		fmt.Println("Connections:")
+		for _, c := range ready.Components {
+			line := fmt.Sprintf("  %-10s %s", c.Name, c.Status)
+			if c.Detail != "" {
+				line += " (" + c.Detail + ")"
+			}
+			fmt.Println(line)
+		}
+		if p := ready.Postgres; p != nil {
+			fmt.Printf("  pg pool    total=%d acquired=%d idle=%d max=%d\n",
+				p.TotalConns, p.AcquiredConns, p.IdleConns, p.MaxConns)
+		}
+	}
+	fmt.Println()
+
+	fmt.Printf("Active Runners: %d\n", m.ActiveRunners)
+	statuses := make([]string, 0, len(m.RunnersByStatus))
+	for status := range m.RunnersByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Printf("  %-10s %d\n", status, m.RunnersByStatus[status])
+	}
+	fmt.Printf("  %-10s %d (waiting for a launch slot)\n", "queued", m.QueuedLaunches)
+	fmt.Printf("Tokens Today:   %s\n", formatNumber(m.TokensToday))
+}
+
+// waitForPortClosed polls until nothing accepts connections on the local
+// port or deadline passes. It reports whether the port closed.
+func waitForPortClosed(port int, deadline time.Time) bool {
+	addr := net.JoinHostPort("localhost", strconv.Itoa(port))
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err != nil {
+			return true
+		}
+		conn.Close()
+
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(daemonStopPollInterval)
+	}
+}
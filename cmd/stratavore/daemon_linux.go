@@ -0,0 +1,32 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processName returns the executable name of pid from /proc/<pid>/comm.
+func processName(pid int) (string, error) {
+	raw, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// daemonExecutable returns the binary pid is running, from /proc/<pid>/exe.
+// It fails if the binary has since been replaced or removed.
+func daemonExecutable(pid int) (string, error) {
+	path, err := os.Readlink("/proc/" + strconv.Itoa(pid) + "/exe")
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(path, " (deleted)") {
+		return "", fmt.Errorf("%s has been replaced since pid %d started", strings.TrimSuffix(path, " (deleted)"), pid)
+	}
+	return path, nil
+}
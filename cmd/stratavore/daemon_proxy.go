@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// sshProxyKeepAlive is how often the proxy checks that its SSH
+	// connection is still alive
+	sshProxyKeepAlive = 30 * time.Second
+
+	// sshProxyDialTimeout bounds connecting to the SSH server
+	sshProxyDialTimeout = 10 * time.Second
+)
+
+func init() {
+	daemonSocketProxyCmd.Flags().String("remote", "", "SSH destination running the daemon, as [user@]host[:port]")
+	daemonSocketProxyCmd.Flags().Int("local-port", 0, "Local port to listen on (default: the daemon's HTTP port, or gRPC port with --grpc)")
+	daemonSocketProxyCmd.Flags().Int("remote-port", 0, "Daemon port on the remote host (default: same as --local-port)")
+	daemonSocketProxyCmd.Flags().String("identity", "", "Private key file (default: ssh-agent, then ~/.ssh/id_ed25519 and ~/.ssh/id_rsa)")
+	daemonSocketProxyCmd.MarkFlagRequired("remote")
+	daemonCmd.AddCommand(daemonSocketProxyCmd)
+}
+
+var daemonSocketProxyCmd = &cobra.Command{
+	Use:   "socket-proxy --remote [user@]host[:port]",
+	Short: "Forward a local port to a daemon on another host over SSH",
+	Long: `Listen on a local port and forward every connection over SSH to the daemon
+on a remote host, like 'ssh -L'. Other CLI invocations on this machine can
+then talk to the remote daemon as if it were local. All forwarded
+connections share one SSH connection, which is re-established if it drops.
+
+The daemon's HTTP port is forwarded, or its gRPC port with --grpc. Ports
+default to those in the local config. The remote host must be in
+~/.ssh/known_hosts; connect once with ssh to add it. Keys are taken from
+ssh-agent, then --identity or the default key files; passphrase-protected
+keys must be loaded into ssh-agent.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		remote, _ := cmd.Flags().GetString("remote")
+		localPort, _ := cmd.Flags().GetInt("local-port")
+		remotePort, _ := cmd.Flags().GetInt("remote-port")
+		identity, _ := cmd.Flags().GetString("identity")
+
+		if localPort == 0 {
+			localPort = defaultDaemonPort()
+		}
+		if remotePort == 0 {
+			remotePort = localPort
+		}
+
+		tunnel, err := newSSHTunnel(remote, identity)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer tunnel.Close()
+
+		// Connect up front so bad credentials fail here rather than on the
+		// first forwarded connection
+		if _, err := tunnel.connect(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			<-ctx.Done()
+			listener.Close()
+		}()
+		go tunnel.keepAlive(ctx)
+
+		remoteAddr := net.JoinHostPort("localhost", strconv.Itoa(remotePort))
+		fmt.Printf("✓ Forwarding 127.0.0.1:%d to %s on %s (Ctrl-C to stop)\n", localPort, remoteAddr, tunnel.addr)
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			go tunnel.forward(conn, remoteAddr)
+		}
+	},
+}
+
+// defaultDaemonPort is the local daemon port the CLI would use in the
+// current --grpc mode
+func defaultDaemonPort() int {
+	cfg, _ := config.LoadConfig()
+	if grpc {
+		if cfg != nil && cfg.Daemon.Port_GRPC != 0 {
+			return cfg.Daemon.Port_GRPC
+		}
+		return 50051
+	}
+	if cfg != nil && cfg.Daemon.Port_HTTP != 0 {
+		return cfg.Daemon.Port_HTTP
+	}
+	return 50049
+}
+
+// sshTunnel multiplexes forwarded connections over one SSH connection,
+// reconnecting when it drops
+type sshTunnel struct {
+	addr   string
+	config *ssh.ClientConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// newSSHTunnel prepares a tunnel to remote, given as [user@]host[:port].
+// It does not connect yet.
+func newSSHTunnel(remote, identity string) (*sshTunnel, error) {
+	user, hostPort, ok := strings.Cut(remote, "@")
+	if !ok {
+		hostPort = user
+		user = os.Getenv("USER")
+		if user == "" {
+			user = os.Getenv("USERNAME")
+		}
+	}
+	if hostPort == "" || user == "" {
+		return nil, fmt.Errorf("invalid --remote %q, want [user@]host[:port]", remote)
+	}
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		hostPort = net.JoinHostPort(hostPort, "22")
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	hostKeys, err := knownhosts.New(filepath.Join(homeDir, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("load known hosts: %w", err)
+	}
+
+	auths, err := sshAuthMethods(homeDir, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sshTunnel{
+		addr: hostPort,
+		config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            auths,
+			HostKeyCallback: hostKeys,
+			Timeout:         sshProxyDialTimeout,
+		},
+	}, nil
+}
+
+// sshAuthMethods offers ssh-agent's keys, if an agent is running, then the
+// identity file, or the default key files when identity is ""
+func sshAuthMethods(homeDir, identity string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	keyFiles := []string{identity}
+	if identity == "" {
+		keyFiles = []string{
+			filepath.Join(homeDir, ".ssh", "id_ed25519"),
+			filepath.Join(homeDir, ".ssh", "id_rsa"),
+		}
+	}
+
+	var signers []ssh.Signer
+	for _, path := range keyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if identity != "" {
+				return nil, fmt.Errorf("read identity: %w", err)
+			}
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			if identity != "" {
+				return nil, fmt.Errorf("%s is passphrase-protected; load it into ssh-agent instead", path)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("no SSH keys found; start ssh-agent or pass --identity")
+	}
+	return methods, nil
+}
+
+// connect returns the shared SSH connection, dialling it if there is none
+func (t *sshTunnel) connect() (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	client, err := ssh.Dial("tcp", t.addr, t.config)
+	if err != nil {
+		return nil, fmt.Errorf("ssh %s: %w", t.addr, err)
+	}
+	t.client = client
+	return client, nil
+}
+
+// reset drops client, if it is still the shared connection, so the next
+// connect dials a new one
+func (t *sshTunnel) reset(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == client {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// Close closes the shared SSH connection
+func (t *sshTunnel) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// keepAlive pings the SSH server so a dead connection is noticed and
+// replaced before the next forwarded connection needs it
+func (t *sshTunnel) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(sshProxyKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.mu.Lock()
+			client := t.client
+			t.mu.Unlock()
+			if client == nil {
+				continue
+			}
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "SSH connection to %s lost: %v\n", t.addr, err)
+				t.reset(client)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// forward copies between local and remoteAddr on the SSH server's side
+// until either end closes. If the SSH connection itself fails, the channel
+// open is retried once on a new connection, in case the old one died since
+// the last keepalive. A channel the server rejects, e.g. because the daemon
+// is down, leaves the shared connection and its other forwards alone.
+func (t *sshTunnel) forward(local net.Conn, remoteAddr string) {
+	defer local.Close()
+
+	var remote net.Conn
+	for attempt := 0; attempt < 2; attempt++ {
+		client, err := t.connect()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return
+		}
+		remote, err = client.Dial("tcp", remoteAddr)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Error: forward to %s: %v\n", remoteAddr, err)
+
+		var rejected *ssh.OpenChannelError
+		if errors.As(err, &rejected) {
+			return
+		}
+		t.reset(client)
+	}
+	if remote == nil {
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,30 @@
+//go:build unix && !linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// processName returns the executable name of pid via `ps`.
+func processName(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err != nil {
+		return "", fmt.Errorf("ps failed: %w", err)
+	}
+	return filepath.Base(strings.TrimSpace(string(out))), nil
+}
+
+// daemonExecutable returns the stratavored binary on PATH, since ps doesn't
+// reliably report a process's full path on every platform
+func daemonExecutable(_ int) (string, error) {
+	path, err := exec.LookPath(daemonProcessName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(path)
+}
@@ -0,0 +1,157 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// runDaemonStop sends SIGTERM to the daemon named by the PID file and waits
+// for it to exit, escalating to SIGKILL with --force
+func runDaemonStop(cmd *cobra.Command) {
+	timeoutSecs, _ := cmd.Flags().GetInt("timeout")
+	force, _ := cmd.Flags().GetBool("force")
+	waitForPort, _ := cmd.Flags().GetBool("wait-for-port")
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	pidFile := cfg.Daemon.PIDFile()
+	pid, err := readPIDFile(pidFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	err = terminateDaemon(pid, pidFile)
+	if errors.Is(err, errDaemonNotRunning) {
+		fmt.Printf("Daemon is not running (stale pid file %s)\n", pidFile)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Sent SIGTERM to daemon (pid %d)\n", pid)
+
+	deadline := time.Now().Add(time.Duration(timeoutSecs) * time.Second)
+	if !waitForExit(pid, deadline) {
+		if !force {
+			fmt.Fprintf(os.Stderr, "Error: daemon (pid %d) did not stop within %ds; use --force to kill it\n", pid, timeoutSecs)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Daemon did not stop within %ds, sending SIGKILL\n", timeoutSecs)
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+			fmt.Fprintf(os.Stderr, "Error: kill daemon (pid %d): %v\n", pid, err)
+			os.Exit(1)
+		}
+		if !waitForExit(pid, time.Now().Add(daemonKillGrace)) {
+			fmt.Fprintf(os.Stderr, "Error: daemon (pid %d) still running after SIGKILL\n", pid)
+			os.Exit(1)
+		}
+	}
+
+	if waitForPort {
+		httpPort := cfg.Daemon.Port_HTTP
+		if httpPort == 0 {
+			httpPort = defaultHTTPPort
+		}
+		// The port may be held briefly after exit; allow at least the kill grace
+		portDeadline := deadline
+		if minDeadline := time.Now().Add(daemonKillGrace); portDeadline.Before(minDeadline) {
+			portDeadline = minDeadline
+		}
+		if !waitForPortClosed(httpPort, portDeadline) {
+			fmt.Fprintf(os.Stderr, "Error: HTTP port %d is still accepting connections\n", httpPort)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("✓ Daemon stopped (pid %d)\n", pid)
+}
+
+// processAlive reports whether pid exists. EPERM means it exists but belongs
+// to another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// waitForExit polls until pid exits or deadline passes, printing progress
+// once a second. It reports whether the process exited.
+func waitForExit(pid int, deadline time.Time) bool {
+	start := time.Now()
+	lastReport := 0
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		if elapsed := int(time.Since(start).Seconds()); elapsed > lastReport {
+			lastReport = elapsed
+			fmt.Printf("Waiting for daemon to stop... %ds\n", elapsed)
+		}
+		time.Sleep(daemonStopPollInterval)
+	}
+	return true
+}
+
+// terminateDaemon sends SIGTERM to pid, read from pidFile, after checking
+// that it is the daemon, so a stale PID file never signals an unrelated
+// process that reused the PID. It returns errDaemonNotRunning if pid has
+// exited.
+func terminateDaemon(pid int, pidFile string) error {
+	if !processAlive(pid) {
+		return errDaemonNotRunning
+	}
+
+	name, err := processName(pid)
+	if err != nil {
+		return fmt.Errorf("inspect pid %d: %w", pid, err)
+	}
+	if name != daemonProcessName {
+		return fmt.Errorf("pid %d is %q, not %s; is %s stale?", pid, name, daemonProcessName, pidFile)
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("signal daemon (pid %d): %w", pid, err)
+	}
+	return nil
+}
+
+// localDaemon returns the PID and binary of the daemon named by pidFile,
+// checking that it is running on this host. It returns errDaemonNotRunning
+// if the PID file is stale.
+func localDaemon(pidFile string) (int, string, error) {
+	pid, err := readPIDFile(pidFile)
+	if err != nil {
+		return 0, "", err
+	}
+	if !processAlive(pid) {
+		return 0, "", errDaemonNotRunning
+	}
+
+	name, err := processName(pid)
+	if err != nil {
+		return 0, "", fmt.Errorf("inspect pid %d: %w", pid, err)
+	}
+	if name != daemonProcessName {
+		return 0, "", fmt.Errorf("pid %d is %q, not %s; is %s stale?", pid, name, daemonProcessName, pidFile)
+	}
+
+	binaryPath, err := daemonExecutable(pid)
+	if err != nil {
+		return 0, "", fmt.Errorf("locate daemon binary: %w", err)
+	}
+	return pid, binaryPath, nil
+}
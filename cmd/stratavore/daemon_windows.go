@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// runDaemonStop is not supported on Windows, which has no SIGTERM for the
+// daemon to shut down on
+func runDaemonStop(_ *cobra.Command) {
+	fmt.Fprintln(os.Stderr, "Error: daemon stop is not supported on Windows; stop stratavored from its service manager")
+	os.Exit(1)
+}
+
+// terminateDaemon is not supported on Windows; the daemon has to be
+// restarted from its service manager
+func terminateDaemon(_ int, _ string) error {
+	return errors.New("signalling the daemon is not supported on Windows")
+}
+
+// localDaemon is not supported on Windows, where the daemon's process can't
+// be checked against the PID file
+func localDaemon(_ string) (int, string, error) {
+	return 0, "", errors.New("upgrading the daemon is not supported on Windows")
+}
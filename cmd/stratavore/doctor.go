@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/meridian-lex/stratavore/internal/storage"
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// doctorTimeout bounds each doctor check
+const doctorTimeout = 5 * time.Second
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check configuration, database and daemon connectivity",
+	Long: `Run a series of checks against the local configuration, the PostgreSQL
+database and the daemon, and suggest a fix for each one that fails.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			printCheck(false, "Configuration", err.Error(), "Run 'stratavore config init' to write a config file")
+			os.Exit(1)
+		}
+		printCheck(true, "Configuration", "loaded", "")
+
+		ok := checkDatabase(&cfg.Database.PostgreSQL)
+		ok = checkDaemon() && ok
+
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+// checkDatabase connects to PostgreSQL and explains any failure
+func checkDatabase(pg *config.PostgreSQLConfig) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	target := fmt.Sprintf("%s@%s:%d/%s", pg.User, pg.Host, pg.Port, pg.Database)
+
+	db, err := storage.NewPostgresClient(ctx, pg.GetConnectionString(), 1, 0, nil)
+	if err != nil {
+		printCheck(false, "Database", err.Error(), databaseHint(err, pg))
+		return false
+	}
+	db.Close()
+
+	printCheck(true, "Database", target, "")
+	return true
+}
+
+// databaseHint suggests a fix for a NewPostgresClient error
+func databaseHint(err error, pg *config.PostgreSQLConfig) string {
+	switch {
+	case errors.Is(err, storage.ErrInvalidConnString):
+		return "Check database.postgresql host, port, user and database in stratavore.yaml"
+	case errors.Is(err, storage.ErrHostNotFound):
+		return fmt.Sprintf("Host %q does not resolve; check database.postgresql.host", pg.Host)
+	case errors.Is(err, storage.ErrConnectionRefused):
+		return fmt.Sprintf("Nothing is listening on %s:%d; is PostgreSQL running?", pg.Host, pg.Port)
+	case errors.Is(err, storage.ErrAuthFailed):
+		return fmt.Sprintf("Wrong password for user %q; check database.postgresql.password", pg.User)
+	case errors.Is(err, storage.ErrDatabaseNotFound):
+		return fmt.Sprintf("Create it with: createdb -h %s -p %d -U %s %s", pg.Host, pg.Port, pg.User, pg.Database)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "Connection timed out; check firewalls between here and the database"
+	}
+	return ""
+}
+
+// checkDaemon pings the daemon's HTTP API
+func checkDaemon() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	if err := getAPIClient().Ping(ctx); err != nil {
+		printCheck(false, "Daemon", err.Error(), "Is stratavored running? Start it with: stratavored")
+		return false
+	}
+
+	printCheck(true, "Daemon", "reachable", "")
+	return true
+}
+
+func printCheck(ok bool, name, detail, hint string) {
+	mark := "✓"
+	if !ok {
+		mark = "✗"
+	}
+	fmt.Printf("%s %-14s %s\n", mark, name, detail)
+	if hint != "" {
+		fmt.Printf("  → %s\n", hint)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	drainCmd.Flags().Duration("timeout", 60*time.Second, "How long runners get to exit before they are killed")
+	drainCmd.ValidArgsFunction = completeProjectNames
+	rootCmd.AddCommand(drainCmd)
+}
+
+var drainCmd = &cobra.Command{
+	Use:   "drain <project>",
+	Short: "Stop all of a project's runners and archive it",
+	Long: `Stop a project's runners gracefully, then archive the project.
+New launches for the project are refused while it drains. Each runner is
+sent SIGTERM, and any still running after --timeout are killed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName := args[0]
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		fmt.Printf("Draining project '%s' (timeout %s)...\n", projectName, timeout)
+
+		resp, err := apiClient.DrainProject(ctx, projectName, timeout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			if resp.Graceful+resp.Forced > 0 {
+				fmt.Fprintf(os.Stderr, "Runners stopped: %d graceful, %d forced\n", resp.Graceful, resp.Forced)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Project '%s' drained and archived\n", projectName)
+		fmt.Printf("  Graceful: %d\n", resp.Graceful)
+		fmt.Printf("  Forced:   %d\n", resp.Forced)
+	},
+}
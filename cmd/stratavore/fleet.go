@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// fleetNodeTimeout bounds each node's requests so one unreachable daemon
+// doesn't hold up the whole fleet view
+const fleetNodeTimeout = 5 * time.Second
+
+// statusNodeOffline replaces the status of runners whose daemon can't be
+// reached
+const statusNodeOffline = "NODE_OFFLINE"
+
+func init() {
+	fleetCmd.AddCommand(fleetRunnersCmd)
+	fleetCmd.AddCommand(fleetStatusCmd)
+	rootCmd.AddCommand(fleetCmd)
+}
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "View every daemon node sharing the database",
+}
+
+var fleetRunnersCmd = &cobra.Command{
+	Use:   "runners",
+	Short: "List active runners across all daemon nodes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		results := queryFleet(fleetNodes())
+		runners := fleetRunners(results)
+
+		if len(runners) == 0 {
+			fmt.Println("No active runners")
+			return
+		}
+
+		fmt.Printf("Active Runners (%d):\n\n", len(runners))
+		fmt.Println("NODE                 ID        PROJECT              STATUS        UPTIME     CPU%   MEM(MB)")
+		fmt.Println("─────────────────────────────────────────────────────────────────────────────────────────────")
+
+		for _, r := range runners {
+			startTime, _ := api.ParseTime(r.StartedAt)
+			uptime := formatDuration(time.Since(startTime))
+
+			fmt.Printf("%-20s %-8s  %-20s %-13s %-10s %5.1f  %7d\n",
+				truncate(valueOrDash(r.NodeID), 20),
+				r.ID[:8],
+				truncate(r.ProjectName, 20),
+				r.Status,
+				uptime,
+				r.CPUPercent,
+				r.MemoryMB)
+		}
+	},
+}
+
+var fleetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show runners, token usage and health for each daemon node",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		results := queryFleet(fleetNodes())
+		runners := fleetRunners(results)
+
+		runnerCount := make(map[string]int)
+		tokens := make(map[string]int64)
+		var totalTokens int64
+		for _, r := range runners {
+			runnerCount[r.NodeID]++
+			tokens[r.NodeID] += r.TokensUsed
+			totalTokens += r.TokensUsed
+		}
+
+		fmt.Printf("Fleet (%d nodes):\n\n", len(results))
+		fmt.Println("NODE                     HOST                 VERSION    HEALTH     RUNNERS  TOKENS")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────────")
+
+		reachable := 0
+		listedRunners := 0
+		var listedTokens int64
+		for _, res := range results {
+			health := "offline"
+			version := res.Node.Version
+			if res.Err == nil {
+				reachable++
+				health = "unhealthy"
+				if res.Status.Daemon != nil {
+					version = res.Status.Daemon.Version
+					if res.Status.Daemon.Healthy {
+						health = "healthy"
+					}
+				}
+			}
+
+			id := res.Node.NodeID
+			fmt.Printf("%-24s %-20s %-10s %-10s %-8d %s\n",
+				truncate(id, 24),
+				truncate(res.Node.Hostname, 20),
+				valueOrDash(version),
+				health,
+				runnerCount[id],
+				formatNumber(tokens[id]))
+
+			listedRunners += runnerCount[id]
+			listedTokens += tokens[id]
+		}
+
+		// Runners with no recorded node, or a node no longer registered
+		if n := len(runners) - listedRunners; n > 0 {
+			fmt.Printf("%-24s %-20s %-10s %-10s %-8d %s\n",
+				"(unassigned)", "-", "-", "-", n, formatNumber(totalTokens-listedTokens))
+		}
+
+		fmt.Println()
+		fmt.Printf("Nodes:   %d reachable, %d offline\n", reachable, len(results)-reachable)
+		fmt.Printf("Runners: %d\n", len(runners))
+		fmt.Printf("Tokens:  %s\n", formatNumber(totalTokens))
+	},
+}
+
+// fleetNodeResult is what one daemon node reported, or why it couldn't
+type fleetNodeResult struct {
+	Node    *api.DaemonNode
+	Runners []*api.Runner
+	Status  *api.GetStatusResponse
+	Err     error
+}
+
+// fleetNodes lists the daemon nodes through the local daemon, caching the
+// list so the fleet can still be reached while the local daemon is down
+func fleetNodes() []*api.DaemonNode {
+	ctx, cancel := context.WithTimeout(context.Background(), fleetNodeTimeout)
+	defer cancel()
+
+	resp, err := getAPIClient().ListNodes(ctx)
+	if err == nil && resp.Error != "" {
+		err = fmt.Errorf("%s", resp.Error)
+	}
+	if err == nil {
+		if err := saveFleetNodes(resp.Nodes); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache node list: %v\n", err)
+		}
+		return resp.Nodes
+	}
+
+	nodes, cacheErr := loadFleetNodes()
+	if cacheErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: local daemon unavailable (%v); using cached node list\n", err)
+	return nodes
+}
+
+// queryFleet fetches runners and status from every node concurrently
+func queryFleet(nodes []*api.DaemonNode) []*fleetNodeResult {
+	token := loadToken()
+	results := make([]*fleetNodeResult, len(nodes))
+
+	var g errgroup.Group
+	for i, node := range nodes {
+		results[i] = &fleetNodeResult{Node: node}
+		g.Go(func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), fleetNodeTimeout)
+			defer cancel()
+
+			c := client.NewClient(node.Hostname, int(node.HTTPPort), 1)
+			if token != "" {
+				c.SetToken(token)
+			}
+
+			// An unreachable node is reported in its result, not as an
+			// error, so the other nodes are still listed
+			res := results[i]
+			runners, err := c.ListRunners(ctx, "")
+			if err == nil && runners.Error != "" {
+				err = fmt.Errorf("%s", runners.Error)
+			}
+			if err != nil {
+				res.Err = err
+				return nil
+			}
+			res.Runners = runners.Runners
+
+			status, err := c.GetStatus(ctx)
+			if err == nil && status.Error != "" {
+				err = fmt.Errorf("%s", status.Error)
+			}
+			if err != nil {
+				res.Err = err
+				return nil
+			}
+			res.Status = status
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results
+}
+
+// fleetRunners merges the nodes' runner lists. Every node reads the shared
+// database, so each runner is listed once: by its own node when that node
+// answered, otherwise by any node, with status NODE_OFFLINE if its node
+// couldn't be reached.
+func fleetRunners(results []*fleetNodeResult) []*api.Runner {
+	reachable := make(map[string]bool)
+	offline := make(map[string]bool)
+	for _, res := range results {
+		if res.Err == nil {
+			reachable[res.Node.NodeID] = true
+		} else {
+			offline[res.Node.NodeID] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var runners []*api.Runner
+	for _, res := range results {
+		for _, r := range res.Runners {
+			if seen[r.ID] {
+				continue
+			}
+			if r.NodeID != res.Node.NodeID && reachable[r.NodeID] {
+				continue // listed by its own node
+			}
+			seen[r.ID] = true
+
+			if offline[r.NodeID] {
+				r.Status = statusNodeOffline
+			}
+			runners = append(runners, r)
+		}
+	}
+
+	sort.Slice(runners, func(i, j int) bool {
+		if runners[i].NodeID != runners[j].NodeID {
+			return runners[i].NodeID < runners[j].NodeID
+		}
+		return runners[i].StartedAt < runners[j].StartedAt
+	})
+
+	return runners
+}
+
+func fleetNodesPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "stratavore", "fleet_nodes.json")
+}
+
+func saveFleetNodes(nodes []*api.DaemonNode) error {
+	path := fleetNodesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func loadFleetNodes() ([]*api.DaemonNode, error) {
+	data, err := os.ReadFile(fleetNodesPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*api.DaemonNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
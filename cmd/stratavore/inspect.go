@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	inspectCmd.ValidArgsFunction = completeRunnerIDs
+	rootCmd.AddCommand(inspectCmd)
+}
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <runner-id>",
+	Short: "Show runner details and lifecycle timeline",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		runnerID := args[0]
+
+		runnerResp, err := apiClient.GetRunner(ctx, runnerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if runnerResp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", runnerResp.Error)
+			os.Exit(1)
+		}
+
+		eventsResp, err := apiClient.GetRunnerEvents(ctx, runnerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if eventsResp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", eventsResp.Error)
+			os.Exit(1)
+		}
+
+		restartsResp, err := apiClient.GetRunnerRestarts(ctx, runnerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if restartsResp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", restartsResp.Error)
+			os.Exit(1)
+		}
+
+		r := runnerResp.Runner
+		startTime, _ := api.ParseTime(r.StartedAt)
+
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Printf("  RUNNER %s\n", r.ID)
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Println()
+		fmt.Printf("Project:    %s\n", r.ProjectName)
+		fmt.Printf("Status:     %s\n", r.Status)
+		fmt.Printf("Runtime:    %s (%s)\n", r.RuntimeType, r.RuntimeID)
+		fmt.Printf("Started:    %s (%s ago)\n", r.StartedAt, formatDuration(time.Since(startTime)))
+		if r.LastHeartbeat != "" {
+			fmt.Printf("Heartbeat:  %s\n", r.LastHeartbeat)
+		}
+		if r.TerminatedAt != "" {
+			fmt.Printf("Terminated: %s (exit %d)\n", r.TerminatedAt, r.ExitCode)
+		}
+		if r.KillReason != "" {
+			fmt.Printf("Killed:     %s\n", r.KillReason)
+		}
+		if r.MaxRuntimeSeconds > 0 {
+			fmt.Printf("Max run:    %s\n", formatDuration(time.Duration(r.MaxRuntimeSeconds)*time.Second))
+		}
+		fmt.Printf("CPU:        %.1f%%\n", r.CPUPercent)
+		fmt.Printf("Memory:     %d MB\n", r.MemoryMB)
+		fmt.Printf("Tokens:     %s\n", formatNumber(r.TokensUsed))
+		fmt.Printf("Restarts:   %d/%d\n", r.RestartAttempts, r.MaxRestartAttempts)
+		fmt.Println()
+
+		if len(restartsResp.Restarts) > 0 {
+			printRunnerRestarts(restartsResp.Restarts)
+			fmt.Println()
+		}
+
+		if len(eventsResp.Events) == 0 {
+			fmt.Println("No lifecycle events recorded")
+			return
+		}
+
+		fmt.Printf("Timeline (%d events):\n\n", len(eventsResp.Events))
+		for _, e := range eventsResp.Events {
+			fmt.Printf("  %s  %-24s %s\n", e.Timestamp, e.EventType, formatEventData(e.Data))
+		}
+	},
+}
+
+// printRunnerRestarts prints a runner's restart history, showing how long
+// each restart came after the previous exit
+func printRunnerRestarts(restarts []*api.RunnerRestart) {
+	fmt.Printf("Restart History (%d):\n\n", len(restarts))
+	fmt.Println("  ATTEMPT  RESTARTED          PREV EXIT  DELAY")
+	fmt.Println("  ─────────────────────────────────────────────────")
+
+	for _, rs := range restarts {
+		exitCode := "-"
+		if rs.PreviousExitCode != nil {
+			exitCode = fmt.Sprintf("%d", *rs.PreviousExitCode)
+		}
+		fmt.Printf("  %-7d  %-16s   %-9s  %s\n",
+			rs.Attempt,
+			formatSessionTime(rs.RestartedAt),
+			exitCode,
+			formatDuration(time.Duration(rs.DelaySeconds*float64(time.Second))))
+	}
+}
+
+// formatEventData renders event data as sorted key=value pairs
+func formatEventData(data map[string]interface{}) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, data[k]))
+	}
+	return strings.Join(parts, " ")
+}
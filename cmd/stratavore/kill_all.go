@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// killAll backs 'stratavore kill --all', stopping every active runner or
+// only those of --project after confirmation
+func killAll(cmd *cobra.Command) {
+	apiClient := getAPIClient()
+	ctx := context.Background()
+
+	projectName, _ := cmd.Flags().GetString("project")
+	force, _ := cmd.Flags().GetBool("force")
+
+	runners, err := apiClient.ListRunners(ctx, projectName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if runners.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", runners.Error)
+		os.Exit(1)
+	}
+
+	if len(runners.Runners) == 0 {
+		fmt.Println("No active runners")
+		return
+	}
+
+	if !force && !confirm(fmt.Sprintf("Kill %d runners?", len(runners.Runners))) {
+		fmt.Println("Aborted")
+		return
+	}
+
+	resp, err := apiClient.StopAllRunners(ctx, projectName, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Stopped %d runners\n", len(resp.Stopped))
+	if len(resp.Failed) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "✗ Failed to stop %d runners:\n", len(resp.Failed))
+	ids := make([]string, 0, len(resp.Failed))
+	for id := range resp.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", id, resp.Failed[id])
+	}
+	os.Exit(1)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+}
+
+// storedToken is the on-disk form of ~/.config/stratavore/token.json
+type storedToken struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authenticate with the daemon using the device flow",
+	Long: `Authenticate with the daemon without a local browser.
+
+A one-time code is printed along with a URL. Open the URL in any browser
+that can reach the daemon (an SSH port forward works), enter the code and
+approve. The issued token is saved to ~/.config/stratavore/token.json and
+sent with every subsequent request.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		start, err := apiClient.StartDeviceAuth(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if start.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", start.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Open %s\n", start.VerificationURI)
+		fmt.Printf("and enter the code: %s\n\n", start.UserCode)
+		fmt.Println("Waiting for approval...")
+
+		interval := time.Duration(start.Interval) * time.Second
+		deadline := time.Now().Add(time.Duration(start.ExpiresIn) * time.Second)
+
+		for time.Now().Before(deadline) {
+			time.Sleep(interval)
+
+			poll, err := apiClient.PollDeviceAuth(ctx, start.DeviceCode)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			switch poll.Error {
+			case "":
+				if err := saveToken(&storedToken{Token: poll.Token, ExpiresAt: poll.ExpiresAt}); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("✓ Logged in (token expires %s)\n", formatSessionTime(poll.ExpiresAt))
+				return
+			case "authorization_pending":
+				continue
+			case "access_denied":
+				fmt.Fprintln(os.Stderr, "Error: login was denied")
+				os.Exit(1)
+			default:
+				fmt.Fprintf(os.Stderr, "Error: %s\n", poll.Error)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Fprintln(os.Stderr, "Error: login code expired, run 'stratavore login' again")
+		os.Exit(1)
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API token",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.Remove(tokenPath()); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Logged out")
+	},
+}
+
+func tokenPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "stratavore", "token.json")
+}
+
+// loadToken returns the stored token, or "" if there is none or it expired
+func loadToken() string {
+	data, err := os.ReadFile(tokenPath())
+	if err != nil {
+		return ""
+	}
+
+	var tok storedToken
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return ""
+	}
+
+	if expiresAt, err := api.ParseTime(tok.ExpiresAt); err == nil && !expiresAt.IsZero() && time.Now().After(expiresAt) {
+		return ""
+	}
+
+	return tok.Token
+}
+
+func saveToken(tok *storedToken) error {
+	path := tokenPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	logsCmd.Flags().IntP("tail", "n", 100, "Number of buffered lines to show (0 = all)")
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new output until the runner exits")
+	logsCmd.Flags().Bool("aggregate", false, "Treat the argument as a project and interleave logs from all its active runners")
+	logsCmd.ValidArgsFunction = completeLogsArgs
+	rootCmd.AddCommand(logsCmd)
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <runner-id>",
+	Short: "Show output from a running runner",
+	Long: `Show output from a running runner.
+
+With --aggregate, the argument is a project name instead: logs from all of
+the project's active runners are interleaved by timestamp, each line
+prefixed with the runner's short ID in its own colour. Runners started
+after the command begins are not included.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tail, _ := cmd.Flags().GetInt("tail")
+		follow, _ := cmd.Flags().GetBool("follow")
+		aggregate, _ := cmd.Flags().GetBool("aggregate")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		go func() {
+			<-sigCh
+			cancel()
+		}()
+
+		var err error
+		if aggregate {
+			err = aggregateLogs(ctx, args[0], tail, follow)
+		} else {
+			err = getAPIClient().StreamLogs(ctx, args[0], tail, follow, printLogLine)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// completeLogsArgs completes project names with --aggregate and runner IDs
+// otherwise
+func completeLogsArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if aggregate, _ := cmd.Flags().GetBool("aggregate"); aggregate {
+		return completeProjectNames(cmd, args, toComplete)
+	}
+	return completeRunnerIDs(cmd, args, toComplete)
+}
+
+// printLogLine writes a runner's line to the matching local stream
+func printLogLine(line *api.LogLine) {
+	if line.Stream == "stderr" {
+		fmt.Fprintln(os.Stderr, line.Content)
+		return
+	}
+	fmt.Println(line.Content)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+)
+
+const (
+	// aggregateStreamBuffer is how many lines one runner's stream may queue
+	// before it stalls, so a chatty runner can't starve the others
+	aggregateStreamBuffer = 100
+
+	// aggregateReorderWindow is how long a followed line is held back so
+	// lines arriving slightly out of order from different runners can be
+	// printed by timestamp
+	aggregateReorderWindow = 250 * time.Millisecond
+)
+
+// runnerColours are cycled through to tell runners apart
+var runnerColours = []string{
+	"\033[36m", // cyan
+	"\033[33m", // yellow
+	"\033[35m", // magenta
+	"\033[32m", // green
+	"\033[34m", // blue
+	"\033[91m", // bright red
+	"\033[96m", // bright cyan
+	"\033[93m", // bright yellow
+}
+
+// aggregateLine is one log line tagged with the runner it came from
+type aggregateLine struct {
+	runnerID  string
+	colour    string
+	line      *api.LogLine
+	timestamp time.Time
+	received  time.Time
+}
+
+// lineHeap orders pending lines by timestamp, oldest first
+type lineHeap []*aggregateLine
+
+func (h lineHeap) Len() int           { return len(h) }
+func (h lineHeap) Less(i, j int) bool { return h[i].timestamp.Before(h[j].timestamp) }
+func (h lineHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *lineHeap) Push(x any)        { *h = append(*h, x.(*aggregateLine)) }
+func (h *lineHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// aggregateLogs streams logs from every active runner of projectName and
+// prints them interleaved by timestamp, each prefixed with the runner's
+// short ID. Runners started after the command begins are not picked up.
+func aggregateLogs(ctx context.Context, projectName string, tail int, follow bool) error {
+	apiClient := getAPIClient()
+
+	resp, err := apiClient.ListRunners(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	if len(resp.Runners) == 0 {
+		return fmt.Errorf("project %s has no active runners", projectName)
+	}
+
+	merged := make(chan *aggregateLine)
+	var wg sync.WaitGroup
+
+	for i, runner := range resp.Runners {
+		id := shortID(runner.ID)
+		colour := runnerColours[i%len(runnerColours)]
+		buffered := make(chan *aggregateLine, aggregateStreamBuffer)
+
+		wg.Add(2)
+		go func(runnerID string) {
+			defer wg.Done()
+			defer close(buffered)
+			err := apiClient.StreamLogs(ctx, runnerID, tail, follow, func(line *api.LogLine) {
+				ts, err := time.Parse(time.RFC3339Nano, line.Timestamp)
+				if err != nil {
+					ts = time.Now()
+				}
+				select {
+				case buffered <- &aggregateLine{runnerID: id, colour: colour, line: line, timestamp: ts, received: time.Now()}:
+				case <-ctx.Done():
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "%s[%s]\033[0m log stream ended: %v\n", colour, id, err)
+			}
+		}(runner.ID)
+
+		go func() {
+			defer wg.Done()
+			for l := range buffered {
+				select {
+				case merged <- l:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	pending := &lineHeap{}
+	ticker := time.NewTicker(aggregateReorderWindow / 2)
+	defer ticker.Stop()
+
+	// flush prints pending lines received before cutoff; a zero cutoff
+	// prints everything
+	flush := func(cutoff time.Time) {
+		for pending.Len() > 0 {
+			next := (*pending)[0]
+			if !cutoff.IsZero() && next.received.After(cutoff) {
+				return
+			}
+			heap.Pop(pending)
+			printAggregateLine(next)
+		}
+	}
+
+	for {
+		select {
+		case l, ok := <-merged:
+			if !ok {
+				flush(time.Time{})
+				return nil
+			}
+			heap.Push(pending, l)
+		case <-ticker.C:
+			// Without --follow the tails are finite, so wait and merge them
+			// fully instead of printing in windows
+			if follow {
+				flush(time.Now().Add(-aggregateReorderWindow))
+			}
+		case <-ctx.Done():
+			flush(time.Time{})
+			return nil
+		}
+	}
+}
+
+// printAggregateLine writes a line with its timestamp and coloured runner
+// prefix to the matching local stream
+func printAggregateLine(l *aggregateLine) {
+	out := os.Stdout
+	if l.line.Stream == "stderr" {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "%s %s[%s]\033[0m %s\n",
+		l.timestamp.Local().Format("15:04:05.000"), l.colour, l.runnerID, l.line.Content)
+}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/meridian-lex/stratavore/internal/auth"
 	"github.com/meridian-lex/stratavore/internal/storage"
 	"github.com/meridian-lex/stratavore/internal/ui"
 	"github.com/meridian-lex/stratavore/pkg/api"
@@ -19,17 +20,38 @@ import (
 func getAPIClient() *client.Client {
 	cfg, _ := config.LoadConfig()
 
+	httpPort := cfg.Daemon.Port_HTTP
+	if httpPort == 0 {
+		httpPort = 50049 // fallback default
+	}
+	c := client.NewClient("localhost", httpPort, 1)
+
+	// Calls the gRPC service doesn't serve still go over HTTP
 	if grpc {
-		// gRPC client
-		return client.NewClient("localhost", cfg.Daemon.Port_GRPC, 1)
-	} else {
-		// HTTP client
-		httpPort := cfg.Daemon.Port_HTTP
-		if httpPort == 0 {
-			httpPort = 50049 // fallback default
+		opts := client.GRPCOptions{AuthSecret: cfg.Security.AuthSecret}
+		if cfg.Security.EnableMTLS {
+			tlsConfig, err := auth.ClientTLSConfig(cfg.Security.CertFile, cfg.Security.KeyFile, cfg.Security.CAFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.TLS = tlsConfig
 		}
-		return client.NewClient("localhost", httpPort, 1)
+
+		g, err := client.NewGRPCClient("localhost", cfg.Daemon.Port_GRPC, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		c.UseGRPC(g)
+	}
+
+	// Token saved by 'stratavore login'
+	if token := loadToken(); token != "" {
+		c.SetToken(token)
 	}
+
+	return c
 }
 
 var (
@@ -60,8 +82,25 @@ func init() {
 
 	launchCmd.Flags().StringSliceP("flag", "f", nil, "Claude Code flags")
 	launchCmd.Flags().StringSliceP("capability", "c", nil, "Capabilities to enable")
+	launchCmd.Flags().IntSlice("cpu-affinity", nil, "CPU cores to pin the runner to, e.g. 0,1 (Linux only)")
+	launchCmd.Flags().String("max-runtime", "", "Stop the runner after this long, e.g. 2h (default: project quota)")
+
+	killCmd.Flags().BoolP("force", "f", false, "Force kill (SIGKILL); with --all, skip the confirmation prompt")
+	killCmd.Flags().Bool("all", false, "Stop every active runner")
+	killCmd.Flags().StringP("project", "p", "", "With --all, only stop this project's runners")
+	killCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
+	runnersCmd.Flags().StringSlice("sort", nil, "Sort by comma-separated keys: cpu, memory, tokens, uptime, project, status")
+	runnersCmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	runnersCmd.Flags().StringArray("filter", nil, "Only show runners matching key=value (status, project, runtime); repeatable")
+	runnersCmd.Flags().String("since", "", "Include ended runners started within this long ago, e.g. 2h or 7d")
+	runnersCmd.Flags().String("until", "", "Include ended runners started more than this long ago, e.g. 1d")
+	runnersCmd.Flags().String("status", "", "Include ended runners with this status, e.g. failed")
 
-	killCmd.Flags().BoolP("force", "f", false, "Force kill (SIGKILL)")
+	addProjectListFlags(projectsCmd)
+
+	watchCmd.Flags().String("runner", "", "Watch a single runner by ID")
+	watchCmd.RegisterFlagCompletionFunc("runner", completeRunnerIDs)
 
 	// Register all sub-commands (each added once)
 	rootCmd.AddCommand(newCmd)
@@ -114,7 +153,7 @@ func rootHandler(cmd *cobra.Command, args []string) {
 	db, err := storage.NewPostgresClient(
 		ctx,
 		cfg.Database.PostgreSQL.GetConnectionString(),
-		5, 1,
+		5, 1, nil,
 	)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
@@ -199,7 +238,14 @@ var newCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Project '%s' created at %s\n", resp.Project.Name, resp.Project.Path)
+		if resp.Created {
+			fmt.Printf("✓ Project '%s' created at %s\n", resp.Project.Name, resp.Project.Path)
+		} else {
+			fmt.Printf("✓ Project '%s' already exists, updated (path: %s)\n", resp.Project.Name, resp.Project.Path)
+		}
+		if err := writeProjectMarker(resp.Project.Path, resp.Project.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 	},
 }
 
@@ -220,6 +266,8 @@ var launchCmd = &cobra.Command{
 		projectName := args[0]
 		flags, _ := cmd.Flags().GetStringSlice("flag")
 		capabilities, _ := cmd.Flags().GetStringSlice("capability")
+		cpuAffinity, _ := cmd.Flags().GetIntSlice("cpu-affinity")
+		maxRuntime, _ := cmd.Flags().GetString("max-runtime")
 
 		req := &api.LaunchRunnerRequest{
 			ProjectName:      projectName,
@@ -229,6 +277,24 @@ var launchCmd = &cobra.Command{
 			ConversationMode: "new",
 			RuntimeType:      "process",
 		}
+		for _, cpu := range cpuAffinity {
+			req.CPUAffinity = append(req.CPUAffinity, int32(cpu))
+		}
+		if maxRuntime != "" {
+			d, err := parseAge(maxRuntime)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --max-runtime: %v\n", err)
+				os.Exit(1)
+			}
+			req.MaxRuntimeSeconds = int32(d.Seconds())
+		}
+
+		// With a preset, leave mode and runtime unset so the preset supplies them
+		if preset != "" {
+			req.PresetName = preset
+			req.ConversationMode = ""
+			req.RuntimeType = ""
+		}
 
 		fmt.Printf("🚀 Launching runner for project '%s'...\n", projectName)
 
@@ -279,6 +345,9 @@ var statusCmd = &cobra.Command{
 		fmt.Printf("Updated:   %s\n", resp.Daemon.LastHeartbeat)
 		fmt.Println()
 		fmt.Printf("Active Runners:  %d\n", resp.Metrics.ActiveRunners)
+		if resp.Metrics.QueuedLaunches > 0 {
+			fmt.Printf("Queued Launches: %d\n", resp.Metrics.QueuedLaunches)
+		}
 		fmt.Printf("Active Projects: %d\n", resp.Metrics.ActiveProjects)
 		fmt.Printf("Total Sessions:  %d\n", resp.Metrics.TotalSessions)
 		fmt.Printf("Tokens Used:     %d\n", resp.Metrics.TokensUsed)
@@ -286,10 +355,20 @@ var statusCmd = &cobra.Command{
 }
 
 var killCmd = &cobra.Command{
-	Use:   "kill <runner-id>",
+	Use:   "kill <runner-id> | --all [--project name]",
 	Short: "Stop a running runner",
-	Args:  cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			killAll(cmd)
+			return
+		}
+
 		apiClient := getAPIClient()
 		ctx := context.Background()
 
@@ -328,7 +407,29 @@ var runnersCmd = &cobra.Command{
 			projectName = args[0]
 		}
 
-		resp, err := apiClient.ListRunners(ctx, projectName)
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		status, _ := cmd.Flags().GetString("status")
+
+		// Any history flag switches from active runners to runner history
+		history := since != "" || until != "" || status != ""
+
+		var resp *api.ListRunnersResponse
+		var err error
+		if history {
+			req := &api.GetRunnerHistoryRequest{ProjectName: projectName, Status: status}
+			if req.Since, err = ageToTime(since); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if req.Until, err = ageToTime(until); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			resp, err = apiClient.GetRunnerHistory(ctx, req)
+		} else {
+			resp, err = apiClient.ListRunners(ctx, projectName)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -339,30 +440,59 @@ var runnersCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if len(resp.Runners) == 0 {
-			fmt.Println("No active runners")
-			return
+		sortKeys, _ := cmd.Flags().GetStringSlice("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+
+		runners, err := filterRunners(resp.Runners, filters)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 
-		fmt.Printf("Active Runners (%d):\n\n", resp.Total)
-		fmt.Println("ID        PROJECT              STATUS    UPTIME     CPU%   MEM(MB)")
-		fmt.Println("─────────────────────────────────────────────────────────────────────")
+		if err := sortRunners(runners, sortKeys, reverse); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 
-		for _, r := range resp.Runners {
-			startTime, _ := api.ParseTime(r.StartedAt)
-			uptime := formatDuration(time.Since(startTime))
+		if len(runners) == 0 {
+			if history {
+				fmt.Println("No runners found")
+			} else {
+				fmt.Println("No active runners")
+			}
+			return
+		}
 
-			fmt.Printf("%-8s  %-20s %-9s %-10s %5.1f  %7d\n",
-				r.ID[:8],
-				truncate(r.ProjectName, 20),
-				r.Status,
-				uptime,
-				r.CPUPercent,
-				r.MemoryMB)
+		if history {
+			printRunnerHistory(runners)
+			return
 		}
+
+		fmt.Printf("Active Runners (%d):\n\n", len(runners))
+		printActiveRunners(runners)
 	},
 }
 
+// printActiveRunners prints the runners table shown by 'runners'
+func printActiveRunners(runners []*api.Runner) {
+	fmt.Println("ID        PROJECT              STATUS    UPTIME     CPU%   MEM(MB)")
+	fmt.Println("─────────────────────────────────────────────────────────────────────")
+
+	for _, r := range runners {
+		startTime, _ := api.ParseTime(r.StartedAt)
+		uptime := formatDuration(time.Since(startTime))
+
+		fmt.Printf("%-8s  %-20s %-9s %-10s %5.1f  %7d\n",
+			r.ID[:8],
+			truncate(r.ProjectName, 20),
+			r.Status,
+			uptime,
+			r.CPUPercent,
+			r.MemoryMB)
+	}
+}
+
 var attachCmd = &cobra.Command{
 	Use:   "attach <runner-id>",
 	Short: "Attach to running instance",
@@ -377,40 +507,81 @@ var attachCmd = &cobra.Command{
 var projectsCmd = &cobra.Command{
 	Use:   "projects",
 	Short: "List all projects",
-	Run: func(cmd *cobra.Command, args []string) {
-		apiClient := getAPIClient()
-		ctx := context.Background()
+	Args:  cobra.NoArgs,
+	Run:   runProjectList,
+}
 
-		resp, err := apiClient.ListProjects(ctx, "")
-		if err != nil {
+// runProjectList backs both 'stratavore projects' and 'stratavore project list'
+func runProjectList(cmd *cobra.Command, args []string) {
+	apiClient := getAPIClient()
+	ctx := context.Background()
+
+	includeArchived, _ := cmd.Flags().GetBool("include-archived")
+	archived, _ := cmd.Flags().GetBool("archived")
+	sortKey, _ := cmd.Flags().GetString("sort")
+	filters, _ := cmd.Flags().GetStringArray("filter")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	status := ""
+	if archived {
+		status = "archived"
+	}
+
+	resp, err := apiClient.ListProjects(ctx, status, includeArchived)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+
+	projects, err := filterProjects(resp.Projects, filters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if sortKey != "" {
+		if err := sortProjects(projects, sortKey); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+	}
 
-		if resp.Error != "" {
-			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
-			os.Exit(1)
-		}
+	total := len(projects)
+	if limit > 0 && len(projects) > limit {
+		projects = projects[:limit]
+	}
 
-		if len(resp.Projects) == 0 {
-			fmt.Println("No projects found")
-			fmt.Println("Create one with: stratavore new <project-name>")
+	if len(projects) == 0 {
+		if len(resp.Projects) > 0 {
+			fmt.Println("No projects match the given filters")
 			return
 		}
+		fmt.Println("No projects found")
+		fmt.Println("Create one with: stratavore new <project-name>")
+		return
+	}
 
-		fmt.Printf("Projects (%d):\n\n", len(resp.Projects))
-		fmt.Println("NAME                 STATUS    RUNNERS  SESSIONS  TOKENS")
-		fmt.Println("──────────────────────────────────────────────────────────")
-
-		for _, p := range resp.Projects {
-			fmt.Printf("%-20s %-9s %2d       %4d      %s\n",
-				truncate(p.Name, 20),
-				p.Status,
-				p.ActiveRunners,
-				p.TotalSessions,
-				formatNumber(p.TotalTokens))
-		}
-	},
+	if len(projects) < total {
+		fmt.Printf("Projects (%d of %d):\n\n", len(projects), total)
+	} else {
+		fmt.Printf("Projects (%d):\n\n", len(projects))
+	}
+	fmt.Println("NAME                 STATUS    RUNNERS  SESSIONS  TOKENS")
+	fmt.Println("──────────────────────────────────────────────────────────")
+
+	for _, p := range projects {
+		fmt.Printf("%-20s %-9s %2d       %4d      %s\n",
+			truncate(p.Name, 20),
+			p.Status,
+			p.ActiveRunners,
+			p.TotalSessions,
+			formatNumber(p.TotalTokens))
+	}
 }
 
 // Helper functions
@@ -445,6 +616,14 @@ func truncate(s string, max int) string {
 	return s[:max-3] + "..."
 }
 
+// shortID returns the 8-character prefix used to show runner IDs
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 func formatNumber(n int64) string {
 	if n < 1000 {
 		return fmt.Sprintf("%d", n)
@@ -458,42 +637,41 @@ var watchCmd = &cobra.Command{
 	Use:   "watch [project]",
 	Short: "Live monitor of runners",
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg, _ := config.LoadConfig()
-		ctx := context.Background()
-		db, err := storage.NewPostgresClient(
-			ctx,
-			cfg.Database.PostgreSQL.GetConnectionString(),
-			5, 1,
-		)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Database error: %v\n", err)
+		if runnerID, _ := cmd.Flags().GetString("runner"); runnerID != "" {
+			watchRunner(runnerID)
 			return
 		}
-		defer db.Close()
-
-		monitor := ui.NewLiveMonitor(db, 2*time.Second)
-
-		// Setup signal handler
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, os.Interrupt)
-		go func() {
-			<-sigCh
-			cancel()
-		}()
 
+		project := ""
 		if len(args) > 0 {
-			// Watch specific project runners
-			monitor.DisplayRunners(ctx, args[0])
-		} else {
-			// Watch all projects
-			monitor.Display(ctx)
+			project = args[0]
+		}
+
+		monitor := ui.NewLiveMonitor(getAPIClient(), 2*time.Second)
+		if err := monitor.Run(context.Background(), project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 	},
 }
 
+// watchRunner polls the daemon every second for a single runner's metrics
+func watchRunner(runnerID string) {
+	monitor := ui.NewRunnerMonitor(getAPIClient(), runnerID, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	monitor.Display(ctx)
+}
+
 var completionCmd = &cobra.Command{
 	Use:   "completion [bash|zsh|fish|powershell]",
 	Short: "Generate shell completion scripts",
@@ -545,7 +723,7 @@ PowerShell:
 }
 
 var daemonCmd = &cobra.Command{
-	Use:   "daemon [start|stop|status]",
+	Use:   "daemon [start|stop|status|upgrade]",
 	Short: "Manage daemon",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -555,9 +733,11 @@ var daemonCmd = &cobra.Command{
 			fmt.Println("Starting daemon...")
 			fmt.Println("(Would start stratavored)")
 		case "stop":
-			fmt.Println("Stopping daemon...")
+			runDaemonStop(cmd)
 		case "status":
-			fmt.Println("Daemon status: Unknown")
+			runDaemonStatus()
+		case "upgrade":
+			runDaemonUpgrade()
 		default:
 			fmt.Printf("Unknown action: %s\n", action)
 		}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// metricsWatchInterval is how often 'metrics --watch' refreshes
+const metricsWatchInterval = 5 * time.Second
+
+// metricsFetchTimeout bounds a single scrape of the metrics endpoint
+const metricsFetchTimeout = 5 * time.Second
+
+func init() {
+	metricsCmd.Flags().BoolP("watch", "w", false, "Refresh every 5 seconds")
+	metricsCmd.Flags().String("filter", "", "Only show metrics whose name starts with this prefix, e.g. stratavore_runners")
+	metricsCmd.Flags().String("url", "", "Metrics endpoint (default: localhost on the configured Prometheus port)")
+	rootCmd.AddCommand(metricsCmd)
+}
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show the daemon's Prometheus metrics as a table",
+	Long: `Scrape the daemon's Prometheus endpoint and print each metric family
+with its type, help text, and current value per label set. Useful for
+checking on the daemon without running Prometheus.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		watch, _ := cmd.Flags().GetBool("watch")
+		filter, _ := cmd.Flags().GetString("filter")
+		url, _ := cmd.Flags().GetString("url")
+
+		if url == "" {
+			url = metricsURL()
+		}
+
+		if !watch {
+			if err := printMetrics(context.Background(), url, filter); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		ticker := time.NewTicker(metricsWatchInterval)
+		defer ticker.Stop()
+
+		fmt.Print("\033[2J\033[H")
+		for {
+			if err := printMetrics(ctx, url, filter); err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			fmt.Printf("\nRefreshing every %s, Ctrl+C to exit\n", metricsWatchInterval)
+
+			select {
+			case <-ticker.C:
+				// Clear to end of screen so shorter frames leave no residue
+				fmt.Print("\033[H\033[J")
+			case <-ctx.Done():
+				return
+			}
+		}
+	},
+}
+
+// metricsURL returns the local metrics endpoint from the daemon's
+// Prometheus settings
+func metricsURL() string {
+	cfg, _ := config.LoadConfig()
+
+	port, path := 9091, "/metrics"
+	if cfg != nil {
+		if cfg.Docker.Prometheus.Port != 0 {
+			port = cfg.Docker.Prometheus.Port
+		}
+		if cfg.Docker.Prometheus.Path != "" {
+			path = cfg.Docker.Prometheus.Path
+		}
+	}
+	return fmt.Sprintf("http://localhost:%d%s", port, path)
+}
+
+// printMetrics scrapes url and prints the families whose name starts with
+// filter
+func printMetrics(ctx context.Context, url, filter string) error {
+	ctx, cancel := context.WithTimeout(ctx, metricsFetchTimeout)
+	defer cancel()
+
+	body, err := httpGet(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	families, err := parseMetrics(body)
+	if err != nil {
+		return fmt.Errorf("parse metrics: %w", err)
+	}
+
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Printf("  METRICS: %s\n", url)
+	fmt.Printf("  %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Println("═══════════════════════════════════════════")
+
+	shown := 0
+	for _, f := range families {
+		if !strings.HasPrefix(f.Name, filter) {
+			continue
+		}
+		shown++
+
+		fmt.Println()
+		title := f.Name
+		if f.Type != "" {
+			title += " (" + f.Type + ")"
+		}
+		fmt.Println(title)
+		if f.Help != "" {
+			fmt.Printf("  %s\n", f.Help)
+		}
+		if len(f.Samples) == 0 {
+			fmt.Println("  (no samples)")
+			continue
+		}
+
+		width := 0
+		for _, s := range f.Samples {
+			width = max(width, len(s.label(f.Name)))
+		}
+		for _, s := range f.Samples {
+			fmt.Printf("  %-*s  %s\n", width, s.label(f.Name), s.Value)
+		}
+	}
+
+	if shown == 0 {
+		fmt.Println()
+		if filter != "" {
+			fmt.Printf("No metrics matching %q\n", filter)
+		} else {
+			fmt.Println("No metrics exported")
+		}
+	}
+
+	return nil
+}
+
+// metricFamily is a metric's HELP and TYPE metadata and its samples
+type metricFamily struct {
+	Name    string
+	Type    string
+	Help    string
+	Samples []metricSample
+}
+
+// metricSample is one line of the Prometheus text format
+type metricSample struct {
+	Name   string
+	Labels string // without braces; empty when unlabelled
+	Value  string
+}
+
+// label names the sample within its family: its labels, prefixed by its
+// suffix for summary and histogram parts such as _sum and _count
+func (s metricSample) label(family string) string {
+	l := strings.TrimPrefix(s.Name, family)
+	if s.Labels != "" {
+		l += "{" + s.Labels + "}"
+	}
+	return l
+}
+
+// familySuffixes are the sample name suffixes that belong to a summary or
+// histogram family rather than forming their own
+var familySuffixes = []string{"_sum", "_count", "_bucket"}
+
+// parseMetrics parses the Prometheus text exposition format into families
+// sorted by name, with samples sorted by label set. Timestamps are ignored.
+func parseMetrics(r io.Reader) ([]*metricFamily, error) {
+	byName := make(map[string]*metricFamily)
+	family := func(name string) *metricFamily {
+		f, ok := byName[name]
+		if !ok {
+			f = &metricFamily{Name: name}
+			byName[name] = f
+		}
+		return f
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "#"); ok {
+			fields := strings.SplitN(strings.TrimSpace(rest), " ", 3)
+			if len(fields) < 3 {
+				continue // plain comment
+			}
+			switch fields[0] {
+			case "HELP":
+				family(fields[1]).Help = unescapeHelp(fields[2])
+			case "TYPE":
+				family(fields[1]).Type = fields[2]
+			}
+			continue
+		}
+
+		s, err := parseSample(line)
+		if err != nil {
+			return nil, err
+		}
+
+		name := s.Name
+		for _, suffix := range familySuffixes {
+			base, ok := strings.CutSuffix(s.Name, suffix)
+			if !ok {
+				continue
+			}
+			if f, exists := byName[base]; exists && (f.Type == "summary" || f.Type == "histogram") {
+				name = base
+			}
+			break
+		}
+		f := family(name)
+		f.Samples = append(f.Samples, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	families := make([]*metricFamily, 0, len(byName))
+	for _, f := range byName {
+		sort.SliceStable(f.Samples, func(i, j int) bool {
+			if f.Samples[i].Name != f.Samples[j].Name {
+				return f.Samples[i].Name < f.Samples[j].Name
+			}
+			return f.Samples[i].Labels < f.Samples[j].Labels
+		})
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+
+	return families, nil
+}
+
+// parseSample splits a sample line into name, labels and value. Label
+// values may contain spaces, braces and escaped quotes.
+func parseSample(line string) (metricSample, error) {
+	var s metricSample
+
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd < 0 {
+		return s, fmt.Errorf("sample without value: %q", line)
+	}
+	s.Name = line[:nameEnd]
+	rest := line[nameEnd:]
+
+	if strings.HasPrefix(rest, "{") {
+		end, inQuotes := -1, false
+		for i := 1; i < len(rest) && end < 0; i++ {
+			switch {
+			case inQuotes && rest[i] == '\\':
+				i++ // skip the escaped character
+			case rest[i] == '"':
+				inQuotes = !inQuotes
+			case !inQuotes && rest[i] == '}':
+				end = i
+			}
+		}
+		if end < 0 {
+			return s, fmt.Errorf("unterminated labels: %q", line)
+		}
+		s.Labels = rest[1:end]
+		rest = rest[end+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return s, fmt.Errorf("sample without value: %q", line)
+	}
+	s.Value = formatMetricValue(fields[0])
+
+	return s, nil
+}
+
+// formatMetricValue trims the trailing zeros %f leaves on whole numbers,
+// so "3.000000" reads as "3"
+func formatMetricValue(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v // NaN and ±Inf parse, anything else is shown as is
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// unescapeHelp reverses the \\ and \n escaping of HELP text
+func unescapeHelp(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n").Replace(s)
+}
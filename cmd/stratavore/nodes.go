@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(nodesCmd)
+}
+
+var nodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "List daemons registered against the shared database",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.ListNodes(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Nodes) == 0 {
+			fmt.Println("No daemon nodes registered")
+			return
+		}
+
+		fmt.Printf("Daemon nodes (%d):\n\n", len(resp.Nodes))
+		fmt.Println("NODE                     HOST                 VERSION    STATUS    RUNNERS  LAST SEEN")
+		fmt.Println("──────────────────────────────────────────────────────────────────────────────────────")
+
+		for _, n := range resp.Nodes {
+			fmt.Printf("%-24s %-20s %-10s %-9s %-8d %s\n",
+				truncate(n.NodeID, 24),
+				truncate(n.Hostname, 20),
+				valueOrDash(n.Version),
+				n.Status,
+				n.ActiveRunners,
+				formatLastSeen(n.LastHeartbeat))
+		}
+	},
+}
+
+// formatLastSeen renders a heartbeat timestamp as time elapsed since it
+func formatLastSeen(ts string) string {
+	t, err := api.ParseTime(ts)
+	if err != nil || t.IsZero() {
+		return "-"
+	}
+	return formatDuration(time.Since(t)) + " ago"
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	notificationsHistoryCmd.Flags().String("event", "", "Only show notifications for this event type (e.g. runner.failed)")
+	notificationsHistoryCmd.Flags().IntP("limit", "n", 50, "Maximum number of entries to show")
+	notificationsCmd.AddCommand(notificationsHistoryCmd)
+	rootCmd.AddCommand(notificationsCmd)
+}
+
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Inspect notification delivery",
+}
+
+var notificationsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent notification delivery attempts",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		eventType, _ := cmd.Flags().GetString("event")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		resp, err := apiClient.GetNotificationHistory(ctx, limit, eventType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Entries) == 0 {
+			fmt.Println("No notifications recorded")
+			return
+		}
+
+		fmt.Printf("%-20s %-9s %-22s %-6s %s\n", "SENT", "BACKEND", "EVENT", "STATUS", "MESSAGE")
+		for _, e := range resp.Entries {
+			status := "✓"
+			detail := e.MessageText
+			if !e.Success {
+				status = "✗"
+				detail = e.Error
+			}
+			fmt.Printf("%-20s %-9s %-22s %-6s %s\n",
+				formatSessionTime(e.SentAt),
+				e.Backend,
+				truncate(e.EventType, 22),
+				status,
+				truncate(firstLine(detail), 60))
+		}
+	},
+}
+
+// firstLine returns s up to its first newline
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	observabilityRulesCmd.Flags().StringP("output", "o", "", "Write the rules to this file instead of stdout")
+	observabilityCmd.AddCommand(observabilityRulesCmd)
+	rootCmd.AddCommand(observabilityCmd)
+}
+
+var observabilityCmd = &cobra.Command{
+	Use:   "observability",
+	Short: "Monitoring integration helpers",
+}
+
+var observabilityRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Export Prometheus alert rules for Stratavore's standard SLOs",
+	Long: `Export a Prometheus rule file alerting on missed runner heartbeats,
+token budgets over 90% used, the daemon going down and a high runner
+launch failure rate. Load it with 'rule_files' in prometheus.yml.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		output, _ := cmd.Flags().GetString("output")
+
+		rules, err := apiClient.GetAlertRules(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if output == "" {
+			os.Stdout.Write(rules)
+			return
+		}
+
+		if err := os.WriteFile(output, rules, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Alert rules written to %s\n", output)
+	},
+}
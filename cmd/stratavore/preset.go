@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	presetCreateCmd.Flags().String("project", "", "Restrict preset to a project (default: any project)")
+	presetCreateCmd.Flags().String("runtime", "", "Runtime type (process, container, remote)")
+	presetCreateCmd.Flags().StringSliceP("flag", "f", nil, "Claude Code flags")
+	presetCreateCmd.Flags().StringSliceP("capability", "c", nil, "Capabilities to enable")
+	presetCreateCmd.Flags().StringSliceP("env", "e", nil, "Environment variables (KEY=VALUE)")
+	presetCreateCmd.Flags().String("mode", "", "Conversation mode (new, continue, resume)")
+	presetCreateCmd.Flags().StringP("description", "d", "", "Preset description")
+
+	presetListCmd.Flags().String("project", "", "Show presets usable by this project")
+
+	presetCmd.AddCommand(presetCreateCmd)
+	presetCmd.AddCommand(presetListCmd)
+	presetCmd.AddCommand(presetDeleteCmd)
+	rootCmd.AddCommand(presetCmd)
+}
+
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Manage reusable launch presets",
+}
+
+var presetCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a launch preset",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		runtimeType, _ := cmd.Flags().GetString("runtime")
+		flags, _ := cmd.Flags().GetStringSlice("flag")
+		capabilities, _ := cmd.Flags().GetStringSlice("capability")
+		envPairs, _ := cmd.Flags().GetStringSlice("env")
+		mode, _ := cmd.Flags().GetString("mode")
+		description, _ := cmd.Flags().GetString("description")
+
+		env := make(map[string]string, len(envPairs))
+		for _, pair := range envPairs {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || key == "" {
+				fmt.Fprintf(os.Stderr, "Error: invalid --env value %q (expected KEY=VALUE)\n", pair)
+				os.Exit(1)
+			}
+			env[key] = value
+		}
+
+		resp, err := apiClient.CreatePreset(ctx, &api.Preset{
+			Name:             args[0],
+			ProjectName:      projectName,
+			RuntimeType:      runtimeType,
+			Flags:            flags,
+			Capabilities:     capabilities,
+			Environment:      env,
+			ConversationMode: mode,
+			Description:      description,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating preset: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Preset '%s' created\n", resp.Preset.Name)
+		fmt.Printf("\nUse 'stratavore launch <project> --preset %s' to launch with it\n", resp.Preset.Name)
+	},
+}
+
+var presetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List launch presets",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+
+		resp, err := apiClient.ListPresets(ctx, projectName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Presets) == 0 {
+			fmt.Println("No presets found")
+			return
+		}
+
+		fmt.Printf("Presets (%d):\n\n", len(resp.Presets))
+		fmt.Println("NAME                 PROJECT          RUNTIME    MODE       FLAGS")
+		fmt.Println("────────────────────────────────────────────────────────────────────────")
+
+		for _, p := range resp.Presets {
+			project := p.ProjectName
+			if project == "" {
+				project = "(any)"
+			}
+			fmt.Printf("%-20s %-16s %-10s %-10s %s\n",
+				truncate(p.Name, 20),
+				truncate(project, 16),
+				valueOrDash(p.RuntimeType),
+				valueOrDash(p.ConversationMode),
+				strings.Join(p.Flags, " "))
+		}
+	},
+}
+
+var presetDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a launch preset",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.DeletePreset(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Preset '%s' deleted\n", args[0])
+	},
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
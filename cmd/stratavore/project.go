@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	projectCreateCmd.Flags().StringP("path", "p", "", "Project path (default: current directory)")
+	projectCreateCmd.Flags().StringP("description", "d", "", "Project description")
+	projectCreateCmd.Flags().StringSlice("tags", nil, "Comma-separated project tags")
+	projectCreateCmd.Flags().Int("max-runners", 0, "Maximum concurrent runners (default: daemon quota default)")
+	projectCreateCmd.Flags().String("max-runtime", "", "Default runtime limit for runners, e.g. 2h (default: unlimited)")
+	projectCreateCmd.Flags().Int64("token-budget", 0, "Token budget per period (0 = no budget)")
+	projectCreateCmd.Flags().String("budget-period", "daily", "Budget period: hourly, daily, weekly or monthly")
+	projectCmd.AddCommand(projectCreateCmd)
+
+	projectNotifyCmd.Flags().StringP("project", "p", "", "Project to route (default: all projects without their own route)")
+	projectNotifyCmd.Flags().String("backend", "", "Notification backend: slack or telegram")
+	projectNotifyCmd.Flags().String("webhook-url", "", "Slack incoming webhook URL")
+	projectNotifyCmd.Flags().String("chat-id", "", "Telegram chat ID")
+	projectNotifyCmd.Flags().StringSlice("events", nil, "Event types to send, e.g. runner.failed,budget.warning (default: all)")
+	projectNotifyCmd.MarkFlagRequired("backend")
+	projectNotifyCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+	projectCmd.AddCommand(projectNotifyCmd)
+
+	projectDuplicateCmd.Flags().String("new-path", "", "Directory for the new project; must differ from the source project's")
+	projectDuplicateCmd.MarkFlagRequired("new-path")
+	projectCmd.AddCommand(projectDuplicateCmd)
+
+	projectUpdateCmd.Flags().StringP("description", "d", "", "New project description")
+	projectUpdateCmd.Flags().StringP("path", "p", "", "New project path")
+	projectUpdateCmd.Flags().StringSlice("tags", nil, "Comma-separated project tags, replacing the current ones")
+	projectCmd.AddCommand(projectUpdateCmd)
+
+	projectMoveCmd.Flags().String("new-path", "", "Directory the project now lives in")
+	projectMoveCmd.MarkFlagRequired("new-path")
+	projectCmd.AddCommand(projectMoveCmd)
+
+	addProjectListFlags(projectListCmd)
+	projectCmd.AddCommand(projectListCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage projects",
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List projects with optional sorting and filtering",
+	Args:  cobra.NoArgs,
+	Run:   runProjectList,
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create <project-name>",
+	Short: "Create a project with optional tags, runner quota and token budget",
+	Long: `Create a project like 'stratavore new', optionally setting its tags,
+concurrent runner quota and token budget in the same request. Either
+everything is created or nothing is.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectPath, _ := cmd.Flags().GetString("path")
+		description, _ := cmd.Flags().GetString("description")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		maxRunners, _ := cmd.Flags().GetInt("max-runners")
+		tokenBudget, _ := cmd.Flags().GetInt64("token-budget")
+		budgetPeriod, _ := cmd.Flags().GetString("budget-period")
+		maxRuntime, _ := cmd.Flags().GetString("max-runtime")
+
+		var maxRuntimeSeconds int32
+		if maxRuntime != "" {
+			d, err := parseAge(maxRuntime)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --max-runtime: %v\n", err)
+				os.Exit(1)
+			}
+			maxRuntimeSeconds = int32(d.Seconds())
+		}
+
+		if projectPath == "" {
+			cwd, _ := os.Getwd()
+			projectPath = cwd
+		}
+
+		req := &api.CreateProjectSetupRequest{
+			Name:              args[0],
+			Path:              projectPath,
+			Description:       description,
+			Tags:              tags,
+			MaxRunners:        int32(maxRunners),
+			TokenBudget:       tokenBudget,
+			BudgetPeriod:      budgetPeriod,
+			MaxRuntimeSeconds: maxRuntimeSeconds,
+		}
+
+		resp, err := apiClient.CreateProjectSetup(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating project: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Project '%s' created at %s\n", resp.Project.Name, resp.Project.Path)
+		if err := writeProjectMarker(resp.Project.Path, resp.Project.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if maxRunners > 0 {
+			fmt.Printf("  Max runners:  %d\n", maxRunners)
+		}
+		if tokenBudget > 0 {
+			fmt.Printf("  Token budget: %s per %s\n", formatNumber(tokenBudget), budgetPeriodUnit(budgetPeriod))
+		}
+		if maxRuntimeSeconds > 0 {
+			fmt.Printf("  Max runtime:  %s\n", formatDuration(time.Duration(maxRuntimeSeconds)*time.Second))
+		}
+	},
+}
+
+var projectDuplicateCmd = &cobra.Command{
+	Use:   "duplicate <source> <new-name>",
+	Short: "Create a project with another project's configuration",
+	Long: `Create a project with the source project's description, tags, health
+probe and resource quota at --new-path. Runners, sessions and token history
+are not copied; the new project starts idle. Each project needs its own
+directory, so --new-path must differ from the source project's path.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		newPath, _ := cmd.Flags().GetString("new-path")
+		newPath, err := filepath.Abs(newPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.DuplicateProject(ctx, &api.DuplicateProjectRequest{
+			SourceName: args[0],
+			NewName:    args[1],
+			NewPath:    newPath,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Project '%s' duplicated from '%s' at %s\n", resp.Project.Name, args[0], resp.Project.Path)
+	},
+}
+
+var projectUpdateCmd = &cobra.Command{
+	Use:   "update <project-name>",
+	Short: "Change a project's description, path or tags",
+	Long: `Change only the fields given as flags; the rest are left as they are.
+Pass --tags "" to clear a project's tags.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		req := &api.UpdateProjectRequest{Name: args[0]}
+		if cmd.Flags().Changed("description") {
+			description, _ := cmd.Flags().GetString("description")
+			req.Description = &description
+		}
+		if cmd.Flags().Changed("path") {
+			path, _ := cmd.Flags().GetString("path")
+			req.Path = &path
+		}
+		if cmd.Flags().Changed("tags") {
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			req.Tags = &tags
+		}
+
+		if req.Description == nil && req.Path == nil && req.Tags == nil {
+			fmt.Fprintln(os.Stderr, "Error: nothing to update; set --description, --path or --tags")
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.UpdateProject(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		p := resp.Project
+		fmt.Printf("✓ Project '%s' updated\n", p.Name)
+		fmt.Printf("  Path:        %s\n", p.Path)
+		fmt.Printf("  Description: %s\n", valueOrDash(p.Description))
+		fmt.Printf("  Tags:        %s\n", valueOrDash(strings.Join(p.Tags, ", ")))
+	},
+}
+
+var projectMoveCmd = &cobra.Command{
+	Use:   "move <project-name>",
+	Short: "Point a project at the directory it was moved to",
+	Long: `Update a project's path after its directory has been moved on disk. The
+new path must already exist on the daemon's host. The old path is kept for
+auditing, and active runners are sent a signal (SIGUSR2 by default) telling
+them the directory changed. Runners keep their working directory until they
+are restarted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		newPath, _ := cmd.Flags().GetString("new-path")
+		newPath, err := filepath.Abs(newPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.MoveProject(ctx, &api.MoveProjectRequest{
+			Name:    args[0],
+			NewPath: newPath,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Project '%s' moved\n", resp.Project.Name)
+		fmt.Printf("  From: %s\n", resp.OldPath)
+		fmt.Printf("  To:   %s\n", resp.Project.Path)
+		if resp.SignalledRunners > 0 {
+			fmt.Printf("  Signalled %d active runner(s); restart them to pick up the new path\n", resp.SignalledRunners)
+		}
+	},
+}
+
+var projectNotifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Route a project's notifications to a channel",
+	Long: `Send notifications for a project to a Slack webhook or Telegram chat.
+Without --project the route applies to every project that has no route of
+its own for the event. Projects with no matching route at all use the
+daemon's configured notification backends.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		backend, _ := cmd.Flags().GetString("backend")
+		webhookURL, _ := cmd.Flags().GetString("webhook-url")
+		chatID, _ := cmd.Flags().GetString("chat-id")
+		events, _ := cmd.Flags().GetStringSlice("events")
+
+		var target string
+		switch backend {
+		case "slack":
+			target = webhookURL
+			if target == "" {
+				fmt.Fprintf(os.Stderr, "Error: --webhook-url is required for slack\n")
+				os.Exit(1)
+			}
+		case "telegram":
+			target = chatID
+			if target == "" {
+				fmt.Fprintf(os.Stderr, "Error: --chat-id is required for telegram\n")
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unsupported backend %q (expected slack or telegram)\n", backend)
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.CreateNotificationRoute(ctx, &api.NotificationRoute{
+			ProjectName: projectName,
+			Backend:     backend,
+			Target:      target,
+			EventTypes:  events,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		scope := "all projects"
+		if projectName != "" {
+			scope = fmt.Sprintf("project '%s'", projectName)
+		}
+		eventList := "all events"
+		if len(events) > 0 {
+			eventList = strings.Join(events, ", ")
+		}
+
+		fmt.Printf("✓ Routing %s for %s to %s\n", eventList, scope, backend)
+	},
+}
+
+// budgetPeriodUnit turns a budget granularity into a noun, e.g. daily → day
+func budgetPeriodUnit(period string) string {
+	switch period {
+	case "hourly":
+		return "hour"
+	case "weekly":
+		return "week"
+	case "monthly":
+		return "month"
+	}
+	return "day"
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// projectInspectSessions and projectInspectEvents are how many recent
+// sessions and runner events 'project inspect' shows
+const (
+	projectInspectSessions = 5
+	projectInspectEvents   = 10
+)
+
+func init() {
+	projectInspectCmd.ValidArgsFunction = completeProjectNames
+	projectInspectCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+	projectCmd.AddCommand(projectInspectCmd)
+}
+
+var projectInspectCmd = &cobra.Command{
+	Use:   "inspect <name>",
+	Short: "Show project details, runners, sessions, budgets and recent events",
+	Long: `Show everything needed to diagnose a project in one view: its metadata,
+active runners, last 5 sessions, global and project token budgets, resource
+quota, and the last 10 runner lifecycle events.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			fmt.Fprintf(os.Stderr, "Error: unknown output %q (use text or json)\n", output)
+			os.Exit(1)
+		}
+
+		view, err := fetchProjectInspect(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if output == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(view); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		printProjectInspect(view)
+	},
+}
+
+// projectInspectView is everything 'project inspect' shows. Budgets are nil
+// when no budget is configured for that scope.
+type projectInspectView struct {
+	Project       *api.Project
+	Runners       []*api.Runner
+	Sessions      []*api.Session
+	GlobalBudget  *api.BudgetPeriod
+	ProjectBudget *api.BudgetPeriod
+	Quota         *api.ResourceQuota
+	Events        []*api.RunnerEvent
+}
+
+// fetchProjectInspect queries the daemon for each part of the view
+// concurrently, failing on the first error
+func fetchProjectInspect(ctx context.Context, name string) (*projectInspectView, error) {
+	apiClient := getAPIClient()
+	view := &projectInspectView{}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		resp, err := apiClient.GetProject(ctx, name)
+		if err != nil {
+			return fmt.Errorf("get project: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("get project: %s", resp.Error)
+		}
+		view.Project = resp.Project
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.ListRunners(ctx, name)
+		if err != nil {
+			return fmt.Errorf("list runners: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("list runners: %s", resp.Error)
+		}
+		view.Runners = resp.Runners
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.ListSessions(ctx, name, projectInspectSessions)
+		if err != nil {
+			return fmt.Errorf("list sessions: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("list sessions: %s", resp.Error)
+		}
+		view.Sessions = resp.Sessions
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.GetBudgetHistory(ctx, "global", "", 1)
+		if err != nil {
+			return fmt.Errorf("get global budget: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("get global budget: %s", resp.Error)
+		}
+		view.GlobalBudget = currentBudgetPeriod(resp.Periods)
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.GetBudgetHistory(ctx, "project", name, 1)
+		if err != nil {
+			return fmt.Errorf("get project budget: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("get project budget: %s", resp.Error)
+		}
+		view.ProjectBudget = currentBudgetPeriod(resp.Periods)
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.GetProjectQuota(ctx, name)
+		if err != nil {
+			return fmt.Errorf("get quota: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("get quota: %s", resp.Error)
+		}
+		view.Quota = resp.Quota
+		return nil
+	})
+
+	g.Go(func() error {
+		resp, err := apiClient.GetProjectEvents(ctx, name, projectInspectEvents)
+		if err != nil {
+			return fmt.Errorf("get runner events: %w", err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("get runner events: %s", resp.Error)
+		}
+		view.Events = resp.Events
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// currentBudgetPeriod returns the newest period if it is still current
+func currentBudgetPeriod(periods []*api.BudgetPeriod) *api.BudgetPeriod {
+	if len(periods) == 0 || !periods[0].Current {
+		return nil
+	}
+	return periods[0]
+}
+
+func printProjectInspect(v *projectInspectView) {
+	p := v.Project
+
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Printf("  PROJECT %s\n", p.Name)
+	fmt.Println("═══════════════════════════════════════════")
+	fmt.Println()
+	fmt.Printf("Path:        %s\n", p.Path)
+	fmt.Printf("Status:      %s\n", p.Status)
+	if p.Description != "" {
+		fmt.Printf("Description: %s\n", p.Description)
+	}
+	if len(p.Tags) > 0 {
+		fmt.Printf("Tags:        %s\n", strings.Join(p.Tags, ", "))
+	}
+	fmt.Printf("Created:     %s\n", p.CreatedAt)
+	if p.UpdatedAt != "" {
+		fmt.Printf("Updated:     %s\n", p.UpdatedAt)
+	}
+	if p.LastAccessedAt != "" {
+		fmt.Printf("Accessed:    %s\n", p.LastAccessedAt)
+	}
+	if p.ArchivedAt != "" {
+		fmt.Printf("Archived:    %s\n", p.ArchivedAt)
+	}
+	fmt.Printf("Runners:     %d active, %d total\n", p.ActiveRunners, p.TotalRunners)
+	fmt.Printf("Sessions:    %d total, %d this week\n", p.TotalSessions, p.SessionsThisWeek)
+	fmt.Printf("Tokens:      %s\n", formatNumber(p.TotalTokens))
+	fmt.Println()
+
+	if len(v.Runners) == 0 {
+		fmt.Println("No active runners")
+	} else {
+		fmt.Printf("Active Runners (%d):\n\n", len(v.Runners))
+		printActiveRunners(v.Runners)
+	}
+	fmt.Println()
+
+	if len(v.Sessions) == 0 {
+		fmt.Println("No sessions")
+	} else {
+		fmt.Printf("Recent Sessions (%d):\n\n", len(v.Sessions))
+		for _, s := range v.Sessions {
+			fmt.Printf("%s %-36s  %-16s  %4d msgs  %s tokens\n",
+				pinIndicator(s),
+				s.ID,
+				formatSessionTime(s.StartedAt),
+				s.MessageCount,
+				formatNumber(s.TokensUsed))
+		}
+	}
+	fmt.Println()
+
+	fmt.Println("Token Budgets:")
+	printBudgetStatus("Global", v.GlobalBudget)
+	printBudgetStatus("Project", v.ProjectBudget)
+	fmt.Println()
+
+	if v.Quota != nil {
+		fmt.Println("Resource Quota:")
+		printResourceQuota(v.Quota)
+		fmt.Println()
+	}
+
+	if len(v.Events) == 0 {
+		fmt.Println("No runner events recorded")
+		return
+	}
+
+	fmt.Printf("Recent Runner Events (%d):\n\n", len(v.Events))
+	for _, e := range v.Events {
+		fmt.Printf("  %s  %-8s  %-24s %s\n", e.Timestamp, shortID(e.RunnerID), e.EventType, formatEventData(e.Data))
+	}
+}
+
+func printBudgetStatus(label string, p *api.BudgetPeriod) {
+	if p == nil {
+		fmt.Printf("  %-8s no budget\n", label)
+		return
+	}
+	fmt.Printf("  %-8s %s %4.0f%%  %s / %s\n",
+		label,
+		budgetBar(p),
+		budgetPercent(p),
+		formatNumber(p.UsedTokens),
+		formatNumber(p.LimitTokens))
+}
+
+// printResourceQuota prints a project's quota limits, indented
+func printResourceQuota(q *api.ResourceQuota) {
+	fmt.Printf("  Runners:    %d concurrent\n", q.MaxConcurrentRunners)
+	fmt.Printf("  Memory:     %s\n", quotaLimit(q.MaxMemoryMB, "MB"))
+	fmt.Printf("  CPU:        %s\n", quotaLimit(int64(q.MaxCPUPercent), "%"))
+	fmt.Printf("  CPUs/run:   %s\n", quotaLimit(int64(q.MaxCPUsPerRunner), ""))
+	fmt.Printf("  Tokens/day: %s\n", quotaLimit(q.MaxTokensPerDay, ""))
+	if q.MaxRuntimeSeconds > 0 {
+		fmt.Printf("  Runtime:    %s\n", formatDuration(time.Duration(q.MaxRuntimeSeconds)*time.Second))
+	} else {
+		fmt.Println("  Runtime:    unlimited")
+	}
+	if q.HeartbeatTTLSeconds > 0 {
+		fmt.Printf("  Heartbeat:  %s TTL\n", formatDuration(time.Duration(q.HeartbeatTTLSeconds)*time.Second))
+	} else {
+		fmt.Println("  Heartbeat:  daemon default TTL")
+	}
+}
+
+// quotaLimit renders a quota value, where zero means unlimited
+func quotaLimit(v int64, unit string) string {
+	if v <= 0 {
+		return "unlimited"
+	}
+	if unit == "" {
+		return formatNumber(v)
+	}
+	return fmt.Sprintf("%s %s", formatNumber(v), unit)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// projectMarkerFile names the file in a project's directory recording which
+// project it belongs to, so the project can be found again after the
+// directory is moved outside Stratavore
+const projectMarkerFile = ".stratavore.json"
+
+// projectMarker is the content of projectMarkerFile
+type projectMarker struct {
+	Name string `json:"name"`
+}
+
+func init() {
+	projectSyncCmd.Flags().StringP("path", "p", "", "Project directory to sync (default: current directory)")
+	projectSyncCmd.Flags().Bool("all", false, "Check every project's path and report which are missing or moved")
+	projectCmd.AddCommand(projectSyncCmd)
+}
+
+var projectSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Update a project's path from the marker file in its directory",
+	Long: `Read the .stratavore.json marker that 'stratavore new' and 'project create'
+write into a project's directory, and if the project's recorded path differs,
+point the project at this directory as 'project move' does. Use this after
+renaming or moving a project directory outside Stratavore.
+
+With --all, check every project's recorded path instead. A project whose
+directory is gone is reported as moved when a sibling directory holds its
+marker, and as missing otherwise.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		if all {
+			runProjectSyncAll()
+			return
+		}
+
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			path, _ = os.Getwd()
+		}
+		path, err := filepath.Abs(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		marker, err := readProjectMarker(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		project, err := apiClient.GetProject(ctx, marker.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if project.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", project.Error)
+			os.Exit(1)
+		}
+
+		if filepath.Clean(project.Project.Path) == path {
+			fmt.Printf("✓ Project '%s' is already at %s\n", marker.Name, path)
+			return
+		}
+
+		resp, err := apiClient.MoveProject(ctx, &api.MoveProjectRequest{
+			Name:    marker.Name,
+			NewPath: path,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Project '%s' synced\n", resp.Project.Name)
+		fmt.Printf("  From: %s\n", resp.OldPath)
+		fmt.Printf("  To:   %s\n", resp.Project.Path)
+		if resp.SignalledRunners > 0 {
+			fmt.Printf("  Signalled %d active runner(s); restart them to pick up the new path\n", resp.SignalledRunners)
+		}
+	},
+}
+
+// runProjectSyncAll reports whether each active project's path still exists
+func runProjectSyncAll() {
+	apiClient := getAPIClient()
+	ctx := context.Background()
+
+	resp, err := apiClient.ListProjects(ctx, "", false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+
+	if len(resp.Projects) == 0 {
+		fmt.Println("No projects found")
+		return
+	}
+
+	var moved, missing []string
+
+	fmt.Printf("%-20s %-8s %s\n", "PROJECT", "STATUS", "PATH")
+	for _, p := range resp.Projects {
+		if info, err := os.Stat(p.Path); err == nil && info.IsDir() {
+			fmt.Printf("%-20s %-8s %s\n", truncate(p.Name, 20), "found", p.Path)
+			continue
+		}
+
+		if newPath := findMovedProject(p.Name, p.Path); newPath != "" {
+			fmt.Printf("%-20s %-8s %s → %s\n", truncate(p.Name, 20), "moved", p.Path, newPath)
+			moved = append(moved, newPath)
+			continue
+		}
+
+		fmt.Printf("%-20s %-8s %s\n", truncate(p.Name, 20), "missing", p.Path)
+		missing = append(missing, p.Name)
+	}
+
+	if len(moved) > 0 {
+		fmt.Println()
+		fmt.Println("Update moved projects with:")
+		for _, path := range moved {
+			fmt.Printf("  stratavore project sync --path %s\n", path)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Println()
+		fmt.Println("Missing projects can be archived, or pointed at their new directory:")
+		for _, name := range missing {
+			fmt.Printf("  stratavore drain %s\n", name)
+			fmt.Printf("  stratavore project move %s --new-path <dir>\n", name)
+		}
+	}
+}
+
+// findMovedProject looks for the marker of projectName in the directories
+// next to its old path, which covers the common case of a renamed
+// directory. It returns "" if none is found.
+func findMovedProject(projectName, oldPath string) string {
+	parent := filepath.Dir(oldPath)
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return ""
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(parent, e.Name())
+		if marker, err := readProjectMarker(dir); err == nil && marker.Name == projectName {
+			return dir
+		}
+	}
+	return ""
+}
+
+// readProjectMarker reads the project marker in dir
+func readProjectMarker(dir string) (*projectMarker, error) {
+	data, err := os.ReadFile(filepath.Join(dir, projectMarkerFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no %s in %s; it is written when a project is created", projectMarkerFile, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var marker projectMarker
+	if err := json.Unmarshal(data, &marker); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, projectMarkerFile), err)
+	}
+	if marker.Name == "" {
+		return nil, fmt.Errorf("%s has no project name", filepath.Join(dir, projectMarkerFile))
+	}
+	return &marker, nil
+}
+
+// writeProjectMarker records in dir that it belongs to projectName. An
+// existing marker is left alone.
+func writeProjectMarker(dir, projectName string) error {
+	data, err := json.MarshalIndent(&projectMarker{Name: projectName}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, projectMarkerFile), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("write project marker: %w", err)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write project marker: %w", err)
+	}
+	return f.Close()
+}
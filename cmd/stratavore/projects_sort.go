@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// projectSortKeys orders projects for 'stratavore projects --sort'. Counts
+// and recency put the largest value first; names sort alphabetically.
+var projectSortKeys = map[string]func(a, b *api.Project) bool{
+	"last-accessed": func(a, b *api.Project) bool { return projectLastAccessed(a).After(projectLastAccessed(b)) },
+	"name":          func(a, b *api.Project) bool { return a.Name < b.Name },
+	"tokens":        func(a, b *api.Project) bool { return a.TotalTokens > b.TotalTokens },
+	"runners":       func(a, b *api.Project) bool { return a.ActiveRunners > b.ActiveRunners },
+}
+
+// projectFilters maps --filter keys to a predicate built from the filter
+// value. has-runners takes no value.
+var projectFilters = map[string]func(value string) func(p *api.Project) bool{
+	"status": func(value string) func(p *api.Project) bool {
+		return func(p *api.Project) bool { return p.Status == value }
+	},
+	"tag": func(value string) func(p *api.Project) bool {
+		return func(p *api.Project) bool { return slices.Contains(p.Tags, value) }
+	},
+	"has-runners": func(string) func(p *api.Project) bool {
+		return func(p *api.Project) bool { return p.ActiveRunners > 0 }
+	},
+}
+
+// addProjectListFlags registers the listing flags shared by 'projects' and
+// 'project list'
+func addProjectListFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("include-archived", false, "Include archived projects")
+	cmd.Flags().Bool("archived", false, "Only show archived projects")
+	cmd.Flags().String("sort", "", "Sort by last-accessed, name, tokens or runners")
+	cmd.Flags().StringArray("filter", nil, "Only show projects matching status=<s>, tag=<t> or has-runners; repeatable")
+	cmd.Flags().Int("limit", 0, "Show at most N projects (0 = no limit)")
+}
+
+// sortProjects sorts projects by key, leaving ties in API order
+func sortProjects(projects []*api.Project, key string) error {
+	less, ok := projectSortKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q (valid: %s)", key, sortedKeys(projectSortKeys))
+	}
+	sort.SliceStable(projects, func(a, b int) bool {
+		return less(projects[a], projects[b])
+	})
+	return nil
+}
+
+// filterProjects keeps projects matching every filter. Filters are key=value
+// except has-runners, which is a bare key.
+func filterProjects(projects []*api.Project, filters []string) ([]*api.Project, error) {
+	preds := make([]func(p *api.Project) bool, 0, len(filters))
+	for _, f := range filters {
+		key, value, hasValue := strings.Cut(f, "=")
+		build, ok := projectFilters[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter key %q (valid: %s)", key, sortedKeys(projectFilters))
+		}
+		if hasValue == (key == "has-runners") {
+			if hasValue {
+				return nil, fmt.Errorf("invalid filter %q (has-runners takes no value)", f)
+			}
+			return nil, fmt.Errorf("invalid filter %q (expected key=value)", f)
+		}
+		preds = append(preds, build(value))
+	}
+
+	filtered := projects[:0:0]
+	for _, p := range projects {
+		keep := true
+		for _, pred := range preds {
+			if !pred(p) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+func projectLastAccessed(p *api.Project) time.Time {
+	t, _ := api.ParseTime(p.LastAccessedAt)
+	return t
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	quotaSetCmd.Flags().Int32("max-runners", 0, "Maximum concurrent runners")
+	quotaSetCmd.Flags().String("max-runtime", "", "Stop runners after this long, e.g. 2h or 1d; 0 for unlimited")
+	quotaSetCmd.Flags().String("heartbeat-ttl", "", "How long a runner may miss heartbeats before it is marked failed, e.g. 2m; 0 for the daemon default")
+	quotaSetCmd.ValidArgsFunction = completeProjectNames
+	quotaCmd.AddCommand(quotaSetCmd)
+	rootCmd.AddCommand(quotaCmd)
+}
+
+var quotaCmd = &cobra.Command{
+	Use:     "quota",
+	Aliases: []string{"quotas"},
+	Short:   "Manage project resource quotas",
+}
+
+var quotaSetCmd = &cobra.Command{
+	Use:   "set <project-name>",
+	Short: "Change a project's quota limits",
+	Long: `Change one or more of a project's quota limits. Limits whose flags are not
+given keep their current value. A heartbeat TTL change also applies to the
+project's running runners from the next stale-runner check.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		req := &api.SetProjectQuotaRequest{ProjectName: args[0]}
+
+		if cmd.Flags().Changed("max-runners") {
+			n, _ := cmd.Flags().GetInt32("max-runners")
+			req.MaxConcurrentRunners = &n
+		}
+		if cmd.Flags().Changed("max-runtime") {
+			s, _ := cmd.Flags().GetString("max-runtime")
+			d, err := parseAge(s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --max-runtime: %v\n", err)
+				os.Exit(1)
+			}
+			secs := int64(d.Seconds())
+			req.MaxRuntimeSeconds = &secs
+		}
+		if cmd.Flags().Changed("heartbeat-ttl") {
+			s, _ := cmd.Flags().GetString("heartbeat-ttl")
+			d, err := parseAge(s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --heartbeat-ttl: %v\n", err)
+				os.Exit(1)
+			}
+			secs := int64(d.Seconds())
+			req.HeartbeatTTLSeconds = &secs
+		}
+
+		if req.MaxConcurrentRunners == nil && req.MaxRuntimeSeconds == nil && req.HeartbeatTTLSeconds == nil {
+			fmt.Fprintln(os.Stderr, "Error: nothing to change; pass --max-runners, --max-runtime or --heartbeat-ttl")
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.SetProjectQuota(ctx, req)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Quota for project '%s' updated\n", args[0])
+		printResourceQuota(resp.Quota)
+	},
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+)
+
+// parseAge parses a look-back duration such as 30m, 2h or 7d. Days aren't
+// supported by time.ParseDuration, so a d suffix is handled here.
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// ageToTime converts a look-back duration to the RFC3339 time that long
+// ago, or "" when age is empty
+func ageToTime(age string) (string, error) {
+	if age == "" {
+		return "", nil
+	}
+	d, err := parseAge(age)
+	if err != nil {
+		return "", err
+	}
+	return api.FormatTime(time.Now().Add(-d)), nil
+}
+
+// printRunnerHistory prints runners in any state. Runners that have ended
+// are greyed out, with their exit code after the status and the time they
+// ended in place of uptime.
+func printRunnerHistory(runners []*api.Runner) {
+	fmt.Printf("Runners (%d):\n\n", len(runners))
+	fmt.Println("ID        PROJECT              STATUS            UPTIME/ENDED       CPU%   MEM(MB)")
+	fmt.Println("───────────────────────────────────────────────────────────────────────────────────")
+
+	for _, r := range runners {
+		ended := r.TerminatedAt != "" || r.Status == "failed" || r.Status == "terminated"
+
+		status := r.Status
+		when := formatDuration(time.Since(runnerStart(r)))
+		if ended {
+			when = formatSessionTime(r.TerminatedAt)
+		}
+		if r.TerminatedAt != "" {
+			status = fmt.Sprintf("%s (%d)", r.Status, r.ExitCode)
+		}
+
+		line := fmt.Sprintf("%-8s  %-20s %-17s %-17s %5.1f  %7d",
+			r.ID[:8],
+			truncate(r.ProjectName, 20),
+			status,
+			when,
+			r.CPUPercent,
+			r.MemoryMB)
+
+		if ended {
+			line = "\033[90m" + line + "\033[0m"
+		}
+		fmt.Println(line)
+	}
+}
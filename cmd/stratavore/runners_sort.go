@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+)
+
+// runnerSortKeys orders runners for 'stratavore runners --sort'. Resource
+// keys put the largest value first; names sort alphabetically.
+var runnerSortKeys = map[string]func(a, b *api.Runner) bool{
+	"cpu":     func(a, b *api.Runner) bool { return a.CPUPercent > b.CPUPercent },
+	"memory":  func(a, b *api.Runner) bool { return a.MemoryMB > b.MemoryMB },
+	"tokens":  func(a, b *api.Runner) bool { return a.TokensUsed > b.TokensUsed },
+	"uptime":  func(a, b *api.Runner) bool { return runnerStart(a).Before(runnerStart(b)) },
+	"project": func(a, b *api.Runner) bool { return a.ProjectName < b.ProjectName },
+	"status":  func(a, b *api.Runner) bool { return a.Status < b.Status },
+}
+
+// runnerFilterFields maps --filter keys to the runner field they match
+var runnerFilterFields = map[string]func(r *api.Runner) string{
+	"status":  func(r *api.Runner) string { return r.Status },
+	"project": func(r *api.Runner) string { return r.ProjectName },
+	"runtime": func(r *api.Runner) string { return r.RuntimeType },
+}
+
+// sortRunners sorts by each key in turn, the first key taking precedence.
+// Stable sorts are applied from the last key to the first so earlier keys
+// only reorder runners that later keys left tied.
+func sortRunners(runners []*api.Runner, keys []string, reverse bool) error {
+	for i := len(keys) - 1; i >= 0; i-- {
+		less, ok := runnerSortKeys[keys[i]]
+		if !ok {
+			return fmt.Errorf("unknown sort key %q (valid: %s)", keys[i], sortedKeys(runnerSortKeys))
+		}
+		sort.SliceStable(runners, func(a, b int) bool {
+			return less(runners[a], runners[b])
+		})
+	}
+
+	if reverse {
+		for i, j := 0, len(runners)-1; i < j; i, j = i+1, j-1 {
+			runners[i], runners[j] = runners[j], runners[i]
+		}
+	}
+	return nil
+}
+
+// filterRunners keeps runners matching every key=value filter
+func filterRunners(runners []*api.Runner, filters []string) ([]*api.Runner, error) {
+	type match struct {
+		field func(r *api.Runner) string
+		value string
+	}
+
+	matches := make([]match, 0, len(filters))
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter %q (expected key=value)", f)
+		}
+		field, ok := runnerFilterFields[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter key %q (valid: %s)", key, sortedKeys(runnerFilterFields))
+		}
+		matches = append(matches, match{field: field, value: value})
+	}
+
+	filtered := runners[:0:0]
+	for _, r := range runners {
+		keep := true
+		for _, m := range matches {
+			if m.field(r) != m.value {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
+func runnerStart(r *api.Runner) time.Time {
+	t, _ := api.ParseTime(r.StartedAt)
+	return t
+}
+
+func sortedKeys[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	sessionsListCmd.Flags().StringP("project", "p", "", "Only show sessions for this project")
+	sessionsListCmd.Flags().IntP("limit", "n", 20, "Maximum number of sessions to show")
+	sessionsListCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
+	sessionsSearchCmd.Flags().StringP("project", "p", "", "Only search sessions for this project")
+	sessionsSearchCmd.Flags().IntP("limit", "n", 10, "Maximum number of results")
+	sessionsSearchCmd.Flags().Bool("semantic", false, "Rank by meaning using the vector store instead of keywords")
+	sessionsSearchCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
+	sessionsResumeCmd.Flags().Int("from-message", 0, "Branch the session after message N and resume the branch")
+
+	sessionsCleanupCmd.Flags().String("older-than", "30d", "Remove sessions that ended longer ago than this (e.g. 12h, 30d)")
+	sessionsCleanupCmd.Flags().StringP("project", "p", "", "Only clean up sessions for this project")
+	sessionsCleanupCmd.Flags().Bool("dry-run", false, "Report what would be removed without deleting anything")
+	sessionsCleanupCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsSearchCmd)
+	sessionsCmd.AddCommand(sessionsPinCmd)
+	sessionsCmd.AddCommand(sessionsUnpinCmd)
+	sessionsCmd.AddCommand(sessionsResumeCmd)
+	sessionsCmd.AddCommand(sessionsCleanupCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Browse and manage conversation sessions",
+}
+
+var sessionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent sessions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		resp, err := apiClient.ListSessions(ctx, projectName, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Sessions) == 0 {
+			fmt.Println("No sessions found")
+			return
+		}
+
+		fmt.Printf("Sessions (%d):\n\n", len(resp.Sessions))
+		fmt.Println("   SESSION ID                            PROJECT          STARTED           MSGS  TOKENS")
+		fmt.Println("─────────────────────────────────────────────────────────────────────────────────────────")
+
+		for _, s := range resp.Sessions {
+			fmt.Printf("%s %-36s  %-15s  %-16s  %4d  %s\n",
+				pinIndicator(s),
+				s.ID,
+				truncate(s.ProjectName, 15),
+				formatSessionTime(s.StartedAt),
+				s.MessageCount,
+				formatNumber(s.TokensUsed))
+		}
+	},
+}
+
+var sessionsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over session summaries",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		limit, _ := cmd.Flags().GetInt("limit")
+		semantic, _ := cmd.Flags().GetBool("semantic")
+		query := strings.Join(args, " ")
+
+		search := apiClient.SearchSessions
+		if semantic {
+			search = apiClient.SemanticSearchSessions
+		}
+
+		resp, err := search(ctx, query, projectName, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if len(resp.Sessions) == 0 {
+			fmt.Printf("No sessions match %q\n", query)
+			return
+		}
+
+		fmt.Printf("Matching sessions (%d):\n\n", len(resp.Sessions))
+
+		for _, s := range resp.Sessions {
+			fmt.Printf("%s %s  %s  %s",
+				pinIndicator(s),
+				s.ID,
+				s.ProjectName,
+				formatSessionTime(s.StartedAt))
+			if semantic {
+				fmt.Printf("  (score %.2f)", s.Score)
+			}
+			fmt.Println()
+			fmt.Printf("   %s\n\n", highlightSnippet(s.Snippet))
+		}
+	},
+}
+
+var sessionsPinCmd = &cobra.Command{
+	Use:   "pin <session-id>",
+	Short: "Protect a session from retention cleanup",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.PinSession(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📌 Session %s pinned\n", args[0])
+	},
+}
+
+var sessionsUnpinCmd = &cobra.Command{
+	Use:   "unpin <session-id>",
+	Short: "Allow a session to be removed by retention cleanup",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		resp, err := apiClient.UnpinSession(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Session %s unpinned\n", args[0])
+	},
+}
+
+var sessionsResumeCmd = &cobra.Command{
+	Use:   "resume <session-id>",
+	Short: "Resume a session, optionally branching from an earlier message",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		fromMessage, _ := cmd.Flags().GetInt("from-message")
+
+		if cmd.Flags().Changed("from-message") {
+			resp, err := apiClient.BranchSession(ctx, args[0], fromMessage)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			if resp.Error != "" {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+				if resp.Session != nil {
+					fmt.Fprintf(os.Stderr, "Branch %s was created but no runner was started\n", resp.Session.ID)
+				}
+				os.Exit(1)
+			}
+
+			fmt.Printf("✓ Branched session %s after message %d\n", args[0], fromMessage)
+			fmt.Printf("  New session: %s\n", resp.Session.ID)
+			fmt.Printf("  Runner:      %s\n", resp.Runner.ID)
+			return
+		}
+
+		resp, err := apiClient.ResumeSession(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if resp.RunnerActive {
+			fmt.Printf("Session %s is still running on runner %s\n", args[0], resp.Runner.ID)
+			fmt.Printf("Use 'stratavore attach %s' to connect\n", resp.Runner.ID)
+			return
+		}
+
+		fmt.Printf("✓ Resumed session %s\n", args[0])
+		fmt.Printf("  Runner: %s\n", resp.Runner.ID)
+	},
+}
+
+var sessionsCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove old sessions that can't be resumed",
+	Long: `Remove ended sessions that can no longer be resumed. Pinned sessions and
+sessions with a stored transcript are always kept.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		projectName, _ := cmd.Flags().GetString("project")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		age, err := parseAge(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --older-than: %v\n", err)
+			os.Exit(1)
+		}
+
+		resp, err := apiClient.CleanupSessions(ctx, time.Now().Add(-age), projectName, dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			fmt.Printf("Would remove %d sessions (%s tokens)\n", resp.Deleted, formatNumber(resp.TokensFreed))
+			return
+		}
+
+		fmt.Printf("✓ Removed %d sessions (%s tokens)\n", resp.Deleted, formatNumber(resp.TokensFreed))
+	},
+}
+
+func pinIndicator(s *api.Session) string {
+	if s.Pinned {
+		return "📌"
+	}
+	return "  "
+}
+
+// highlightSnippet turns the <b></b> match markers from the daemon into
+// bold terminal text
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer("<b>", "\033[1m", "</b>", "\033[0m").Replace(snippet)
+}
+
+// formatSessionTime renders an RFC3339 timestamp as local "YYYY-MM-DD HH:MM"
+func formatSessionTime(ts string) string {
+	t, err := api.ParseTime(ts)
+	if err != nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// statsBarWidth is the width of the longest bar in the usage chart
+const statsBarWidth = 40
+
+func init() {
+	statsCmd.Flags().StringP("project", "p", "", "Report on a single project instead of global usage")
+	statsCmd.Flags().IntP("days", "d", 30, "Number of days to report")
+	statsCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
+	statsLeaderboardCmd.Flags().String("period", "week", "Period to rank: today, week, month or all")
+	statsLeaderboardCmd.Flags().IntP("limit", "n", 10, "Number of projects to show")
+	statsLeaderboardCmd.RegisterFlagCompletionFunc("period", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"today", "week", "month", "all"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	statsCmd.AddCommand(statsLeaderboardCmd)
+	rootCmd.AddCommand(statsCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show daily token usage trends",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		projectName, _ := cmd.Flags().GetString("project")
+		days, _ := cmd.Flags().GetInt("days")
+
+		scope := "global"
+		if projectName != "" {
+			scope = "project"
+		}
+
+		resp, err := apiClient.GetUsageReport(ctx, scope, projectName, days)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		title := "GLOBAL"
+		if projectName != "" {
+			title = strings.ToUpper(projectName)
+		}
+
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Printf("  TOKEN USAGE: %s (last %d days)\n", title, days)
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Println()
+
+		printUsageChart(resp.DailyUsage, resp.PeakDayUsage)
+
+		fmt.Println()
+		fmt.Printf("Total:             %s\n", formatNumber(resp.TotalUsage))
+		fmt.Printf("Daily average:     %s\n", formatNumber(int64(resp.AverageDailyUsage)))
+		if resp.PeakDayDate != "" {
+			fmt.Printf("Peak day:          %s (%s)\n", formatNumber(resp.PeakDayUsage), resp.PeakDayDate)
+		}
+		fmt.Printf("Projected monthly: %s\n", formatNumber(resp.ProjectedMonthlyUsage))
+	},
+}
+
+// printUsageChart draws one horizontal bar per day scaled to the peak day
+func printUsageChart(points []*api.DailyUsagePoint, peak int64) {
+	for _, p := range points {
+		width := 0
+		if peak > 0 {
+			width = int(p.Tokens * statsBarWidth / peak)
+		}
+		if width == 0 && p.Tokens > 0 {
+			width = 1
+		}
+		bar := strings.Repeat("█", width) + strings.Repeat(" ", statsBarWidth-width)
+		fmt.Printf("%s │%s %s\n", p.Date, bar, formatNumber(p.Tokens))
+	}
+}
+
+var statsLeaderboardCmd = &cobra.Command{
+	Use:   "leaderboard",
+	Short: "Rank projects by token usage",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		apiClient := getAPIClient()
+		ctx := context.Background()
+
+		period, _ := cmd.Flags().GetString("period")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		resp, err := apiClient.GetTokenLeaderboard(ctx, period, limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if resp.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+			os.Exit(1)
+		}
+
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Printf("  TOKEN LEADERBOARD: %s\n", strings.ToUpper(period))
+		fmt.Println("═══════════════════════════════════════════")
+		fmt.Println()
+
+		if len(resp.Entries) == 0 {
+			fmt.Println("No token usage recorded")
+			return
+		}
+
+		printLeaderboardChart(resp.Entries)
+
+		var total int64
+		var cost float64
+		for _, e := range resp.Entries {
+			total += e.TokensUsed
+			cost += e.EstimatedCostUSD
+		}
+		fmt.Println()
+		fmt.Printf("Total:          %s\n", formatNumber(total))
+		fmt.Printf("Estimated cost: $%.2f\n", cost)
+	},
+}
+
+// printLeaderboardChart draws one horizontal bar per project scaled to the
+// top spender
+func printLeaderboardChart(entries []*api.TokenLeaderboardEntry) {
+	nameWidth := 0
+	for _, e := range entries {
+		nameWidth = max(nameWidth, len(e.ProjectName))
+	}
+
+	peak := entries[0].TokensUsed
+	for _, e := range entries {
+		width := 0
+		if peak > 0 {
+			width = int(e.TokensUsed * statsBarWidth / peak)
+		}
+		if width == 0 && e.TokensUsed > 0 {
+			width = 1
+		}
+		bar := strings.Repeat("█", width) + strings.Repeat(" ", statsBarWidth-width)
+		fmt.Printf("%2d. %-*s │%s %s ($%.2f)\n", e.Rank, nameWidth, e.ProjectName, bar, formatNumber(e.TokensUsed), e.EstimatedCostUSD)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/config"
+)
+
+// upgradeDownloadTimeout bounds fetching the new binary and its checksum
+const upgradeDownloadTimeout = 10 * time.Minute
+
+// runDaemonUpgrade replaces the daemon binary with the latest release and
+// asks the running daemon to exit so its supervisor restarts it. The binary
+// to replace is found from the local daemon's process, never from the API,
+// and the upgrade is refused unless the daemon answering the API is that
+// process: a remote daemon must not choose which local file is overwritten.
+func runDaemonUpgrade() {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	pidFile := cfg.Daemon.PIDFile()
+	pid, binaryPath, err := localDaemon(pidFile)
+	if errors.Is(err, errDaemonNotRunning) {
+		err = fmt.Errorf("no daemon is running on this host (stale pid file %s)", pidFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Run 'stratavore daemon upgrade' on the daemon's host while it is running.")
+		os.Exit(1)
+	}
+
+	apiClient := getAPIClient()
+	ctx := context.Background()
+
+	resp, err := apiClient.GetDaemonVersion(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if resp.Error != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		os.Exit(1)
+	}
+
+	if resp.BinaryPath != binaryPath {
+		fmt.Fprintf(os.Stderr, "Error: the daemon answering the API runs %q, not the local daemon's %q\n", resp.BinaryPath, binaryPath)
+		fmt.Fprintln(os.Stderr, "Run 'stratavore daemon upgrade' on the daemon's host.")
+		os.Exit(1)
+	}
+
+	fmt.Printf("Current version: %s\n", resp.CurrentVersion)
+	if resp.LatestVersion != "" {
+		fmt.Printf("Latest version:  %s\n", resp.LatestVersion)
+		if !resp.UpdateAvailable {
+			fmt.Println("✓ Daemon is up to date")
+			return
+		}
+	}
+
+	if resp.DownloadURL == "" || resp.ChecksumURL == "" {
+		fmt.Fprintln(os.Stderr, "Error: upgrade.release_url and upgrade.checksum_url must be configured on the daemon")
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, upgradeDownloadTimeout)
+	defer cancel()
+
+	fmt.Printf("Downloading %s\n", resp.DownloadURL)
+	tmpPath, sum, err := downloadBinary(ctx, resp.DownloadURL, filepath.Dir(binaryPath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: download: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.Remove(tmpPath) // no-op once renamed
+
+	expected, err := fetchChecksum(ctx, resp.ChecksumURL, path.Base(resp.DownloadURL))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: checksum: %v\n", err)
+		os.Exit(1)
+	}
+	if !strings.EqualFold(sum, expected) {
+		fmt.Fprintf(os.Stderr, "Error: checksum mismatch (got %s, want %s)\n", sum, expected)
+		os.Exit(1)
+	}
+	fmt.Println("✓ Checksum verified")
+
+	// Keep the permissions of the binary being replaced
+	mode := os.FileMode(0755)
+	if info, err := os.Stat(binaryPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.Rename(tmpPath, binaryPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: replace binary: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Replaced %s\n", binaryPath)
+
+	err = terminateDaemon(pid, pidFile)
+	if errors.Is(err, errDaemonNotRunning) {
+		fmt.Println("Daemon has exited; it will run the new version once started")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "The new binary is installed; restart the daemon manually.")
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Sent SIGTERM to daemon (pid %d); it will run the new version once restarted\n", pid)
+}
+
+// downloadBinary streams url into a temp file in dir, returning the file's
+// path and hex SHA256. dir must be on the same filesystem as the binary being
+// replaced so the final rename is atomic.
+func downloadBinary(ctx context.Context, url, dir string) (string, string, error) {
+	body, err := httpGet(ctx, url)
+	if err != nil {
+		return "", "", err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(dir, ".stratavored-upgrade-*")
+	if err != nil {
+		return "", "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", "", err
+	}
+
+	return tmp.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fetchChecksum reads a checksum file that is either a bare hex digest or
+// sha256sum output, in which case the line for name is used
+func fetchChecksum(ctx context.Context, url, name string) (string, error) {
+	body, err := httpGet(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(lines) == 1 || (len(fields) > 1 && strings.TrimPrefix(fields[1], "*") == name) {
+			return fields[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+func httpGet(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func readPIDFile(pidPath string) (int, error) {
+	data, err := os.ReadFile(pidPath)
+	if err != nil {
+		return 0, fmt.Errorf("read pid file: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pid file %s", pidPath)
+	}
+
+	return pid, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// versionCheckTimeout bounds asking the daemon for its version
+const versionCheckTimeout = 5 * time.Second
+
+func init() {
+	versionCmd.Flags().Bool("check", false, "Compare with the running daemon's version; exits 1 if incompatible")
+	versionCmd.Flags().Bool("json", false, "Output as JSON, including the daemon version")
+	versionCmd.Flags().Bool("short", false, "Show only the version number")
+	rootCmd.AddCommand(versionCmd)
+
+	// Lets "stratavore --version --json" print the same report
+	rootCmd.Flags().Bool("json", false, "With --version, output version information as JSON")
+	cobra.AddTemplateFunc("versionOutput", versionOutput)
+	rootCmd.SetVersionTemplate(`{{versionOutput .}}`)
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show version information",
+	Long: `Show the CLI's version. With --check, also compare it with the running
+daemon's: a different minor version prints a warning, and a different major
+version, or a CLI older than the daemon supports, is an error.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		short, _ := cmd.Flags().GetBool("short")
+
+		if short && !check && !asJSON {
+			fmt.Println(Version)
+			return
+		}
+
+		if !check && !asJSON {
+			fmt.Printf("stratavore %s (built %s, commit %s)\n", Version, BuildTime, Commit)
+			return
+		}
+
+		report, err := checkDaemonVersion()
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if encErr := enc.Encode(report); encErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", encErr)
+				os.Exit(1)
+			}
+			if check && (err != nil || !report.Compatible) {
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Printf("CLI:    %s (built %s, commit %s)\n", Version, BuildTime, Commit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Daemon: %s (built %s, commit %s)\n",
+			report.DaemonVersion, valueOrDash(report.daemonBuildTime), valueOrDash(report.daemonCommit))
+
+		if !report.Compatible {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", report.skew)
+			os.Exit(1)
+		}
+		if report.skew != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", report.skew)
+			return
+		}
+		fmt.Println("✓ CLI and daemon versions are compatible")
+	},
+}
+
+// versionReport is the JSON form of version information
+type versionReport struct {
+	Version       string `json:"version"`
+	BuildTime     string `json:"build_time"`
+	Commit        string `json:"commit"`
+	DaemonVersion string `json:"daemon_version"`
+	Compatible    bool   `json:"compatible"`
+
+	daemonBuildTime string
+	daemonCommit    string
+	skew            string // why the versions differ, if they do
+}
+
+// checkDaemonVersion asks the daemon for its version and compares it with
+// the CLI's. The report is filled in as far as possible even when err is
+// set, e.g. when the daemon is unreachable.
+func checkDaemonVersion() (*versionReport, error) {
+	report := &versionReport{Version: Version, BuildTime: BuildTime, Commit: Commit}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionCheckTimeout)
+	defer cancel()
+
+	resp, err := getAPIClient().GetDaemonVersion(ctx)
+	if err != nil {
+		return report, fmt.Errorf("query daemon version: %w", err)
+	}
+	// Error may only mean the latest release couldn't be looked up, which
+	// doesn't matter here as long as the running version came back
+	if resp.CurrentVersion == "" {
+		if resp.Error != "" {
+			return report, fmt.Errorf("query daemon version: %s", resp.Error)
+		}
+		return report, fmt.Errorf("daemon did not report its version")
+	}
+
+	report.DaemonVersion = resp.CurrentVersion
+	report.daemonBuildTime = resp.BuildTime
+	report.daemonCommit = resp.Commit
+
+	cli, err := api.ParseVersion(Version)
+	if err != nil {
+		return report, err
+	}
+	daemon, err := api.ParseVersion(resp.CurrentVersion)
+	if err != nil {
+		return report, err
+	}
+
+	if cli.Major != daemon.Major {
+		report.skew = fmt.Sprintf("CLI %s and daemon %s have different major versions and are incompatible", Version, resp.CurrentVersion)
+		return report, nil
+	}
+	if min, err := api.ParseVersion(resp.MinCompatibleClientVersion); err == nil && cli.Less(min) {
+		report.skew = fmt.Sprintf("CLI %s is older than %s, the oldest version daemon %s supports", Version, resp.MinCompatibleClientVersion, resp.CurrentVersion)
+		return report, nil
+	}
+
+	report.Compatible = true
+	if cli.Minor != daemon.Minor {
+		report.skew = fmt.Sprintf("CLI %s and daemon %s have different minor versions; upgrade the older one", Version, resp.CurrentVersion)
+	}
+	return report, nil
+}
+
+// versionOutput renders "stratavore --version", as JSON with --json
+func versionOutput(cmd *cobra.Command) string {
+	if asJSON, _ := cmd.Flags().GetBool("json"); !asJSON {
+		return fmt.Sprintf("stratavore version %s\n", cmd.Version)
+	}
+
+	// The daemon is optional here; an unreachable one leaves daemon_version
+	// empty and compatible false
+	report, _ := checkDaemonVersion()
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error: %v\n", err)
+	}
+	return string(data) + "\n"
+}
@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/internal/budget"
+	"github.com/meridian-lex/stratavore/internal/cache"
 	"github.com/meridian-lex/stratavore/internal/daemon"
 	"github.com/meridian-lex/stratavore/internal/messaging"
 	"github.com/meridian-lex/stratavore/internal/notifications"
 	"github.com/meridian-lex/stratavore/internal/observability"
+	"github.com/meridian-lex/stratavore/internal/session"
 	"github.com/meridian-lex/stratavore/internal/storage"
+	"github.com/meridian-lex/stratavore/internal/vectorstore"
 	"github.com/meridian-lex/stratavore/pkg/config"
+	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -24,7 +33,18 @@ var (
 	Commit    = "unknown"
 )
 
+// nodeHeartbeatInterval is how often the daemon refreshes its node
+// registration; well inside daemon.DaemonNodeStaleAfter
+const nodeHeartbeatInterval = 30 * time.Second
+
+// skipCacheWarm disables the startup cache warm-up, e.g. during rolling
+// restarts where the extra DB query is undesirable
+var skipCacheWarm bool
+
 func main() {
+	flag.BoolVar(&skipCacheWarm, "skip-cache-warm", false, "Skip pre-populating the cache from the database at startup")
+	flag.Parse()
+
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -64,6 +84,7 @@ func run() error {
 		cfg.Database.PostgreSQL.GetConnectionString(),
 		cfg.Database.PostgreSQL.MaxConns,
 		cfg.Database.PostgreSQL.MinConns,
+		logger,
 	)
 	if err != nil {
 		return fmt.Errorf("connect to postgres: %w", err)
@@ -84,6 +105,13 @@ func run() error {
 		Password:          cfg.Docker.RabbitMQ.Password,
 		Exchange:          cfg.Docker.RabbitMQ.Exchange,
 		PublisherConfirms: cfg.Docker.RabbitMQ.PublisherConfirms,
+		Reconnect: messaging.ReconnectionPolicy{
+			InitialDelay:  cfg.Docker.RabbitMQ.Reconnect.InitialDelay,
+			MaxDelay:      cfg.Docker.RabbitMQ.Reconnect.MaxDelay,
+			MaxAttempts:   cfg.Docker.RabbitMQ.Reconnect.MaxAttempts,
+			BackoffFactor: cfg.Docker.RabbitMQ.Reconnect.BackoffFactor,
+			Jitter:        cfg.Docker.RabbitMQ.Reconnect.Jitter,
+		},
 	}, logger)
 	if err != nil {
 		return fmt.Errorf("connect to rabbitmq: %w", err)
@@ -97,26 +125,160 @@ func run() error {
 		logger.Error("failed to declare queue", zap.Error(err))
 	}
 
-	// Initialize Telegram notifications
-	var notifier *notifications.Client
+	// Connect to Redis cache (optional; falls back to pass-through)
+	var cacheCfg *cache.Config
+	if cfg.Docker.Redis.Enabled {
+		cacheCfg = &cache.Config{
+			Host:     cfg.Docker.Redis.Host,
+			Port:     cfg.Docker.Redis.Port,
+			Password: cfg.Docker.Redis.Password,
+			DB:       cfg.Docker.Redis.DB,
+		}
+	}
+	cacheMgr, err := cache.NewManager(cacheCfg, logger)
+	if err != nil {
+		return fmt.Errorf("create cache manager: %w", err)
+	}
+	defer cacheMgr.Close()
+
+	if cacheMgr.Enabled() && !skipCacheWarm {
+		warmCache(ctx, db, cacheMgr, logger)
+	}
+
+	// Initialize notification backends
+	var notifiers []notifications.Notifier
+	var telegramClient *notifications.Client
 	if cfg.Docker.Telegram.Token != "" && cfg.Docker.Telegram.ChatID != "" {
-		notifier = notifications.NewClient(notifications.Config{
+		telegramClient = notifications.NewClient(notifications.Config{
 			Token:  cfg.Docker.Telegram.Token,
 			ChatID: cfg.Docker.Telegram.ChatID,
 		}, logger)
-
-		hostname, _ := os.Hostname()
-		notifier.DaemonStarted(Version, hostname)
+		telegramClient.SetRecorder(db)
+		notifiers = append(notifiers, telegramClient)
 		logger.Info("telegram notifications enabled")
 	} else {
 		logger.Warn("telegram notifications disabled (no token/chat_id configured)")
 	}
 
+	if cfg.Docker.Email.SMTPHost != "" && len(cfg.Docker.Email.SMTPTo) > 0 {
+		// The general digest below already batches email, so batching it
+		// again would hold events back for two intervals
+		emailDigest := cfg.Docker.Email.DigestInterval
+		if cfg.Docker.Notify.DigestInterval > 0 && emailDigest > 0 {
+			logger.Warn("ignoring docker.email.digest_interval; docker.notifications.digest_interval applies to email",
+				zap.Duration("email_digest_interval", emailDigest),
+				zap.Duration("digest_interval", cfg.Docker.Notify.DigestInterval))
+			emailDigest = 0
+		}
+
+		emailClient := notifications.NewEmailClient(notifications.EmailConfig{
+			SMTPHost:       cfg.Docker.Email.SMTPHost,
+			SMTPPort:       cfg.Docker.Email.SMTPPort,
+			SMTPUser:       cfg.Docker.Email.SMTPUser,
+			SMTPPassword:   cfg.Docker.Email.SMTPPassword,
+			SMTPFrom:       cfg.Docker.Email.SMTPFrom,
+			SMTPTo:         cfg.Docker.Email.SMTPTo,
+			UseTLS:         cfg.Docker.Email.UseTLS,
+			DigestInterval: emailDigest,
+		}, logger)
+		emailClient.SetRecorder(db)
+		go emailClient.RunDigest(ctx)
+		notifiers = append(notifiers, emailClient)
+		logger.Info("email notifications enabled",
+			zap.String("smtp_host", cfg.Docker.Email.SMTPHost),
+			zap.Duration("digest_interval", emailDigest))
+	}
+
+	notifier := notifications.NewMulti(notifiers...)
+	if notifier != nil && cfg.Docker.Notify.DigestInterval > 0 {
+		digest := notifications.NewDigestClient(notifier, cfg.Docker.Notify.DigestInterval, logger)
+		go digest.FlushLoop(ctx)
+		notifier = digest
+	}
+	if notifier != nil {
+		hostname, _ := os.Hostname()
+		notifier.DaemonStarted(Version, hostname)
+	}
+
+	// Route project events through per-project notification routes, falling
+	// back to the backends above
+	dispatcher := notifications.NewDispatcher(db, notifier, logger)
+	dispatcher.SetTelegram(telegramClient)
+	dispatcher.SetRecorder(db)
+
+	// Once RabbitMQ reconnection is abandoned events pile up in the outbox
+	// until the daemon is restarted, so make sure someone hears about it
+	mqClient.SetOnPermanentFailure(func() {
+		dispatcher.Notify("", notifications.EventSystemAlert, map[string]interface{}{
+			"title": "RabbitMQ connection lost",
+			"message": fmt.Sprintf("Gave up reconnecting to %s:%d. Events are queued in the outbox; restart the daemon once the broker is back.",
+				cfg.Docker.RabbitMQ.Host, cfg.Docker.RabbitMQ.Port),
+		})
+	})
+
+	// Create budget manager
+	budgetMgr := budget.NewManager(db, dispatcher, logger)
+
+	// Identify this daemon among those sharing the database
+	hostname, _ := os.Hostname()
+	nodeID := cfg.Daemon.NodeID
+	if nodeID == "" {
+		nodeID = fmt.Sprintf("%s-%d", hostname, cfg.Daemon.Port_GRPC)
+	}
+
 	// Create runner manager
-	runnerMgr := daemon.NewRunnerManager(db, mqClient, logger)
+	runnerMgr := daemon.NewRunnerManager(db, mqClient, budgetMgr, logger)
+	runnerMgr.SetNodeID(nodeID)
+	runnerMgr.SetDispatcher(dispatcher)
+	runnerMgr.SetCache(cacheMgr)
+	runnerMgr.SetDrainTimeout(time.Duration(cfg.Daemon.RunnerDrainTimeout) * time.Second)
+	runnerMgr.SetHeartbeatTTL(3 * time.Duration(cfg.Daemon.HeartbeatInterval) * time.Second)
+	runnerMgr.SetMaxConcurrentLaunches(cfg.Daemon.MaxConcurrentLaunches)
+	runnerMgr.SetStartupTimeout(time.Duration(cfg.Daemon.StartupTimeout) * time.Second)
+	if err := runnerMgr.SetDirChangedSignal(cfg.Daemon.RunnerDirChangedSignal); err != nil {
+		logger.Warn("runners will not be signalled when their project moves", zap.Error(err))
+	}
+
+	// Create session manager; transcripts live under the data directory
+	sessionMgr := session.NewManager(db, logger)
+	sessionMgr.SetRedis(cacheMgr.RedisClient())
+	sessionMgr.SetDispatcher(dispatcher, cfg.Daemon.MaxMessagesPerMinute)
+	if store, err := session.NewDirStore(cfg.Daemon.DataPath("transcripts")); err != nil {
+		logger.Warn("transcript storage disabled", zap.Error(err))
+	} else {
+		sessionMgr.SetTranscriptStore(store)
+	}
+
+	// Index ended sessions in Qdrant for semantic search
+	var vectorStore *vectorstore.QdrantClient
+	if cfg.Docker.Qdrant.Enabled {
+		if cfg.Observability.EmbeddingEndpoint == "" {
+			logger.Warn("qdrant enabled but observability.embedding_endpoint is empty; semantic search disabled")
+		} else {
+			embedder := vectorstore.NewHTTPEmbedder(cfg.Observability.EmbeddingEndpoint, cfg.Observability.EmbeddingModel)
+			vectorStore, err = vectorstore.NewQdrantClient(cfg.Docker.Qdrant.Host, cfg.Docker.Qdrant.GRPCPort, cfg.Docker.Qdrant.Collection, embedder, logger)
+			if err != nil {
+				logger.Warn("semantic search disabled", zap.Error(err))
+			} else {
+				defer vectorStore.Close()
+				sessionMgr.SetIndexer(vectorStore)
+				logger.Info("semantic session search enabled",
+					zap.String("qdrant", fmt.Sprintf("%s:%d", cfg.Docker.Qdrant.Host, cfg.Docker.Qdrant.GRPCPort)))
+			}
+		}
+	}
 
 	// Create API handler
-	apiHandler := daemon.NewGRPCServer(runnerMgr, db, logger, cfg.Daemon.Port_GRPC)
+	apiHandler := daemon.NewGRPCServer(runnerMgr, db, cacheMgr, logger, cfg.Daemon.Port_GRPC, Version, cfg.Security.AuthSecret)
+	apiHandler.SetUpgradeConfig(cfg.Upgrade)
+	apiHandler.SetBuildInfo(BuildTime, Commit)
+	apiHandler.SetSessionManager(sessionMgr)
+	if vectorStore != nil {
+		apiHandler.SetVectorStore(vectorStore)
+	}
+	if err := apiHandler.LoadRevokedAPITokens(ctx); err != nil {
+		logger.Warn("failed to load revoked API tokens", zap.Error(err))
+	}
 
 	// Start HTTP API server
 	httpServer := daemon.NewHTTPServer(cfg.Daemon.Port_HTTP, apiHandler, logger, &cfg.Security)
@@ -135,6 +297,13 @@ func run() error {
 		logger,
 	)
 	go outboxPublisher.Start(ctx)
+	go outboxPublisher.CleanupLoop(ctx, time.Duration(cfg.Daemon.OutboxRetention)*24*time.Hour)
+	go outboxPublisher.PartitionLoop(ctx, cfg.Daemon.OutboxPartitionRetention)
+
+	// Purge old sessions when a retention period is configured
+	if cfg.Daemon.SessionRetention > 0 {
+		go sessionMgr.CleanupLoop(ctx, time.Duration(cfg.Daemon.SessionRetention)*24*time.Hour)
+	}
 
 	// Start reconciliation loop
 	go startReconciliationLoop(ctx, runnerMgr, cfg.Daemon.ReconcileInterval, logger)
@@ -149,18 +318,52 @@ func run() error {
 			}
 		}()
 
+		runnerMgr.SetMetrics(metricsServer)
+
 		// Update metrics periodically
-		go startMetricsUpdateLoop(ctx, metricsServer, runnerMgr, logger)
+		go startMetricsUpdateLoop(ctx, metricsServer, runnerMgr, db, logger)
 	}
 
 	// Start gRPC server
-	grpcServer := daemon.NewGRPCServer(runnerMgr, db, logger, cfg.Daemon.Port_GRPC)
+	grpcServer := daemon.NewGRPCServer(runnerMgr, db, cacheMgr, logger, cfg.Daemon.Port_GRPC, Version, cfg.Security.AuthSecret)
+	grpcServer.SetSessionManager(sessionMgr)
+	if vectorStore != nil {
+		grpcServer.SetVectorStore(vectorStore)
+	}
+	if cfg.Security.EnableMTLS {
+		tlsConfig, err := auth.ServerTLSConfig(cfg.Security.CertFile, cfg.Security.KeyFile, cfg.Security.CAFile)
+		if err != nil {
+			return fmt.Errorf("load gRPC TLS config: %w", err)
+		}
+		grpcServer.SetTLSConfig(tlsConfig)
+	}
 	go func() {
 		if err := grpcServer.Start(); err != nil {
 			logger.Error("gRPC server error", zap.Error(err))
 		}
 	}()
 
+	// Register this daemon so others sharing the database can see it
+	if err := db.UpsertDaemonInfo(ctx, &types.DaemonInfo{
+		NodeID:    nodeID,
+		Hostname:  hostname,
+		Version:   Version,
+		StartedAt: time.Now(),
+		GRPCPort:  cfg.Daemon.Port_GRPC,
+		HTTPPort:  cfg.Daemon.Port_HTTP,
+	}); err != nil {
+		logger.Warn("failed to register daemon node", zap.Error(err))
+	}
+	go startNodeHeartbeatLoop(ctx, db, runnerMgr, nodeID, logger)
+
+	// Record our PID so 'stratavore daemon upgrade' can restart us
+	pidFile := cfg.Daemon.PIDFile()
+	if err := writePIDFile(pidFile); err != nil {
+		logger.Warn("failed to write pid file", zap.String("path", pidFile), zap.Error(err))
+	} else {
+		defer os.Remove(pidFile)
+	}
+
 	logger.Info("stratavore daemon started successfully",
 		zap.Int("grpc_port", cfg.Daemon.Port_GRPC),
 		zap.Int("metrics_port", cfg.Docker.Prometheus.Port))
@@ -207,6 +410,14 @@ func run() error {
 	return nil
 }
 
+// writePIDFile writes the current process ID to path, creating its directory
+func writePIDFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
 func setupLogger(level, format string) (*zap.Logger, error) {
 	var zapLevel zapcore.Level
 	if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
@@ -246,7 +457,7 @@ func startReconciliationLoop(ctx context.Context, mgr *daemon.RunnerManager, int
 	}
 }
 
-func startMetricsUpdateLoop(ctx context.Context, metrics *observability.MetricsServer, mgr *daemon.RunnerManager, logger *zap.Logger) {
+func startMetricsUpdateLoop(ctx context.Context, metrics *observability.MetricsServer, mgr *daemon.RunnerManager, db *storage.PostgresClient, logger *zap.Logger) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
@@ -255,11 +466,67 @@ func startMetricsUpdateLoop(ctx context.Context, metrics *observability.MetricsS
 	for {
 		select {
 		case <-ticker.C:
-			runners := mgr.GetActiveRunners()
-			metrics.UpdateRunnerMetrics(runners)
+			metrics.UpdateLocalRunners(mgr.GetActiveRunners())
 			metrics.UpdateDaemonUptime(time.Since(startTime).Seconds())
+
+			if summary, err := db.GetRunnerMetricsSummary(ctx); err == nil {
+				metrics.UpdateRunnerMetrics(summary)
+			} else {
+				logger.Warn("runner metrics update failed", zap.Error(err))
+			}
+
+			budgets, err := db.GetCurrentBudgets(ctx, time.Now())
+			if err != nil {
+				logger.Warn("budget metrics update failed", zap.Error(err))
+				continue
+			}
+			metrics.UpdateBudgetMetrics(budgets)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startNodeHeartbeatLoop keeps this daemon's daemon_nodes row fresh so
+// other daemons don't mark it inactive
+func startNodeHeartbeatLoop(ctx context.Context, db *storage.PostgresClient, mgr *daemon.RunnerManager, nodeID string, logger *zap.Logger) {
+	ticker := time.NewTicker(nodeHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := db.UpdateDaemonHeartbeat(ctx, nodeID, len(mgr.GetActiveRunners())); err != nil {
+				logger.Warn("daemon node heartbeat failed", zap.Error(err))
+			}
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// warmCache pre-populates the cache with active projects and runners so the
+// first requests after a restart don't all fall through to the database
+func warmCache(ctx context.Context, db *storage.PostgresClient, cacheMgr *cache.Manager, logger *zap.Logger) {
+	start := time.Now()
+
+	projects, err := db.ListProjects(ctx, "active")
+	if err != nil {
+		logger.Warn("cache warm skipped: list projects failed", zap.Error(err))
+		return
+	}
+
+	runners, err := db.GetAllActiveRunners(ctx, storage.RunnerFilter{})
+	if err != nil {
+		logger.Warn("cache warm skipped: list runners failed", zap.Error(err))
+		return
+	}
+
+	cacheMgr.Warm(ctx, projects, runners)
+	cacheMgr.SetProjectList(ctx, "active", projects)
+
+	logger.Info("cache warm-up complete",
+		zap.Int("projects", len(projects)),
+		zap.Int("runners", len(runners)),
+		zap.Duration("duration", time.Since(start)))
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMaxAge is how long browsers may cache a preflight response, in seconds
+const corsMaxAge = 3600
+
+var (
+	corsAllowMethods  = strings.Join([]string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"}, ", ")
+	corsAllowHeaders  = strings.Join([]string{"Authorization", "Content-Type", "X-API-Key", RequestIDHeader}, ", ")
+	corsExposeHeaders = strings.Join([]string{RequestIDHeader, "X-RateLimit-Remaining", "Retry-After"}, ", ")
+)
+
+// CORSMiddleware lets browser clients served from allowedOrigins call the
+// API. Preflight requests are answered here without reaching next, so it
+// must wrap auth and rate limiting. An origin of "*" allows any origin and
+// is meant for development. With no allowed origins it is a no-op.
+func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAny := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		if o == "*" {
+			allowAny = true
+		}
+		allowed[strings.TrimSuffix(o, "/")] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				// Not a cross-origin browser request
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if !allowAny && !allowed[origin] {
+				if preflight {
+					WriteError(w, http.StatusForbidden, "origin not allowed")
+					return
+				}
+				// Without CORS headers the browser hides the response
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAny {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if preflight {
+				h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+				h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+				h.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	// Stands in for auth: anything reaching it is rejected
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+
+	request := func(handler http.Handler, method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/status", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	handler := CORSMiddleware([]string{"https://dash.example.com/"})(inner)
+
+	t.Run("preflight from allowed origin skips auth", func(t *testing.T) {
+		rec := request(handler, http.MethodOptions, "https://dash.example.com", true)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+		}
+		want := map[string]string{
+			"Access-Control-Allow-Origin": "https://dash.example.com",
+			"Access-Control-Max-Age":      "3600",
+		}
+		for name, value := range want {
+			if got := rec.Header().Get(name); got != value {
+				t.Errorf("%s = %q, want %q", name, got, value)
+			}
+		}
+		if rec.Header().Get("Access-Control-Allow-Headers") == "" {
+			t.Error("Access-Control-Allow-Headers not set")
+		}
+	})
+
+	t.Run("preflight from other origin is refused", func(t *testing.T) {
+		rec := request(handler, http.MethodOptions, "https://evil.example.com", true)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+		}
+	})
+
+	t.Run("actual request reaches auth with CORS headers", func(t *testing.T) {
+		rec := request(handler, http.MethodGet, "https://dash.example.com", false)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+			t.Errorf("Access-Control-Allow-Origin = %q", got)
+		}
+		if got := rec.Header().Get("Access-Control-Expose-Headers"); got == "" {
+			t.Error("Access-Control-Expose-Headers not set")
+		}
+	})
+
+	t.Run("wildcard allows any origin", func(t *testing.T) {
+		rec := request(CORSMiddleware([]string{"*"})(inner), http.MethodOptions, "http://localhost:5173", true)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusNoContent)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+		}
+	})
+
+	t.Run("disabled without origins", func(t *testing.T) {
+		rec := request(CORSMiddleware(nil)(inner), http.MethodOptions, "https://dash.example.com", true)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
@@ -61,7 +61,24 @@ func VerifyRequest(req *http.Request, secret string) error {
 	tsHeader := req.Header.Get("X-Stratavore-Timestamp")
 	sigHeader := req.Header.Get("X-Stratavore-Signature")
 
-	if tsHeader == "" || sigHeader == "" {
+	// Read body for verification without consuming it
+	var bodyBytes []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return fmt.Errorf("hmac: read body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	}
+
+	return verifySignature(secret, req.Method, req.URL.RequestURI(), tsHeader, sigHeader, bodyBytes)
+}
+
+// verifySignature checks the timestamp window and compares sig against the
+// expected signature for the given request components.
+func verifySignature(secret, method, path, tsHeader, sig string, body []byte) error {
+	if tsHeader == "" || sig == "" {
 		return fmt.Errorf("%w: missing HMAC headers", ErrUnauthorized)
 	}
 
@@ -75,18 +92,8 @@ func VerifyRequest(req *http.Request, secret string) error {
 		return fmt.Errorf("%w: timestamp outside replay-safe window (age=%s)", ErrUnauthorized, age)
 	}
 
-	// Read body for verification without consuming it
-	var bodyBytes []byte
-	if req.Body != nil && req.Body != http.NoBody {
-		bodyBytes, err = io.ReadAll(req.Body)
-		if err != nil {
-			return fmt.Errorf("hmac: read body: %w", err)
-		}
-		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
-	}
-
-	expected := computeSignature(secret, req.Method, req.URL.RequestURI(), tsHeader, bodyBytes)
-	if !hmac.Equal([]byte(sigHeader), []byte(expected)) {
+	expected := computeSignature(secret, method, path, tsHeader, body)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
 		return fmt.Errorf("%w: signature mismatch", ErrUnauthorized)
 	}
 	return nil
@@ -102,7 +109,7 @@ func HMACMiddleware(secret string) func(http.Handler) http.Handler {
 				return
 			}
 			if err := VerifyRequest(r, secret); err != nil {
-				http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusUnauthorized)
+				WriteError(w, http.StatusUnauthorized, err.Error())
 				return
 			}
 			next.ServeHTTP(w, r)
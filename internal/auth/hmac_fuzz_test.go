@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// FuzzVerifyRequest feeds arbitrary requests to VerifyRequest. An empty sig
+// input means "sign the request correctly". VerifyRequest must never panic,
+// must leave the body readable, must only fail with ErrUnauthorized, and must
+// accept correctly signed requests whose timestamp is inside the
+// replay-safe window.
+func FuzzVerifyRequest(f *testing.F) {
+	window := int64(ReplaySafeWindow / time.Second)
+
+	f.Add("POST", "/api/v1/runners/launch", []byte(`{"project_name":"demo"}`), int64(0), "")
+	f.Add("GET", "/api/v1/status", []byte(nil), int64(0), "")
+	f.Add("POST", "/api/v1/runners/launch", []byte(`{"project_name":"demo"}`), int64(0), "deadbeef")
+	f.Add("POST", "/api/v1/runners/stop", []byte(`{}`), window+60, "")
+	f.Add("POST", "/api/v1/runners/stop", []byte(`{}`), -window-60, "")
+	f.Add("GET", "/", []byte(nil), int64(1<<40), "")
+	f.Add("", "", []byte{0xff, 0x00}, int64(-1), "not-hex")
+
+	f.Fuzz(func(t *testing.T, method, path string, body []byte, tsOffset int64, sig string) {
+		// Keep now+tsOffset clear of int64 overflow
+		tsOffset %= 1 << 40
+		ts := strconv.FormatInt(time.Now().Unix()+tsOffset, 10)
+
+		req := &http.Request{
+			Method: method,
+			URL:    &url.URL{Path: path},
+			Header: make(http.Header),
+			Body:   http.NoBody,
+		}
+		if len(body) > 0 {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		signed := sig == ""
+		if signed {
+			sig = computeSignature(fuzzSecret, method, req.URL.RequestURI(), ts, body)
+		}
+		req.Header.Set("X-Stratavore-Timestamp", ts)
+		req.Header.Set("X-Stratavore-Signature", sig)
+
+		err := VerifyRequest(req, fuzzSecret)
+		if err != nil && !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("VerifyRequest returned unexpected error type: %v", err)
+		}
+
+		// A few seconds of slack either side of the window edge
+		inWindow := tsOffset > -window+5 && tsOffset < window-5
+		outOfWindow := tsOffset < -window-5 || tsOffset > window+5
+		if signed && inWindow && err != nil {
+			t.Fatalf("VerifyRequest rejected a correctly signed request: %v", err)
+		}
+		if outOfWindow && err == nil {
+			t.Fatalf("VerifyRequest accepted a timestamp %ds from now", tsOffset)
+		}
+
+		rest, readErr := io.ReadAll(req.Body)
+		if readErr != nil {
+			t.Fatalf("body unreadable after VerifyRequest: %v", readErr)
+		}
+		if !bytes.Equal(rest, body) {
+			t.Fatalf("VerifyRequest altered the body: got %q, want %q", rest, body)
+		}
+	})
+}
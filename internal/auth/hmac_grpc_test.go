@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type logsRequest struct {
+	RunnerID string
+}
+
+// requestStream is a server stream whose client sent req with md
+type requestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req logsRequest
+}
+
+func (s *requestStream) Context() context.Context { return s.ctx }
+
+func (s *requestStream) RecvMsg(m interface{}) error {
+	*m.(*logsRequest) = s.req
+	return nil
+}
+
+func TestHMACStreamSignatureCoversRequest(t *testing.T) {
+	const secret = "test-secret"
+	const method = "/stratavore.StratavoreService/StreamLogs"
+
+	body, _ := json.Marshal(logsRequest{RunnerID: "runner-a"})
+	signed := signedContext(context.Background(), secret, method, body)
+	md, _ := metadata.FromOutgoingContext(signed)
+	incoming := metadata.NewIncomingContext(context.Background(), md)
+
+	interceptor := HMACStreamServerInterceptor(secret)
+	info := &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		var req logsRequest
+		return ss.RecvMsg(&req)
+	}
+
+	if err := interceptor(nil, &requestStream{ctx: incoming, req: logsRequest{RunnerID: "runner-a"}}, info, handler); err != nil {
+		t.Errorf("signed request: %v", err)
+	}
+
+	err := interceptor(nil, &requestStream{ctx: incoming, req: logsRequest{RunnerID: "runner-b"}}, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("replayed signature for another runner: got %v, want Unauthenticated", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
@@ -24,8 +25,13 @@ var ErrUnauthorized = errors.New("unauthorized")
 // ErrTokenExpired is returned when a JWT has passed its expiry time.
 var ErrTokenExpired = errors.New("token expired")
 
+// ErrRotationTooLate is returned when rotating a token in the last part of
+// its validity period.
+var ErrRotationTooLate = errors.New("token too close to expiry to rotate")
+
 // Claims represents the payload embedded in a Stratavore JWT.
 type Claims struct {
+	ID        string    `json:"jti,omitempty"` // set on revocable API tokens
 	Subject   string    `json:"sub"`
 	IssuedAt  int64     `json:"iat"`
 	ExpiresAt int64     `json:"exp"`
@@ -52,6 +58,17 @@ func (c *Claims) HasScope(scope string) bool {
 	return false
 }
 
+// CanManageTokensOf reports whether the claims may list and revoke API
+// tokens issued to subject. Admins may manage every subject's tokens;
+// everyone else only their own. As for the admin rate limit, AdminScope
+// must be listed explicitly.
+func (c *Claims) CanManageTokensOf(subject string) bool {
+	if slices.Contains(c.Scope, AdminScope) {
+		return true
+	}
+	return c.Subject != "" && c.Subject == subject
+}
+
 // ---------------------------------------------------------------------------
 // Validator
 // ---------------------------------------------------------------------------
@@ -60,8 +77,9 @@ func (c *Claims) HasScope(scope string) bool {
 // It uses a simple HS256-style HMAC scheme over JSON payloads (not a full
 // JWT library dependency) so that the binary stays light.
 type Validator struct {
-	secret  []byte
-	enabled bool
+	secret      []byte
+	enabled     bool
+	revocations RevocationList
 }
 
 // NewValidator creates a Validator using the provided HMAC secret.
@@ -73,6 +91,12 @@ func NewValidator(secret string) *Validator {
 	}
 }
 
+// SetRevocationList makes Validate reject tokens whose ID is on list.
+// Tokens without an ID cannot be revoked.
+func (v *Validator) SetRevocationList(list RevocationList) {
+	v.revocations = list
+}
+
 // Enabled reports whether authentication is enforced.
 func (v *Validator) Enabled() bool { return v.enabled }
 
@@ -128,6 +152,27 @@ func (v *Validator) Validate(token string) (*Claims, error) {
 		return nil, err
 	}
 
+	if v.revocations != nil {
+		// The token's own ID and its rotation entry are checked in one
+		// lookup, so a shared list costs one round trip per request
+		ids := []string{rotatedTokenID(sig)}
+		if claims.ID != "" {
+			ids = append(ids, claims.ID)
+		}
+		revoked, err := v.revocations.IsRevoked(ids...)
+
+		// Tokens with an ID are long-lived API tokens, so one revoked on
+		// another daemon must not be accepted for as long as the shared
+		// list is unreachable: they fail closed. Short-lived session tokens
+		// fail open, relying on this daemon's own revocations.
+		if err != nil && claims.ID != "" {
+			return nil, fmt.Errorf("%w: cannot check revocation: %v", ErrUnauthorized, err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("%w: token revoked or rotated", ErrUnauthorized)
+		}
+	}
+
 	return &claims, nil
 }
 
@@ -137,10 +182,86 @@ func (v *Validator) sign(payload string) string {
 	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
 }
 
+// ---------------------------------------------------------------------------
+// Rotator
+// ---------------------------------------------------------------------------
+
+// rotationCutoffDivisor sets how late a token can be rotated: not in the
+// last 1/rotationCutoffDivisor of its validity period. Otherwise a client
+// could keep a token alive forever by rotating it just before it expires.
+const rotationCutoffDivisor = 10
+
+// Rotator exchanges a valid token for a new one with the same subject,
+// scope and ID and a refreshed expiry. The old token is revoked for the
+// rest of its validity.
+type Rotator struct {
+	validator   *Validator
+	revocations RevocationList
+}
+
+// NewRotator creates a Rotator that issues tokens with v and records
+// rotated tokens on revocations, which v should also check.
+func NewRotator(v *Validator, revocations RevocationList) *Rotator {
+	return &Rotator{validator: v, revocations: revocations}
+}
+
+// Rotate validates token and returns its replacement, valid for as long as
+// token originally was. If revoking the old token fails, the new token is
+// still returned along with the error: revocation lists backed by Redis
+// keep the revocation locally even when sharing it fails.
+func (r *Rotator) Rotate(ctx context.Context, token string) (string, *Claims, error) {
+	if !r.validator.enabled {
+		return "", nil, errors.New("auth: cannot rotate token: no secret configured")
+	}
+
+	claims, err := r.validator.Validate(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if claims.ExpiresAt <= claims.IssuedAt {
+		return "", nil, fmt.Errorf("%w: token has no validity period", ErrUnauthorized)
+	}
+	// A replacement issued in the same second would have the same claims,
+	// and so the same signature as the token being revoked
+	if claims.IssuedAt >= time.Now().Unix() {
+		return "", nil, errors.New("auth: token was issued too recently to rotate")
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	ttl := expiresAt.Sub(time.Unix(claims.IssuedAt, 0))
+	if time.Until(expiresAt) < ttl/rotationCutoffDivisor {
+		return "", nil, ErrRotationTooLate
+	}
+
+	next := *claims
+	next.ExpiresAt = time.Now().Add(ttl).Unix()
+	newToken, err := r.validator.Generate(next)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, sig, _ := strings.Cut(token, ".")
+	if err := r.revocations.Revoke(ctx, rotatedTokenID(sig), expiresAt); err != nil {
+		return newToken, &next, fmt.Errorf("auth: revoke rotated token: %w", err)
+	}
+	return newToken, &next, nil
+}
+
+// rotatedTokenID is the revocation list entry of a rotated token. Tokens
+// are told apart by their signature, as rotation keeps the claims' ID.
+func rotatedTokenID(sig string) string {
+	return "rotated:" + sig
+}
+
 // ---------------------------------------------------------------------------
 // HTTP Middleware
 // ---------------------------------------------------------------------------
 
+// DeviceAuthPathPrefix is the HTTP path prefix of the device flow endpoints,
+// which must be reachable without a token.
+const DeviceAuthPathPrefix = "/api/v1/auth/device/"
+
 type contextKey string
 
 const claimsContextKey contextKey = "auth_claims"
@@ -155,28 +276,27 @@ func Middleware(v *Validator) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Allow health + metrics endpoints unauthenticated
-			if r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/metrics") {
+			// Allow health + metrics endpoints unauthenticated, and the
+			// device flow endpoints used to obtain a token in the first place
+			if r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/metrics") ||
+				strings.HasPrefix(r.URL.Path, DeviceAuthPathPrefix) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			token := extractBearerToken(r)
+			token := TokenFromRequest(r)
 			if token == "" {
-				// Also accept X-API-Key header for CLI convenience
-				token = r.Header.Get("X-API-Key")
-			}
-			if token == "" {
-				http.Error(w, `{"error":"missing authorization"}`, http.StatusUnauthorized)
+				WriteError(w, http.StatusUnauthorized, "missing authorization")
 				return
 			}
 
 			claims, err := v.Validate(token)
 			if err != nil {
-				http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusUnauthorized)
+				WriteError(w, http.StatusUnauthorized, err.Error())
 				return
 			}
 
+			setRequestUser(r.Context(), claims.Subject)
 			ctx := context.WithValue(r.Context(), claimsContextKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -189,6 +309,15 @@ func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
 	return c, ok
 }
 
+// TokenFromRequest returns the request's bearer token, or its X-API-Key
+// header, which is also accepted for CLI convenience.
+func TokenFromRequest(r *http.Request) string {
+	if token := extractBearerToken(r); token != "" {
+		return token
+	}
+	return r.Header.Get("X-API-Key")
+}
+
 func extractBearerToken(r *http.Request) string {
 	auth := r.Header.Get("Authorization")
 	if strings.HasPrefix(auth, "Bearer ") {
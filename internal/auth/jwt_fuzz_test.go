@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fuzzSecret = "fuzz-secret"
+
+// FuzzValidatorValidate feeds arbitrary token strings to Validate. It must
+// never panic, must only fail with ErrUnauthorized or ErrTokenExpired, and
+// must accept any correctly signed payload that holds unexpired claims.
+func FuzzValidatorValidate(f *testing.F) {
+	v := NewValidator(fuzzSecret)
+
+	valid, err := v.Generate(Claims{Subject: "fuzz", Scope: []string{"runners:read"}})
+	if err != nil {
+		f.Fatal(err)
+	}
+	expired, err := v.Generate(Claims{Subject: "fuzz", ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	// Flip one bit in the first signature character
+	flipped := []byte(valid)
+	dot := strings.IndexByte(valid, '.')
+	flipped[dot+1] ^= 0x01
+
+	f.Add(valid)
+	f.Add(expired)
+	f.Add(string(flipped))
+	f.Add("")
+	f.Add(".")
+	f.Add("no-dot-at-all")
+	f.Add("!!!not-base64!!!." + v.sign("!!!not-base64!!!"))
+	f.Add("a.b.c")
+	f.Add(base64.RawURLEncoding.EncodeToString([]byte("null")) + ".sig")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		claims, err := v.Validate(token)
+		if err != nil {
+			if claims != nil {
+				t.Fatalf("Validate(%q) returned claims alongside error %v", token, err)
+			}
+			if !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrTokenExpired) {
+				t.Fatalf("Validate(%q) returned unexpected error type: %v", token, err)
+			}
+		} else if claims == nil {
+			t.Fatalf("Validate(%q) returned nil claims without error", token)
+		}
+
+		// Re-sign the payload part. If it decodes to claims that stay valid
+		// for the duration of the test, the signed token must be accepted.
+		payload, _, _ := strings.Cut(token, ".")
+		raw, err := base64.RawURLEncoding.DecodeString(payload)
+		if err != nil {
+			return
+		}
+		var want Claims
+		if err := json.Unmarshal(raw, &want); err != nil {
+			return
+		}
+		if want.ExpiresAt != 0 && want.ExpiresAt < time.Now().Add(time.Minute).Unix() {
+			return
+		}
+
+		got, err := v.Validate(payload + "." + v.sign(payload))
+		if err != nil {
+			t.Fatalf("Validate rejected correctly signed payload %q: %v", raw, err)
+		}
+		if got.Subject != want.Subject || got.ExpiresAt != want.ExpiresAt {
+			t.Fatalf("Validate returned %+v, want %+v", got, want)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// signClaims signs claims as-is, unlike Generate, which stamps IssuedAt
+// with the current time
+func signClaims(t *testing.T, v *Validator, claims Claims) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString(payload)
+	return b64 + "." + v.sign(b64)
+}
+
+func TestRotatorRotate(t *testing.T) {
+	v := NewValidator("test-secret")
+	revocations := NewMemoryRevocationList()
+	v.SetRevocationList(revocations)
+	r := NewRotator(v, revocations)
+	ctx := context.Background()
+
+	now := time.Now()
+	old := signClaims(t, v, Claims{
+		ID:        "token-1",
+		Subject:   "ci",
+		Scope:     []string{"runners:read"},
+		IssuedAt:  now.Add(-time.Hour).Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	})
+
+	rotated, claims, err := r.Rotate(ctx, old)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if claims.ID != "token-1" || claims.Subject != "ci" || len(claims.Scope) != 1 || claims.Scope[0] != "runners:read" {
+		t.Errorf("claims changed: %+v", claims)
+	}
+	if want := now.Add(2 * time.Hour).Unix(); claims.ExpiresAt < want-1 || claims.ExpiresAt > want+1 {
+		t.Errorf("ExpiresAt = %d, want about %d", claims.ExpiresAt, want)
+	}
+
+	if _, err := v.Validate(old); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("old token: got %v, want ErrUnauthorized", err)
+	}
+	if _, err := v.Validate(rotated); err != nil {
+		t.Errorf("new token: %v", err)
+	}
+
+	t.Run("too close to expiry", func(t *testing.T) {
+		late := signClaims(t, v, Claims{
+			Subject:   "ci",
+			IssuedAt:  now.Add(-95 * time.Minute).Unix(),
+			ExpiresAt: now.Add(5 * time.Minute).Unix(),
+		})
+		if _, _, err := r.Rotate(ctx, late); !errors.Is(err, ErrRotationTooLate) {
+			t.Errorf("got %v, want ErrRotationTooLate", err)
+		}
+		if _, err := v.Validate(late); err != nil {
+			t.Errorf("refused rotation revoked the token: %v", err)
+		}
+	})
+}
+
+func TestClaimsCanManageTokensOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  Claims
+		subject string
+		want    bool
+	}{
+		{name: "own token", claims: Claims{Subject: "ci", Scope: []string{"runners:read"}}, subject: "ci", want: true},
+		{name: "other subject", claims: Claims{Subject: "ci", Scope: []string{"runners:read"}}, subject: "alice", want: false},
+		{name: "wildcard is not admin", claims: Claims{Subject: "ci", Scope: []string{"*"}}, subject: "alice", want: false},
+		{name: "admin", claims: Claims{Subject: "ops", Scope: []string{AdminScope}}, subject: "alice", want: true},
+		{name: "no subject", claims: Claims{Scope: []string{"runners:read"}}, subject: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.claims.CanManageTokensOf(tt.subject); got != tt.want {
+				t.Errorf("CanManageTokensOf(%q) = %v, want %v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 )
@@ -83,17 +84,34 @@ func (rl *RateLimiter) evict(now time.Time) {
 	}
 }
 
-// Middleware returns an HTTP middleware that enforces the rate limit.
-// The client key is derived from the X-Forwarded-For or RemoteAddr header.
-func RateLimitMiddleware(rl *RateLimiter) func(http.Handler) http.Handler {
+// AdminScope marks admin tokens, which get the admin rate limit and may
+// manage every subject's API tokens. It must be listed explicitly; the "*"
+// wildcard does not count.
+const AdminScope = "admin"
+
+// RateLimitMiddleware returns an HTTP middleware that enforces the rate
+// limit. It must run inside Middleware: authenticated requests are limited
+// per token subject, so one client can't use up a shared IP's budget, and
+// requests without claims fall back to the X-Forwarded-For or RemoteAddr
+// address. Admin tokens are limited by admin instead of rl; a nil admin
+// limits them like everyone else.
+func RateLimitMiddleware(rl, admin Limiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter := rl
 			key := clientKey(r)
-			ok, remaining := rl.Allow(key)
+			if claims, ok := ClaimsFromContext(r.Context()); ok {
+				key = "sub:" + claims.Subject
+				if admin != nil && slices.Contains(claims.Scope, AdminScope) {
+					limiter = admin
+				}
+			}
+
+			ok, remaining := limiter.Allow(key)
 			w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%d", remaining))
 			if !ok {
 				w.Header().Set("Retry-After", "60")
-				http.Error(w, `{"error":"rate limit exceeded"}`, http.StatusTooManyRequests)
+				WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// redisRateLimitTimeout bounds each Redis round trip so a slow Redis falls
+// back to the in-memory limiter instead of stalling requests
+const redisRateLimitTimeout = 250 * time.Millisecond
+
+// Limiter decides whether a client may make another request
+type Limiter interface {
+	Allow(key string) (bool, int)
+}
+
+// RedisRateLimiter is a fixed-window counter shared by every daemon using the
+// same Redis, so multi-daemon deployments enforce one limit per client.
+// Requests fall back to an in-memory limiter while Redis is unavailable.
+// The window has no burst allowance: a burst setting only applies to the
+// fallback.
+type RedisRateLimiter struct {
+	client   *redis.Client
+	name     string
+	limit    int
+	interval time.Duration
+	fallback *RateLimiter
+	logger   *zap.Logger
+
+	fallbackOnce sync.Once
+}
+
+// NewRedisRateLimiter creates a limiter allowing limit requests per client
+// per interval. name keeps its counters apart from other limiters sharing
+// the Redis. fallback is used whenever a Redis call fails; the first such
+// failure is logged to logger.
+func NewRedisRateLimiter(client *redis.Client, name string, limit int, interval time.Duration, fallback *RateLimiter, logger *zap.Logger) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client:   client,
+		name:     name,
+		limit:    limit,
+		interval: interval,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+// Allow increments the client's counter for the current window and reports
+// whether it is still within the limit, along with the requests remaining.
+func (rl *RedisRateLimiter) Allow(key string) (bool, int) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisRateLimitTimeout)
+	defer cancel()
+
+	windowStart := time.Now().Truncate(rl.interval).Unix()
+	redisKey := fmt.Sprintf("rl:%s:%s:%d", rl.name, key, windowStart)
+
+	pipe := rl.client.Pipeline()
+	incr := pipe.Incr(ctx, redisKey)
+	pipe.Expire(ctx, redisKey, rl.interval)
+	if _, err := pipe.Exec(ctx); err != nil {
+		rl.fallbackOnce.Do(func() {
+			rl.logger.Warn("Redis rate limiting failed; falling back to the in-memory limiter",
+				zap.String("limit", rl.name),
+				zap.Error(err))
+		})
+		return rl.fallback.Allow(key)
+	}
+
+	count := int(incr.Val())
+	if count > rl.limit {
+		return false, 0
+	}
+	return true, rl.limit - count
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+)
+
+// WriteError writes message as a JSON error envelope with the given status
+// code, so middleware rejections look the same to clients as handler errors.
+func WriteError(w http.ResponseWriter, status int, message string) {
+	env := api.NewResponse[any](nil, w.Header().Get(RequestIDHeader))
+	env.Error = message
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(env)
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// redisRevocationTimeout bounds each Redis round trip made while validating
+// a token
+const redisRevocationTimeout = 250 * time.Millisecond
+
+// RevocationList records revoked token IDs until the tokens would have
+// expired anyway. IsRevoked reports whether any of the given IDs has been
+// revoked, checking them all in one lookup. It returns an error when the
+// list could not be fully checked; its bool then only reflects what could
+// be.
+type RevocationList interface {
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(tokenIDs ...string) (bool, error)
+}
+
+// MemoryRevocationList is a RevocationList local to one daemon
+type MemoryRevocationList struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time // token ID -> token expiry
+}
+
+// NewMemoryRevocationList creates an empty in-memory revocation list
+func NewMemoryRevocationList() *MemoryRevocationList {
+	return &MemoryRevocationList{revoked: make(map[string]time.Time)}
+}
+
+// Revoke adds tokenID to the list, dropping entries for tokens that have
+// since expired
+func (l *MemoryRevocationList) Revoke(_ context.Context, tokenID string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for id, exp := range l.revoked {
+		if now.After(exp) {
+			delete(l.revoked, id)
+		}
+	}
+	l.revoked[tokenID] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether any of tokenIDs has been revoked. It never
+// fails.
+func (l *MemoryRevocationList) IsRevoked(tokenIDs ...string) (bool, error) {
+	return l.isRevoked(tokenIDs...), nil
+}
+
+func (l *MemoryRevocationList) isRevoked(tokenIDs ...string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range tokenIDs {
+		if _, ok := l.revoked[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RedisRevocationList shares revocations between every daemon using the
+// same Redis. Each revocation is also kept in memory, so this daemon's own
+// revocations hold while Redis is unavailable; those made by other daemons
+// can't be seen then, and IsRevoked reports the failure.
+type RedisRevocationList struct {
+	client   *redis.Client
+	fallback *MemoryRevocationList
+	logger   *zap.Logger
+
+	failures atomic.Int64 // failed Redis lookups since startup
+	failing  atomic.Bool  // the last lookup failed
+}
+
+// NewRedisRevocationList creates a revocation list stored in Redis, backed
+// by fallback for this daemon's own revocations. The first failed lookup of
+// each Redis outage, and the recovery from it, are logged to logger.
+func NewRedisRevocationList(client *redis.Client, fallback *MemoryRevocationList, logger *zap.Logger) *RedisRevocationList {
+	return &RedisRevocationList{
+		client:   client,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+// Revoke stores tokenID in Redis with a TTL ending at the token's expiry
+func (l *RedisRevocationList) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	l.fallback.Revoke(ctx, tokenID, expiresAt)
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // already unusable
+	}
+	return l.client.Set(ctx, revocationKey(tokenID), 1, ttl).Err()
+}
+
+// IsRevoked reports whether any of tokenIDs has been revoked by any daemon,
+// with a single EXISTS round trip. If Redis can't be reached it returns
+// false and the error unless this daemon revoked one of them itself.
+func (l *RedisRevocationList) IsRevoked(tokenIDs ...string) (bool, error) {
+	if len(tokenIDs) == 0 {
+		return false, nil
+	}
+	if l.fallback.isRevoked(tokenIDs...) {
+		return true, nil
+	}
+
+	keys := make([]string, len(tokenIDs))
+	for i, id := range tokenIDs {
+		keys[i] = revocationKey(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisRevocationTimeout)
+	defer cancel()
+
+	n, err := l.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		failures := l.failures.Add(1)
+		if l.failing.CompareAndSwap(false, true) {
+			l.logger.Warn("Redis revocation check failed; only this daemon's revocations are enforced",
+				zap.Int64("failures", failures),
+				zap.Error(err))
+		}
+		return false, err
+	}
+	if l.failing.CompareAndSwap(true, false) {
+		l.logger.Info("Redis revocation checks recovered",
+			zap.Int64("failures", l.failures.Load()))
+	}
+	return n > 0, nil
+}
+
+func revocationKey(tokenID string) string {
+	return "auth:revoked:" + tokenID
+}
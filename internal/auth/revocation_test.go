@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateRejectsRevokedTokens(t *testing.T) {
+	v := NewValidator("test-secret")
+	revocations := NewMemoryRevocationList()
+	v.SetRevocationList(revocations)
+
+	expiresAt := time.Now().Add(time.Hour)
+	revocable, err := v.Generate(Claims{ID: "token-1", Subject: "ci", ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := v.Generate(Claims{Subject: "device:ABCD-EFGH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Validate(revocable); err != nil {
+		t.Fatalf("before revocation: %v", err)
+	}
+
+	if err := revocations.Revoke(context.Background(), "token-1", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Validate(revocable); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("revoked token: got %v, want ErrUnauthorized", err)
+	}
+	if _, err := v.Validate(session); err != nil {
+		t.Errorf("token without an ID: %v", err)
+	}
+}
+
+func TestMemoryRevocationListDropsExpiredEntries(t *testing.T) {
+	l := NewMemoryRevocationList()
+	ctx := context.Background()
+
+	l.Revoke(ctx, "old", time.Now().Add(-time.Minute))
+	l.Revoke(ctx, "new", time.Now().Add(time.Hour))
+
+	if revoked, _ := l.IsRevoked("old"); revoked {
+		t.Error("expired entry was kept")
+	}
+	if revoked, _ := l.IsRevoked("new"); !revoked {
+		t.Error("unexpired entry was dropped")
+	}
+}
+
+// unreachableRevocationList fails every lookup, like a Redis list during an
+// outage
+type unreachableRevocationList struct{}
+
+func (unreachableRevocationList) Revoke(context.Context, string, time.Time) error { return nil }
+func (unreachableRevocationList) IsRevoked(...string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestValidateFailsClosedForAPITokens(t *testing.T) {
+	v := NewValidator("test-secret")
+	v.SetRevocationList(unreachableRevocationList{})
+
+	apiToken, err := v.Generate(Claims{ID: "token-1", Subject: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := v.Generate(Claims{Subject: "device:ABCD-EFGH"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Validate(apiToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("API token: got %v, want ErrUnauthorized", err)
+	}
+	if _, err := v.Validate(session); err != nil {
+		t.Errorf("token without an ID: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package budget
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,19 +12,27 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrBudgetExceeded is returned when a launch would exceed a token budget
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// MinLaunchTokens is the remaining budget a runner launch requires, so that
+// launches are refused once a budget is exactly exhausted
+const MinLaunchTokens int64 = 1
+
 // Manager handles token budget tracking and enforcement
 type Manager struct {
-	db       *storage.PostgresClient
-	notifier *notifications.Client
-	logger   *zap.Logger
+	db         *storage.PostgresClient
+	dispatcher *notifications.Dispatcher
+	logger     *zap.Logger
 }
 
-// NewManager creates a new budget manager
-func NewManager(db *storage.PostgresClient, notifier *notifications.Client, logger *zap.Logger) *Manager {
+// NewManager creates a new budget manager. A nil dispatcher disables
+// budget warning notifications.
+func NewManager(db *storage.PostgresClient, dispatcher *notifications.Dispatcher, logger *zap.Logger) *Manager {
 	return &Manager{
-		db:       db,
-		notifier: notifier,
-		logger:   logger,
+		db:         db,
+		dispatcher: dispatcher,
+		logger:     logger,
 	}
 }
 
@@ -70,15 +79,25 @@ func (m *Manager) RecordUsage(ctx context.Context, scope, scopeID string, tokens
 
 	percent := int((float64(budget.UsedTokens) / float64(budget.LimitTokens)) * 100)
 
+	// Global budgets only reach global routes
+	projectName := ""
+	if scope == "project" {
+		projectName = scopeID
+	}
+	warning := map[string]interface{}{
+		"scope":   fmt.Sprintf("%s:%s", scope, scopeID),
+		"percent": percent,
+	}
+
 	// Send notifications at thresholds
-	if percent >= 90 && m.notifier != nil {
-		m.notifier.TokenBudgetWarning(fmt.Sprintf("%s:%s", scope, scopeID), percent)
+	if percent >= 90 {
+		m.dispatcher.Notify(projectName, notifications.EventBudgetWarning, warning)
 		m.logger.Warn("token budget critical",
 			zap.String("scope", scope),
 			zap.String("scope_id", scopeID),
 			zap.Int("percent", percent))
-	} else if percent >= 75 && m.notifier != nil {
-		m.notifier.TokenBudgetWarning(fmt.Sprintf("%s:%s", scope, scopeID), percent)
+	} else if percent >= 75 {
+		m.dispatcher.Notify(projectName, notifications.EventBudgetWarning, warning)
 		m.logger.Warn("token budget warning",
 			zap.String("scope", scope),
 			zap.String("scope_id", scopeID),
@@ -104,6 +123,32 @@ func (m *Manager) CreateBudget(ctx context.Context, budget *types.TokenBudget) e
 	return nil
 }
 
+// PeriodBounds returns the budget period of the given granularity (hourly,
+// daily, weekly or monthly) that contains now, in UTC. Weeks start on Monday.
+func PeriodBounds(granularity string, now time.Time) (start, end time.Time, err error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch granularity {
+	case "hourly":
+		start = now.Truncate(time.Hour)
+		end = start.Add(time.Hour)
+	case "daily":
+		start = day
+		end = start.Add(24 * time.Hour)
+	case "weekly":
+		start = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		end = start.Add(7 * 24 * time.Hour)
+	case "monthly":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unsupported budget period: %s", granularity)
+	}
+
+	return start, end, nil
+}
+
 // RolloverBudgets rolls over expired budgets to new period
 func (m *Manager) RolloverBudgets(ctx context.Context) error {
 	now := time.Now()
@@ -114,37 +159,11 @@ func (m *Manager) RolloverBudgets(ctx context.Context) error {
 	}
 
 	for _, budget := range budgets {
-		// Calculate new period
-		var newStart, newEnd time.Time
-
-		switch budget.PeriodGranularity {
-		case "hourly":
-			newStart = budget.PeriodEnd
-			newEnd = newStart.Add(time.Hour)
-		case "daily":
-			newStart = budget.PeriodEnd
-			newEnd = newStart.Add(24 * time.Hour)
-		case "weekly":
-			newStart = budget.PeriodEnd
-			newEnd = newStart.Add(7 * 24 * time.Hour)
-		case "monthly":
-			newStart = budget.PeriodEnd
-			newEnd = newStart.AddDate(0, 1, 0)
-		default:
+		newBudget, err := nextPeriod(budget)
+		if err != nil {
 			continue
 		}
 
-		// Create new budget period
-		newBudget := &types.TokenBudget{
-			Scope:             budget.Scope,
-			ScopeID:           budget.ScopeID,
-			LimitTokens:       budget.LimitTokens,
-			UsedTokens:        0,
-			PeriodGranularity: budget.PeriodGranularity,
-			PeriodStart:       newStart,
-			PeriodEnd:         newEnd,
-		}
-
 		err = m.db.CreateTokenBudget(ctx, newBudget)
 		if err != nil {
 			m.logger.Error("failed to rollover budget",
@@ -157,13 +176,58 @@ func (m *Manager) RolloverBudgets(ctx context.Context) error {
 		m.logger.Info("budget rolled over",
 			zap.String("scope", budget.Scope),
 			zap.String("scope_id", budget.ScopeID),
-			zap.Time("new_start", newStart),
-			zap.Time("new_end", newEnd))
+			zap.Time("new_start", newBudget.PeriodStart),
+			zap.Time("new_end", newBudget.PeriodEnd))
 	}
 
 	return nil
 }
 
+// addMonth returns t one calendar month later, on the same day of the month
+// or the last day of the next month if that is shorter. AddDate would carry
+// a January 31 start over into March.
+func addMonth(t time.Time) time.Time {
+	year, month, day := t.Date()
+	lastDay := time.Date(year, month+2, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+1, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// nextPeriod returns the budget period that follows budget: it starts when
+// budget ends, lasts one granularity unit, and has the same limit with no
+// usage. Period arithmetic is done in UTC, as in PeriodBounds, so that
+// months don't shift when the database hands back times in a local zone
+// with DST.
+func nextPeriod(budget *types.TokenBudget) (*types.TokenBudget, error) {
+	newStart := budget.PeriodEnd.UTC()
+
+	var newEnd time.Time
+	switch budget.PeriodGranularity {
+	case "hourly":
+		newEnd = newStart.Add(time.Hour)
+	case "daily":
+		newEnd = newStart.Add(24 * time.Hour)
+	case "weekly":
+		newEnd = newStart.Add(7 * 24 * time.Hour)
+	case "monthly":
+		newEnd = addMonth(newStart)
+	default:
+		return nil, fmt.Errorf("unsupported budget period: %s", budget.PeriodGranularity)
+	}
+
+	return &types.TokenBudget{
+		Scope:             budget.Scope,
+		ScopeID:           budget.ScopeID,
+		LimitTokens:       budget.LimitTokens,
+		UsedTokens:        0,
+		PeriodGranularity: budget.PeriodGranularity,
+		PeriodStart:       newStart,
+		PeriodEnd:         newEnd,
+	}, nil
+}
+
 // GetBudgetStatus returns current budget status
 func (m *Manager) GetBudgetStatus(ctx context.Context, scope, scopeID string) (*BudgetStatus, error) {
 	budget, err := m.db.GetTokenBudget(ctx, scope, scopeID)
@@ -204,6 +268,31 @@ func (m *Manager) GetBudgetStatus(ctx context.Context, scope, scopeID string) (*
 	}, nil
 }
 
+// RemainingTokens returns the smallest remaining allowance across the global
+// and project budgets. unlimited is true when neither budget is configured.
+func (m *Manager) RemainingTokens(ctx context.Context, projectName string) (remaining int64, unlimited bool, err error) {
+	unlimited = true
+
+	for _, scope := range []struct{ scope, id string }{
+		{"global", ""},
+		{"project", projectName},
+	} {
+		status, err := m.GetBudgetStatus(ctx, scope.scope, scope.id)
+		if err != nil {
+			return 0, false, err
+		}
+		if !status.HasBudget {
+			continue
+		}
+		if unlimited || status.RemainingTokens < remaining {
+			remaining = status.RemainingTokens
+		}
+		unlimited = false
+	}
+
+	return remaining, unlimited, nil
+}
+
 // BudgetStatus represents current budget state
 type BudgetStatus struct {
 	Scope           string
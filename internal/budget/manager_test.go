@@ -0,0 +1,200 @@
+package budget
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+	_ "time/tzdata" // zones below must load on hosts without zoneinfo
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+)
+
+var rolloverGranularities = []string{"hourly", "daily", "weekly", "monthly"}
+
+// rolloverZones are the zones period times may come back from the database
+// in: DST in either hemisphere, a 30-minute DST shift, and a fractional
+// offset without DST
+var rolloverZones = []string{
+	"UTC",
+	"America/New_York",
+	"Europe/London",
+	"Australia/Sydney",
+	"Australia/Lord_Howe",
+	"Asia/Kolkata",
+}
+
+// rolloverCase is an expired budget generated by testing/quick. Aligned
+// budgets have the period PeriodBounds would have created; the rest have
+// arbitrary bounds, as left behind by a manual insert or an older release.
+type rolloverCase struct {
+	Budget  types.TokenBudget
+	Aligned bool
+}
+
+func (rolloverCase) Generate(r *rand.Rand, size int) reflect.Value {
+	loc, err := time.LoadLocation(rolloverZones[r.Intn(len(rolloverZones))])
+	if err != nil {
+		panic(err)
+	}
+
+	// 1970 to 2100 covers leap years, the 2000 leap century and the 2100
+	// non-leap century
+	instant := time.Unix(r.Int63n(4102444800), 0)
+
+	c := rolloverCase{
+		Budget: types.TokenBudget{
+			Scope:             []string{"global", "project"}[r.Intn(2)],
+			ScopeID:           "proj",
+			LimitTokens:       r.Int63n(1 << 40),
+			UsedTokens:        r.Int63n(1 << 40),
+			PeriodGranularity: rolloverGranularities[r.Intn(len(rolloverGranularities))],
+		},
+		Aligned: r.Intn(2) == 0,
+	}
+
+	if c.Aligned {
+		c.Budget.PeriodStart, c.Budget.PeriodEnd, _ = PeriodBounds(c.Budget.PeriodGranularity, instant)
+	} else {
+		c.Budget.PeriodStart = instant
+		c.Budget.PeriodEnd = instant.Add(time.Duration(r.Int63n(int64(40 * 24 * time.Hour))))
+	}
+	c.Budget.PeriodStart = c.Budget.PeriodStart.In(loc)
+	c.Budget.PeriodEnd = c.Budget.PeriodEnd.In(loc)
+
+	return reflect.ValueOf(c)
+}
+
+// TestRolloverBudgetsFuzz checks the invariants of the period RolloverBudgets
+// creates for an expired budget, whatever zone its times are in
+func TestRolloverBudgetsFuzz(t *testing.T) {
+	property := func(c rolloverCase) bool {
+		old := c.Budget
+		next, err := nextPeriod(&old)
+		if err != nil {
+			t.Errorf("nextPeriod(%s): %v", old.PeriodGranularity, err)
+			return false
+		}
+
+		ok := true
+		fail := func(format string, args ...interface{}) {
+			t.Errorf("%s budget %s → %s: "+format,
+				append([]interface{}{old.PeriodGranularity, old.PeriodStart, old.PeriodEnd}, args...)...)
+			ok = false
+		}
+
+		if !next.PeriodStart.Equal(old.PeriodEnd) {
+			fail("new start %s, want old end", next.PeriodStart)
+		}
+		if next.UsedTokens != 0 {
+			fail("used tokens %d, want 0", next.UsedTokens)
+		}
+		if next.LimitTokens != old.LimitTokens {
+			fail("limit %d, want %d", next.LimitTokens, old.LimitTokens)
+		}
+		if next.Scope != old.Scope || next.ScopeID != old.ScopeID || next.PeriodGranularity != old.PeriodGranularity {
+			fail("scope or granularity changed to %s/%s/%s", next.Scope, next.ScopeID, next.PeriodGranularity)
+		}
+
+		length := next.PeriodEnd.Sub(next.PeriodStart)
+		switch old.PeriodGranularity {
+		case "hourly":
+			if length != time.Hour {
+				fail("length %s, want 1h", length)
+			}
+		case "daily":
+			if length != 24*time.Hour {
+				fail("length %s, want 24h", length)
+			}
+		case "weekly":
+			if length != 7*24*time.Hour {
+				fail("length %s, want 168h", length)
+			}
+		case "monthly":
+			// One calendar month in UTC, whatever the zone: the same day
+			// and time in the following month, or its last day if that
+			// month is shorter
+			start, end := next.PeriodStart.UTC(), next.PeriodEnd.UTC()
+			wantYear, wantMonth := start.Year(), start.Month()+1
+			if wantMonth > time.December {
+				wantYear, wantMonth = wantYear+1, time.January
+			}
+			wantDay := min(start.Day(), daysIn(wantYear, wantMonth))
+
+			if end.Year() != wantYear || end.Month() != wantMonth || end.Day() != wantDay {
+				fail("new end %s, want %d-%02d-%02d", end, wantYear, wantMonth, wantDay)
+			}
+			if end.Hour() != start.Hour() || end.Minute() != start.Minute() ||
+				end.Second() != start.Second() || end.Nanosecond() != start.Nanosecond() {
+				fail("new end %s, want the time of day of %s", end, start)
+			}
+		}
+
+		// A period PeriodBounds created rolls over to the next one it would
+		// create, so aligned budgets stay aligned across month and year ends
+		if c.Aligned {
+			start, end, _ := PeriodBounds(old.PeriodGranularity, old.PeriodEnd)
+			if !next.PeriodStart.Equal(start) || !next.PeriodEnd.Equal(end) {
+				fail("new period %s → %s, want %s → %s", next.PeriodStart, next.PeriodEnd, start, end)
+			}
+		}
+
+		return ok
+	}
+
+	cfg := &quick.Config{MaxCount: 5000}
+	if testing.Short() {
+		cfg.MaxCount = 500
+	}
+	if err := quick.Check(property, cfg); err != nil {
+		t.Error(err)
+	}
+}
+
+// daysIn returns the number of days in a month by the Gregorian calendar
+// rules, without the time package's date normalisation
+func daysIn(year int, month time.Month) int {
+	switch month {
+	case time.February:
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 29
+		}
+		return 28
+	case time.April, time.June, time.September, time.November:
+		return 30
+	}
+	return 31
+}
+
+func TestNextPeriodClampsMonthEnds(t *testing.T) {
+	tests := []struct {
+		start, want string
+	}{
+		{"2025-01-31T09:30:00Z", "2025-02-28T09:30:00Z"},
+		{"2024-01-31T09:30:00Z", "2024-02-29T09:30:00Z"},
+		{"2025-03-31T00:00:00Z", "2025-04-30T00:00:00Z"},
+		{"2025-12-31T23:59:59Z", "2026-01-31T23:59:59Z"},
+		{"2025-02-28T12:00:00Z", "2025-03-28T12:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		start, _ := time.Parse(time.RFC3339, tt.start)
+		want, _ := time.Parse(time.RFC3339, tt.want)
+
+		next, err := nextPeriod(&types.TokenBudget{PeriodGranularity: "monthly", PeriodEnd: start})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !next.PeriodEnd.Equal(want) {
+			t.Errorf("period from %s ends %s, want %s", tt.start, next.PeriodEnd.Format(time.RFC3339), tt.want)
+		}
+	}
+}
+
+func TestNextPeriodRejectsUnknownGranularity(t *testing.T) {
+	_, err := nextPeriod(&types.TokenBudget{PeriodGranularity: "fortnightly"})
+	if err == nil {
+		t.Error("nextPeriod accepted an unknown granularity")
+	}
+}
@@ -0,0 +1,83 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DailyUsagePoint is the token usage for one UTC day
+type DailyUsagePoint struct {
+	Date   time.Time
+	Tokens int64
+}
+
+// UsageReport summarises token burn over a window of days
+type UsageReport struct {
+	Scope                 string
+	ScopeID               string
+	Days                  int
+	DailyUsage            []DailyUsagePoint // one point per day, oldest first, zero-filled
+	TotalUsage            int64
+	AverageDailyUsage     float64
+	PeakDayUsage          int64
+	PeakDayDate           time.Time
+	ProjectedMonthlyUsage int64 // average daily usage extrapolated to 30 days
+}
+
+// GetUsageReport aggregates token usage events per day over the last days
+// days, including today. scope is "global" or "project" (with scopeID as the
+// project name).
+func (m *Manager) GetUsageReport(ctx context.Context, scope, scopeID string, days int) (*UsageReport, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive")
+	}
+
+	projectName := ""
+	switch scope {
+	case "global":
+	case "project":
+		if scopeID == "" {
+			return nil, fmt.Errorf("scope_id is required for project scope")
+		}
+		projectName = scopeID
+	default:
+		return nil, fmt.Errorf("unsupported scope: %s", scope)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -(days - 1))
+	to := today.AddDate(0, 0, 1)
+
+	points, err := m.db.GetTokenUsageTimeSeries(ctx, projectName, from, to, "day")
+	if err != nil {
+		return nil, fmt.Errorf("get usage: %w", err)
+	}
+
+	byDay := make(map[string]int64, len(points))
+	for _, p := range points {
+		byDay[p.Bucket.UTC().Format("2006-01-02")] += p.Tokens
+	}
+
+	report := &UsageReport{
+		Scope:      scope,
+		ScopeID:    scopeID,
+		Days:       days,
+		DailyUsage: make([]DailyUsagePoint, 0, days),
+	}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		tokens := byDay[day.Format("2006-01-02")]
+		report.DailyUsage = append(report.DailyUsage, DailyUsagePoint{Date: day, Tokens: tokens})
+		report.TotalUsage += tokens
+		if tokens > report.PeakDayUsage {
+			report.PeakDayUsage = tokens
+			report.PeakDayDate = day
+		}
+	}
+
+	report.AverageDailyUsage = float64(report.TotalUsage) / float64(days)
+	report.ProjectedMonthlyUsage = int64(report.AverageDailyUsage * 30)
+
+	return report, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 )
@@ -43,6 +44,24 @@ func NewManager(cfg *Config, logger *zap.Logger) (*Manager, error) {
 // Enabled reports whether the backing Redis cache is active.
 func (m *Manager) Enabled() bool { return m.redis != nil }
 
+// RedisClient returns the underlying Redis client, or nil when the cache is
+// disabled, for features that share the connection (e.g. rate limiting).
+func (m *Manager) RedisClient() *redis.Client {
+	if m.redis == nil {
+		return nil
+	}
+	return m.redis.client
+}
+
+// Ping checks the Redis connection. It returns nil when the cache is
+// disabled.
+func (m *Manager) Ping(ctx context.Context) error {
+	if m.redis == nil {
+		return nil
+	}
+	return m.redis.client.Ping(ctx).Err()
+}
+
 // Close shuts down the Redis connection if one exists.
 func (m *Manager) Close() error {
 	if m.redis == nil {
@@ -74,6 +93,7 @@ func (m *Manager) GetProject(ctx context.Context, name string) *types.Project {
 }
 
 // SetProject stores a project in the cache. Errors are logged but not returned.
+// Cached project lists are dropped since the project may have changed status.
 func (m *Manager) SetProject(ctx context.Context, project *types.Project) {
 	if m.redis == nil || project == nil {
 		return
@@ -81,9 +101,12 @@ func (m *Manager) SetProject(ctx context.Context, project *types.Project) {
 	if err := m.redis.SetProject(ctx, project); err != nil {
 		m.logger.Debug("cache set error", zap.String("key", "project:"+project.Name), zap.Error(err))
 	}
+	m.invalidateProjectLists(ctx, projectListStatuses...)
 }
 
-// InvalidateProject removes a project entry from the cache.
+// InvalidateProject removes a project entry, and every cached project list,
+// from the cache. Should be called whenever a project is created, changed or
+// removed.
 func (m *Manager) InvalidateProject(ctx context.Context, name string) {
 	if m.redis == nil {
 		return
@@ -91,6 +114,97 @@ func (m *Manager) InvalidateProject(ctx context.Context, name string) {
 	if err := m.redis.InvalidateProject(ctx, name); err != nil {
 		m.logger.Debug("cache invalidate error", zap.String("key", "project:"+name), zap.Error(err))
 	}
+	m.invalidateProjectLists(ctx, projectListStatuses...)
+}
+
+// InvalidateProjectLists removes every cached project list. Runner status
+// changes should call it, as the lists carry each project's runner counts.
+func (m *Manager) InvalidateProjectLists(ctx context.Context) {
+	if m.redis == nil {
+		return
+	}
+	m.invalidateProjectLists(ctx, projectListStatuses...)
+}
+
+// projectListStatuses are the status filters project lists are cached
+// under; "" is the unfiltered list
+var projectListStatuses = []string{
+	"",
+	string(types.ProjectActive),
+	string(types.ProjectIdle),
+	string(types.ProjectArchived),
+}
+
+// GetProjectList returns the cached project list for a status filter ("" for
+// all projects) or nil on miss / disabled cache.
+func (m *Manager) GetProjectList(ctx context.Context, status string) []*types.Project {
+	if m.redis == nil {
+		return nil
+	}
+	projects, err := m.redis.GetProjectList(ctx, status)
+	if err != nil {
+		m.logger.Debug("cache get error", zap.String("key", "projects:list:"+status), zap.Error(err))
+		return nil
+	}
+	if projects != nil {
+		m.hits++
+	} else {
+		m.misses++
+	}
+	return projects
+}
+
+// SetProjectList stores the project list for a status filter in the cache.
+func (m *Manager) SetProjectList(ctx context.Context, status string, projects []*types.Project) {
+	if m.redis == nil {
+		return
+	}
+	if err := m.redis.SetProjectList(ctx, status, projects); err != nil {
+		m.logger.Debug("cache set error", zap.String("key", "projects:list:"+status), zap.Error(err))
+	}
+}
+
+// InvalidateProjectList removes the project list for a status filter, and
+// the unfiltered list that includes it, from the cache.
+func (m *Manager) InvalidateProjectList(ctx context.Context, status string) {
+	if m.redis == nil {
+		return
+	}
+	m.invalidateProjectLists(ctx, "", status)
+}
+
+func (m *Manager) invalidateProjectLists(ctx context.Context, statuses ...string) {
+	if err := m.redis.InvalidateProjectList(ctx, statuses...); err != nil {
+		m.logger.Debug("cache invalidate error", zap.String("key", "projects:list:*"), zap.Error(err))
+	}
+}
+
+// GetProjectStats returns cached project stats or nil on miss / disabled cache.
+func (m *Manager) GetProjectStats(ctx context.Context, name string) *types.ProjectStats {
+	if m.redis == nil {
+		return nil
+	}
+	stats, err := m.redis.GetProjectStats(ctx, name)
+	if err != nil {
+		m.logger.Debug("cache get error", zap.String("key", "project_stats:"+name), zap.Error(err))
+		return nil
+	}
+	if stats != nil {
+		m.hits++
+	} else {
+		m.misses++
+	}
+	return stats
+}
+
+// SetProjectStats stores project stats in the cache.
+func (m *Manager) SetProjectStats(ctx context.Context, stats *types.ProjectStats) {
+	if m.redis == nil || stats == nil {
+		return
+	}
+	if err := m.redis.SetProjectStats(ctx, stats); err != nil {
+		m.logger.Debug("cache set error", zap.String("key", "project_stats:"+stats.ProjectName), zap.Error(err))
+	}
 }
 
 // ---------------------------------------------------------------------------
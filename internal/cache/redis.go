@@ -46,11 +46,12 @@ func NewRedisCache(cfg Config, logger *zap.Logger) (*RedisCache, error) {
 		client: client,
 		logger: logger,
 		ttl: map[string]time.Duration{
-			"project":      5 * time.Minute,
-			"runner":       30 * time.Second,
-			"runner_list":  10 * time.Second,
-			"project_list": 1 * time.Minute,
-			"status":       5 * time.Second,
+			"project":       5 * time.Minute,
+			"project_stats": 30 * time.Second,
+			"runner":        30 * time.Second,
+			"runner_list":   10 * time.Second,
+			"project_list":  1 * time.Minute,
+			"status":        5 * time.Second,
 		},
 	}
 
@@ -94,6 +95,37 @@ func (c *RedisCache) SetProject(ctx context.Context, project *types.Project) err
 	return c.client.Set(ctx, key, data, c.ttl["project"]).Err()
 }
 
+// GetProjectStats retrieves cached project stats
+func (c *RedisCache) GetProjectStats(ctx context.Context, name string) (*types.ProjectStats, error) {
+	key := fmt.Sprintf("project_stats:%s", name)
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stats types.ProjectStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("cache hit", zap.String("key", key))
+	return &stats, nil
+}
+
+// SetProjectStats caches project stats
+func (c *RedisCache) SetProjectStats(ctx context.Context, stats *types.ProjectStats) error {
+	key := fmt.Sprintf("project_stats:%s", stats.ProjectName)
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, key, data, c.ttl["project_stats"]).Err()
+}
+
 // GetRunner retrieves cached runner
 func (c *RedisCache) GetRunner(ctx context.Context, runnerID string) (*types.Runner, error) {
 	key := fmt.Sprintf("runner:%s", runnerID)
@@ -156,6 +188,48 @@ func (c *RedisCache) SetRunnerList(ctx context.Context, projectName string, runn
 	return c.client.Set(ctx, key, data, c.ttl["runner_list"]).Err()
 }
 
+// GetProjectList retrieves the cached project list for a status filter
+// ("" for all projects)
+func (c *RedisCache) GetProjectList(ctx context.Context, status string) ([]*types.Project, error) {
+	key := fmt.Sprintf("projects:list:%s", status)
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*types.Project
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("cache hit", zap.String("key", key))
+	return projects, nil
+}
+
+// SetProjectList caches the project list for a status filter
+func (c *RedisCache) SetProjectList(ctx context.Context, status string, projects []*types.Project) error {
+	key := fmt.Sprintf("projects:list:%s", status)
+	data, err := json.Marshal(projects)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, key, data, c.ttl["project_list"]).Err()
+}
+
+// InvalidateProjectList removes the project lists for the given status
+// filters from cache
+func (c *RedisCache) InvalidateProjectList(ctx context.Context, statuses ...string) error {
+	keys := make([]string, len(statuses))
+	for i, status := range statuses {
+		keys[i] = fmt.Sprintf("projects:list:%s", status)
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
 // InvalidateProject removes project from cache
 func (c *RedisCache) InvalidateProject(ctx context.Context, name string) error {
 	key := fmt.Sprintf("project:%s", name)
@@ -0,0 +1,38 @@
+//go:build linux
+
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// setCPUAffinity pins every thread of pid to cpus. sched_setaffinity only
+// affects the thread it is given, so each entry in /proc/<pid>/task is set;
+// threads and children created afterwards inherit the mask.
+func setCPUAffinity(pid int, cpus []int) error {
+	var mask unix.CPUSet
+	for _, cpu := range cpus {
+		mask.Set(cpu)
+	}
+
+	tids := []int{pid}
+	if entries, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid)); err == nil {
+		tids = tids[:0]
+		for _, e := range entries {
+			if tid, err := strconv.Atoi(e.Name()); err == nil {
+				tids = append(tids, tid)
+			}
+		}
+	}
+
+	for _, tid := range tids {
+		if err := unix.SchedSetaffinity(tid, &mask); err != nil {
+			return fmt.Errorf("sched_setaffinity %d: %w", tid, err)
+		}
+	}
+	return nil
+}
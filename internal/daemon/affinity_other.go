@@ -0,0 +1,8 @@
+//go:build !linux
+
+package daemon
+
+// setCPUAffinity is a no-op outside Linux
+func setCPUAffinity(pid int, cpus []int) error {
+	return errAffinityUnsupported
+}
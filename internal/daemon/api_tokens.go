@@ -0,0 +1,373 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/internal/cache"
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// apiTokenDefaultTTL is the lifetime of API tokens created without an
+	// explicit expiry
+	apiTokenDefaultTTL = 30 * 24 * time.Hour
+
+	// apiTokenMaxTTL caps how long an API token may be valid
+	apiTokenMaxTTL = 365 * 24 * time.Hour
+
+	// apiTokenTouchInterval limits how often a token's last_used_at is
+	// written, so busy scripts don't cause a database write per request
+	apiTokenTouchInterval = time.Minute
+)
+
+// newRevocationList shares revocations through Redis when the cache is up,
+// and keeps them in memory otherwise
+func newRevocationList(c *cache.Manager, logger *zap.Logger) auth.RevocationList {
+	mem := auth.NewMemoryRevocationList()
+	if c != nil && c.Enabled() {
+		return auth.NewRedisRevocationList(c.RedisClient(), mem, logger)
+	}
+	return mem
+}
+
+// CreateAPIToken issues a long-lived API token for the caller. A token can
+// only carry scopes its creator holds.
+func (s *GRPCServer) CreateAPIToken(ctx context.Context, req *api.CreateAPITokenRequest) (*api.CreateAPITokenResponse, error) {
+	if s.authSecret == "" {
+		return &api.CreateAPITokenResponse{
+			Error: "authentication is not enabled on this daemon",
+		}, nil
+	}
+
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return &api.CreateAPITokenResponse{Error: "not authenticated"}, nil
+	}
+
+	if req.Name == "" {
+		return &api.CreateAPITokenResponse{Error: "token name is required"}, nil
+	}
+
+	scope := req.Scope
+	if len(scope) == 0 {
+		scope = claims.Scope
+	}
+	for _, sc := range scope {
+		if !claims.HasScope(sc) {
+			return &api.CreateAPITokenResponse{
+				Error: "cannot grant scope not held by the caller: " + sc,
+			}, nil
+		}
+	}
+
+	ttl := time.Duration(req.ExpiresInSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = apiTokenDefaultTTL
+	}
+	if ttl > apiTokenMaxTTL {
+		return &api.CreateAPITokenResponse{
+			Error: "expiry must be at most 365 days",
+		}, nil
+	}
+
+	t := &types.APIToken{
+		Name:      req.Name,
+		Scope:     scope,
+		Subject:   claims.Subject,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.storage.CreateAPIToken(ctx, t); err != nil {
+		return &api.CreateAPITokenResponse{Error: err.Error()}, nil
+	}
+
+	token, err := auth.NewValidator(s.authSecret).Generate(auth.Claims{
+		ID:        t.ID,
+		Subject:   t.Subject,
+		ExpiresAt: t.ExpiresAt.Unix(),
+		Scope:     t.Scope,
+	})
+	if err != nil {
+		return &api.CreateAPITokenResponse{Error: err.Error()}, nil
+	}
+
+	s.logger.Info("API token created",
+		zap.String("token_id", t.ID),
+		zap.String("name", t.Name),
+		zap.String("subject", t.Subject))
+
+	return &api.CreateAPITokenResponse{
+		Token:    token,
+		APIToken: convertAPITokenToAPI(t),
+	}, nil
+}
+
+// ListAPITokens returns the caller's API tokens that are neither revoked
+// nor expired, or every subject's for admins
+func (s *GRPCServer) ListAPITokens(ctx context.Context, req *api.ListAPITokensRequest) (*api.ListAPITokensResponse, error) {
+	caller, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return &api.ListAPITokensResponse{Error: "not authenticated"}, nil
+	}
+
+	tokens, err := s.storage.ListAPITokens(ctx)
+	if err != nil {
+		return &api.ListAPITokensResponse{Error: err.Error()}, nil
+	}
+
+	apiTokens := make([]*api.APIToken, 0, len(tokens))
+	for _, t := range tokens {
+		if caller.CanManageTokensOf(t.Subject) {
+			apiTokens = append(apiTokens, convertAPITokenToAPI(t))
+		}
+	}
+
+	return &api.ListAPITokensResponse{Tokens: apiTokens}, nil
+}
+
+// RevokeAPIToken revokes an API token. The revocation is recorded in the
+// database and published to the revocation list every daemon checks.
+// Callers can only revoke their own tokens unless they are admins.
+func (s *GRPCServer) RevokeAPIToken(ctx context.Context, req *api.RevokeAPITokenRequest) (*api.RevokeAPITokenResponse, error) {
+	caller, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return &api.RevokeAPITokenResponse{Error: "not authenticated"}, nil
+	}
+
+	existing, err := s.storage.GetAPIToken(ctx, req.ID)
+	if err != nil {
+		return &api.RevokeAPITokenResponse{Error: err.Error()}, nil
+	}
+	// Report other subjects' tokens as missing rather than confirming
+	// that they exist
+	if !caller.CanManageTokensOf(existing.Subject) {
+		return &api.RevokeAPITokenResponse{Error: "token not found or already revoked: " + req.ID}, nil
+	}
+
+	t, err := s.storage.RevokeAPIToken(ctx, req.ID)
+	if err != nil {
+		return &api.RevokeAPITokenResponse{Error: err.Error()}, nil
+	}
+
+	if err := s.revocations.Revoke(ctx, t.ID, t.ExpiresAt); err != nil {
+		// Still revoked on this daemon; others pick it up on restart
+		s.logger.Warn("failed to publish token revocation",
+			zap.String("token_id", t.ID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("API token revoked",
+		zap.String("token_id", t.ID),
+		zap.String("name", t.Name),
+		zap.String("revoked_by", caller.Subject))
+
+	return &api.RevokeAPITokenResponse{Success: true}, nil
+}
+
+// RotateToken exchanges a valid token for one with the same subject and
+// scope and a refreshed expiry, revoking the old token. Callers can only
+// rotate their own tokens.
+func (s *GRPCServer) RotateToken(ctx context.Context, req *api.RotateTokenRequest) (*api.RotateTokenResponse, error) {
+	if s.authSecret == "" {
+		return &api.RotateTokenResponse{
+			Error: "authentication is not enabled on this daemon",
+		}, nil
+	}
+
+	caller, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return &api.RotateTokenResponse{Error: "not authenticated"}, nil
+	}
+
+	validator := auth.NewValidator(s.authSecret)
+	validator.SetRevocationList(s.revocations)
+
+	old, err := validator.Validate(req.Token)
+	if err != nil {
+		return &api.RotateTokenResponse{Error: err.Error()}, nil
+	}
+	if old.Subject != caller.Subject {
+		return &api.RotateTokenResponse{Error: "cannot rotate another subject's token"}, nil
+	}
+
+	token, claims, err := auth.NewRotator(validator, s.revocations).Rotate(ctx, req.Token)
+	if token == "" {
+		return &api.RotateTokenResponse{Error: err.Error()}, nil
+	}
+	if err != nil {
+		// Still revoked on this daemon
+		s.logger.Warn("failed to publish rotated token revocation",
+			zap.String("subject", claims.Subject),
+			zap.Error(err))
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if claims.ID != "" {
+		if err := s.storage.SetAPITokenExpiry(ctx, claims.ID, expiresAt); err != nil {
+			s.logger.Warn("failed to record rotated API token expiry",
+				zap.String("token_id", claims.ID),
+				zap.Error(err))
+		}
+	}
+
+	s.logger.Info("token rotated",
+		zap.String("subject", claims.Subject),
+		zap.String("token_id", claims.ID),
+		zap.Time("expires_at", expiresAt))
+
+	return &api.RotateTokenResponse{
+		Token:     token,
+		ExpiresAt: api.FormatTime(expiresAt),
+	}, nil
+}
+
+// LoadRevokedAPITokens restores the revocation list from the database, so
+// revocations survive a daemon restart or a Redis flush
+func (s *GRPCServer) LoadRevokedAPITokens(ctx context.Context) error {
+	tokens, err := s.storage.ListRevokedAPITokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tokens {
+		if err := s.revocations.Revoke(ctx, t.ID, t.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	if len(tokens) > 0 {
+		s.logger.Info("loaded revoked API tokens", zap.Int("count", len(tokens)))
+	}
+	return nil
+}
+
+func convertAPITokenToAPI(t *types.APIToken) *api.APIToken {
+	at := &api.APIToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Scope:     t.Scope,
+		Subject:   t.Subject,
+		CreatedAt: api.FormatTime(t.CreatedAt),
+		ExpiresAt: api.FormatTime(t.ExpiresAt),
+	}
+	if t.LastUsedAt != nil {
+		at.LastUsedAt = api.FormatTime(*t.LastUsedAt)
+	}
+	return at
+}
+
+// ===== HTTP =====
+
+func (s *HTTPServer) handleCreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var req api.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CreateAPIToken(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleListAPITokens(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handler.ListAPITokens(r.Context(), &api.ListAPITokensRequest{})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleRevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handler.RevokeAPIToken(r.Context(), &api.RevokeAPITokenRequest{
+		ID: r.PathValue("id"),
+	})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleRotateToken(w http.ResponseWriter, r *http.Request) {
+	var req api.RotateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Token == "" {
+		req.Token = auth.TokenFromRequest(r)
+	}
+
+	resp, err := s.handler.RotateToken(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// apiTokenUsage records when API tokens are used, writing each token's
+// last_used_at at most once per apiTokenTouchInterval
+type apiTokenUsage struct {
+	handler *GRPCServer
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	touched map[string]time.Time
+}
+
+// middleware must run inside auth.Middleware, which puts the token's claims
+// on the request context. Only tokens with an ID are tracked.
+func (u *apiTokenUsage) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if claims, ok := auth.ClaimsFromContext(r.Context()); ok && claims.ID != "" && u.due(claims.ID) {
+			go u.touch(claims.ID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (u *apiTokenUsage) due(tokenID string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := u.touched[tokenID]; ok && now.Sub(last) < apiTokenTouchInterval {
+		return false
+	}
+	for id, last := range u.touched {
+		if now.Sub(last) >= apiTokenTouchInterval {
+			delete(u.touched, id)
+		}
+	}
+	u.touched[tokenID] = now
+	return true
+}
+
+func (u *apiTokenUsage) touch(tokenID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := u.handler.storage.TouchAPIToken(ctx, tokenID); err != nil {
+		u.logger.Warn("failed to record API token use",
+			zap.String("token_id", tokenID),
+			zap.Error(err))
+	}
+}
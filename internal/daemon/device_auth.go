@@ -0,0 +1,356 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// deviceCodeTTL is how long a user has to approve a device login
+	deviceCodeTTL = 15 * time.Minute
+
+	// devicePollInterval is the minimum time clients wait between polls
+	devicePollInterval = 5 * time.Second
+
+	// deviceTokenTTL is the lifetime of tokens issued through the device flow
+	deviceTokenTTL = 30 * 24 * time.Hour
+
+	// userCodeAlphabet avoids vowels and look-alike characters
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+	// deviceCSRFCookie holds the CSRF token the verification form must echo
+	deviceCSRFCookie = "stratavore_device_csrf"
+)
+
+// StartDeviceAuth begins an OAuth2 device flow login (RFC 8628). The caller
+// fills in the verification URI since only the transport knows the address
+// the user can reach.
+func (s *GRPCServer) StartDeviceAuth(ctx context.Context) (*api.StartDeviceAuthResponse, error) {
+	if s.authSecret == "" {
+		return &api.StartDeviceAuthResponse{
+			Error: "authentication is not enabled on this daemon",
+		}, nil
+	}
+
+	if n, err := s.storage.DeleteExpiredDeviceCodes(ctx); err != nil {
+		s.logger.Warn("failed to delete expired device codes", zap.Error(err))
+	} else if n > 0 {
+		s.logger.Debug("deleted expired device codes", zap.Int64("count", n))
+	}
+
+	deviceCode, err := randomDeviceCode()
+	if err != nil {
+		return &api.StartDeviceAuthResponse{Error: err.Error()}, nil
+	}
+	userCode, err := randomUserCode()
+	if err != nil {
+		return &api.StartDeviceAuthResponse{Error: err.Error()}, nil
+	}
+
+	dc := &types.DeviceCode{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ExpiresAt:  time.Now().Add(deviceCodeTTL),
+	}
+	if err := s.storage.CreateDeviceCode(ctx, dc); err != nil {
+		return &api.StartDeviceAuthResponse{Error: err.Error()}, nil
+	}
+
+	s.logger.Info("device login started", zap.String("user_code", userCode))
+
+	return &api.StartDeviceAuthResponse{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ExpiresIn:  int32(deviceCodeTTL.Seconds()),
+		Interval:   int32(devicePollInterval.Seconds()),
+	}, nil
+}
+
+// PollDeviceAuth exchanges an approved device code for an API token. The
+// device code is single use: it is deleted as the token is issued.
+func (s *GRPCServer) PollDeviceAuth(ctx context.Context, req *api.PollDeviceAuthRequest) (*api.PollDeviceAuthResponse, error) {
+	dc, err := s.storage.GetDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		return &api.PollDeviceAuthResponse{Error: "expired_token"}, nil
+	}
+
+	switch dc.Status {
+	case types.DeviceCodePending:
+		return &api.PollDeviceAuthResponse{Error: "authorization_pending"}, nil
+	case types.DeviceCodeDenied:
+		s.storage.DeleteDeviceCode(ctx, dc.DeviceCode)
+		return &api.PollDeviceAuthResponse{Error: "access_denied"}, nil
+	}
+
+	// Claim the code before issuing a token, so concurrent polls can't each
+	// get one; only the poll that deletes the row goes on
+	dc, err = s.storage.ClaimApprovedDeviceCode(ctx, req.DeviceCode)
+	if err != nil {
+		return &api.PollDeviceAuthResponse{Error: err.Error()}, nil
+	}
+	if dc == nil {
+		return &api.PollDeviceAuthResponse{Error: "expired_token"}, nil
+	}
+
+	expiresAt := time.Now().Add(deviceTokenTTL)
+	token, err := auth.NewValidator(s.authSecret).Generate(auth.Claims{
+		Subject:   dc.Subject,
+		ExpiresAt: expiresAt.Unix(),
+		Scope:     []string{"*"},
+	})
+	if err != nil {
+		return &api.PollDeviceAuthResponse{Error: err.Error()}, nil
+	}
+
+	s.logger.Info("device login completed",
+		zap.String("user_code", dc.UserCode),
+		zap.String("subject", dc.Subject))
+
+	return &api.PollDeviceAuthResponse{
+		Token:     token,
+		ExpiresAt: api.FormatTime(expiresAt),
+	}, nil
+}
+
+// ResolveDeviceAuth approves or denies a pending device login by user code
+func (s *GRPCServer) ResolveDeviceAuth(ctx context.Context, userCode string, approve bool, subject string) error {
+	status := types.DeviceCodeDenied
+	if approve {
+		status = types.DeviceCodeApproved
+	}
+	if subject == "" {
+		subject = "device:" + userCode
+	}
+	return s.storage.ResolveDeviceCode(ctx, normalizeUserCode(userCode), status, subject)
+}
+
+func randomDeviceCode() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate device code: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// randomUserCode returns a short code like "BDFG-HJKL" for the user to type
+func randomUserCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate user code: %w", err)
+	}
+	code := make([]byte, 0, 9)
+	for i, b := range buf {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, userCodeAlphabet[int(b)%len(userCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// normalizeUserCode accepts codes typed in lower case or without the dash
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) == 8 {
+		code = code[:4] + "-" + code[4:]
+	}
+	return code
+}
+
+// ===== HTTP =====
+
+func (s *HTTPServer) handleDeviceStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	resp, err := s.handler.StartDeviceAuth(r.Context())
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if resp.Error == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		resp.VerificationURI = fmt.Sprintf("%s://%s%sverify", scheme, r.Host, auth.DeviceAuthPathPrefix)
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleDevicePoll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	resp, err := s.handler.PollDeviceAuth(r.Context(), &api.PollDeviceAuthRequest{
+		DeviceCode: r.URL.Query().Get("device_code"),
+	})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+var deviceVerifyTemplate = template.Must(template.New("verify").Parse(`<html>
+<head><title>Stratavore device login</title></head>
+<body style="font-family: sans-serif; max-width: 32em; margin: 4em auto;">
+<h2>Stratavore device login</h2>
+{{if .Message}}<p>{{.Message}}</p>{{end}}
+{{if not .Done}}
+<form method="POST">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+<p>Enter the code shown by <code>stratavore login</code>:</p>
+<p><input name="user_code" value="{{.UserCode}}" autofocus></p>
+<p>Name for this device (optional): <input name="subject"></p>
+<p><button name="action" value="approve">Approve</button>
+<button name="action" value="deny">Deny</button></p>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// handleDeviceVerify serves the page where a user approves a device login.
+// Approving mints a token with every scope, so a POST must come from this
+// page: its Origin must match the daemon and it must echo the CSRF token
+// set in a SameSite cookie when the page was served. Approval must also
+// come from the daemon host (e.g. through an SSH port forward) or carry an
+// existing valid token; otherwise anyone who can reach the daemon could
+// grant themselves access.
+func (s *HTTPServer) handleDeviceVerify(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		UserCode  string
+		CSRFToken string
+		Message   string
+		Done      bool
+	}{UserCode: r.URL.Query().Get("user_code")}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !sameOrigin(r) || !validDeviceCSRFToken(r) {
+			w.WriteHeader(http.StatusForbidden)
+			data.UserCode = r.FormValue("user_code")
+			data.Message = "This form was not submitted from this page. Check the code and try again."
+			break
+		}
+		if !s.mayApproveDevice(r) {
+			w.WriteHeader(http.StatusForbidden)
+			data.Message = "Device logins can only be approved from the daemon host or with an existing token."
+			data.Done = true
+			break
+		}
+
+		userCode := r.FormValue("user_code")
+		approve := r.FormValue("action") == "approve"
+		if err := s.handler.ResolveDeviceAuth(r.Context(), userCode, approve, r.FormValue("subject")); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			data.UserCode = userCode
+			data.Message = err.Error()
+			break
+		}
+
+		data.Done = true
+		if approve {
+			data.Message = "✓ Device approved. You can return to your terminal."
+		} else {
+			data.Message = "Device login denied."
+		}
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Every rendered form gets a fresh token
+	if !data.Done {
+		token, err := randomDeviceCode()
+		if err != nil {
+			s.respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     deviceCSRFCookie,
+			Value:    token,
+			Path:     auth.DeviceAuthPathPrefix + "verify",
+			MaxAge:   int(deviceCodeTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+		data.CSRFToken = token
+	}
+
+	if err := deviceVerifyTemplate.Execute(w, data); err != nil {
+		s.logger.Error("failed to render device verification page", zap.Error(err))
+	}
+}
+
+// sameOrigin reports whether r carries an Origin header naming the host it
+// was sent to. Browsers send Origin on every form POST, so a missing one
+// means the request didn't come from the verification page.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return u.Host != "" && strings.EqualFold(u.Host, r.Host)
+}
+
+// validDeviceCSRFToken reports whether the form's CSRF token matches the
+// cookie set when the form was served
+func validDeviceCSRFToken(r *http.Request) bool {
+	cookie, err := r.Cookie(deviceCSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(r.FormValue("csrf_token"))) == 1
+}
+
+// mayApproveDevice reports whether the caller may approve device logins.
+// Being on the daemon host is only trusted once the request has passed the
+// Origin and CSRF checks, since a browser on the host would otherwise
+// approve on behalf of any page it has open.
+func (s *HTTPServer) mayApproveDevice(r *http.Request) bool {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return false
+	}
+	validator := auth.NewValidator(s.handler.authSecret)
+	validator.SetRevocationList(s.handler.revocations)
+	_, err := validator.Validate(token)
+	return err == nil
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// dirChangedSignals are the signals daemon.runner_dir_changed_signal may
+// name. stratavore-agent handles SIGUSR2; the others suit custom runtimes
+// that treat them as a reload.
+var dirChangedSignals = map[string]syscall.Signal{
+	"SIGUSR2":  syscall.SIGUSR2,
+	"SIGHUP":   syscall.SIGHUP,
+	"SIGWINCH": syscall.SIGWINCH,
+}
+
+// parseDirChangedSignal resolves a signal name; "" means none
+func parseDirChangedSignal(name string) (syscall.Signal, error) {
+	if name == "" {
+		return 0, nil
+	}
+	sig, ok := dirChangedSignals[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported directory changed signal %q (use SIGUSR2, SIGHUP or SIGWINCH)", name)
+	}
+	return sig, nil
+}
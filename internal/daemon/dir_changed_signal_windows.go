@@ -0,0 +1,15 @@
+package daemon
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// parseDirChangedSignal always fails for a named signal: Windows has none
+// that a runner could treat as "directory changed"
+func parseDirChangedSignal(name string) (syscall.Signal, error) {
+	if name == "" {
+		return 0, nil
+	}
+	return 0, fmt.Errorf("directory changed signal %s is not supported on Windows", name)
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// drainPollInterval is how often DrainProject checks for exited runners
+	drainPollInterval = 250 * time.Millisecond
+
+	// defaultDrainTimeout is the grace period when a drain request sets none
+	defaultDrainTimeout = 60 * time.Second
+)
+
+// DrainResult counts how a drained project's runners were stopped
+type DrainResult struct {
+	Graceful int
+	Forced   int
+}
+
+// DrainProject winds down every runner of a project and archives it. New
+// launches for the project are refused with ErrProjectDraining while the
+// drain runs. Runners get SIGTERM and up to waitTimeout to exit before
+// they are killed.
+func (rm *RunnerManager) DrainProject(ctx context.Context, projectName string, waitTimeout time.Duration) (*DrainResult, error) {
+	rm.mu.Lock()
+	if rm.draining[projectName] {
+		rm.mu.Unlock()
+		return nil, ErrProjectDraining
+	}
+	rm.draining[projectName] = true
+
+	var runners []*ManagedRunner
+	for _, managed := range rm.activeRunners {
+		if managed.Runner.ProjectName == projectName {
+			runners = append(runners, managed)
+		}
+	}
+	rm.mu.Unlock()
+
+	// Once archived, Launch refuses the project on its own
+	defer func() {
+		rm.mu.Lock()
+		delete(rm.draining, projectName)
+		rm.mu.Unlock()
+	}()
+
+	rm.logger.Info("draining project",
+		zap.String("project", projectName),
+		zap.Int("runners", len(runners)),
+		zap.Duration("wait_timeout", waitTimeout))
+
+	result := rm.stopGracefully(ctx, runners, waitTimeout, "project_drain")
+
+	if err := rm.db.ArchiveProject(ctx, projectName); err != nil {
+		return result, fmt.Errorf("archive project: %w", err)
+	}
+
+	rm.logger.Info("project drained",
+		zap.String("project", projectName),
+		zap.Int("graceful", result.Graceful),
+		zap.Int("forced", result.Forced))
+
+	return result, nil
+}
+
+// stopGracefully sends SIGTERM to every runner, waits up to waitTimeout
+// for them to exit and kills the rest
+func (rm *RunnerManager) stopGracefully(ctx context.Context, runners []*ManagedRunner, waitTimeout time.Duration, reason string) *DrainResult {
+	for _, managed := range runners {
+		runnerID := managed.Runner.ID
+
+		managed.signalStop()
+
+		rm.recordEvent(ctx, runnerID, "runner.stopped", map[string]interface{}{
+			"reason": reason,
+		})
+
+		if managed.Process != nil && managed.Process.Process != nil {
+			managed.Process.Process.Signal(syscall.SIGTERM)
+		}
+	}
+
+	remaining := rm.waitForExit(ctx, runners, waitTimeout)
+
+	result := &DrainResult{Graceful: len(runners) - len(remaining)}
+	for _, managed := range remaining {
+		rm.logger.Warn("runner did not exit before timeout, killing",
+			zap.String("runner_id", managed.Runner.ID),
+			zap.String("reason", reason))
+		if managed.Process != nil && managed.Process.Process != nil {
+			managed.Process.Process.Kill()
+		}
+		result.Forced++
+	}
+
+	return result
+}
+
+// waitForExit waits until every runner has exited, the timeout passes or
+// ctx is cancelled, and returns the runners still active
+func (rm *RunnerManager) waitForExit(ctx context.Context, runners []*ManagedRunner, timeout time.Duration) []*ManagedRunner {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var remaining []*ManagedRunner
+		for _, managed := range runners {
+			if rm.isActive(managed.Runner.ID) {
+				remaining = append(remaining, managed)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+			runners = remaining
+		case <-deadline.C:
+			return remaining
+		case <-ctx.Done():
+			return remaining
+		}
+	}
+}
+
+// isDraining reports whether DrainProject is running for the project
+func (rm *RunnerManager) isDraining(projectName string) bool {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.draining[projectName]
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// drainSignal asks stratavore-agent to wrap up its Claude Code session
+const drainSignal = syscall.SIGUSR1
@@ -0,0 +1,7 @@
+package daemon
+
+import "syscall"
+
+// drainSignal is unset on Windows, which has no SIGUSR1; StopRunner goes
+// straight to terminating the runner
+const drainSignal syscall.Signal = 0
@@ -2,41 +2,80 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"time"
 
+	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/internal/budget"
+	"github.com/meridian-lex/stratavore/internal/cache"
+	"github.com/meridian-lex/stratavore/internal/notifications"
+	"github.com/meridian-lex/stratavore/internal/session"
 	"github.com/meridian-lex/stratavore/internal/storage"
+	"github.com/meridian-lex/stratavore/internal/vectorstore"
 	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/config"
 	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // GRPCServer implements the Stratavore gRPC API
 type GRPCServer struct {
 	runnerManager *RunnerManager
 	storage       *storage.PostgresClient
+	cache         *cache.Manager
 	logger        *zap.Logger
 	server        *grpc.Server
 	port          int
+	version       string
+	buildTime     string
+	commit        string
+	authSecret    string
+	revocations   auth.RevocationList
+	startedAt     time.Time
+	upgrade       config.UpgradeConfig
+	sessions      *session.Manager
+	vectors       *vectorstore.QdrantClient
+	tlsConfig     *tls.Config
 }
 
+var _ api.StratavoreServiceServer = (*GRPCServer)(nil)
+
 // NewGRPCServer creates a new gRPC server
 func NewGRPCServer(
 	runnerManager *RunnerManager,
 	storage *storage.PostgresClient,
+	cache *cache.Manager,
 	logger *zap.Logger,
 	port int,
+	version string,
+	authSecret string,
 ) *GRPCServer {
 	return &GRPCServer{
 		runnerManager: runnerManager,
 		storage:       storage,
+		cache:         cache,
 		logger:        logger,
 		port:          port,
+		version:       version,
+		authSecret:    authSecret,
+		revocations:   newRevocationList(cache, logger),
+		startedAt:     time.Now(),
 	}
 }
 
+// SetTLSConfig serves gRPC over TLS; call before Start
+func (s *GRPCServer) SetTLSConfig(cfg *tls.Config) {
+	s.tlsConfig = cfg
+}
+
 func (s *GRPCServer) Start() error {
 	addr := fmt.Sprintf(":%d", s.port)
 	lis, err := net.Listen("tcp", addr)
@@ -45,8 +84,21 @@ func (s *GRPCServer) Start() error {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
 
-	s.server = grpc.NewServer()
-	// api.RegisterStratavoreServiceServer(s.server, s)
+	var opts []grpc.ServerOption
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+		s.logger.Info("gRPC mTLS enabled")
+	}
+	if s.authSecret != "" {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(auth.HMACUnaryServerInterceptor(s.authSecret)),
+			grpc.ChainStreamInterceptor(auth.HMACStreamServerInterceptor(s.authSecret)),
+		)
+		s.logger.Info("gRPC HMAC auth enabled")
+	}
+
+	s.server = grpc.NewServer(opts...)
+	api.RegisterStratavoreServiceServer(s.server, s)
 
 	s.logger.Info("gRPC server starting", zap.String("address", addr))
 	if err := s.server.Serve(lis); err != nil {
@@ -79,14 +131,52 @@ func (s *GRPCServer) LaunchRunner(ctx context.Context, req *api.LaunchRunnerRequ
 		ConversationMode: types.ConversationMode(req.ConversationMode),
 		SessionID:        req.SessionID,
 		RuntimeType:      types.RuntimeType(req.RuntimeType),
+		PresetName:       req.PresetName,
 	}
+	for _, cpu := range req.CPUAffinity {
+		launchReq.CPUAffinity = append(launchReq.CPUAffinity, int(cpu))
+	}
+	launchReq.MaxRuntimeSeconds = int(req.MaxRuntimeSeconds)
 
 	// Launch runner
 	runner, err := s.runnerManager.Launch(ctx, launchReq)
 	if err != nil {
 		s.logger.Error("failed to launch runner", zap.Error(err))
 		return &api.LaunchRunnerResponse{
-			Error: err.Error(),
+			Error:     err.Error(),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	return &api.LaunchRunnerResponse{
+		Runner: convertRunnerToAPI(runner),
+	}, nil
+}
+
+// CloneRunner launches a copy of an existing runner's configuration
+func (s *GRPCServer) CloneRunner(ctx context.Context, req *api.CloneRunnerRequest) (*api.LaunchRunnerResponse, error) {
+	s.logger.Info("clone runner request",
+		zap.String("source_runner_id", req.SourceRunnerID),
+		zap.String("project", req.NewProjectName))
+
+	var overrides *types.LaunchRequest
+	if o := req.Overrides; o != nil {
+		overrides = &types.LaunchRequest{
+			Flags:            o.Flags,
+			Capabilities:     o.Capabilities,
+			Environment:      o.Environment,
+			ConversationMode: types.ConversationMode(o.ConversationMode),
+			SessionID:        o.SessionID,
+			RuntimeType:      types.RuntimeType(o.RuntimeType),
+		}
+	}
+
+	runner, err := s.runnerManager.Clone(ctx, req.SourceRunnerID, req.NewProjectName, overrides, req.ResumeSession)
+	if err != nil {
+		s.logger.Error("failed to clone runner", zap.Error(err))
+		return &api.LaunchRunnerResponse{
+			Error:     err.Error(),
+			ErrorCode: errorCode(err),
 		}, nil
 	}
 
@@ -115,6 +205,57 @@ func (s *GRPCServer) StopRunner(ctx context.Context, req *api.StopRunnerRequest)
 	}, nil
 }
 
+// GetRunnerHistory lists runners in any state, including terminated and
+// failed ones
+func (s *GRPCServer) GetRunnerHistory(ctx context.Context, req *api.GetRunnerHistoryRequest) (*api.ListRunnersResponse, error) {
+	since, err := api.ParseTime(req.Since)
+	if err != nil {
+		return &api.ListRunnersResponse{Error: fmt.Sprintf("invalid since: %v", err)}, nil
+	}
+	until, err := api.ParseTime(req.Until)
+	if err != nil {
+		return &api.ListRunnersResponse{Error: fmt.Sprintf("invalid until: %v", err)}, nil
+	}
+
+	runners, err := s.storage.GetRunnerHistory(ctx, storage.RunnerHistoryFilter{
+		ProjectName: req.ProjectName,
+		Status:      types.RunnerStatus(req.Status),
+		Since:       since,
+		Until:       until,
+		Limit:       int(req.Limit),
+	})
+	if err != nil {
+		return &api.ListRunnersResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiRunners := make([]*api.Runner, len(runners))
+	for i, r := range runners {
+		apiRunners[i] = convertRunnerToAPI(r)
+	}
+
+	return &api.ListRunnersResponse{
+		Runners: apiRunners,
+	}, nil
+}
+
+// StopAllRunners stops every active runner, or only a project's
+func (s *GRPCServer) StopAllRunners(ctx context.Context, req *api.StopAllRunnersRequest) (*api.StopAllRunnersResponse, error) {
+	s.logger.Info("stop all runners request", zap.String("project", req.ProjectName))
+
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+
+	result := s.runnerManager.KillAll(ctx, req.ProjectName, timeout)
+	return &api.StopAllRunnersResponse{
+		Stopped: result.Stopped,
+		Failed:  result.Failed,
+	}, nil
+}
+
 // GetRunner retrieves runner details
 func (s *GRPCServer) GetRunner(ctx context.Context, req *api.GetRunnerRequest) (*api.GetRunnerResponse, error) {
 	runner, err := s.storage.GetRunner(ctx, req.RunnerID)
@@ -131,15 +272,11 @@ func (s *GRPCServer) GetRunner(ctx context.Context, req *api.GetRunnerRequest) (
 
 // ListRunners lists active runners
 func (s *GRPCServer) ListRunners(ctx context.Context, req *api.ListRunnersRequest) (*api.ListRunnersResponse, error) {
-	var runners []*types.Runner
-	var err error
-
-	if req.ProjectName != "" {
-		runners, err = s.storage.GetActiveRunners(ctx, req.ProjectName)
-	} else {
-		runners = s.runnerManager.GetActiveRunners()
-	}
-
+	// Read from the database; the in-memory map may diverge after a crash
+	runners, err := s.storage.GetAllActiveRunners(ctx, storage.RunnerFilter{
+		ProjectName: req.ProjectName,
+		Status:      types.RunnerStatus(req.Status),
+	})
 	if err != nil {
 		return &api.ListRunnersResponse{
 			Error: err.Error(),
@@ -157,6 +294,194 @@ func (s *GRPCServer) ListRunners(ctx context.Context, req *api.ListRunnersReques
 	}, nil
 }
 
+// GetRunnerEvents returns the lifecycle event timeline for a runner
+func (s *GRPCServer) GetRunnerEvents(ctx context.Context, req *api.GetRunnerEventsRequest) (*api.GetRunnerEventsResponse, error) {
+	events, err := s.storage.ListRunnerEvents(ctx, req.RunnerID)
+	if err != nil {
+		return &api.GetRunnerEventsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiEvents := make([]*api.RunnerEvent, len(events))
+	for i, e := range events {
+		apiEvents[i] = convertEventToAPI(e)
+	}
+
+	return &api.GetRunnerEventsResponse{
+		Events: apiEvents,
+	}, nil
+}
+
+// GetRunnerRestarts returns a runner's restart history, oldest first
+func (s *GRPCServer) GetRunnerRestarts(ctx context.Context, req *api.GetRunnerRestartsRequest) (*api.GetRunnerRestartsResponse, error) {
+	restarts, err := s.storage.GetRunnerRestarts(ctx, req.RunnerID)
+	if err != nil {
+		return &api.GetRunnerRestartsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiRestarts := make([]*api.RunnerRestart, len(restarts))
+	for i, r := range restarts {
+		apiRestarts[i] = &api.RunnerRestart{
+			Attempt:      int32(r.Attempt),
+			RestartedAt:  api.FormatTime(r.RestartedAt),
+			DelaySeconds: r.DelaySeconds,
+		}
+		if r.PreviousExitCode != nil {
+			code := int32(*r.PreviousExitCode)
+			apiRestarts[i].PreviousExitCode = &code
+		}
+	}
+
+	return &api.GetRunnerRestartsResponse{
+		Restarts: apiRestarts,
+	}, nil
+}
+
+// GetProjectEvents returns the most recent lifecycle events of a project's
+// runners, newest first
+func (s *GRPCServer) GetProjectEvents(ctx context.Context, req *api.GetProjectEventsRequest) (*api.GetRunnerEventsResponse, error) {
+	events, err := s.storage.ListProjectRunnerEvents(ctx, req.ProjectName, int(req.Limit))
+	if err != nil {
+		return &api.GetRunnerEventsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiEvents := make([]*api.RunnerEvent, len(events))
+	for i, e := range events {
+		apiEvents[i] = convertEventToAPI(e)
+	}
+
+	return &api.GetRunnerEventsResponse{
+		Events: apiEvents,
+	}, nil
+}
+
+// GetProjectQuota returns a project's resource quota, or the default quota
+// if it has none of its own
+func (s *GRPCServer) GetProjectQuota(ctx context.Context, req *api.GetProjectQuotaRequest) (*api.GetProjectQuotaResponse, error) {
+	quota, err := s.storage.GetResourceQuota(ctx, req.ProjectName)
+	if err != nil {
+		return &api.GetProjectQuotaResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &api.GetProjectQuotaResponse{
+		Quota: convertQuotaToAPI(quota),
+	}, nil
+}
+
+// minHeartbeatTTLSeconds is the shortest heartbeat TTL a project may set:
+// below the agent's 10s heartbeat interval every runner would go stale
+const minHeartbeatTTLSeconds = 10
+
+// SetProjectQuota changes the quota limits set in req, keeping the others
+func (s *GRPCServer) SetProjectQuota(ctx context.Context, req *api.SetProjectQuotaRequest) (*api.GetProjectQuotaResponse, error) {
+	s.logger.Info("set project quota request", zap.String("project", req.ProjectName))
+
+	if _, err := s.storage.GetProject(ctx, req.ProjectName); err != nil {
+		return &api.GetProjectQuotaResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	quota, err := s.storage.GetResourceQuota(ctx, req.ProjectName)
+	if err != nil {
+		return &api.GetProjectQuotaResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	if req.MaxConcurrentRunners != nil {
+		if *req.MaxConcurrentRunners < 1 {
+			return &api.GetProjectQuotaResponse{
+				Error: "max runners must be at least 1",
+			}, nil
+		}
+		quota.MaxConcurrentRunners = int(*req.MaxConcurrentRunners)
+	}
+	if req.MaxRuntimeSeconds != nil {
+		if *req.MaxRuntimeSeconds < 0 {
+			return &api.GetProjectQuotaResponse{
+				Error: "max runtime must not be negative",
+			}, nil
+		}
+		quota.MaxRuntimeSeconds = int(*req.MaxRuntimeSeconds)
+	}
+	if req.HeartbeatTTLSeconds != nil {
+		ttl := *req.HeartbeatTTLSeconds
+		if ttl != 0 && ttl < minHeartbeatTTLSeconds {
+			return &api.GetProjectQuotaResponse{
+				Error: fmt.Sprintf("heartbeat TTL must be 0 (daemon default) or at least %ds", minHeartbeatTTLSeconds),
+			}, nil
+		}
+		quota.HeartbeatTTLSeconds = int(ttl)
+	}
+
+	if err := s.storage.UpsertResourceQuota(ctx, quota); err != nil {
+		return &api.GetProjectQuotaResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &api.GetProjectQuotaResponse{
+		Quota: convertQuotaToAPI(quota),
+	}, nil
+}
+
+func convertQuotaToAPI(q *types.ResourceQuota) *api.ResourceQuota {
+	return &api.ResourceQuota{
+		ProjectName:          q.ProjectName,
+		MaxConcurrentRunners: int32(q.MaxConcurrentRunners),
+		MaxMemoryMB:          q.MaxMemoryMB,
+		MaxCPUPercent:        int32(q.MaxCPUPercent),
+		MaxTokensPerDay:      q.MaxTokensPerDay,
+		MaxCPUsPerRunner:     int32(q.MaxCPUsPerRunner),
+		MaxRuntimeSeconds:    int64(q.MaxRuntimeSeconds),
+		HeartbeatTTLSeconds:  int64(q.HeartbeatTTLSeconds),
+	}
+}
+
+// StreamLogs sends the buffered tail of a runner's output and, with
+// req.Follow, keeps streaming new lines until the runner exits or the
+// caller goes away
+func (s *GRPCServer) StreamLogs(req *api.RunnerLogsRequest, stream api.StratavoreService_StreamLogsServer) error {
+	backlog, live, cancel, err := s.runnerManager.SubscribeLogs(req.RunnerID, int(req.TailLines), req.Follow)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	for _, entry := range backlog {
+		if err := stream.Send(convertLogEntryToAPI(entry)); err != nil {
+			return err
+		}
+	}
+
+	if live == nil {
+		return nil
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case entry, ok := <-live:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(convertLogEntryToAPI(entry)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 // CreateProject creates a new project
 func (s *GRPCServer) CreateProject(ctx context.Context, req *api.CreateProjectRequest) (*api.CreateProjectResponse, error) {
 	project := &types.Project{
@@ -169,117 +494,1321 @@ func (s *GRPCServer) CreateProject(ctx context.Context, req *api.CreateProjectRe
 		UpdatedAt:   time.Now(),
 	}
 
-	err := s.storage.CreateProject(ctx, project)
+	// Re-running 'stratavore new' updates the existing project
+	created, err := s.storage.UpsertProject(ctx, project)
 	if err != nil {
 		return &api.CreateProjectResponse{
 			Error: err.Error(),
 		}, nil
 	}
+	s.invalidateProject(ctx, req.Name)
+
+	if !created {
+		if project, err = s.storage.GetProject(ctx, req.Name); err != nil {
+			return &api.CreateProjectResponse{
+				Error: err.Error(),
+			}, nil
+		}
+	}
 
 	return &api.CreateProjectResponse{
 		Project: convertProjectToAPI(project),
+		Created: created,
 	}, nil
 }
 
-// GetProject retrieves project details
-func (s *GRPCServer) GetProject(ctx context.Context, req *api.GetProjectRequest) (*api.GetProjectResponse, error) {
-	project, err := s.storage.GetProject(ctx, req.Name)
+// DuplicateProject creates a project with another project's description,
+// tags, health probe and resource quota. Runners, sessions and token usage
+// are not copied; the new project starts idle with zeroed counters.
+func (s *GRPCServer) DuplicateProject(ctx context.Context, req *api.DuplicateProjectRequest) (*api.CreateProjectResponse, error) {
+	if req.SourceName == "" || req.NewName == "" {
+		return &api.CreateProjectResponse{
+			Error: "source and new project names required",
+		}, nil
+	}
+	if req.NewPath == "" {
+		return &api.CreateProjectResponse{
+			Error: "new project path required",
+		}, nil
+	}
+
+	source, err := s.storage.GetProject(ctx, req.SourceName)
 	if err != nil {
-		return &api.GetProjectResponse{
+		return &api.CreateProjectResponse{
 			Error: err.Error(),
 		}, nil
 	}
 
-	return &api.GetProjectResponse{
-		Project: convertProjectToAPI(project),
-	}, nil
-}
+	if _, err := s.storage.GetProject(ctx, req.NewName); err == nil {
+		return &api.CreateProjectResponse{
+			Error: fmt.Sprintf("project already exists: %s", req.NewName),
+		}, nil
+	}
 
-// ListProjects lists all projects
-func (s *GRPCServer) ListProjects(ctx context.Context, req *api.ListProjectsRequest) (*api.ListProjectsResponse, error) {
-	projects, err := s.storage.ListProjects(ctx, req.Status)
+	quota, err := s.storage.GetResourceQuota(ctx, req.SourceName)
 	if err != nil {
-		return &api.ListProjectsResponse{
+		return &api.CreateProjectResponse{
 			Error: err.Error(),
 		}, nil
 	}
+	quota.ProjectName = req.NewName
 
-	apiProjects := make([]*api.Project, len(projects))
-	for i, p := range projects {
-		apiProjects[i] = convertProjectToAPI(p)
+	// Project paths are unique; catch the clash before the insert does
+	if filepath.Clean(req.NewPath) == filepath.Clean(source.Path) {
+		return &api.CreateProjectResponse{
+			Error: fmt.Sprintf("project %s already uses %s; choose a different path", req.SourceName, source.Path),
+		}, nil
 	}
 
-	return &api.ListProjectsResponse{
-		Projects: apiProjects,
+	now := time.Now()
+	project := &types.Project{
+		Name:        req.NewName,
+		Path:        req.NewPath,
+		Description: source.Description,
+		Tags:        source.Tags,
+		Status:      types.ProjectIdle,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.storage.CreateProjectSetup(ctx, project, quota, nil); err != nil {
+		return &api.CreateProjectResponse{
+			Error: err.Error(),
+		}, nil
+	}
+	s.invalidateProject(ctx, req.NewName)
+
+	if source.HealthProbe != nil {
+		if err := s.storage.SetProjectHealthProbe(ctx, req.NewName, source.HealthProbe); err != nil {
+			return &api.CreateProjectResponse{
+				Error: fmt.Sprintf("copy health probe: %v", err),
+			}, nil
+		}
+		project.HealthProbe = source.HealthProbe
+	}
+
+	hostname, _ := os.Hostname()
+	event := &types.Event{
+		Timestamp:  now,
+		EventType:  "project.duplicated",
+		EntityType: "project",
+		EntityID:   req.NewName,
+		Data: map[string]interface{}{
+			"source":      req.SourceName,
+			"destination": req.NewName,
+		},
+		Hostname: hostname,
+	}
+	if err := s.storage.InsertRunnerEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to record project duplicated event", zap.Error(err))
+	}
+
+	s.logger.Info("project duplicated",
+		zap.String("source", req.SourceName),
+		zap.String("project", req.NewName))
+
+	return &api.CreateProjectResponse{
+		Project: convertProjectToAPI(project),
+		Created: true,
 	}, nil
 }
 
-// SendHeartbeat processes heartbeat from agent
-func (s *GRPCServer) SendHeartbeat(ctx context.Context, req *api.HeartbeatRequest) (*api.HeartbeatResponse, error) {
-	hb := &types.Heartbeat{
-		RunnerID:     req.RunnerID,
-		Status:       types.RunnerStatus(req.Status),
-		Timestamp:    time.Now(),
-		CPUPercent:   req.CPUPercent,
-		MemoryMB:     req.MemoryMB,
-		TokensUsed:   req.TokensUsed,
-		SessionID:    req.SessionID,
-		AgentVersion: req.AgentVersion,
-		Hostname:     req.Hostname,
+// CreateProjectSetup creates a project with its quota and budget in a single
+// call, all or nothing
+func (s *GRPCServer) CreateProjectSetup(ctx context.Context, req *api.CreateProjectSetupRequest) (*api.CreateProjectResponse, error) {
+	if req.MaxRunners < 0 || req.TokenBudget < 0 || req.MaxRuntimeSeconds < 0 {
+		return &api.CreateProjectResponse{
+			Error: "max runners, token budget and max runtime must not be negative",
+		}, nil
 	}
 
-	err := s.runnerManager.ProcessHeartbeat(ctx, hb)
-	if err != nil {
-		s.logger.Error("heartbeat processing failed",
-			zap.String("runner_id", req.RunnerID),
-			zap.Error(err))
-		return &api.HeartbeatResponse{
-			Success: false,
-			Error:   err.Error(),
+	now := time.Now()
+	project := &types.Project{
+		Name:        req.Name,
+		Path:        req.Path,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Status:      types.ProjectIdle,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	var quota *types.ResourceQuota
+	if req.MaxRunners > 0 || req.MaxRuntimeSeconds > 0 {
+		quota = &types.ResourceQuota{
+			ProjectName:          req.Name,
+			MaxConcurrentRunners: int(req.MaxRunners),
+			MaxRuntimeSeconds:    int(req.MaxRuntimeSeconds),
+		}
+		if quota.MaxConcurrentRunners == 0 {
+			quota.MaxConcurrentRunners = storage.DefaultMaxConcurrentRunners
+		}
+	}
+
+	var tokenBudget *types.TokenBudget
+	if req.TokenBudget > 0 {
+		period := req.BudgetPeriod
+		if period == "" {
+			period = "daily"
+		}
+		start, end, err := budget.PeriodBounds(period, now)
+		if err != nil {
+			return &api.CreateProjectResponse{
+				Error: err.Error(),
+			}, nil
+		}
+		tokenBudget = &types.TokenBudget{
+			Scope:             "project",
+			ScopeID:           req.Name,
+			LimitTokens:       req.TokenBudget,
+			PeriodGranularity: period,
+			PeriodStart:       start,
+			PeriodEnd:         end,
+		}
+	}
+
+	if err := s.storage.CreateProjectSetup(ctx, project, quota, tokenBudget); err != nil {
+		return &api.CreateProjectResponse{
+			Error: err.Error(),
 		}, nil
 	}
+	s.invalidateProject(ctx, project.Name)
 
-	return &api.HeartbeatResponse{
-		Success: true,
+	return &api.CreateProjectResponse{
+		Project: convertProjectToAPI(project),
+		Created: true,
 	}, nil
 }
 
-// GetStatus returns daemon status
-func (s *GRPCServer) GetStatus(ctx context.Context, req *api.GetStatusRequest) (*api.GetStatusResponse, error) {
-	activeRunners := len(s.runnerManager.GetActiveRunners())
+// UpdateProject changes a project's description, path or tags and returns
+// the updated project
+func (s *GRPCServer) UpdateProject(ctx context.Context, req *api.UpdateProjectRequest) (*api.GetProjectResponse, error) {
+	s.logger.Info("update project request", zap.String("project", req.Name))
 
-	metrics := &api.GlobalMetrics{
-		ActiveRunners: int32(activeRunners),
+	updates := storage.ProjectUpdates{
+		Description: req.Description,
+		Path:        req.Path,
+		Tags:        req.Tags,
 	}
-
-	daemonStatus := &api.DaemonStatus{
-		Healthy:       true,
-		LastHeartbeat: time.Now().Format(time.RFC3339),
+	if err := s.storage.UpdateProject(ctx, req.Name, updates); err != nil {
+		return &api.GetProjectResponse{
+			Error: err.Error(),
+		}, nil
 	}
 
-	return &api.GetStatusResponse{
-		Daemon:  daemonStatus,
-		Metrics: metrics,
-	}, nil
+	s.invalidateProject(ctx, req.Name)
+
+	return s.GetProject(ctx, &api.GetProjectRequest{Name: req.Name})
 }
 
-// TriggerReconciliation manually triggers stale runner cleanup
-func (s *GRPCServer) TriggerReconciliation(ctx context.Context, req *api.TriggerReconciliationRequest) (*api.TriggerReconciliationResponse, error) {
-	s.logger.Info("manual reconciliation triggered")
+// MoveProject changes a project's path after its directory has been moved,
+// keeping the old path in previous_path. Active runners on this node are
+// sent the configured directory changed signal so they can re-initialise.
+func (s *GRPCServer) MoveProject(ctx context.Context, req *api.MoveProjectRequest) (*api.MoveProjectResponse, error) {
+	s.logger.Info("move project request",
+		zap.String("project", req.Name),
+		zap.String("new_path", req.NewPath))
 
-	err := s.runnerManager.ReconcileRunners(ctx)
+	if !filepath.IsAbs(req.NewPath) {
+		return &api.MoveProjectResponse{
+			Error: fmt.Sprintf("new path must be absolute: %q", req.NewPath),
+		}, nil
+	}
+	newPath := filepath.Clean(req.NewPath)
+
+	info, err := os.Stat(newPath)
 	if err != nil {
-		return &api.TriggerReconciliationResponse{
+		return &api.MoveProjectResponse{
+			Error: fmt.Sprintf("new path: %v", err),
+		}, nil
+	}
+	if !info.IsDir() {
+		return &api.MoveProjectResponse{
+			Error: fmt.Sprintf("new path is not a directory: %s", newPath),
+		}, nil
+	}
+
+	project, err := s.storage.GetProject(ctx, req.Name)
+	if err != nil {
+		return &api.MoveProjectResponse{
 			Error: err.Error(),
 		}, nil
 	}
+	oldPath := project.Path
+	if oldPath == newPath {
+		return &api.MoveProjectResponse{
+			Error: fmt.Sprintf("project %s is already at %s", req.Name, newPath),
+		}, nil
+	}
 
-	return &api.TriggerReconciliationResponse{
-		ReconciledCount: 0,
-	}, nil
-}
+	if err := s.storage.UpdateProject(ctx, req.Name, storage.ProjectUpdates{Path: &newPath}); err != nil {
+		return &api.MoveProjectResponse{
+			Error: err.Error(),
+		}, nil
+	}
+	s.invalidateProject(ctx, req.Name)
 
-// Helper functions to convert between types
+	signalled := s.runnerManager.SignalDirChanged(req.Name)
+
+	hostname, _ := os.Hostname()
+	event := &types.Event{
+		Timestamp:  time.Now(),
+		EventType:  "project.moved",
+		EntityType: "project",
+		EntityID:   req.Name,
+		Data: map[string]interface{}{
+			"old_path":          oldPath,
+			"new_path":          newPath,
+			"signalled_runners": signalled,
+		},
+		Hostname: hostname,
+	}
+	if err := s.storage.InsertRunnerEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to record project moved event", zap.Error(err))
+	}
+
+	s.logger.Info("project moved",
+		zap.String("project", req.Name),
+		zap.String("old_path", oldPath),
+		zap.String("new_path", newPath),
+		zap.Int("signalled_runners", signalled))
+
+	project.Path = newPath
+	return &api.MoveProjectResponse{
+		Project:          convertProjectToAPI(project),
+		OldPath:          oldPath,
+		SignalledRunners: int32(signalled),
+	}, nil
+}
+
+// invalidateProject drops a changed project, and the cached project lists,
+// from the cache
+func (s *GRPCServer) invalidateProject(ctx context.Context, name string) {
+	if s.cache != nil {
+		s.cache.InvalidateProject(ctx, name)
+	}
+}
+
+// GetProject retrieves project details
+func (s *GRPCServer) GetProject(ctx context.Context, req *api.GetProjectRequest) (*api.GetProjectResponse, error) {
+	project, err := s.storage.GetProject(ctx, req.Name)
+	if err != nil {
+		return &api.GetProjectResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	stats, err := s.projectStats(ctx, req.Name)
+	if err != nil {
+		return &api.GetProjectResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiProject := convertProjectToAPI(project)
+	apiProject.ActiveRunners = int32(stats.ActiveRunners)
+	apiProject.ActiveTokens = stats.ActiveTokens
+	apiProject.SessionsThisWeek = int32(stats.SessionsThisWeek)
+	apiProject.AvgSessionSeconds = stats.AvgSessionSeconds
+
+	return &api.GetProjectResponse{
+		Project: apiProject,
+	}, nil
+}
+
+// projectStats returns live project aggregates, served from cache when fresh
+func (s *GRPCServer) projectStats(ctx context.Context, name string) (*types.ProjectStats, error) {
+	if s.cache != nil {
+		if stats := s.cache.GetProjectStats(ctx, name); stats != nil {
+			return stats, nil
+		}
+	}
+
+	stats, err := s.storage.GetProjectStats(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("project stats: %w", err)
+	}
+
+	if s.cache != nil {
+		s.cache.SetProjectStats(ctx, stats)
+	}
+	return stats, nil
+}
+
+// ListProjects lists all projects
+func (s *GRPCServer) ListProjects(ctx context.Context, req *api.ListProjectsRequest) (*api.ListProjectsResponse, error) {
+	var projects []*types.Project
+	if s.cache != nil {
+		projects = s.cache.GetProjectList(ctx, req.Status)
+	}
+	if projects == nil {
+		var err error
+		projects, err = s.storage.ListProjects(ctx, req.Status)
+		if err != nil {
+			return &api.ListProjectsResponse{
+				Error: err.Error(),
+			}, nil
+		}
+		if s.cache != nil {
+			s.cache.SetProjectList(ctx, req.Status, projects)
+		}
+	}
+
+	// Archived projects are hidden unless asked for explicitly
+	apiProjects := make([]*api.Project, 0, len(projects))
+	for _, p := range projects {
+		if p.Status == types.ProjectArchived && req.Status == "" && !req.IncludeArchived {
+			continue
+		}
+		apiProjects = append(apiProjects, convertProjectToAPI(p))
+	}
+
+	return &api.ListProjectsResponse{
+		Projects: apiProjects,
+	}, nil
+}
+
+// SearchProjects lists projects matching the request's name, tag, status and
+// creation date conditions
+func (s *GRPCServer) SearchProjects(ctx context.Context, req *api.SearchProjectsRequest) (*api.ListProjectsResponse, error) {
+	createdAfter, err := api.ParseTime(req.CreatedAfter)
+	if err != nil {
+		return &api.ListProjectsResponse{Error: fmt.Sprintf("invalid created_after: %v", err)}, nil
+	}
+	createdBefore, err := api.ParseTime(req.CreatedBefore)
+	if err != nil {
+		return &api.ListProjectsResponse{Error: fmt.Sprintf("invalid created_before: %v", err)}, nil
+	}
+
+	query := storage.ProjectSearchQuery{
+		NameContains:     req.Query,
+		Tags:             req.Tags,
+		CreatedAfter:     createdAfter,
+		CreatedBefore:    createdBefore,
+		HasActiveRunners: req.HasActiveRunners,
+	}
+	for _, st := range req.Status {
+		query.Status = append(query.Status, types.ProjectStatus(st))
+	}
+
+	projects, err := s.storage.SearchProjects(ctx, query)
+	if err != nil {
+		return &api.ListProjectsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiProjects := make([]*api.Project, len(projects))
+	for i, p := range projects {
+		apiProjects[i] = convertProjectToAPI(p)
+	}
+
+	return &api.ListProjectsResponse{
+		Projects: apiProjects,
+	}, nil
+}
+
+// ArchiveProject archives a project
+func (s *GRPCServer) ArchiveProject(ctx context.Context, req *api.ArchiveProjectRequest) (*api.ArchiveProjectResponse, error) {
+	s.logger.Info("archive project request", zap.String("project", req.Name))
+
+	if err := s.storage.ArchiveProject(ctx, req.Name); err != nil {
+		return &api.ArchiveProjectResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	s.invalidateProject(ctx, req.Name)
+
+	return &api.ArchiveProjectResponse{
+		Success: true,
+	}, nil
+}
+
+// DrainProject stops all of a project's runners and archives it
+func (s *GRPCServer) DrainProject(ctx context.Context, req *api.DrainProjectRequest) (*api.DrainProjectResponse, error) {
+	s.logger.Info("drain project request", zap.String("project", req.Name))
+
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+
+	result, err := s.runnerManager.DrainProject(ctx, req.Name, timeout)
+	s.invalidateProject(ctx, req.Name)
+	resp := &api.DrainProjectResponse{}
+	if result != nil {
+		resp.Graceful = int32(result.Graceful)
+		resp.Forced = int32(result.Forced)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+		resp.ErrorCode = errorCode(err)
+		return resp, nil
+	}
+
+	resp.Success = true
+	return resp, nil
+}
+
+// errorCode returns the API error code for err, or "" if clients have no
+// reason to tell it apart from other failures
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrProjectArchived):
+		return api.ErrorCodeProjectArchived
+	case errors.Is(err, ErrProjectDraining):
+		return api.ErrorCodeProjectDraining
+	case errors.Is(err, ErrNotClonable):
+		return api.ErrorCodeNotClonable
+	}
+	return ""
+}
+
+// UnarchiveProject restores an archived project
+func (s *GRPCServer) UnarchiveProject(ctx context.Context, req *api.ArchiveProjectRequest) (*api.ArchiveProjectResponse, error) {
+	s.logger.Info("unarchive project request", zap.String("project", req.Name))
+
+	if err := s.storage.UnarchiveProject(ctx, req.Name); err != nil {
+		return &api.ArchiveProjectResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+	s.invalidateProject(ctx, req.Name)
+
+	return &api.ArchiveProjectResponse{
+		Success: true,
+	}, nil
+}
+
+// ListSessions lists recent sessions
+func (s *GRPCServer) ListSessions(ctx context.Context, req *api.ListSessionsRequest) (*api.ListSessionsResponse, error) {
+	sessions, err := s.storage.ListSessions(ctx, req.ProjectName, int(req.Limit))
+	if err != nil {
+		return &api.ListSessionsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiSessions := make([]*api.Session, len(sessions))
+	for i, sess := range sessions {
+		apiSessions[i] = convertSessionToAPI(sess)
+	}
+
+	return &api.ListSessionsResponse{
+		Sessions: apiSessions,
+	}, nil
+}
+
+// GetSessionsByTimeRange lists sessions started in a time window with their
+// total token usage
+func (s *GRPCServer) GetSessionsByTimeRange(ctx context.Context, req *api.GetSessionsByTimeRangeRequest) (*api.GetSessionsByTimeRangeResponse, error) {
+	from, err := api.ParseTime(req.From)
+	if err != nil {
+		return &api.GetSessionsByTimeRangeResponse{Error: fmt.Sprintf("invalid from: %v", err)}, nil
+	}
+	to, err := api.ParseTime(req.To)
+	if err != nil {
+		return &api.GetSessionsByTimeRangeResponse{Error: fmt.Sprintf("invalid to: %v", err)}, nil
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	sessions, totalTokens, err := s.storage.GetSessionsByTimeRange(ctx, from, to, req.ProjectName, req.IncludeActive)
+	if err != nil {
+		return &api.GetSessionsByTimeRangeResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiSessions := make([]*api.Session, len(sessions))
+	for i, sess := range sessions {
+		apiSessions[i] = convertSessionToAPI(sess)
+	}
+
+	return &api.GetSessionsByTimeRangeResponse{
+		Sessions:      apiSessions,
+		TotalSessions: int32(len(apiSessions)),
+		TotalTokens:   totalTokens,
+	}, nil
+}
+
+// SearchSessions runs a full-text search over session summaries
+func (s *GRPCServer) SearchSessions(ctx context.Context, req *api.SearchSessionsRequest) (*api.ListSessionsResponse, error) {
+	if req.Query == "" {
+		return &api.ListSessionsResponse{
+			Error: "query required",
+		}, nil
+	}
+
+	sessions, err := s.storage.SearchSessions(ctx, req.Query, req.ProjectName, int(req.Limit))
+	if err != nil {
+		return &api.ListSessionsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiSessions := make([]*api.Session, len(sessions))
+	for i, sess := range sessions {
+		apiSessions[i] = convertSessionToAPI(sess)
+	}
+
+	return &api.ListSessionsResponse{
+		Sessions: apiSessions,
+	}, nil
+}
+
+// SetVectorStore enables semantic session search
+func (s *GRPCServer) SetVectorStore(vectors *vectorstore.QdrantClient) {
+	s.vectors = vectors
+}
+
+// SemanticSearchSessions finds sessions whose summaries are closest in
+// meaning to the query, best match first
+func (s *GRPCServer) SemanticSearchSessions(ctx context.Context, req *api.SemanticSearchSessionsRequest) (*api.ListSessionsResponse, error) {
+	if s.vectors == nil {
+		return &api.ListSessionsResponse{
+			Error: "semantic search is not enabled",
+		}, nil
+	}
+	if req.Query == "" {
+		return &api.ListSessionsResponse{
+			Error: "query required",
+		}, nil
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 10
+	}
+
+	matches, err := s.vectors.SearchSessions(ctx, req.Query, req.ProjectName, limit)
+	if err != nil {
+		return &api.ListSessionsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiSessions := make([]*api.Session, 0, len(matches))
+	for _, match := range matches {
+		sess, err := s.storage.GetSession(ctx, match.SessionID)
+		if err != nil {
+			// Indexed but since removed by retention cleanup
+			s.logger.Debug("skipping semantic match",
+				zap.String("session_id", match.SessionID),
+				zap.Error(err))
+			continue
+		}
+		apiSession := convertSessionToAPI(sess)
+		apiSession.Score = match.Score
+		apiSession.Snippet = sess.Summary
+		apiSessions = append(apiSessions, apiSession)
+	}
+
+	return &api.ListSessionsResponse{
+		Sessions: apiSessions,
+	}, nil
+}
+
+// PinSession protects a session from retention cleanup
+func (s *GRPCServer) PinSession(ctx context.Context, req *api.PinSessionRequest) (*api.PinSessionResponse, error) {
+	if err := s.storage.PinSession(ctx, req.SessionID); err != nil {
+		return &api.PinSessionResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.PinSessionResponse{
+		Success: true,
+	}, nil
+}
+
+// UnpinSession makes a session eligible for retention cleanup again
+func (s *GRPCServer) UnpinSession(ctx context.Context, req *api.PinSessionRequest) (*api.PinSessionResponse, error) {
+	if err := s.storage.UnpinSession(ctx, req.SessionID); err != nil {
+		return &api.PinSessionResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.PinSessionResponse{
+		Success: true,
+	}, nil
+}
+
+// CleanupSessions deletes non-resumable sessions without a stored
+// transcript that ended before req.Before, or only counts them on a dry run
+func (s *GRPCServer) CleanupSessions(ctx context.Context, req *api.CleanupSessionsRequest) (*api.CleanupSessionsResponse, error) {
+	before, err := api.ParseTime(req.Before)
+	if err != nil || before.IsZero() {
+		return &api.CleanupSessionsResponse{Error: fmt.Sprintf("invalid before: %q", req.Before)}, nil
+	}
+
+	clean := s.storage.CleanSessions
+	if req.DryRun {
+		clean = s.storage.CountCleanableSessions
+	}
+
+	deleted, tokens, err := clean(ctx, before, req.ProjectName)
+	if err != nil {
+		return &api.CleanupSessionsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	if !req.DryRun {
+		s.logger.Info("cleaned sessions",
+			zap.Int64("deleted", deleted),
+			zap.Time("before", before),
+			zap.String("project", req.ProjectName))
+	}
+
+	return &api.CleanupSessionsResponse{
+		Deleted:     deleted,
+		TokensFreed: tokens,
+		DryRun:      req.DryRun,
+	}, nil
+}
+
+// SetSessionManager enables the session operations that need transcripts
+func (s *GRPCServer) SetSessionManager(sessions *session.Manager) {
+	s.sessions = sessions
+}
+
+// BranchSession forks a session after the given message and launches a
+// runner that resumes the new branch
+func (s *GRPCServer) BranchSession(ctx context.Context, req *api.BranchSessionRequest) (*api.BranchSessionResponse, error) {
+	if s.sessions == nil {
+		return &api.BranchSessionResponse{
+			Error: "session branching not available",
+		}, nil
+	}
+
+	branch, err := s.sessions.BranchSession(ctx, req.SourceSessionID, int(req.ForkAfterMessage))
+	if err != nil {
+		return &api.BranchSessionResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	runner, err := s.launchResumeRunner(ctx, branch)
+	if err != nil {
+		return &api.BranchSessionResponse{
+			Session: convertSessionToAPI(branch),
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.BranchSessionResponse{
+		Session: convertSessionToAPI(branch),
+		Runner:  convertRunnerToAPI(runner),
+	}, nil
+}
+
+// ResumeSession reattaches to a session's runner if it is still running,
+// otherwise launches a new runner that resumes the session
+func (s *GRPCServer) ResumeSession(ctx context.Context, req *api.ResumeSessionRequest) (*api.ResumeSessionResponse, error) {
+	if s.sessions == nil {
+		return &api.ResumeSessionResponse{
+			Error: "session resume not available",
+		}, nil
+	}
+
+	info, err := s.sessions.ResumeSession(ctx, req.SessionID)
+	if err != nil {
+		return &api.ResumeSessionResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	if info.RunnerActive {
+		runner, err := s.storage.GetRunner(ctx, info.RunnerID)
+		if err != nil {
+			return &api.ResumeSessionResponse{
+				Error: err.Error(),
+			}, nil
+		}
+		return &api.ResumeSessionResponse{
+			Session:      convertSessionToAPI(info.Session),
+			Runner:       convertRunnerToAPI(runner),
+			RunnerActive: true,
+		}, nil
+	}
+
+	runner, err := s.launchResumeRunner(ctx, info.Session)
+	if err != nil {
+		return &api.ResumeSessionResponse{
+			Session: convertSessionToAPI(info.Session),
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.ResumeSessionResponse{
+		Session: convertSessionToAPI(info.Session),
+		Runner:  convertRunnerToAPI(runner),
+	}, nil
+}
+
+// launchResumeRunner starts a runner in the session's project that resumes
+// the session's conversation
+func (s *GRPCServer) launchResumeRunner(ctx context.Context, sess *types.Session) (*types.Runner, error) {
+	project, err := s.storage.GetProject(ctx, sess.ProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+
+	runner, err := s.runnerManager.Launch(ctx, &types.LaunchRequest{
+		ProjectName:      sess.ProjectName,
+		ProjectPath:      project.Path,
+		ConversationMode: types.ModeResume,
+		SessionID:        sess.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("launch runner: %w", err)
+	}
+
+	return runner, nil
+}
+
+// CreatePreset stores a reusable launch configuration
+func (s *GRPCServer) CreatePreset(ctx context.Context, req *api.CreatePresetRequest) (*api.CreatePresetResponse, error) {
+	if req.Preset == nil || req.Preset.Name == "" {
+		return &api.CreatePresetResponse{
+			Error: "preset name required",
+		}, nil
+	}
+
+	preset := &types.Preset{
+		Name:             req.Preset.Name,
+		ProjectName:      req.Preset.ProjectName,
+		RuntimeType:      types.RuntimeType(req.Preset.RuntimeType),
+		Flags:            req.Preset.Flags,
+		Capabilities:     req.Preset.Capabilities,
+		Environment:      req.Preset.Environment,
+		ConversationMode: types.ConversationMode(req.Preset.ConversationMode),
+		Description:      req.Preset.Description,
+	}
+
+	if err := s.storage.CreatePreset(ctx, preset); err != nil {
+		return &api.CreatePresetResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	created, err := s.storage.GetPreset(ctx, preset.Name)
+	if err != nil {
+		return &api.CreatePresetResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &api.CreatePresetResponse{
+		Preset: convertPresetToAPI(created),
+	}, nil
+}
+
+// ListPresets lists presets usable by a project
+func (s *GRPCServer) ListPresets(ctx context.Context, req *api.ListPresetsRequest) (*api.ListPresetsResponse, error) {
+	presets, err := s.storage.ListPresets(ctx, req.ProjectName)
+	if err != nil {
+		return &api.ListPresetsResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiPresets := make([]*api.Preset, len(presets))
+	for i, p := range presets {
+		apiPresets[i] = convertPresetToAPI(p)
+	}
+
+	return &api.ListPresetsResponse{
+		Presets: apiPresets,
+	}, nil
+}
+
+// DeletePreset removes a preset
+func (s *GRPCServer) DeletePreset(ctx context.Context, req *api.DeletePresetRequest) (*api.DeletePresetResponse, error) {
+	if err := s.storage.DeletePreset(ctx, req.Name); err != nil {
+		return &api.DeletePresetResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.DeletePresetResponse{
+		Success: true,
+	}, nil
+}
+
+// SendHeartbeat processes heartbeat from agent
+func (s *GRPCServer) SendHeartbeat(ctx context.Context, req *api.HeartbeatRequest) (*api.HeartbeatResponse, error) {
+	hb := &types.Heartbeat{
+		RunnerID:     req.RunnerID,
+		Status:       types.RunnerStatus(req.Status),
+		Timestamp:    time.Now(),
+		CPUPercent:   req.CPUPercent,
+		MemoryMB:     req.MemoryMB,
+		TokensUsed:   req.TokensUsed,
+		SessionID:    req.SessionID,
+		AgentVersion: req.AgentVersion,
+		Hostname:     req.Hostname,
+		ErrorContext: req.ErrorContext,
+	}
+
+	for _, cpu := range req.CPUAffinity {
+		hb.CPUAffinity = append(hb.CPUAffinity, int(cpu))
+	}
+
+	if s.sessions != nil && req.SessionID != "" {
+		hb.MessagesPerMinute = s.sessions.MessagesPerMinute(ctx, req.SessionID)
+	}
+
+	if req.PreflightResult != nil {
+		hb.PreflightResult = &types.PreflightResult{Passed: req.PreflightResult.Passed}
+		for _, c := range req.PreflightResult.Checks {
+			hb.PreflightResult.Checks = append(hb.PreflightResult.Checks, types.PreflightCheck{
+				Name:     c.Name,
+				Passed:   c.Passed,
+				Critical: c.Critical,
+				Message:  c.Message,
+			})
+		}
+	}
+
+	for _, g := range req.GPUSamples {
+		hb.GPUSamples = append(hb.GPUSamples, types.GPUSample{
+			GPUIndex:           int(g.GPUIndex),
+			UtilizationPercent: g.UtilizationPercent,
+			MemoryUsedMB:       g.MemoryUsedMB,
+			MemoryTotalMB:      g.MemoryTotalMB,
+		})
+	}
+
+	err := s.runnerManager.ProcessHeartbeat(ctx, hb)
+	if errors.Is(err, storage.ErrRunnerTerminated) {
+		s.logger.Warn("heartbeat from terminated runner, telling agent to exit",
+			zap.String("runner_id", req.RunnerID),
+			zap.Error(err))
+		return &api.HeartbeatResponse{
+			Success: false,
+			Command: api.HeartbeatCommandExit,
+			Error:   err.Error(),
+		}, nil
+	}
+	if err != nil {
+		s.logger.Error("heartbeat processing failed",
+			zap.String("runner_id", req.RunnerID),
+			zap.Error(err))
+		return &api.HeartbeatResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &api.HeartbeatResponse{
+		Success: true,
+	}, nil
+}
+
+// GetStatus returns daemon status
+func (s *GRPCServer) GetStatus(ctx context.Context, req *api.GetStatusRequest) (*api.GetStatusResponse, error) {
+	byStatus := make(map[string]int32)
+	var active int
+	var tokensUsed int64
+	if summary, err := s.storage.GetRunnerMetricsSummary(ctx); err == nil {
+		for status, sc := range summary.ByStatus {
+			byStatus[string(status)] = int32(sc.Count)
+		}
+		active = summary.Total()
+		tokensUsed = summary.TotalTokensUsed
+	} else {
+		// Fall back to this daemon's own runners
+		s.logger.Debug("failed to get runner metrics summary", zap.Error(err))
+		runners := s.runnerManager.GetActiveRunners()
+		for _, r := range runners {
+			byStatus[string(r.Status)]++
+			tokensUsed += r.TokensUsed
+		}
+		active = len(runners)
+	}
+
+	metrics := &api.GlobalMetrics{
+		ActiveRunners:   int32(active),
+		TokensUsed:      tokensUsed,
+		RunnersByStatus: byStatus,
+		QueuedLaunches:  int32(s.runnerManager.QueuedLaunches()),
+	}
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if tokens, err := s.storage.GetTokensUsedSince(ctx, midnight); err == nil {
+		metrics.TokensToday = tokens
+	} else {
+		s.logger.Debug("failed to get tokens used today", zap.Error(err))
+	}
+
+	hostname, _ := os.Hostname()
+	daemonStatus := &api.DaemonStatus{
+		Hostname:      hostname,
+		Version:       s.version,
+		StartedAt:     api.FormatTime(s.startedAt),
+		Healthy:       true,
+		LastHeartbeat: now.Format(time.RFC3339),
+		PID:           int32(os.Getpid()),
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		Goroutines:    int32(runtime.NumGoroutine()),
+	}
+
+	return &api.GetStatusResponse{
+		Daemon:  daemonStatus,
+		Metrics: metrics,
+	}, nil
+}
+
+// CheckBudget reports whether a launch estimated at req.EstimatedTokens fits
+// within the global and project token budgets
+func (s *GRPCServer) CheckBudget(ctx context.Context, req *api.CheckBudgetRequest) (*api.CheckBudgetResponse, error) {
+	if s.runnerManager.budgets == nil {
+		return &api.CheckBudgetResponse{
+			Allowed:   true,
+			Unlimited: true,
+		}, nil
+	}
+
+	remaining, unlimited, err := s.runnerManager.budgets.RemainingTokens(ctx, req.ProjectName)
+	if err != nil {
+		return &api.CheckBudgetResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	resp := &api.CheckBudgetResponse{
+		Allowed:         true,
+		RemainingTokens: remaining,
+		Unlimited:       unlimited,
+	}
+
+	if err := s.runnerManager.CheckBudget(ctx, req.ProjectName, req.EstimatedTokens); err != nil {
+		resp.Allowed = false
+		resp.Error = err.Error()
+	}
+
+	return resp, nil
+}
+
+// GetUsageReport returns daily token usage trends for a budget scope
+func (s *GRPCServer) GetUsageReport(ctx context.Context, req *api.GetUsageReportRequest) (*api.GetUsageReportResponse, error) {
+	if s.runnerManager.budgets == nil {
+		return &api.GetUsageReportResponse{
+			Error: "budget tracking is disabled",
+		}, nil
+	}
+
+	report, err := s.runnerManager.budgets.GetUsageReport(ctx, req.Scope, req.ScopeID, int(req.Days))
+	if err != nil {
+		return &api.GetUsageReportResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	resp := &api.GetUsageReportResponse{
+		Scope:                 report.Scope,
+		ScopeID:               report.ScopeID,
+		DailyUsage:            make([]*api.DailyUsagePoint, len(report.DailyUsage)),
+		TotalUsage:            report.TotalUsage,
+		AverageDailyUsage:     report.AverageDailyUsage,
+		PeakDayUsage:          report.PeakDayUsage,
+		ProjectedMonthlyUsage: report.ProjectedMonthlyUsage,
+	}
+	for i, p := range report.DailyUsage {
+		resp.DailyUsage[i] = &api.DailyUsagePoint{
+			Date:   p.Date.Format("2006-01-02"),
+			Tokens: p.Tokens,
+		}
+	}
+	if !report.PeakDayDate.IsZero() {
+		resp.PeakDayDate = report.PeakDayDate.Format("2006-01-02")
+	}
+
+	return resp, nil
+}
+
+// GetTokenLeaderboard ranks projects by token usage over a period
+func (s *GRPCServer) GetTokenLeaderboard(ctx context.Context, req *api.GetTokenLeaderboardRequest) (*api.GetTokenLeaderboardResponse, error) {
+	entries, err := s.storage.GetTokenUsageLeaderboard(ctx, int(req.Limit), req.Period)
+	if err != nil {
+		return &api.GetTokenLeaderboardResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	resp := &api.GetTokenLeaderboardResponse{
+		Period:  req.Period,
+		Entries: make([]*api.TokenLeaderboardEntry, len(entries)),
+	}
+	for i, e := range entries {
+		resp.Entries[i] = &api.TokenLeaderboardEntry{
+			Rank:             int32(e.Rank),
+			ProjectName:      e.ProjectName,
+			TokensUsed:       e.TokensUsed,
+			EstimatedCostUSD: e.EstimatedCostUSD,
+		}
+	}
+
+	return resp, nil
+}
+
+// GetBudgetHistory lists a budget scope's periods, newest first
+func (s *GRPCServer) GetBudgetHistory(ctx context.Context, req *api.GetBudgetHistoryRequest) (*api.GetBudgetHistoryResponse, error) {
+	budgets, err := s.storage.GetBudgetHistory(ctx, req.Scope, req.ScopeID, int(req.Limit))
+	if err != nil {
+		return &api.GetBudgetHistoryResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	now := time.Now()
+	periods := make([]*api.BudgetPeriod, len(budgets))
+	for i, b := range budgets {
+		periods[i] = &api.BudgetPeriod{
+			PeriodGranularity: b.PeriodGranularity,
+			PeriodStart:       api.FormatTime(b.PeriodStart),
+			PeriodEnd:         api.FormatTime(b.PeriodEnd),
+			LimitTokens:       b.LimitTokens,
+			UsedTokens:        b.UsedTokens,
+			Current:           !now.Before(b.PeriodStart) && now.Before(b.PeriodEnd),
+		}
+	}
+
+	return &api.GetBudgetHistoryResponse{
+		Periods: periods,
+	}, nil
+}
+
+// ListNodes lists every daemon registered against the shared database
+func (s *GRPCServer) ListNodes(ctx context.Context) (*api.ListNodesResponse, error) {
+	nodes, err := s.storage.ListDaemonNodes(ctx)
+	if err != nil {
+		return &api.ListNodesResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiNodes := make([]*api.DaemonNode, len(nodes))
+	for i, n := range nodes {
+		apiNodes[i] = &api.DaemonNode{
+			NodeID:        n.NodeID,
+			Hostname:      n.Hostname,
+			Version:       n.Version,
+			StartedAt:     api.FormatTime(n.StartedAt),
+			LastHeartbeat: api.FormatTime(n.LastHeartbeat),
+			GRPCPort:      int32(n.GRPCPort),
+			HTTPPort:      int32(n.HTTPPort),
+			ActiveRunners: int32(n.ActiveRunners),
+			Status:        string(n.Status),
+		}
+	}
+
+	return &api.ListNodesResponse{
+		Nodes: apiNodes,
+	}, nil
+}
+
+// CreateNotificationRoute sends a project's (or, without a project, every
+// project's) notifications to a backend target
+func (s *GRPCServer) CreateNotificationRoute(ctx context.Context, req *api.CreateNotificationRouteRequest) (*api.CreateNotificationRouteResponse, error) {
+	if req.Route == nil {
+		return &api.CreateNotificationRouteResponse{
+			Error: "route required",
+		}, nil
+	}
+
+	route := &types.NotificationRoute{
+		ProjectName: req.Route.ProjectName,
+		Backend:     req.Route.Backend,
+		Target:      req.Route.Target,
+		EventTypes:  req.Route.EventTypes,
+	}
+
+	if err := notifications.ValidateRoute(route); err != nil {
+		return &api.CreateNotificationRouteResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	if route.ProjectName != "" {
+		if _, err := s.storage.GetProject(ctx, route.ProjectName); err != nil {
+			return &api.CreateNotificationRouteResponse{
+				Error: err.Error(),
+			}, nil
+		}
+	}
+
+	if err := s.storage.CreateNotificationRoute(ctx, route); err != nil {
+		return &api.CreateNotificationRouteResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &api.CreateNotificationRouteResponse{
+		Route: &api.NotificationRoute{
+			ID:          route.ID,
+			ProjectName: route.ProjectName,
+			Backend:     route.Backend,
+			Target:      route.Target,
+			EventTypes:  route.EventTypes,
+			CreatedAt:   api.FormatTime(route.CreatedAt),
+		},
+	}, nil
+}
+
+// GetNotificationHistory lists recent notification delivery attempts
+func (s *GRPCServer) GetNotificationHistory(ctx context.Context, req *api.GetNotificationHistoryRequest) (*api.GetNotificationHistoryResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	entries, err := s.storage.GetNotificationHistory(ctx, limit, req.EventType)
+	if err != nil {
+		return &api.GetNotificationHistoryResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiEntries := make([]*api.NotificationLogEntry, len(entries))
+	for i, e := range entries {
+		apiEntries[i] = &api.NotificationLogEntry{
+			ID:          e.ID,
+			SentAt:      api.FormatTime(e.SentAt),
+			Backend:     e.Backend,
+			EventType:   e.EventType,
+			Target:      e.Target,
+			MessageText: e.MessageText,
+			Success:     e.Success,
+			Error:       e.Error,
+		}
+	}
+
+	return &api.GetNotificationHistoryResponse{
+		Entries: apiEntries,
+	}, nil
+}
+
+// GetActivity returns the recent activity feed across all projects
+func (s *GRPCServer) GetActivity(ctx context.Context, req *api.GetActivityRequest) (*api.GetActivityResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	events, err := s.storage.GetProjectActivity(ctx, limit)
+	if err != nil {
+		return &api.GetActivityResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	apiEvents := make([]*api.ActivityEvent, len(events))
+	for i, e := range events {
+		apiEvents[i] = &api.ActivityEvent{
+			Timestamp:   api.FormatTime(e.Timestamp),
+			EventType:   e.EventType,
+			ProjectName: e.ProjectName,
+			ActorID:     e.ActorID,
+			Description: e.Description,
+		}
+	}
+
+	return &api.GetActivityResponse{
+		Events: apiEvents,
+	}, nil
+}
+
+// redisReadinessTimeout bounds the Redis ping in GetReadiness, so a hung
+// connection fails the check instead of stalling the probe
+const redisReadinessTimeout = 2 * time.Second
+
+// GetReadiness reports whether the daemon's dependencies are reachable
+func (s *GRPCServer) GetReadiness(ctx context.Context, req *api.GetReadinessRequest) (*api.GetReadinessResponse, error) {
+	resp := &api.GetReadinessResponse{Ready: true}
+
+	// A ping only proves one connection works, so also check the pool
+	// still has connections and isn't close to running out
+	pg := &api.ComponentHealth{Name: "postgres", Status: "ok"}
+	err := s.storage.HealthCheck(ctx)
+	stats := s.storage.PoolStats()
+	switch {
+	case err != nil:
+		pg.Status = "down"
+		pg.Detail = err.Error()
+		resp.Ready = false
+	case stats.TotalConns < 1:
+		pg.Status = "down"
+		pg.Detail = "no open connections"
+		resp.Ready = false
+	case stats.Saturated():
+		pg.Status = "degraded"
+		pg.Detail = fmt.Sprintf("%d of %d connections in use", stats.AcquiredConns, stats.MaxConns)
+		resp.Ready = false
+		resp.Degraded = true
+		resp.Reason = "pool_saturation"
+	}
+	resp.Postgres = &api.PoolStats{
+		TotalConns:    stats.TotalConns,
+		AcquiredConns: stats.AcquiredConns,
+		IdleConns:     stats.IdleConns,
+		MaxConns:      stats.MaxConns,
+		AcquireCount:  stats.AcquireCount,
+	}
+
+	mq := &api.ComponentHealth{Name: "rabbitmq", Status: "ok"}
+	if s.runnerManager.messaging == nil {
+		mq.Status = "disabled"
+	} else if !s.runnerManager.messaging.IsConnected() {
+		mq.Status = "down"
+		mq.Detail = s.runnerManager.messaging.State().String()
+		resp.Ready = false
+	}
+
+	redis := &api.ComponentHealth{Name: "redis", Status: "disabled"}
+	if s.cache != nil && s.cache.Enabled() {
+		redis.Status = "ok"
+		pingCtx, cancel := context.WithTimeout(ctx, redisReadinessTimeout)
+		err := s.cache.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			redis.Status = "down"
+			redis.Detail = err.Error()
+			resp.Ready = false
+		}
+	}
+
+	resp.Components = []*api.ComponentHealth{pg, mq, redis}
+	return resp, nil
+}
+
+// TriggerReconciliation manually triggers stale runner cleanup
+func (s *GRPCServer) TriggerReconciliation(ctx context.Context, req *api.TriggerReconciliationRequest) (*api.TriggerReconciliationResponse, error) {
+	s.logger.Info("manual reconciliation triggered")
+
+	err := s.runnerManager.ReconcileRunners(ctx)
+	if err != nil {
+		return &api.TriggerReconciliationResponse{
+			Error: err.Error(),
+		}, nil
+	}
+
+	return &api.TriggerReconciliationResponse{
+		ReconciledCount: 0,
+	}, nil
+}
+
+// Helper functions to convert between types
 
 func convertRunnerToAPI(r *types.Runner) *api.Runner {
 	apiRunner := &api.Runner{
@@ -315,10 +1844,72 @@ func convertRunnerToAPI(r *types.Runner) *api.Runner {
 	if r.ExitCode != nil {
 		apiRunner.ExitCode = int32(*r.ExitCode)
 	}
+	apiRunner.MaxRuntimeSeconds = int32(r.MaxRuntimeSeconds)
+	apiRunner.KillReason = r.KillReason
 
 	return apiRunner
 }
 
+func convertEventToAPI(e *types.Event) *api.RunnerEvent {
+	return &api.RunnerEvent{
+		ID:        e.ID,
+		EventID:   e.EventID,
+		Timestamp: api.FormatTime(e.Timestamp),
+		EventType: e.EventType,
+		RunnerID:  e.EntityID,
+		Data:      e.Data,
+		Hostname:  e.Hostname,
+	}
+}
+
+func convertLogEntryToAPI(e logEntry) *api.LogLine {
+	return &api.LogLine{
+		Timestamp: e.Time.Format(time.RFC3339Nano),
+		Content:   string(e.Content),
+		Stream:    e.Stream,
+	}
+}
+
+func convertSessionToAPI(sess *types.Session) *api.Session {
+	apiSession := &api.Session{
+		ID:           sess.ID,
+		RunnerID:     sess.RunnerID,
+		ProjectName:  sess.ProjectName,
+		StartedAt:    api.FormatTime(sess.StartedAt),
+		MessageCount: int32(sess.MessageCount),
+		TokensUsed:   sess.TokensUsed,
+		Resumable:    sess.Resumable,
+		ResumedFrom:  sess.ResumedFrom,
+		Summary:      sess.Summary,
+		Pinned:       sess.Pinned,
+		Snippet:      sess.Snippet,
+	}
+
+	if sess.EndedAt != nil {
+		apiSession.EndedAt = api.FormatTime(*sess.EndedAt)
+	}
+	if sess.LastMessageAt != nil {
+		apiSession.LastMessageAt = api.FormatTime(*sess.LastMessageAt)
+	}
+
+	return apiSession
+}
+
+func convertPresetToAPI(p *types.Preset) *api.Preset {
+	return &api.Preset{
+		Name:             p.Name,
+		ProjectName:      p.ProjectName,
+		RuntimeType:      string(p.RuntimeType),
+		Flags:            p.Flags,
+		Capabilities:     p.Capabilities,
+		Environment:      p.Environment,
+		ConversationMode: string(p.ConversationMode),
+		Description:      p.Description,
+		CreatedAt:        api.FormatTime(p.CreatedAt),
+		UpdatedAt:        api.FormatTime(p.UpdatedAt),
+	}
+}
+
 func convertProjectToAPI(p *types.Project) *api.Project {
 	apiProject := &api.Project{
 		Name:          p.Name,
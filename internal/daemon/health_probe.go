@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Health probe defaults for fields left at zero
+const (
+	defaultProbeInterval         = 30 * time.Second
+	defaultProbeTimeout          = 5 * time.Second
+	defaultProbeFailureThreshold = 3
+)
+
+// runHealthProbe checks a runner with its project's probe until the runner
+// stops. After FailureThreshold consecutive failures the runner is stopped.
+func (rm *RunnerManager) runHealthProbe(managed *ManagedRunner, probe *types.HealthProbe, workDir string) {
+	runnerID := managed.Runner.ID
+
+	interval := time.Duration(probe.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	threshold := probe.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultProbeFailureThreshold
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-managed.StopCh:
+			return
+		case <-ticker.C:
+		}
+
+		if !rm.isActive(runnerID) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := checkHealthProbe(ctx, probe, workDir)
+		cancel()
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		rm.logger.Warn("runner health probe failed",
+			zap.String("runner_id", runnerID),
+			zap.String("probe", string(probe.Type)),
+			zap.Int("consecutive_failures", failures),
+			zap.Error(err))
+
+		if failures < threshold {
+			continue
+		}
+
+		ctx = context.Background()
+		rm.recordEvent(ctx, runnerID, "runner.health_check_failed", map[string]interface{}{
+			"probe":    string(probe.Type),
+			"target":   probe.Target,
+			"failures": failures,
+			"error":    err.Error(),
+		})
+
+		if err := rm.StopRunner(ctx, runnerID); err != nil {
+			rm.logger.Error("failed to stop unhealthy runner",
+				zap.String("runner_id", runnerID),
+				zap.Error(err))
+		}
+		return
+	}
+}
+
+// checkHealthProbe runs a single probe, returning nil when healthy
+func checkHealthProbe(ctx context.Context, probe *types.HealthProbe, workDir string) error {
+	switch probe.Type {
+	case types.HealthProbeHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.Target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("http status %d", resp.StatusCode)
+		}
+		return nil
+
+	case types.HealthProbeTCP:
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", probe.Target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+
+	case types.HealthProbeExec:
+		cmd := exec.CommandContext(ctx, "sh", "-c", probe.Target)
+		cmd.Dir = workDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, truncateOutput(out, 200))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown health probe type: %s", probe.Type)
+}
+
+// isActive reports whether the runner is still managed by this daemon
+func (rm *RunnerManager) isActive(runnerID string) bool {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	_, exists := rm.activeRunners[runnerID]
+	return exists
+}
+
+func truncateOutput(out []byte, max int) string {
+	if len(out) > max {
+		out = out[:max]
+	}
+	return string(out)
+}
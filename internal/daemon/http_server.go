@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/internal/budget"
+	"github.com/meridian-lex/stratavore/internal/cache"
+	"github.com/meridian-lex/stratavore/internal/observability"
 	"github.com/meridian-lex/stratavore/pkg/api"
 	"github.com/meridian-lex/stratavore/pkg/config"
 	"go.uber.org/zap"
@@ -33,22 +37,83 @@ func NewHTTPServer(port int, handler *GRPCServer, logger *zap.Logger, cfg *confi
 
 	// Register routes
 	mux.HandleFunc("/api/v1/runners/launch", httpServer.handleLaunchRunner)
+	mux.HandleFunc("/api/v1/runners/clone", httpServer.handleCloneRunner)
 	mux.HandleFunc("/api/v1/runners/stop", httpServer.handleStopRunner)
+	mux.HandleFunc("/api/v1/runners/stop-all", httpServer.handleStopAllRunners)
 	mux.HandleFunc("/api/v1/runners/list", httpServer.handleListRunners)
 	mux.HandleFunc("/api/v1/runners/get", httpServer.handleGetRunner)
-	mux.HandleFunc("/api/v1/projects/create", httpServer.handleCreateProject)
-	mux.HandleFunc("/api/v1/projects/list", httpServer.handleListProjects)
+	mux.HandleFunc("GET /api/v1/runners/history", httpServer.handleGetRunnerHistory)
+	mux.HandleFunc("GET /api/v1/runners/{id}/events", httpServer.handleGetRunnerEvents)
+	mux.HandleFunc("GET /api/v1/runners/{id}/restarts", httpServer.handleGetRunnerRestarts)
+	mux.HandleFunc("/api/v1/runners/logs", httpServer.handleRunnerLogs)
+	// Project routes carry methods so they don't conflict with the
+	// PATCH /api/v1/projects/{name} wildcard
+	mux.HandleFunc("POST /api/v1/projects/create", httpServer.handleCreateProject)
+	mux.HandleFunc("POST /api/v1/projects/setup", httpServer.handleCreateProjectSetup)
+	mux.HandleFunc("POST /api/v1/projects/duplicate", httpServer.handleDuplicateProject)
+	mux.HandleFunc("POST /api/v1/projects/move", httpServer.handleMoveProject)
+	mux.HandleFunc("GET /api/v1/projects/list", httpServer.handleListProjects)
+	mux.HandleFunc("GET /api/v1/projects/search", httpServer.handleSearchProjects)
+	mux.HandleFunc("GET /api/v1/projects/get", httpServer.handleGetProject)
+	mux.HandleFunc("PATCH /api/v1/projects/{name}", httpServer.handleUpdateProject)
+	mux.HandleFunc("GET /api/v1/projects/{name}/events", httpServer.handleGetProjectEvents)
+	mux.HandleFunc("GET /api/v1/projects/{name}/quota", httpServer.handleGetProjectQuota)
+	mux.HandleFunc("PATCH /api/v1/projects/{name}/quota", httpServer.handleSetProjectQuota)
+	mux.HandleFunc("POST /api/v1/projects/archive", httpServer.handleArchiveProject)
+	mux.HandleFunc("POST /api/v1/projects/drain", httpServer.handleDrainProject)
+	mux.HandleFunc("POST /api/v1/projects/unarchive", httpServer.handleUnarchiveProject)
+	mux.HandleFunc("GET /api/v1/sessions", httpServer.handleGetSessionsByTimeRange)
+	mux.HandleFunc("/api/v1/sessions/list", httpServer.handleListSessions)
+	mux.HandleFunc("/api/v1/sessions/search", httpServer.handleSearchSessions)
+	mux.HandleFunc("GET /api/v1/sessions/semantic-search", httpServer.handleSemanticSearchSessions)
+	mux.HandleFunc("/api/v1/sessions/pin", httpServer.handlePinSession)
+	mux.HandleFunc("/api/v1/sessions/unpin", httpServer.handleUnpinSession)
+	mux.HandleFunc("POST /api/v1/sessions/cleanup", httpServer.handleCleanupSessions)
+	mux.HandleFunc("/api/v1/sessions/resume", httpServer.handleResumeSession)
+	mux.HandleFunc("/api/v1/sessions/branch", httpServer.handleBranchSession)
+	mux.HandleFunc("/api/v1/presets/create", httpServer.handleCreatePreset)
+	mux.HandleFunc("/api/v1/presets/list", httpServer.handleListPresets)
+	mux.HandleFunc("/api/v1/presets/delete", httpServer.handleDeletePreset)
+	mux.HandleFunc("/api/v1/budget/check", httpServer.handleBudgetCheck)
+	mux.HandleFunc("/api/v1/budget/report", httpServer.handleBudgetReport)
+	mux.HandleFunc("GET /api/v1/budget/history", httpServer.handleBudgetHistory)
+	mux.HandleFunc("GET /api/v1/stats/leaderboard", httpServer.handleStatsLeaderboard)
+	mux.HandleFunc(auth.DeviceAuthPathPrefix+"start", httpServer.handleDeviceStart)
+	mux.HandleFunc(auth.DeviceAuthPathPrefix+"poll", httpServer.handleDevicePoll)
+	mux.HandleFunc(auth.DeviceAuthPathPrefix+"verify", httpServer.handleDeviceVerify)
+	mux.HandleFunc("POST /api/v1/auth/tokens", httpServer.handleCreateAPIToken)
+	mux.HandleFunc("GET /api/v1/auth/tokens", httpServer.handleListAPITokens)
+	mux.HandleFunc("DELETE /api/v1/auth/tokens/{id}", httpServer.handleRevokeAPIToken)
+	mux.HandleFunc("POST /api/v1/auth/rotate", httpServer.handleRotateToken)
+	mux.HandleFunc("/api/v1/notifications/history", httpServer.handleNotificationHistory)
+	mux.HandleFunc("/api/v1/notifications/routes", httpServer.handleCreateNotificationRoute)
+	mux.HandleFunc("GET /api/v1/activity", httpServer.handleActivity)
 	mux.HandleFunc("/api/v1/heartbeat", httpServer.handleHeartbeat)
 	mux.HandleFunc("/api/v1/status", httpServer.handleStatus)
+	mux.HandleFunc("/api/v1/daemon/version", httpServer.handleDaemonVersion)
+	mux.HandleFunc("/api/v1/nodes", httpServer.handleListNodes)
+	mux.HandleFunc("GET /api/v1/observability/alert-rules", httpServer.handleAlertRules)
 	mux.HandleFunc("/api/v1/reconcile", httpServer.handleReconcile)
 	mux.HandleFunc("/api/v1/health", httpServer.handleHealth)
+	mux.HandleFunc("/api/v1/health/ready", httpServer.handleReady)
 
-	// Build middleware chain: rate-limit → JWT auth → mux
+	// Build middleware chain: security headers → request ID → logging →
+	// CORS → JWT auth → rate-limit → API token usage → mux
 	var handler_ http.Handler = mux
 
-	// JWT auth (disabled when auth_secret is empty)
 	if cfg != nil {
+		usage := &apiTokenUsage{handler: handler, logger: logger, touched: make(map[string]time.Time)}
+		handler_ = usage.middleware(handler_)
+
+		// Rate limiting (always active; defaults to 300 req/min, burst 50).
+		// It runs inside auth so it can key authenticated clients by subject.
+		rl := newRateLimiter("default", cfg.RateLimit, 300, 50, handler.cache, logger)
+		admin := newRateLimiter("admin", cfg.AdminRateLimit, 1200, 200, handler.cache, logger)
+		handler_ = auth.RateLimitMiddleware(rl, admin)(handler_)
+
+		// JWT auth (disabled when auth_secret is empty)
 		validator := auth.NewValidator(cfg.AuthSecret)
+		validator.SetRevocationList(handler.revocations)
 		if validator.Enabled() {
 			logger.Info("HTTP API auth enabled")
 		} else {
@@ -56,21 +121,20 @@ func NewHTTPServer(port int, handler *GRPCServer, logger *zap.Logger, cfg *confi
 		}
 		handler_ = auth.Middleware(validator)(handler_)
 
-		// Rate limiting (always active; defaults to 300 req/min, burst 50)
-		ratePerMin := cfg.RateLimit.RequestsPerMinute
-		if ratePerMin <= 0 {
-			ratePerMin = 300
-		}
-		burst := cfg.RateLimit.Burst
-		if burst <= 0 {
-			burst = 50
-		}
-		rl := auth.NewRateLimiter(ratePerMin, time.Minute, burst)
-		handler_ = auth.RateLimitMiddleware(rl)(handler_)
+		// CORS preflights carry no credentials, so they are answered
+		// before auth sees them (disabled when no origins are configured)
+		handler_ = auth.CORSMiddleware(cfg.CORSAllowedOrigins)(handler_)
+	}
+
+	// Outermost, so every request is logged with its ID, including those
+	// rejected by rate limiting or auth
+	handler_ = auth.LoggingMiddleware(logger)(handler_)
+	handler_ = auth.RequestIDMiddleware(handler_)
 
-		logger.Info("HTTP API rate limiting enabled",
-			zap.Int("requests_per_minute", ratePerMin),
-			zap.Int("burst", burst))
+	// Security headers wrap everything so error responses carry them too.
+	// A reverse proxy that sets its own can turn them off.
+	if cfg == nil || !cfg.DisableSecurityHeaders {
+		handler_ = auth.SecurityHeadersMiddleware(handler_)
 	}
 
 	httpServer.server = &http.Server{
@@ -83,6 +147,37 @@ func NewHTTPServer(port int, handler *GRPCServer, logger *zap.Logger, cfg *confi
 	return httpServer
 }
 
+// newRateLimiter builds a limiter from cfg, using the given defaults for
+// unset fields. Limits are shared across daemons through Redis when the
+// cache is up; burst then only applies while Redis is unreachable.
+func newRateLimiter(name string, cfg config.RateLimitConfig, defaultRate, defaultBurst int, c *cache.Manager, logger *zap.Logger) auth.Limiter {
+	ratePerMin := cfg.RequestsPerMinute
+	if ratePerMin <= 0 {
+		ratePerMin = defaultRate
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+
+	memLimiter := auth.NewRateLimiter(ratePerMin, time.Minute, burst)
+	var rl auth.Limiter = memLimiter
+	backend := "memory"
+
+	if c != nil && c.Enabled() {
+		rl = auth.NewRedisRateLimiter(c.RedisClient(), name, ratePerMin, time.Minute, memLimiter, logger)
+		backend = "redis"
+	}
+
+	logger.Info("HTTP API rate limiting enabled",
+		zap.String("limit", name),
+		zap.Int("requests_per_minute", ratePerMin),
+		zap.Int("burst", burst),
+		zap.String("backend", backend))
+
+	return rl
+}
+
 // Start begins serving HTTP requests
 func (s *HTTPServer) Start() error {
 	s.logger.Info("HTTP API server starting", zap.String("addr", s.server.Addr))
@@ -101,19 +196,65 @@ func (s *HTTPServer) Stop(ctx context.Context) error {
 
 func (s *HTTPServer) handleLaunchRunner(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req api.LaunchRunnerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Hard-stop: refuse launches once the token budget is exhausted
+	budgetResp, err := s.handler.CheckBudget(r.Context(), &api.CheckBudgetRequest{
+		ProjectName:     req.ProjectName,
+		EstimatedTokens: budget.MinLaunchTokens,
+	})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !budgetResp.Allowed {
+		s.respondBudgetExceeded(w, budgetResp.RemainingTokens)
 		return
 	}
 
 	resp, err := s.handler.LaunchRunner(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if resp.ErrorCode == api.ErrorCodeProjectArchived || resp.ErrorCode == api.ErrorCodeProjectDraining {
+		s.respondError(w, http.StatusConflict, resp.Error)
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleCloneRunner(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.CloneRunnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CloneRunner(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if resp.ErrorCode == api.ErrorCodeProjectArchived || resp.ErrorCode == api.ErrorCodeProjectDraining ||
+		resp.ErrorCode == api.ErrorCodeNotClonable {
+		s.respondError(w, http.StatusConflict, resp.Error)
 		return
 	}
 
@@ -122,19 +263,19 @@ func (s *HTTPServer) handleLaunchRunner(w http.ResponseWriter, r *http.Request)
 
 func (s *HTTPServer) handleStopRunner(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req api.StopRunnerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	resp, err := s.handler.StopRunner(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -143,14 +284,16 @@ func (s *HTTPServer) handleStopRunner(w http.ResponseWriter, r *http.Request) {
 
 func (s *HTTPServer) handleListRunners(w http.ResponseWriter, r *http.Request) {
 	projectName := r.URL.Query().Get("project")
+	status := r.URL.Query().Get("status")
 
 	req := &api.ListRunnersRequest{
 		ProjectName: projectName,
+		Status:      status,
 	}
 
 	resp, err := s.handler.ListRunners(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -160,108 +303,1016 @@ func (s *HTTPServer) handleListRunners(w http.ResponseWriter, r *http.Request) {
 func (s *HTTPServer) handleGetRunner(w http.ResponseWriter, r *http.Request) {
 	runnerID := r.URL.Query().Get("id")
 	if runnerID == "" {
-		http.Error(w, "runner_id required", http.StatusBadRequest)
+		s.respondError(w, http.StatusBadRequest, "runner_id required")
 		return
 	}
 
 	req := &api.GetRunnerRequest{RunnerID: runnerID}
 	resp, err := s.handler.GetRunner(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleGetRunnerEvents(w http.ResponseWriter, r *http.Request) {
+	runnerID := r.PathValue("id")
+	if runnerID == "" {
+		s.respondError(w, http.StatusBadRequest, "runner_id required")
+		return
+	}
+
+	req := &api.GetRunnerEventsRequest{RunnerID: runnerID}
+	resp, err := s.handler.GetRunnerEvents(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleGetRunnerRestarts(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetRunnerRestartsRequest{RunnerID: r.PathValue("id")}
+	resp, err := s.handler.GetRunnerRestarts(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
+// handleRunnerLogs streams log lines as newline-delimited JSON, sharing the
+// StreamLogs implementation with gRPC
+func (s *HTTPServer) handleRunnerLogs(w http.ResponseWriter, r *http.Request) {
+	req := &api.RunnerLogsRequest{
+		RunnerID: r.URL.Query().Get("id"),
+		Follow:   r.URL.Query().Get("follow") == "true",
+	}
+	if tail := r.URL.Query().Get("tail"); tail != "" {
+		n, err := strconv.Atoi(tail)
+		if err != nil {
+			s.respondError(w, http.StatusBadRequest, "invalid tail")
+			return
+		}
+		req.TailLines = int32(n)
+	}
+
+	// Followed streams outlive the server's write timeout
+	if req.Follow {
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	stream := &httpLogStream{ctx: r.Context(), w: w, enc: json.NewEncoder(w)}
+	if err := s.handler.StreamLogs(req, stream); err != nil && !stream.started {
+		s.respondError(w, http.StatusNotFound, err.Error())
+	}
+}
+
+// httpLogStream adapts an HTTP response to the StreamLogs server stream
+type httpLogStream struct {
+	ctx     context.Context
+	w       http.ResponseWriter
+	enc     *json.Encoder
+	started bool
+}
+
+func (st *httpLogStream) Send(line *api.LogLine) error {
+	st.started = true
+	if err := st.enc.Encode(line); err != nil {
+		return err
+	}
+	if f, ok := st.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (st *httpLogStream) Context() context.Context {
+	return st.ctx
+}
+
 func (s *HTTPServer) handleCreateProject(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req api.CreateProjectRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	resp, err := s.handler.CreateProject(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) handleListProjects(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
+func (s *HTTPServer) handleCreateProjectSetup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
 
-	req := &api.ListProjectsRequest{Status: status}
-	resp, err := s.handler.ListProjects(r.Context(), req)
+	var req api.CreateProjectSetupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CreateProjectSetup(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+func (s *HTTPServer) handleDuplicateProject(w http.ResponseWriter, r *http.Request) {
+	var req api.DuplicateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.DuplicateProject(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleMoveProject(w http.ResponseWriter, r *http.Request) {
+	var req api.MoveProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.MoveProject(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleUpdateProject serves PATCH /api/v1/projects/{name}. Fields missing
+// from the body are left unchanged.
+func (s *HTTPServer) handleUpdateProject(w http.ResponseWriter, r *http.Request) {
+	var req api.UpdateProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Name = r.PathValue("name")
+
+	resp, err := s.handler.UpdateProject(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleArchiveProject(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	var req api.HeartbeatRequest
+	var req api.ArchiveProjectRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		s.respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	resp, err := s.handler.SendHeartbeat(r.Context(), &req)
+	resp, err := s.handler.ArchiveProject(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
-	req := &api.GetStatusRequest{}
-	resp, err := s.handler.GetStatus(r.Context(), req)
+func (s *HTTPServer) handleGetRunnerHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	req := &api.GetRunnerHistoryRequest{
+		ProjectName: q.Get("project"),
+		Status:      q.Get("status"),
+		Since:       q.Get("since"),
+		Until:       q.Get("until"),
+		Limit:       int32(limit),
+	}
+	resp, err := s.handler.GetRunnerHistory(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) handleReconcile(w http.ResponseWriter, r *http.Request) {
+func (s *HTTPServer) handleStopAllRunners(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	req := &api.TriggerReconciliationRequest{}
-	resp, err := s.handler.TriggerReconciliation(r.Context(), req)
+	var req api.StopAllRunnersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Runners are stopped before responding, which can outlast the
+	// server's write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	resp, err := s.handler.StopAllRunners(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		s.respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
+func (s *HTTPServer) handleDrainProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.DrainProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Draining waits for runners, which can outlast the server's write timeout
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 30*time.Second))
+
+	resp, err := s.handler.DrainProject(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if resp.ErrorCode == api.ErrorCodeProjectDraining {
+		s.respondError(w, http.StatusConflict, resp.Error)
+		return
+	}
+
+	s.respondJSON(w, resp)
 }
 
-func (s *HTTPServer) respondJSON(w http.ResponseWriter, data interface{}) {
+func (s *HTTPServer) handleUnarchiveProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.ArchiveProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.UnarchiveProject(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleGetSessionsByTimeRange(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetSessionsByTimeRangeRequest{
+		From:          r.URL.Query().Get("from"),
+		To:            r.URL.Query().Get("to"),
+		ProjectName:   r.URL.Query().Get("project"),
+		IncludeActive: r.URL.Query().Get("include_active") == "true",
+	}
+	if req.From == "" {
+		s.respondError(w, http.StatusBadRequest, "from is required")
+		return
+	}
+
+	resp, err := s.handler.GetSessionsByTimeRange(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	req := &api.ListSessionsRequest{ProjectName: project, Limit: int32(limit)}
+	resp, err := s.handler.ListSessions(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleSearchSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		s.respondError(w, http.StatusBadRequest, "q required")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	req := &api.SearchSessionsRequest{
+		Query:       query,
+		ProjectName: r.URL.Query().Get("project"),
+		Limit:       int32(limit),
+	}
+	resp, err := s.handler.SearchSessions(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleSemanticSearchSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		s.respondError(w, http.StatusBadRequest, "q required")
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	req := &api.SemanticSearchSessionsRequest{
+		Query:       query,
+		ProjectName: r.URL.Query().Get("project"),
+		Limit:       int32(limit),
+	}
+	resp, err := s.handler.SemanticSearchSessions(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handlePinSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.PinSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.PinSession(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleResumeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.ResumeSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.ResumeSession(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleBranchSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.BranchSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.BranchSession(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleUnpinSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.PinSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.UnpinSession(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleCleanupSessions(w http.ResponseWriter, r *http.Request) {
+	var req api.CleanupSessionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CleanupSessions(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleCreatePreset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.CreatePresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CreatePreset(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleListPresets(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+
+	req := &api.ListPresetsRequest{ProjectName: project}
+	resp, err := s.handler.ListPresets(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleDeletePreset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.DeletePresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.DeletePreset(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		s.respondError(w, http.StatusBadRequest, "name required")
+		return
+	}
+
+	req := &api.GetProjectRequest{Name: name}
+	resp, err := s.handler.GetProject(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleGetProjectEvents serves GET /api/v1/projects/{name}/events. limit
+// defaults to 10 events.
+func (s *HTTPServer) handleGetProjectEvents(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetProjectEventsRequest{
+		ProjectName: r.PathValue("name"),
+		Limit:       10,
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	resp, err := s.handler.GetProjectEvents(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleGetProjectQuota(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetProjectQuotaRequest{ProjectName: r.PathValue("name")}
+	resp, err := s.handler.GetProjectQuota(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleSetProjectQuota serves PATCH /api/v1/projects/{name}/quota. Limits
+// missing from the body are left unchanged.
+func (s *HTTPServer) handleSetProjectQuota(w http.ResponseWriter, r *http.Request) {
+	var req api.SetProjectQuotaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ProjectName = r.PathValue("name")
+
+	resp, err := s.handler.SetProjectQuota(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleListProjects(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+
+	req := &api.ListProjectsRequest{Status: status, IncludeArchived: includeArchived}
+	resp, err := s.handler.ListProjects(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleSearchProjects serves GET /api/v1/projects/search. tag and status
+// may be repeated.
+func (s *HTTPServer) handleSearchProjects(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := &api.SearchProjectsRequest{
+		Query:            q.Get("q"),
+		Tags:             q["tag"],
+		Status:           q["status"],
+		CreatedAfter:     q.Get("created_after"),
+		CreatedBefore:    q.Get("created_before"),
+		HasActiveRunners: q.Get("has_runners") == "true",
+	}
+
+	resp, err := s.handler.SearchProjects(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleBudgetCheck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req := &api.CheckBudgetRequest{
+		ProjectName: r.URL.Query().Get("project"),
+	}
+	if est := r.URL.Query().Get("estimated_tokens"); est != "" {
+		n, err := strconv.ParseInt(est, 10, 64)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid estimated_tokens")
+			return
+		}
+		req.EstimatedTokens = n
+	}
+
+	resp, err := s.handler.CheckBudget(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !resp.Allowed {
+		s.respondBudgetExceeded(w, resp.RemainingTokens)
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleBudgetReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req := &api.GetUsageReportRequest{
+		Scope:   r.URL.Query().Get("scope"),
+		ScopeID: r.URL.Query().Get("scope_id"),
+		Days:    30,
+	}
+	if req.Scope == "" {
+		req.Scope = "global"
+	}
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 366 {
+			s.respondError(w, http.StatusBadRequest, "invalid days")
+			return
+		}
+		req.Days = int32(n)
+	}
+
+	resp, err := s.handler.GetUsageReport(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleBudgetHistory serves GET /api/v1/budget/history. scope defaults to
+// global and limit to 10 periods.
+func (s *HTTPServer) handleBudgetHistory(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetBudgetHistoryRequest{
+		Scope:   r.URL.Query().Get("scope"),
+		ScopeID: r.URL.Query().Get("scope_id"),
+		Limit:   10,
+	}
+	if req.Scope == "" {
+		req.Scope = "global"
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	resp, err := s.handler.GetBudgetHistory(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+// handleStatsLeaderboard serves GET /api/v1/stats/leaderboard. period
+// defaults to week and limit to 10 projects.
+func (s *HTTPServer) handleStatsLeaderboard(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetTokenLeaderboardRequest{
+		Period: r.URL.Query().Get("period"),
+		Limit:  10,
+	}
+	if req.Period == "" {
+		req.Period = "week"
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	resp, err := s.handler.GetTokenLeaderboard(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleCreateNotificationRoute(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.CreateNotificationRouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.CreateNotificationRoute(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleNotificationHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req := &api.GetNotificationHistoryRequest{
+		EventType: r.URL.Query().Get("event"),
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	resp, err := s.handler.GetNotificationHistory(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleActivity(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetActivityRequest{}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		req.Limit = int32(n)
+	}
+
+	resp, err := s.handler.GetActivity(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req api.HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.handler.SendHeartbeat(r.Context(), &req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	req := &api.GetStatusRequest{}
+	resp, err := s.handler.GetStatus(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleDaemonVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	resp, err := s.handler.GetDaemonVersion(r.Context(), &api.GetDaemonVersionRequest{})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleListNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	resp, err := s.handler.ListNodes(r.Context())
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleReconcile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req := &api.TriggerReconciliationRequest{}
+	resp, err := s.handler.TriggerReconciliation(r.Context(), req)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.respondJSON(w, resp)
+}
+
+func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+func (s *HTTPServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.handler.GetReadiness(r.Context(), &api.GetReadinessRequest{})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if !resp.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	s.respondStatus(w, status, resp)
+}
+
+func (s *HTTPServer) respondBudgetExceeded(w http.ResponseWriter, remaining int64) {
+	env := api.NewResponse[any](map[string]interface{}{
+		"remaining_tokens": remaining,
+	}, w.Header().Get(auth.RequestIDHeader))
+	env.Error = "budget_exceeded"
+	s.writeEnvelope(w, http.StatusPaymentRequired, env)
+}
+
+// respondError writes a JSON error envelope with the given status code, in
+// the same shape as errors from the auth middleware
+func (s *HTTPServer) respondError(w http.ResponseWriter, status int, message string) {
+	auth.WriteError(w, status, message)
+}
+
+// handleAlertRules serves the standard SLO alerts as a Prometheus rule file
+func (s *HTTPServer) handleAlertRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := observability.AlertRulesYAML()
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(rules)
+}
+
+// respondJSON writes data wrapped in the API response envelope
+func (s *HTTPServer) respondJSON(w http.ResponseWriter, data interface{}) {
+	s.respondStatus(w, http.StatusOK, data)
+}
+
+// respondStatus writes data wrapped in the API response envelope with the
+// given status code. The request ID is the one RequestIDMiddleware set on
+// the response.
+func (s *HTTPServer) respondStatus(w http.ResponseWriter, status int, data interface{}) {
+	s.writeEnvelope(w, status, api.NewResponse(data, w.Header().Get(auth.RequestIDHeader)))
+}
+
+func (s *HTTPServer) writeEnvelope(w http.ResponseWriter, status int, env *api.Response[any]) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(env)
 }
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+// KillAllResult reports which runners KillAll stopped
+type KillAllResult struct {
+	Stopped []string
+	Failed  map[string]string // runner ID to error
+}
+
+// KillAll stops every active runner. With a project name only that
+// project's runners are stopped, using the same SIGTERM, wait and kill
+// sequence as DrainProject but leaving the project active. Without one,
+// runners are stopped one by one as on daemon shutdown. A daemon.kill_all
+// event is recorded either way.
+func (rm *RunnerManager) KillAll(ctx context.Context, projectName string, waitTimeout time.Duration) *KillAllResult {
+	rm.mu.RLock()
+	var runners []*ManagedRunner
+	for _, managed := range rm.activeRunners {
+		if projectName == "" || managed.Runner.ProjectName == projectName {
+			runners = append(runners, managed)
+		}
+	}
+	rm.mu.RUnlock()
+
+	sort.Slice(runners, func(i, j int) bool {
+		return runners[i].Runner.ID < runners[j].Runner.ID
+	})
+
+	rm.logger.Info("killing all runners",
+		zap.String("project", projectName),
+		zap.Int("runners", len(runners)))
+
+	result := &KillAllResult{Failed: make(map[string]string)}
+
+	if projectName != "" {
+		rm.stopGracefully(ctx, runners, waitTimeout, "kill_all")
+		for _, managed := range runners {
+			result.Stopped = append(result.Stopped, managed.Runner.ID)
+		}
+	} else {
+		for _, managed := range runners {
+			id := managed.Runner.ID
+			if err := rm.StopRunner(ctx, id); err != nil {
+				rm.logger.Error("error stopping runner during kill all",
+					zap.String("runner_id", id),
+					zap.Error(err))
+				result.Failed[id] = err.Error()
+				continue
+			}
+			result.Stopped = append(result.Stopped, id)
+		}
+
+		// Mark any runners not already recorded by monitorProcess in one
+		// query; their exit codes are unknown
+		exitCodes := make(map[string]int, len(result.Stopped))
+		for _, id := range result.Stopped {
+			exitCodes[id] = -1
+		}
+		if err := rm.db.BulkTerminateRunners(ctx, exitCodes); err != nil {
+			rm.logger.Error("error marking runners terminated during kill all",
+				zap.Int("count", len(result.Stopped)),
+				zap.Error(err))
+		}
+		rm.invalidateProjectCache(ctx)
+	}
+
+	hostname, _ := os.Hostname()
+	event := &types.Event{
+		Timestamp:  time.Now(),
+		EventType:  "daemon.kill_all",
+		EntityType: "daemon",
+		EntityID:   hostname,
+		Data: map[string]interface{}{
+			"project": projectName,
+			"stopped": len(result.Stopped),
+			"failed":  len(result.Failed),
+		},
+		Hostname: hostname,
+	}
+	if err := rm.db.InsertRunnerEvent(ctx, event); err != nil {
+		rm.logger.Warn("failed to record kill all event", zap.Error(err))
+	}
+
+	return result
+}
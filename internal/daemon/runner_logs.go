@@ -0,0 +1,159 @@
+package daemon
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// logBufferLines is how many recent lines are kept per runner for tailing
+const logBufferLines = 1000
+
+// logEntry is one captured line of runner output
+type logEntry struct {
+	Time    time.Time
+	Stream  string // "stdout" or "stderr"
+	Content []byte
+}
+
+// logBuffer keeps a ring of recent output lines for a runner and fans new
+// lines out to live subscribers
+type logBuffer struct {
+	mu          sync.Mutex
+	lines       []logEntry
+	next        int
+	full        bool
+	subscribers map[chan logEntry]struct{}
+	writers     []*logWriter
+	closed      bool
+}
+
+func newLogBuffer() *logBuffer {
+	return &logBuffer{
+		lines:       make([]logEntry, logBufferLines),
+		subscribers: make(map[chan logEntry]struct{}),
+	}
+}
+
+// append records a line and delivers it to subscribers. Slow subscribers
+// drop lines rather than block the process pipe.
+func (b *logBuffer) append(entry logEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.lines[b.next] = entry
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// subscribe returns up to tail buffered lines and, when follow is set, a
+// channel of subsequent lines. The channel is closed when the runner exits
+// or cancel is called.
+func (b *logBuffer) subscribe(tail int, follow bool) ([]logEntry, <-chan logEntry, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backlog := b.tailLocked(tail)
+	if !follow || b.closed {
+		return backlog, nil, func() {}
+	}
+
+	ch := make(chan logEntry, 256)
+	b.subscribers[ch] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return backlog, ch, cancel
+}
+
+func (b *logBuffer) tailLocked(tail int) []logEntry {
+	count := b.next
+	if b.full {
+		count = len(b.lines)
+	}
+	if tail <= 0 || tail > count {
+		tail = count
+	}
+
+	out := make([]logEntry, 0, tail)
+	start := (b.next - tail + len(b.lines)) % len(b.lines)
+	for i := 0; i < tail; i++ {
+		out = append(out, b.lines[(start+i)%len(b.lines)])
+	}
+	return out
+}
+
+// close flushes partial lines and ends all live subscriptions once the
+// runner process has exited
+func (b *logBuffer) close() {
+	for _, w := range b.writers {
+		w.flush()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// writer returns an io.Writer for one process stream that splits output
+// into lines
+func (b *logBuffer) writer(stream string) *logWriter {
+	w := &logWriter{buf: b, stream: stream}
+	b.writers = append(b.writers, w)
+	return w
+}
+
+// logWriter adapts a process pipe to the line-oriented logBuffer
+type logWriter struct {
+	buf     *logBuffer
+	stream  string
+	partial []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	data := append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := append([]byte(nil), bytes.TrimSuffix(data[:i], []byte("\r"))...)
+		w.buf.append(logEntry{Time: time.Now(), Stream: w.stream, Content: line})
+		data = data[i+1:]
+	}
+	w.partial = append(w.partial[:0:0], data...)
+	return len(p), nil
+}
+
+// flush emits any trailing output that did not end in a newline
+func (w *logWriter) flush() {
+	if len(w.partial) > 0 {
+		w.buf.append(logEntry{Time: time.Now(), Stream: w.stream, Content: w.partial})
+		w.partial = nil
+	}
+}
@@ -2,28 +2,71 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/meridian-lex/stratavore/internal/budget"
+	"github.com/meridian-lex/stratavore/internal/cache"
 	"github.com/meridian-lex/stratavore/internal/messaging"
+	"github.com/meridian-lex/stratavore/internal/notifications"
+	"github.com/meridian-lex/stratavore/internal/observability"
 	"github.com/meridian-lex/stratavore/internal/storage"
 	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 )
 
+// ErrProjectArchived is returned when launching a runner on an archived project
+var ErrProjectArchived = errors.New("project is archived")
+
+// ErrProjectDraining is returned when launching a runner on a project that
+// DrainProject is winding down
+var ErrProjectDraining = errors.New("project is draining")
+
+// ErrNotClonable is returned when cloning a runner that is still starting or
+// has failed
+var ErrNotClonable = errors.New("runner cannot be cloned in its current state")
+
+// DaemonNodeStaleAfter is how long a daemon node may go without a heartbeat
+// before reconciliation marks it inactive
+const DaemonNodeStaleAfter = 5 * time.Minute
+
+// errAffinityUnsupported is returned by setCPUAffinity on platforms without
+// sched_setaffinity
+var errAffinityUnsupported = errors.New("cpu affinity is only supported on linux")
+
 // RunnerManager manages Claude Code runner lifecycles
 type RunnerManager struct {
 	db            *storage.PostgresClient
 	messaging     *messaging.Client
+	budgets       *budget.Manager
+	dispatcher    *notifications.Dispatcher
+	metrics       *observability.MetricsServer
+	cache         *cache.Manager
 	logger        *zap.Logger
 	activeRunners map[string]*ManagedRunner
+	draining      map[string]bool // project names; guarded by mu
+	shuttingDown  bool            // guarded by mu; see Shutdown
+	drainTimeout  time.Duration
+	dirChanged    syscall.Signal // sent to runners when their project moves; 0 = none
+	heartbeatTTL  int            // seconds; for projects whose quota sets none
+	startTimeout  time.Duration  // before a runner still starting is failed
+	nodeID        string
 	mu            sync.RWMutex
+
+	// launchSemaphore bounds how many agent processes are being started at
+	// once; queuedLaunches counts launches waiting for a slot
+	launchSemaphore chan struct{}
+	queuedLaunches  atomic.Int32
 }
 
 // ManagedRunner represents an actively managed runner
@@ -32,23 +75,228 @@ type ManagedRunner struct {
 	Process    *exec.Cmd
 	Heartbeats chan *types.Heartbeat
 	StopCh     chan struct{}
+	Logs       *logBuffer
+
+	// DrainSignal asks the agent to wrap up before it is terminated; zero
+	// skips the drain. The agent has DrainTimeout to exit on its own.
+	DrainSignal  syscall.Signal
+	DrainTimeout time.Duration
+
+	stopOnce sync.Once
+
+	// exited is closed once monitorProcess has reaped the process, after
+	// setting exitCode
+	exited   chan struct{}
+	exitCode int
 }
 
-// NewRunnerManager creates a new runner manager
+// signalStop closes StopCh, reporting whether this call closed it. User
+// stops, drains and automatic stops can overlap; only the first one closes
+// the channel.
+func (m *ManagedRunner) signalStop() bool {
+	closed := false
+	m.stopOnce.Do(func() {
+		close(m.StopCh)
+		closed = true
+	})
+	return closed
+}
+
+const (
+	// defaultRunnerDrainTimeout is how long StopRunner waits after the drain
+	// signal before sending SIGTERM
+	defaultRunnerDrainTimeout = 60 * time.Second
+
+	// stopTimeout is how long StopRunner waits after SIGTERM before killing
+	stopTimeout = 10 * time.Second
+
+	// agentPreflightExitCode is the agent's exit code when it refuses to
+	// start claude code because a pre-flight check failed
+	agentPreflightExitCode = 2
+
+	// stopReasonRequested is the stop reason of runners stopped through
+	// StopRunner. Other stop reasons are daemon kills, recorded as the
+	// runner's kill reason.
+	stopReasonRequested = "stop_requested"
+
+	// killReasonMaxRuntime is the kill reason of runners stopped for
+	// running longer than their max runtime
+	killReasonMaxRuntime = "max_runtime_exceeded"
+
+	// killReasonStartupTimeout is the kill reason of runners failed for
+	// staying in the starting state longer than the startup timeout
+	killReasonStartupTimeout = "startup_timeout"
+
+	// defaultStartupTimeout is how long a runner may stay starting unless
+	// SetStartupTimeout says otherwise
+	defaultStartupTimeout = 60 * time.Second
+
+	// defaultMaxConcurrentLaunches is how many agent processes may be
+	// starting at once unless SetMaxConcurrentLaunches says otherwise
+	defaultMaxConcurrentLaunches = 10
+
+	// slowLaunchWait is how long a launch may wait for a slot before it is
+	// logged as a warning
+	slowLaunchWait = 5 * time.Second
+)
+
+// NewRunnerManager creates a new runner manager.
+// A nil budgets manager disables token budget enforcement.
 func NewRunnerManager(
 	db *storage.PostgresClient,
 	messaging *messaging.Client,
+	budgets *budget.Manager,
 	logger *zap.Logger,
 ) *RunnerManager {
 	return &RunnerManager{
 		db:            db,
 		messaging:     messaging,
+		budgets:       budgets,
 		logger:        logger,
 		activeRunners: make(map[string]*ManagedRunner),
+		draining:      make(map[string]bool),
+		drainTimeout:  defaultRunnerDrainTimeout,
+		heartbeatTTL:  storage.DefaultHeartbeatTTLSeconds,
+		startTimeout:  defaultStartupTimeout,
+
+		launchSemaphore: make(chan struct{}, defaultMaxConcurrentLaunches),
+	}
+}
+
+// SetDispatcher enables notifications for runner failures and quota
+// rejections
+func (rm *RunnerManager) SetDispatcher(d *notifications.Dispatcher) {
+	rm.dispatcher = d
+}
+
+// SetCache lets runner status changes drop the cached project lists, whose
+// runner counts would otherwise be stale until they expire
+func (rm *RunnerManager) SetCache(c *cache.Manager) {
+	rm.cache = c
+}
+
+// SetMetrics enables launch outcome metrics
+func (rm *RunnerManager) SetMetrics(m *observability.MetricsServer) {
+	rm.metrics = m
+}
+
+// SetNodeID records which daemon node launches runners, so fleet views can
+// tell the nodes' runners apart in the shared database
+func (rm *RunnerManager) SetNodeID(nodeID string) {
+	rm.nodeID = nodeID
+}
+
+// SetDrainTimeout sets how long runners launched from now on get to wrap
+// up after the drain signal. Zero disables the drain.
+func (rm *RunnerManager) SetDrainTimeout(d time.Duration) {
+	rm.drainTimeout = d
+}
+
+// SetHeartbeatTTL sets how long runners of projects without their own
+// heartbeat TTL may go without a heartbeat before they are marked failed
+func (rm *RunnerManager) SetHeartbeatTTL(d time.Duration) {
+	if d > 0 {
+		rm.heartbeatTTL = int(d.Seconds())
+	}
+}
+
+// SetStartupTimeout sets how long a runner may stay starting before
+// reconciliation marks it failed
+func (rm *RunnerManager) SetStartupTimeout(d time.Duration) {
+	if d > 0 {
+		rm.startTimeout = d
 	}
 }
 
-// Launch starts a new runner
+// SetMaxConcurrentLaunches sets how many agent processes may be starting
+// at once. It must be called before the first launch.
+func (rm *RunnerManager) SetMaxConcurrentLaunches(n int) {
+	if n > 0 {
+		rm.launchSemaphore = make(chan struct{}, n)
+	}
+}
+
+// QueuedLaunches returns how many launches are waiting for a free launch
+// slot on this node
+func (rm *RunnerManager) QueuedLaunches() int {
+	return int(rm.queuedLaunches.Load())
+}
+
+// acquireLaunchSlot waits for a free launch slot. The returned func
+// releases it.
+func (rm *RunnerManager) acquireLaunchSlot(ctx context.Context, runnerID string) (func(), error) {
+	release := func() { <-rm.launchSemaphore }
+
+	select {
+	case rm.launchSemaphore <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	rm.queuedLaunches.Add(1)
+	defer rm.queuedLaunches.Add(-1)
+
+	start := time.Now()
+	select {
+	case rm.launchSemaphore <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if waited := time.Since(start); waited > slowLaunchWait {
+		rm.logger.Warn("runner launch waited for a free slot",
+			zap.String("runner_id", runnerID),
+			zap.Duration("waited", waited),
+			zap.Int("max_concurrent_launches", cap(rm.launchSemaphore)))
+	}
+	return release, nil
+}
+
+// SetDirChangedSignal sets the signal, by name, sent to a project's active
+// runners when the project is moved, e.g. "SIGUSR2". "" sends none.
+func (rm *RunnerManager) SetDirChangedSignal(name string) error {
+	sig, err := parseDirChangedSignal(name)
+	if err != nil {
+		return err
+	}
+	rm.dirChanged = sig
+	return nil
+}
+
+// SignalDirChanged tells this node's active runners of projectName that
+// the project's directory has moved, and returns how many were signalled
+func (rm *RunnerManager) SignalDirChanged(projectName string) int {
+	if rm.dirChanged == 0 {
+		return 0
+	}
+
+	rm.mu.RLock()
+	var runners []*ManagedRunner
+	for _, managed := range rm.activeRunners {
+		if managed.Runner.ProjectName == projectName {
+			runners = append(runners, managed)
+		}
+	}
+	rm.mu.RUnlock()
+
+	signalled := 0
+	for _, managed := range runners {
+		if managed.Process == nil || managed.Process.Process == nil {
+			continue
+		}
+		if err := managed.Process.Process.Signal(rm.dirChanged); err != nil {
+			rm.logger.Warn("failed to signal runner of project move",
+				zap.String("runner_id", managed.Runner.ID),
+				zap.Error(err))
+			continue
+		}
+		signalled++
+	}
+	return signalled
+}
+
+// Launch starts a new runner. Rejections by project state, budget or quota
+// are not counted as launch failures in metrics.
 func (rm *RunnerManager) Launch(ctx context.Context, req *types.LaunchRequest) (*types.Runner, error) {
 	rm.logger.Info("launching runner",
 		zap.String("project", req.ProjectName),
@@ -60,15 +308,62 @@ func (rm *RunnerManager) Launch(ctx context.Context, req *types.LaunchRequest) (
 		return nil, fmt.Errorf("get project: %w", err)
 	}
 
+	if project.Status == types.ProjectArchived {
+		return nil, ErrProjectArchived
+	}
+
+	if rm.isDraining(req.ProjectName) {
+		return nil, ErrProjectDraining
+	}
+
+	if req.PresetName != "" {
+		preset, err := rm.db.GetPreset(ctx, req.PresetName)
+		if err != nil {
+			return nil, fmt.Errorf("get preset: %w", err)
+		}
+		if preset.ProjectName != "" && preset.ProjectName != req.ProjectName {
+			return nil, fmt.Errorf("preset %s belongs to project %s", preset.Name, preset.ProjectName)
+		}
+		req = mergePreset(req, preset)
+	}
+
+	// Refuse to launch once the token budget is exhausted
+	if err := rm.CheckBudget(ctx, req.ProjectName, budget.MinLaunchTokens); err != nil {
+		return nil, err
+	}
+
 	// Get quota
 	quota, err := rm.db.GetResourceQuota(ctx, req.ProjectName)
 	if err != nil {
 		return nil, fmt.Errorf("get quota: %w", err)
 	}
 
+	if err := validateCPUAffinity(req.CPUAffinity, quota.MaxCPUsPerRunner); err != nil {
+		return nil, err
+	}
+
+	if req.MaxRuntimeSeconds < 0 {
+		return nil, fmt.Errorf("max runtime must not be negative")
+	}
+	if req.MaxRuntimeSeconds == 0 {
+		req.MaxRuntimeSeconds = quota.MaxRuntimeSeconds
+	}
+
+	if quota.HeartbeatTTLSeconds == 0 {
+		quota.HeartbeatTTLSeconds = rm.heartbeatTTL
+	}
+
 	// Create runner with transactional outbox (atomic with quota check)
-	runner, err := rm.db.CreateRunnerTx(ctx, req, quota.MaxConcurrentRunners)
+	runner, err := rm.db.CreateRunnerTx(ctx, req, quota, rm.nodeID)
 	if err != nil {
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			rm.dispatcher.Notify(req.ProjectName, notifications.EventQuotaExceeded, map[string]interface{}{
+				"resource": "runners",
+				"limit":    quota.MaxConcurrentRunners,
+			})
+		} else {
+			rm.metrics.RecordLaunch(false)
+		}
 		return nil, fmt.Errorf("create runner: %w", err)
 	}
 
@@ -77,17 +372,37 @@ func (rm *RunnerManager) Launch(ctx context.Context, req *types.LaunchRequest) (
 	if err != nil {
 		// Mark as failed
 		rm.db.UpdateRunnerStatus(ctx, runner.ID, types.StatusFailed)
+		rm.invalidateProjectCache(ctx, runner.ProjectName)
+		rm.metrics.RecordLaunch(false)
 		return nil, fmt.Errorf("start agent: %w", err)
 	}
+	rm.metrics.RecordLaunch(true)
 
 	// Register runner
 	rm.mu.Lock()
 	rm.activeRunners[runner.ID] = managed
 	rm.mu.Unlock()
 
+	if project.HealthProbe != nil {
+		workDir := req.ProjectPath
+		if workDir == "" {
+			workDir = project.Path
+		}
+		go rm.runHealthProbe(managed, project.HealthProbe, workDir)
+	}
+
 	// Update project access time
 	rm.updateProjectAccess(ctx, project.Name)
 
+	// The project's runner count and status have changed
+	rm.invalidateProjectCache(ctx, project.Name)
+
+	rm.recordEvent(ctx, runner.ID, "runner.started", map[string]interface{}{
+		"project_name": req.ProjectName,
+		"runtime_type": string(req.RuntimeType),
+		"runtime_id":   runner.RuntimeID,
+	})
+
 	rm.logger.Info("runner launched successfully",
 		zap.String("runner_id", runner.ID),
 		zap.String("project", req.ProjectName))
@@ -95,6 +410,113 @@ func (rm *RunnerManager) Launch(ctx context.Context, req *types.LaunchRequest) (
 	return runner, nil
 }
 
+// Clone launches a new runner reusing the launch configuration of an existing
+// one. Overrides replace the runtime, conversation mode and capabilities when
+// set, add to the source flags, and are merged into the environment. The
+// clone starts a new conversation unless resumeSession is set.
+func (rm *RunnerManager) Clone(
+	ctx context.Context,
+	sourceID string,
+	newProjectName string,
+	overrides *types.LaunchRequest,
+	resumeSession bool,
+) (*types.Runner, error) {
+	source, err := rm.db.GetRunner(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get source runner: %w", err)
+	}
+
+	switch source.Status {
+	case types.StatusRunning, types.StatusPaused, types.StatusTerminated:
+	default:
+		return nil, fmt.Errorf("%w: %s is %s", ErrNotClonable, sourceID, source.Status)
+	}
+
+	req := cloneLaunchRequest(source, overrides, resumeSession)
+
+	if newProjectName != "" && newProjectName != source.ProjectName {
+		project, err := rm.db.GetProject(ctx, newProjectName)
+		if err != nil {
+			return nil, fmt.Errorf("get project: %w", err)
+		}
+		req.ProjectName = project.Name
+		req.ProjectPath = project.Path
+	}
+
+	runner, err := rm.Launch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rm.recordEvent(ctx, runner.ID, "runner.cloned", map[string]interface{}{
+		"source_runner_id": sourceID,
+	})
+
+	return runner, nil
+}
+
+// cloneLaunchRequest builds a launch request from a source runner's config
+// with overrides applied
+func cloneLaunchRequest(source *types.Runner, overrides *types.LaunchRequest, resumeSession bool) *types.LaunchRequest {
+	req := &types.LaunchRequest{
+		ProjectName:       source.ProjectName,
+		ProjectPath:       source.ProjectPath,
+		Flags:             append([]string(nil), source.Flags...),
+		Capabilities:      source.Capabilities,
+		RuntimeType:       source.RuntimeType,
+		ConversationMode:  types.ModeNew,
+		MaxRuntimeSeconds: source.MaxRuntimeSeconds,
+	}
+
+	if resumeSession && source.SessionID != "" {
+		req.SessionID = source.SessionID
+		req.ConversationMode = types.ModeResume
+	}
+
+	env := make(map[string]string, len(source.Environment))
+	for k, v := range source.Environment {
+		env[k] = v
+	}
+
+	if overrides != nil {
+		req.Flags = append(req.Flags, overrides.Flags...)
+		if len(overrides.Capabilities) > 0 {
+			req.Capabilities = overrides.Capabilities
+		}
+		if overrides.RuntimeType != "" {
+			req.RuntimeType = overrides.RuntimeType
+		}
+		if overrides.ConversationMode != "" {
+			req.ConversationMode = overrides.ConversationMode
+		}
+		if overrides.SessionID != "" {
+			req.SessionID = overrides.SessionID
+		}
+		for k, v := range overrides.Environment {
+			env[k] = v
+		}
+	}
+	req.Environment = env
+
+	if req.RuntimeType == "" {
+		req.RuntimeType = types.RuntimeProcess
+	}
+
+	return req
+}
+
+// CheckBudget verifies that the global and project token budgets can absorb
+// estimatedTokens. Any violation is returned wrapped as budget.ErrBudgetExceeded.
+func (rm *RunnerManager) CheckBudget(ctx context.Context, projectName string, estimatedTokens int64) error {
+	if rm.budgets == nil {
+		return nil
+	}
+	if err := rm.budgets.CheckBudget(ctx, projectName, estimatedTokens); err != nil {
+		return fmt.Errorf("%w: %v", budget.ErrBudgetExceeded, err)
+	}
+	return nil
+}
+
 // startAgent spawns the stratavore-agent process
 func (rm *RunnerManager) startAgent(
 	ctx context.Context,
@@ -113,23 +535,50 @@ func (rm *RunnerManager) startAgent(
 		args = append(args, "--claude-flag", flag)
 	}
 
+	// The agent runs its own countdown after the drain signal; presets can
+	// change what it sends Claude Code to wrap up
+	if rm.drainTimeout > 0 {
+		args = append(args, "--drain-timeout-seconds", strconv.Itoa(int(rm.drainTimeout.Seconds())))
+	}
+	if command := req.Environment["STRATAVORE_DRAIN_COMMAND"]; command != "" {
+		args = append(args, "--drain-command", command)
+	}
+
 	// Create command with context for graceful shutdown
 	cmd, err := launchAgent(ctx, args) // This will return the command, but we need to set it up first
 	if err != nil {
 		return nil, fmt.Errorf("launch agent: %w", err)
 	}
 
-	// Set up logging (could redirect to structured log files)
-	// cmd.Stdout = ...
-	// cmd.Stderr = ...
+	// Capture output into the runner's log ring buffer for tailing
+	logs := newLogBuffer()
+	cmd.Stdout = logs.writer("stdout")
+	cmd.Stderr = logs.writer("stderr")
 
-	// Start the process
-	if err := cmd.Start(); err != nil {
+	// Only starting the process is bounded, so that batch launches don't
+	// fork hundreds of processes at once; the slot is free again as soon
+	// as the process is running
+	release, err := rm.acquireLaunchSlot(ctx, runner.ID)
+	if err != nil {
+		return nil, fmt.Errorf("wait for launch slot: %w", err)
+	}
+	err = cmd.Start()
+	release()
+	if err != nil {
 		return nil, fmt.Errorf("start process: %w", err)
 	}
 
 	pid := cmd.Process.Pid
 
+	if len(req.CPUAffinity) > 0 {
+		if err := setCPUAffinity(pid, req.CPUAffinity); errors.Is(err, errAffinityUnsupported) {
+			rm.logger.Debug("skipping cpu affinity", zap.String("runner_id", runner.ID), zap.Error(err))
+		} else if err != nil {
+			cmd.Process.Kill()
+			return nil, fmt.Errorf("set cpu affinity: %w", err)
+		}
+	}
+
 	// Update runner with runtime ID (PID)
 	if err := rm.db.UpdateRunnerRuntimeID(ctx, runner.ID, fmt.Sprintf("%d", pid)); err != nil {
 		cmd.Process.Kill()
@@ -138,19 +587,79 @@ func (rm *RunnerManager) startAgent(
 
 	runner.RuntimeID = fmt.Sprintf("%d", pid)
 
+	if runner.RestartAttempts > 0 {
+		rm.recordRestart(ctx, runner)
+	}
+
 	managed := &ManagedRunner{
 		Runner:     runner,
 		Process:    cmd,
 		Heartbeats: make(chan *types.Heartbeat, 10),
 		StopCh:     make(chan struct{}),
+		Logs:       logs,
+		exited:     make(chan struct{}),
+	}
+	if rm.drainTimeout > 0 {
+		managed.DrainSignal = drainSignal
+		managed.DrainTimeout = rm.drainTimeout
 	}
 
 	// Monitor process lifecycle
 	go rm.monitorProcess(runner.ID, cmd)
 
+	if req.MaxRuntimeSeconds > 0 {
+		go rm.enforceMaxRuntime(managed, time.Duration(req.MaxRuntimeSeconds)*time.Second)
+	}
+
 	return managed, nil
 }
 
+// recordRestart stores a restart of runner, with the delay measured from
+// its previous termination
+func (rm *RunnerManager) recordRestart(ctx context.Context, runner *types.Runner) {
+	now := time.Now()
+	restart := &types.RunnerRestart{
+		RunnerID:         runner.ID,
+		Attempt:          runner.RestartAttempts,
+		RestartedAt:      now,
+		PreviousExitCode: runner.ExitCode,
+	}
+	if runner.TerminatedAt != nil {
+		restart.DelaySeconds = now.Sub(*runner.TerminatedAt).Seconds()
+	}
+
+	if err := rm.db.RecordRunnerRestartEvent(ctx, restart); err != nil {
+		rm.logger.Warn("failed to record runner restart",
+			zap.String("runner_id", runner.ID),
+			zap.Int("attempt", runner.RestartAttempts),
+			zap.Error(err))
+	}
+}
+
+// validateCPUAffinity checks requested cores exist on this host, are not
+// repeated and do not exceed the project's per-runner cap (0 = no cap)
+func validateCPUAffinity(cpus []int, maxCPUs int) error {
+	if len(cpus) == 0 {
+		return nil
+	}
+	if maxCPUs > 0 && len(cpus) > maxCPUs {
+		return fmt.Errorf("cpu affinity requests %d cores, quota allows %d", len(cpus), maxCPUs)
+	}
+
+	numCPU := runtime.NumCPU()
+	seen := make(map[int]bool, len(cpus))
+	for _, cpu := range cpus {
+		if cpu < 0 || cpu >= numCPU {
+			return fmt.Errorf("cpu %d out of range (host has %d cpus)", cpu, numCPU)
+		}
+		if seen[cpu] {
+			return fmt.Errorf("cpu %d listed more than once", cpu)
+		}
+		seen[cpu] = true
+	}
+	return nil
+}
+
 // launchAgent returns an exec.Cmd pointing to stratavore-agent
 func launchAgent(ctx context.Context, args []string) (*exec.Cmd, error) {
 	exeName := "stratavore-agent"
@@ -194,14 +703,63 @@ func (rm *RunnerManager) monitorProcess(runnerID string, cmd *exec.Cmd) {
 
 	ctx := context.Background()
 
-	// Update database
-	rm.db.TerminateRunner(ctx, runnerID, exitCode)
+	// Update database, unless Shutdown has taken this runner and will
+	// record its exit code with the others in one query
+	rm.mu.RLock()
+	managed := rm.activeRunners[runnerID]
+	deferred := rm.shuttingDown && managed != nil
+	rm.mu.RUnlock()
+	if !deferred {
+		rm.db.TerminateRunner(ctx, runnerID, exitCode)
+	}
 
 	// Remove from active runners
 	rm.mu.Lock()
 	delete(rm.activeRunners, runnerID)
 	rm.mu.Unlock()
 
+	if managed != nil {
+		managed.exitCode = exitCode
+		close(managed.exited)
+	}
+
+	if managed != nil && managed.Logs != nil {
+		managed.Logs.close()
+	}
+	if managed != nil {
+		rm.invalidateProjectCache(ctx, managed.Runner.ProjectName)
+	} else {
+		rm.invalidateProjectCache(ctx)
+	}
+
+	// A non-zero exit that wasn't requested via StopRunner is a crash
+	stopRequested := false
+	if managed != nil {
+		select {
+		case <-managed.StopCh:
+			stopRequested = true
+		default:
+		}
+	}
+	if exitCode != 0 && !stopRequested {
+		rm.recordEvent(ctx, runnerID, "runner.failed", map[string]interface{}{
+			"exit_code": exitCode,
+		})
+
+		projectName := ""
+		if managed != nil {
+			projectName = managed.Runner.ProjectName
+		}
+		reason := fmt.Sprintf("exit code %d", exitCode)
+		if exitCode == agentPreflightExitCode {
+			reason = "agent pre-flight checks failed"
+		}
+		rm.dispatcher.Notify(projectName, notifications.EventRunnerFailed, map[string]interface{}{
+			"runner_id": runnerID,
+			"reason":    reason,
+		})
+	}
+
 	// Publish termination event
 	event := map[string]interface{}{
 		"runner_id": runnerID,
@@ -223,6 +781,10 @@ func (rm *RunnerManager) ProcessHeartbeat(ctx context.Context, hb *types.Heartbe
 	rm.mu.RUnlock()
 
 	if !exists {
+		// Tell the agent of a runner that has already finished to exit
+		if terminated, err := rm.db.RunnerTerminated(ctx, hb.RunnerID); err == nil && terminated {
+			return fmt.Errorf("%w: %s", storage.ErrRunnerTerminated, hb.RunnerID)
+		}
 		return fmt.Errorf("runner not found: %s", hb.RunnerID)
 	}
 
@@ -231,6 +793,14 @@ func (rm *RunnerManager) ProcessHeartbeat(ctx context.Context, hb *types.Heartbe
 		return fmt.Errorf("update heartbeat: %w", err)
 	}
 
+	if hb.ErrorContext != "" {
+		rm.recordAgentError(ctx, managed.Runner, hb.ErrorContext)
+	}
+
+	if hb.PreflightResult != nil {
+		rm.recordPreflight(ctx, managed.Runner, hb.PreflightResult)
+	}
+
 	// Forward to channel for monitoring
 	select {
 	case managed.Heartbeats <- hb:
@@ -241,8 +811,99 @@ func (rm *RunnerManager) ProcessHeartbeat(ctx context.Context, hb *types.Heartbe
 	return nil
 }
 
+// recordAgentError stores a stderr error reported by a runner's agent and
+// raises a system alert when the runner ran out of memory
+func (rm *RunnerManager) recordAgentError(ctx context.Context, runner *types.Runner, errorContext string) {
+	rm.logger.Warn("agent reported error",
+		zap.String("runner_id", runner.ID),
+		zap.String("error", errorContext))
+
+	rm.recordEvent(ctx, runner.ID, "agent.stderr_error", map[string]interface{}{
+		"error": errorContext,
+	})
+
+	if strings.Contains(strings.ToLower(errorContext), "out of memory") {
+		rm.dispatcher.Notify(runner.ProjectName, notifications.EventSystemAlert, map[string]interface{}{
+			"title":     "Runner out of memory",
+			"message":   fmt.Sprintf("Runner %s in %s: %s", runner.ID, runner.ProjectName, errorContext),
+			"runner_id": runner.ID,
+		})
+	}
+}
+
+// recordPreflight stores the agent's pre-flight results as a runner event
+// and logs the checks that failed
+func (rm *RunnerManager) recordPreflight(ctx context.Context, runner *types.Runner, result *types.PreflightResult) {
+	checks := make([]map[string]interface{}, 0, len(result.Checks))
+	for _, c := range result.Checks {
+		checks = append(checks, map[string]interface{}{
+			"name":     c.Name,
+			"passed":   c.Passed,
+			"critical": c.Critical,
+			"message":  c.Message,
+		})
+		if !c.Passed {
+			rm.logger.Warn("agent pre-flight check failed",
+				zap.String("runner_id", runner.ID),
+				zap.String("check", c.Name),
+				zap.Bool("critical", c.Critical),
+				zap.String("message", c.Message))
+		}
+	}
+
+	rm.recordEvent(ctx, runner.ID, "agent.preflight", map[string]interface{}{
+		"passed": result.Passed,
+		"checks": checks,
+	})
+}
+
+// enforceMaxRuntime stops a runner that is still active after maxRuntime,
+// recording why and alerting the user
+func (rm *RunnerManager) enforceMaxRuntime(managed *ManagedRunner, maxRuntime time.Duration) {
+	timer := time.NewTimer(maxRuntime)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-managed.StopCh:
+		return
+	}
+
+	runner := managed.Runner
+	rm.mu.RLock()
+	_, active := rm.activeRunners[runner.ID]
+	rm.mu.RUnlock()
+	if !active {
+		return
+	}
+
+	rm.logger.Warn("runner exceeded max runtime, stopping",
+		zap.String("runner_id", runner.ID),
+		zap.Duration("max_runtime", maxRuntime))
+
+	ctx := context.Background()
+	if err := rm.stopRunner(ctx, runner.ID, killReasonMaxRuntime); err != nil {
+		// Exited on its own in the meantime
+		return
+	}
+
+	rm.dispatcher.Notify(runner.ProjectName, notifications.EventSystemAlert, map[string]interface{}{
+		"title":     "Runner exceeded max runtime",
+		"message":   fmt.Sprintf("Runner %s in %s was stopped after running for %s", runner.ID, runner.ProjectName, maxRuntime),
+		"runner_id": runner.ID,
+	})
+}
+
 // StopRunner gracefully stops a runner
 func (rm *RunnerManager) StopRunner(ctx context.Context, runnerID string) error {
+	return rm.stopRunner(ctx, runnerID, stopReasonRequested)
+}
+
+// stopRunner stops a runner, recording reason in its stop event and, for
+// daemon kills, as its kill reason. Only the stop that claims the runner
+// records anything, so a runner that exited or was already being stopped
+// keeps the reason it has.
+func (rm *RunnerManager) stopRunner(ctx context.Context, runnerID, reason string) error {
 	rm.mu.RLock()
 	managed, exists := rm.activeRunners[runnerID]
 	rm.mu.RUnlock()
@@ -251,35 +912,84 @@ func (rm *RunnerManager) StopRunner(ctx context.Context, runnerID string) error
 		return fmt.Errorf("runner not active: %s", runnerID)
 	}
 
+	// Another stop may already be draining or terminating the runner, and
+	// escalates to a kill on its own
+	if !managed.signalStop() {
+		rm.logger.Info("runner already stopping", zap.String("runner_id", runnerID))
+		return nil
+	}
+
 	rm.logger.Info("stopping runner", zap.String("runner_id", runnerID))
 
-	// Signal stop
-	close(managed.StopCh)
+	if reason != stopReasonRequested {
+		if err := rm.db.SetRunnerKillReason(ctx, runnerID, reason); err != nil {
+			rm.logger.Warn("failed to record kill reason",
+				zap.String("runner_id", runnerID),
+				zap.Error(err))
+		}
+	}
 
-	// Send SIGTERM to process
-	if managed.Process != nil && managed.Process.Process != nil {
+	rm.recordEvent(ctx, runnerID, "runner.stopped", map[string]interface{}{
+		"reason": reason,
+	})
 
-		managed.Process.Process.Signal(syscall.SIGTERM)
+	rm.terminateProcess(ctx, managed)
+	return nil
+}
 
-		// Wait for graceful shutdown with timeout
-		done := make(chan struct{})
-		go func() {
-			managed.Process.Wait()
-			close(done)
-		}()
+// terminateProcess asks a runner's agent to wrap up, then terminates it,
+// then kills it, returning once the process has exited or been killed
+func (rm *RunnerManager) terminateProcess(ctx context.Context, managed *ManagedRunner) {
+	if managed.Process == nil || managed.Process.Process == nil {
+		return
+	}
 
-		select {
-		case <-done:
-			// Process exited gracefully
-		case <-time.After(10 * time.Second):
-			// Force kill
-			rm.logger.Warn("runner did not exit gracefully, killing",
-				zap.String("runner_id", runnerID))
-			managed.Process.Process.Kill()
-		}
+	if rm.signalAndWait(ctx, managed, managed.DrainSignal, managed.DrainTimeout) {
+		return
+	}
+	if managed.DrainSignal != 0 {
+		rm.logger.Info("runner did not drain, terminating",
+			zap.String("runner_id", managed.Runner.ID))
+	}
+	if rm.signalAndWait(ctx, managed, syscall.SIGTERM, stopTimeout) {
+		return
 	}
 
-	return nil
+	rm.logger.Warn("runner did not exit gracefully, killing",
+		zap.String("runner_id", managed.Runner.ID))
+	managed.Process.Process.Kill()
+}
+
+// waitForExitCode returns a runner's exit code once monitorProcess has
+// reaped it, or -1 if it has no process or ctx ends first
+func (rm *RunnerManager) waitForExitCode(ctx context.Context, managed *ManagedRunner) int {
+	if managed.Process == nil || managed.Process.Process == nil {
+		return -1
+	}
+	select {
+	case <-managed.exited:
+		return managed.exitCode
+	case <-ctx.Done():
+		return -1
+	}
+}
+
+// signalAndWait sends sig to a runner's agent and reports whether it exited
+// within timeout. A zero signal, or one the platform can't deliver, returns
+// false straight away.
+func (rm *RunnerManager) signalAndWait(ctx context.Context, managed *ManagedRunner, sig syscall.Signal, timeout time.Duration) bool {
+	if sig == 0 {
+		return false
+	}
+	if err := managed.Process.Process.Signal(sig); err != nil {
+		rm.logger.Debug("failed to signal runner",
+			zap.String("runner_id", managed.Runner.ID),
+			zap.String("signal", sig.String()),
+			zap.Error(err))
+		return false
+	}
+
+	return len(rm.waitForExit(ctx, []*ManagedRunner{managed}, timeout)) == 0
 }
 
 // GetActiveRunners returns all active runners
@@ -295,20 +1005,54 @@ func (rm *RunnerManager) GetActiveRunners() []*types.Runner {
 	return runners
 }
 
+// SubscribeLogs returns the last tail lines of a runner's output and, when
+// follow is set, a channel of new lines that closes when the runner exits.
+// The returned cancel func must be called to release the subscription.
+func (rm *RunnerManager) SubscribeLogs(runnerID string, tail int, follow bool) ([]logEntry, <-chan logEntry, func(), error) {
+	rm.mu.RLock()
+	managed, exists := rm.activeRunners[runnerID]
+	rm.mu.RUnlock()
+
+	if !exists || managed.Logs == nil {
+		return nil, nil, nil, fmt.Errorf("runner not active: %s", runnerID)
+	}
+
+	backlog, live, cancel := managed.Logs.subscribe(tail, follow)
+	return backlog, live, cancel, nil
+}
+
 // ReconcileRunners checks for stale runners and marks them as failed
 func (rm *RunnerManager) ReconcileRunners(ctx context.Context) error {
-	failedIDs, err := rm.db.ReconcileStaleRunners(ctx, 30)
+	// Any daemon may notice that another has stopped heartbeating
+	if staleNodes, err := rm.db.MarkStaleDaemonNodesInactive(ctx, DaemonNodeStaleAfter); err != nil {
+		rm.logger.Warn("failed to mark stale daemon nodes", zap.Error(err))
+	} else if len(staleNodes) > 0 {
+		rm.logger.Warn("marked stale daemon nodes inactive",
+			zap.Strings("node_ids", staleNodes))
+	}
+
+	stale, err := rm.db.ReconcileStaleRunners(ctx, rm.heartbeatTTL)
 	if err != nil {
 		return fmt.Errorf("reconcile stale runners: %w", err)
 	}
 
-	if len(failedIDs) > 0 {
+	if len(stale) > 0 {
+		failedIDs := make([]string, len(stale))
+		for i, r := range stale {
+			failedIDs[i] = r.ID
+		}
 		rm.logger.Warn("marked stale runners as failed",
 			zap.Int("count", len(failedIDs)),
 			zap.Strings("runner_ids", failedIDs))
+		rm.invalidateProjectCache(ctx)
 
 		// Publish failed events
-		for _, id := range failedIDs {
+		for _, r := range stale {
+			id := r.ID
+			rm.recordEvent(ctx, id, "runner.heartbeat_missed", map[string]interface{}{
+				"ttl_seconds": r.TTLSeconds,
+			})
+
 			event := map[string]interface{}{
 				"runner_id": id,
 				"reason":    "heartbeat_timeout",
@@ -318,9 +1062,136 @@ func (rm *RunnerManager) ReconcileRunners(ctx context.Context) error {
 		}
 	}
 
+	stuck, err := rm.db.GetStuckRunners(ctx, rm.startTimeout)
+	if err != nil {
+		return fmt.Errorf("get stuck runners: %w", err)
+	}
+	for _, r := range stuck {
+		rm.failStuckRunner(ctx, r)
+	}
+
 	return nil
 }
 
+// failStuckRunner fails a runner that never left the starting state, and
+// stops its agent if this node launched it
+func (rm *RunnerManager) failStuckRunner(ctx context.Context, runner *types.Runner) {
+	failed, err := rm.db.FailStartingRunner(ctx, runner.ID, killReasonStartupTimeout)
+	if err != nil {
+		rm.logger.Warn("failed to fail stuck runner",
+			zap.String("runner_id", runner.ID),
+			zap.Error(err))
+		return
+	}
+	if !failed {
+		return
+	}
+	rm.invalidateProjectCache(ctx, runner.ProjectName)
+
+	rm.logger.Warn("runner did not start in time, marked failed",
+		zap.String("runner_id", runner.ID),
+		zap.String("project", runner.ProjectName),
+		zap.Time("started_at", runner.StartedAt),
+		zap.Duration("startup_timeout", rm.startTimeout))
+
+	rm.recordEvent(ctx, runner.ID, "runner.startup_timeout", map[string]interface{}{
+		"startup_timeout_seconds": int(rm.startTimeout.Seconds()),
+	})
+	rm.messaging.Publish(ctx, fmt.Sprintf("runner.failed.%s", runner.ID), map[string]interface{}{
+		"runner_id": runner.ID,
+		"reason":    killReasonStartupTimeout,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+	rm.dispatcher.Notify(runner.ProjectName, notifications.EventRunnerFailed, map[string]interface{}{
+		"runner_id": runner.ID,
+		"reason":    fmt.Sprintf("agent did not start within %s", rm.startTimeout),
+	})
+
+	// The agent may still be alive but hung; stopping it also keeps
+	// monitorProcess from reporting the failure a second time
+	rm.mu.RLock()
+	_, local := rm.activeRunners[runner.ID]
+	rm.mu.RUnlock()
+	if local {
+		go rm.stopRunner(context.Background(), runner.ID, killReasonStartupTimeout)
+	}
+}
+
+// mergePreset returns a copy of req with unset fields filled from preset.
+// Fields set on the request take precedence; environment maps are merged
+// key by key.
+func mergePreset(req *types.LaunchRequest, preset *types.Preset) *types.LaunchRequest {
+	merged := *req
+
+	if merged.RuntimeType == "" {
+		merged.RuntimeType = preset.RuntimeType
+	}
+	if merged.ConversationMode == "" {
+		merged.ConversationMode = preset.ConversationMode
+	}
+	if len(merged.Flags) == 0 {
+		merged.Flags = preset.Flags
+	}
+	if len(merged.Capabilities) == 0 {
+		merged.Capabilities = preset.Capabilities
+	}
+
+	env := make(map[string]string, len(preset.Environment)+len(req.Environment))
+	for k, v := range preset.Environment {
+		env[k] = v
+	}
+	for k, v := range req.Environment {
+		env[k] = v
+	}
+	merged.Environment = env
+
+	if merged.RuntimeType == "" {
+		merged.RuntimeType = types.RuntimeProcess
+	}
+	if merged.ConversationMode == "" {
+		merged.ConversationMode = types.ModeNew
+	}
+
+	return &merged
+}
+
+// recordEvent writes a runner lifecycle event to the audit trail.
+// Failures are logged and never block the lifecycle operation itself.
+func (rm *RunnerManager) recordEvent(ctx context.Context, runnerID, eventType string, data map[string]interface{}) {
+	hostname, _ := os.Hostname()
+	event := &types.Event{
+		Timestamp:  time.Now(),
+		EventType:  eventType,
+		EntityType: "runner",
+		EntityID:   runnerID,
+		Data:       data,
+		Hostname:   hostname,
+	}
+
+	if err := rm.db.InsertRunnerEvent(ctx, event); err != nil {
+		rm.logger.Warn("failed to record runner event",
+			zap.String("runner_id", runnerID),
+			zap.String("event_type", eventType),
+			zap.Error(err))
+	}
+}
+
+// invalidateProjectCache drops the named projects, and every cached project
+// list, from the cache after their runners change status. With no names
+// only the lists are dropped.
+func (rm *RunnerManager) invalidateProjectCache(ctx context.Context, projectNames ...string) {
+	if rm.cache == nil {
+		return
+	}
+	if len(projectNames) == 0 {
+		rm.cache.InvalidateProjectLists(ctx)
+		return
+	}
+	for _, name := range projectNames {
+		rm.cache.InvalidateProject(ctx, name)
+	}
+}
+
 // updateProjectAccess updates the last accessed timestamp
 func (rm *RunnerManager) updateProjectAccess(ctx context.Context, projectName string) {
 	// This would be a simple UPDATE query
@@ -331,21 +1202,45 @@ func (rm *RunnerManager) updateProjectAccess(ctx context.Context, projectName st
 func (rm *RunnerManager) Shutdown(ctx context.Context) error {
 	rm.logger.Info("shutting down runner manager")
 
-	rm.mu.RLock()
-	runnerIDs := make([]string, 0, len(rm.activeRunners))
-	for id := range rm.activeRunners {
-		runnerIDs = append(runnerIDs, id)
+	// From here monitorProcess leaves the termination of every runner
+	// taken below to the bulk query at the end
+	rm.mu.Lock()
+	rm.shuttingDown = true
+	runners := make([]*ManagedRunner, 0, len(rm.activeRunners))
+	for _, managed := range rm.activeRunners {
+		runners = append(runners, managed)
 	}
-	rm.mu.RUnlock()
+	rm.mu.Unlock()
 
-	// Stop all runners
-	for _, id := range runnerIDs {
-		if err := rm.StopRunner(ctx, id); err != nil {
-			rm.logger.Error("error stopping runner during shutdown",
-				zap.String("runner_id", id),
-				zap.Error(err))
+	// Stop all runners at once so each gets its full drain period
+	var wg sync.WaitGroup
+	for _, managed := range runners {
+		if !managed.signalStop() {
+			// Another stop is already terminating it
+			continue
 		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rm.terminateProcess(ctx, managed)
+		}()
+	}
+	wg.Wait()
+
+	// Stops that were already in flight finish on their own; every process
+	// must be reaped before its exit code is known
+	exitCodes := make(map[string]int, len(runners))
+	for _, managed := range runners {
+		exitCodes[managed.Runner.ID] = rm.waitForExitCode(ctx, managed)
+	}
+
+	// Record every termination in one query instead of one per runner
+	if err := rm.db.BulkTerminateRunners(ctx, exitCodes); err != nil {
+		rm.logger.Error("error marking runners terminated during shutdown",
+			zap.Int("count", len(exitCodes)),
+			zap.Error(err))
 	}
+	rm.invalidateProjectCache(ctx)
 
 	return nil
 }
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/config"
+	"go.uber.org/zap"
+)
+
+// versionCheckTimeout bounds the request for the latest released version
+const versionCheckTimeout = 10 * time.Second
+
+// SetUpgradeConfig sets where release artifacts are published for
+// GetDaemonVersion
+func (s *GRPCServer) SetUpgradeConfig(cfg config.UpgradeConfig) {
+	s.upgrade = cfg
+}
+
+// SetBuildInfo records when and from which commit the daemon was built, for
+// GetDaemonVersion
+func (s *GRPCServer) SetBuildInfo(buildTime, commit string) {
+	s.buildTime = buildTime
+	s.commit = commit
+}
+
+// minCompatibleClientVersion is the oldest CLI version the daemon supports:
+// the first release of its own major version
+func minCompatibleClientVersion(daemonVersion string) string {
+	v, err := api.ParseVersion(daemonVersion)
+	if err != nil {
+		return ""
+	}
+	return api.Version{Major: v.Major}.String()
+}
+
+// GetDaemonVersion reports the running version and, when upgrade.version_url
+// is configured, the latest released version with its download URLs
+func (s *GRPCServer) GetDaemonVersion(ctx context.Context, req *api.GetDaemonVersionRequest) (*api.GetDaemonVersionResponse, error) {
+	binaryPath, err := os.Executable()
+	if err == nil {
+		binaryPath, err = filepath.EvalSymlinks(binaryPath)
+	}
+	if err != nil {
+		return &api.GetDaemonVersionResponse{
+			Error: fmt.Sprintf("locate daemon binary: %v", err),
+		}, nil
+	}
+
+	resp := &api.GetDaemonVersionResponse{
+		CurrentVersion:             s.version,
+		BuildTime:                  s.buildTime,
+		Commit:                     s.commit,
+		MinCompatibleClientVersion: minCompatibleClientVersion(s.version),
+		BinaryPath:                 binaryPath,
+	}
+
+	if s.upgrade.VersionURL != "" {
+		latest, err := fetchLatestVersion(ctx, s.upgrade.VersionURL)
+		if err != nil {
+			s.logger.Warn("failed to fetch latest version", zap.Error(err))
+			resp.Error = fmt.Sprintf("fetch latest version: %v", err)
+			return resp, nil
+		}
+		resp.LatestVersion = latest
+		resp.UpdateAvailable = latest != "" && latest != s.version
+	}
+
+	resp.DownloadURL = expandVersion(s.upgrade.ReleaseURL, resp.LatestVersion)
+	resp.ChecksumURL = expandVersion(s.upgrade.ChecksumURL, resp.LatestVersion)
+
+	return resp, nil
+}
+
+// fetchLatestVersion reads a plain-text version string, e.g. "1.5.0"
+func fetchLatestVersion(ctx context.Context, versionURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, versionURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(string(body)), "v"), nil
+}
+
+// expandVersion substitutes "{version}" in a release URL
+func expandVersion(url, version string) string {
+	return strings.ReplaceAll(url, "{version}", version)
+}
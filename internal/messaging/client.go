@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -19,7 +20,44 @@ type Client struct {
 	confirms  chan amqp.Confirmation
 	logger    *zap.Logger
 	mu        sync.RWMutex
-	connected bool
+	connected bool // state == StateConnected
+	state     ConnectionState
+
+	url    string
+	policy ReconnectionPolicy
+
+	// reconnectAttempts counts every reconnection attempt since the client
+	// was created
+	reconnectAttempts  atomic.Int64
+	onPermanentFailure func()
+
+	// closed stops reconnection once Close is called
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// confirmMode and registeredQueues are replayed onto a new channel
+	// when the current one dies
+	confirmMode      bool
+	registeredQueues []QueueDeclaration
+
+	// publishMu serialises publish-and-confirm so each publisher waits for
+	// its own delivery tag. Confirms arrive on one channel in publish
+	// order, so concurrent publishers would otherwise take each other's.
+	publishMu sync.Mutex
+}
+
+// confirmTimeout bounds the wait for the broker to confirm a publish
+const confirmTimeout = 5 * time.Second
+
+// channelRetryInterval is how long to wait before retrying a failed channel
+// recovery
+const channelRetryInterval = 2 * time.Second
+
+// QueueDeclaration is a queue declared through DeclareQueue, kept so it can
+// be declared again on a recovered channel
+type QueueDeclaration struct {
+	Name        string
+	BindingKeys []string
 }
 
 // Config for RabbitMQ client
@@ -30,6 +68,7 @@ type Config struct {
 	Password          string
 	Exchange          string
 	PublisherConfirms bool
+	Reconnect         ReconnectionPolicy // zero fields take DefaultReconnectionPolicy's
 }
 
 // NewClient creates a new RabbitMQ client
@@ -41,57 +80,41 @@ func NewClient(cfg Config, logger *zap.Logger) (*Client, error) {
 		return nil, fmt.Errorf("dial rabbitmq: %w", err)
 	}
 	
-	channel, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("open channel: %w", err)
+	client := &Client{
+		conn:        conn,
+		exchange:    cfg.Exchange,
+		logger:      logger,
+		connected:   true,
+		state:       StateConnected,
+		url:         url,
+		policy:      cfg.Reconnect.withDefaults(),
+		closed:      make(chan struct{}),
+		confirmMode: cfg.PublisherConfirms,
 	}
-	
-	// Declare exchange
-	err = channel.ExchangeDeclare(
-		cfg.Exchange, // name
-		"topic",      // type
-		true,         // durable
-		false,        // auto-deleted
-		false,        // internal
-		false,        // no-wait
-		nil,          // arguments
-	)
+
+	channel, confirms, err := client.openChannel(conn)
 	if err != nil {
-		channel.Close()
 		conn.Close()
-		return nil, fmt.Errorf("declare exchange: %w", err)
-	}
-	
-	client := &Client{
-		conn:      conn,
-		channel:   channel,
-		exchange:  cfg.Exchange,
-		logger:    logger,
-		connected: true,
-	}
-	
-	// Enable publisher confirms if requested
-	if cfg.PublisherConfirms {
-		if err := channel.Confirm(false); err != nil {
-			client.Close()
-			return nil, fmt.Errorf("enable confirms: %w", err)
-		}
-		client.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 100))
+		return nil, err
 	}
-	
-	// Monitor connection
-	go client.monitorConnection()
+	client.channel = channel
+	client.confirms = confirms
+
+	// Monitor connection and channel
+	go client.monitorConnection(conn)
+	go client.monitorChannel(conn, channel)
 	
 	return client, nil
 }
 
 // Close closes the RabbitMQ connection
 func (c *Client) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
-	c.connected = false
+	c.setState(StateClosed)
 	
 	if c.channel != nil {
 		c.channel.Close()
@@ -102,15 +125,18 @@ func (c *Client) Close() error {
 	return nil
 }
 
-// Publish publishes a message to the exchange
+// Publish publishes a message to the exchange. With publisher confirms it
+// waits for the broker to confirm this message; concurrent publishes are
+// serialised while they wait.
 func (c *Client) Publish(ctx context.Context, routingKey string, payload interface{}) error {
 	c.mu.RLock()
 	if !c.connected {
 		c.mu.RUnlock()
 		return fmt.Errorf("not connected to rabbitmq")
 	}
+	channel, confirms := c.channel, c.confirms
 	c.mu.RUnlock()
-	
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
@@ -126,8 +152,32 @@ func (c *Client) Publish(ctx context.Context, routingKey string, payload interfa
 		DeliveryMode: amqp.Persistent, // Persistent messages
 	}
 	
-	// Publish with context
-	err = c.channel.PublishWithContext(
+	if confirms == nil {
+		if err := c.publish(ctx, channel, routingKey, msg); err != nil {
+			return err
+		}
+	} else {
+		c.publishMu.Lock()
+		seqNo := channel.GetNextPublishSeqNo()
+		err := c.publish(ctx, channel, routingKey, msg)
+		if err == nil {
+			err = waitForConfirm(ctx, confirms, seqNo)
+		}
+		c.publishMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.logger.Debug("published message",
+		zap.String("routing_key", routingKey),
+		zap.Int("body_size", len(body)))
+	
+	return nil
+}
+
+func (c *Client) publish(ctx context.Context, channel *amqp.Channel, routingKey string, msg amqp.Publishing) error {
+	err := channel.PublishWithContext(
 		ctx,
 		c.exchange,
 		routingKey,
@@ -135,43 +185,78 @@ func (c *Client) Publish(ctx context.Context, routingKey string, payload interfa
 		false, // immediate
 		msg,
 	)
-	
 	if err != nil {
 		return fmt.Errorf("publish message: %w", err)
 	}
-	
-	// Wait for publisher confirmation if enabled
-	if c.confirms != nil {
+	return nil
+}
+
+// waitForConfirm waits for the broker to confirm delivery tag seqNo.
+// Confirms for earlier tags belong to publishes that already timed out and
+// are discarded.
+func waitForConfirm(ctx context.Context, confirms <-chan amqp.Confirmation, seqNo uint64) error {
+	timer := time.NewTimer(confirmTimeout)
+	defer timer.Stop()
+
+	for {
 		select {
-		case confirm := <-c.confirms:
+		case confirm, ok := <-confirms:
+			if !ok {
+				return fmt.Errorf("channel closed before confirmation")
+			}
+			if confirm.DeliveryTag < seqNo {
+				continue
+			}
+			if confirm.DeliveryTag > seqNo {
+				return fmt.Errorf("confirmation for delivery tag %d missed", seqNo)
+			}
 			if !confirm.Ack {
 				return fmt.Errorf("message not acknowledged by broker")
 			}
-		case <-time.After(5 * time.Second):
+			return nil
+		case <-timer.C:
 			return fmt.Errorf("confirmation timeout")
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
-	
-	c.logger.Debug("published message",
-		zap.String("routing_key", routingKey),
-		zap.Int("body_size", len(body)))
-	
-	return nil
 }
 
-// DeclareQueue declares a queue and binds it to the exchange
+// DeclareQueue declares a queue and binds it to the exchange. The
+// declaration is repeated if the channel is recovered.
 func (c *Client) DeclareQueue(name string, bindingKeys []string) error {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if !c.connected {
 		return fmt.Errorf("not connected to rabbitmq")
 	}
-	
-	// Declare queue
-	_, err := c.channel.QueueDeclare(
+
+	if err := c.declareQueue(c.channel, name, bindingKeys); err != nil {
+		return err
+	}
+
+	decl := QueueDeclaration{Name: name, BindingKeys: bindingKeys}
+	registered := false
+	for i, q := range c.registeredQueues {
+		if q.Name == name {
+			c.registeredQueues[i] = decl
+			registered = true
+		}
+	}
+	if !registered {
+		c.registeredQueues = append(c.registeredQueues, decl)
+	}
+
+	c.logger.Info("declared queue",
+		zap.String("queue", name),
+		zap.Strings("binding_keys", bindingKeys))
+
+	return nil
+}
+
+func (c *Client) declareQueue(channel *amqp.Channel, name string, bindingKeys []string) error {
+	_, err := channel.QueueDeclare(
 		name,  // name
 		true,  // durable
 		false, // delete when unused
@@ -184,10 +269,10 @@ func (c *Client) DeclareQueue(name string, bindingKeys []string) error {
 	if err != nil {
 		return fmt.Errorf("declare queue: %w", err)
 	}
-	
+
 	// Bind to exchange with routing keys
 	for _, key := range bindingKeys {
-		err = c.channel.QueueBind(
+		err = channel.QueueBind(
 			name,       // queue name
 			key,        // routing key
 			c.exchange, // exchange
@@ -198,11 +283,7 @@ func (c *Client) DeclareQueue(name string, bindingKeys []string) error {
 			return fmt.Errorf("bind queue: %w", err)
 		}
 	}
-	
-	c.logger.Info("declared queue",
-		zap.String("queue", name),
-		zap.Strings("binding_keys", bindingKeys))
-	
+
 	return nil
 }
 
@@ -213,10 +294,11 @@ func (c *Client) Consume(queueName string, handler func([]byte) error) error {
 		c.mu.RUnlock()
 		return fmt.Errorf("not connected to rabbitmq")
 	}
+	channel := c.channel
 	c.mu.RUnlock()
-	
+
 	// Set QoS
-	err := c.channel.Qos(
+	err := channel.Qos(
 		20,    // prefetch count
 		0,     // prefetch size
 		false, // global
@@ -225,7 +307,7 @@ func (c *Client) Consume(queueName string, handler func([]byte) error) error {
 		return fmt.Errorf("set qos: %w", err)
 	}
 	
-	msgs, err := c.channel.Consume(
+	msgs, err := channel.Consume(
 		queueName,
 		"",    // consumer tag
 		false, // auto-ack
@@ -256,17 +338,237 @@ func (c *Client) Consume(queueName string, handler func([]byte) error) error {
 	return nil
 }
 
-// monitorConnection watches for connection issues
-func (c *Client) monitorConnection() {
-	closeChan := make(chan *amqp.Error)
-	c.conn.NotifyClose(closeChan)
-	
-	err := <-closeChan
+// SetOnPermanentFailure sets a callback run once if the client gives up
+// reconnecting. Call before the connection can drop.
+func (c *Client) SetOnPermanentFailure(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onPermanentFailure = fn
+}
+
+// setState moves the client to state; callers hold c.mu
+func (c *Client) setState(state ConnectionState) {
+	c.state = state
+	c.connected = state == StateConnected
+}
+
+// monitorConnection waits for conn to drop, then reconnects according to
+// the reconnection policy. It hands over to a new monitorConnection for
+// each replacement connection.
+func (c *Client) monitorConnection(conn *amqp.Connection) {
+	closeErr, ok := <-conn.NotifyClose(make(chan *amqp.Error, 1))
+	if !ok || closeErr == nil {
+		// Closed by Close
+		return
+	}
+
+	c.mu.Lock()
+	if c.state == StateClosed {
+		c.mu.Unlock()
+		return
+	}
+	c.setState(StateDisconnected)
+	c.mu.Unlock()
+
+	c.logger.Error("rabbitmq connection lost", zap.Error(closeErr))
+
+	for attempt := 1; ; attempt++ {
+		if c.policy.exhausted(attempt) {
+			c.fail(attempt - 1)
+			return
+		}
+
+		delay := c.policy.delay(attempt)
+		c.mu.Lock()
+		c.setState(StateReconnecting)
+		c.mu.Unlock()
+		c.reconnectAttempts.Add(1)
+
+		c.logger.Warn("reconnecting to rabbitmq",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", c.policy.MaxAttempts),
+			zap.Duration("delay", delay))
+
+		select {
+		case <-time.After(delay):
+		case <-c.closed:
+			return
+		}
+
+		next, err := c.reconnect()
+		if err != nil {
+			c.logger.Warn("rabbitmq reconnection attempt failed",
+				zap.Int("attempt", attempt),
+				zap.Error(err))
+			continue
+		}
+
+		c.logger.Info("reconnected to rabbitmq", zap.Int("attempts", attempt))
+		go c.monitorConnection(next)
+		return
+	}
+}
+
+// reconnect dials a new connection and restores the channel, exchange,
+// confirm mode and registered queues on it. Consumers are not restored.
+func (c *Client) reconnect() (*amqp.Connection, error) {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return nil, fmt.Errorf("dial rabbitmq: %w", err)
+	}
+
+	c.mu.RLock()
+	queues := append([]QueueDeclaration(nil), c.registeredQueues...)
+	c.mu.RUnlock()
+
+	channel, confirms, err := c.openChannel(conn)
+	if err == nil {
+		for _, q := range queues {
+			if err = c.declareQueue(channel, q.Name, q.BindingKeys); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.state == StateClosed {
+		c.mu.Unlock()
+		conn.Close()
+		return nil, fmt.Errorf("client closed")
+	}
+	c.conn = conn
+	c.channel = channel
+	c.confirms = confirms
+	c.setState(StateConnected)
+	c.mu.Unlock()
+
+	go c.monitorChannel(conn, channel)
+	return conn, nil
+}
+
+// fail enters the terminal failed state and runs the permanent failure
+// callback
+func (c *Client) fail(attempts int) {
+	c.mu.Lock()
+	if c.state == StateClosed {
+		c.mu.Unlock()
+		return
+	}
+	c.setState(StateFailed)
+	onFailure := c.onPermanentFailure
+	c.mu.Unlock()
+
+	c.logger.Error("giving up reconnecting to rabbitmq", zap.Int("attempts", attempts))
+
+	if onFailure != nil {
+		onFailure()
+	}
+}
+
+// openChannel opens a channel on the connection, declares the exchange and,
+// in confirm mode, enables publisher confirms
+func (c *Client) openChannel(conn *amqp.Connection) (*amqp.Channel, chan amqp.Confirmation, error) {
+	channel, err := conn.Channel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open channel: %w", err)
+	}
+
+	// Declare exchange
+	err = channel.ExchangeDeclare(
+		c.exchange, // name
+		"topic",    // type
+		true,       // durable
+		false,      // auto-deleted
+		false,      // internal
+		false,      // no-wait
+		nil,        // arguments
+	)
 	if err != nil {
-		c.logger.Error("connection closed", zap.Error(err))
+		channel.Close()
+		return nil, nil, fmt.Errorf("declare exchange: %w", err)
+	}
+
+	var confirms chan amqp.Confirmation
+	if c.confirmMode {
+		if err := channel.Confirm(false); err != nil {
+			channel.Close()
+			return nil, nil, fmt.Errorf("enable confirms: %w", err)
+		}
+		confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 100))
+	}
+
+	return channel, confirms, nil
+}
+
+// monitorChannel watches for channel-level errors, such as publishing to a
+// missing exchange, that close the channel while the connection stays up.
+// The channel is replaced and the exchange, confirm mode and registered
+// queues are restored. Consumers started on the old channel are not.
+// Channels closed along with their connection are left to
+// monitorConnection.
+func (c *Client) monitorChannel(conn *amqp.Connection, channel *amqp.Channel) {
+	for {
+		closeErr, ok := <-channel.NotifyClose(make(chan *amqp.Error, 1))
+		if !ok || closeErr == nil {
+			// Closed by Close
+			return
+		}
+
+		c.logger.Warn("channel closed, recovering", zap.Error(closeErr))
+
+		next, err := c.recoverChannel(conn)
+		if err != nil {
+			c.logger.Warn("channel recovery abandoned", zap.Error(err))
+			return
+		}
+		channel = next
+	}
+}
+
+// recoverChannel opens a replacement channel on conn, retrying until it
+// succeeds or conn or the client is closed
+func (c *Client) recoverChannel(conn *amqp.Connection) (*amqp.Channel, error) {
+	for {
+		c.mu.RLock()
+		current := c.connected && c.conn == conn
+		queues := append([]QueueDeclaration(nil), c.registeredQueues...)
+		c.mu.RUnlock()
+
+		if !current || conn.IsClosed() {
+			return nil, fmt.Errorf("connection closed")
+		}
+
+		channel, confirms, err := c.openChannel(conn)
+		if err == nil {
+			for _, q := range queues {
+				if err = c.declareQueue(channel, q.Name, q.BindingKeys); err != nil {
+					channel.Close()
+					break
+				}
+			}
+		}
+		if err != nil {
+			c.logger.Warn("channel recovery failed", zap.Error(err))
+			time.Sleep(channelRetryInterval)
+			continue
+		}
+
 		c.mu.Lock()
-		c.connected = false
+		if !c.connected || c.conn != conn {
+			c.mu.Unlock()
+			channel.Close()
+			return nil, fmt.Errorf("connection closed")
+		}
+		c.channel = channel
+		c.confirms = confirms
 		c.mu.Unlock()
+
+		c.logger.Warn("channel recovered", zap.Int("queues", len(queues)))
+		return channel, nil
 	}
 }
 
@@ -276,3 +578,16 @@ func (c *Client) IsConnected() bool {
 	defer c.mu.RUnlock()
 	return c.connected
 }
+
+// State returns where the client is in its connection lifecycle
+func (c *Client) State() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// ReconnectAttempts returns the number of reconnection attempts made since
+// the client was created
+func (c *Client) ReconnectAttempts() int64 {
+	return c.reconnectAttempts.Load()
+}
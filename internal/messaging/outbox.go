@@ -2,6 +2,8 @@ package messaging
 
 import (
 	"context"
+	"math/rand/v2"
+	"sync/atomic"
 	"time"
 
 	"github.com/meridian-lex/stratavore/internal/storage"
@@ -9,6 +11,24 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// DefaultOutboxRetention is how long delivered entries are kept before cleanup
+	DefaultOutboxRetention = 7 * 24 * time.Hour
+
+	// outboxCleanupInterval is how often CleanupLoop purges delivered entries
+	outboxCleanupInterval = 6 * time.Hour
+
+	// DefaultOutboxPartitionRetentionMonths is how many whole months of
+	// outbox partitions are kept besides the current one
+	DefaultOutboxPartitionRetentionMonths = 3
+
+	// outboxPartitionInterval is how often PartitionLoop runs
+	outboxPartitionInterval = 24 * time.Hour
+
+	// outboxPartitionLead is how far ahead next month's partition is created
+	outboxPartitionLead = 7 * 24 * time.Hour
+)
+
 // OutboxPublisher polls the outbox table and publishes events
 type OutboxPublisher struct {
 	db        *storage.PostgresClient
@@ -17,6 +37,9 @@ type OutboxPublisher struct {
 	batchSize int
 	logger    *zap.Logger
 	stopCh    chan struct{}
+
+	// Cleanup stats
+	cleanedTotal int64
 }
 
 // NewOutboxPublisher creates a new outbox publisher
@@ -37,8 +60,20 @@ func NewOutboxPublisher(
 	}
 }
 
-// Start begins polling and publishing
+// Start begins polling and publishing. The first poll is delayed by a
+// random fraction of the interval so daemons restarted together don't
+// poll in lockstep.
 func (p *OutboxPublisher) Start(ctx context.Context) {
+	select {
+	case <-time.After(rand.N(p.interval)):
+	case <-p.stopCh:
+		p.logger.Info("outbox publisher stopped")
+		return
+	case <-ctx.Done():
+		p.logger.Info("outbox publisher context cancelled")
+		return
+	}
+
 	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
@@ -129,11 +164,110 @@ func (p *OutboxPublisher) processEntry(ctx context.Context, entry *types.OutboxE
 		zap.String("routing_key", entry.RoutingKey))
 }
 
+// CleanupLoop periodically deletes delivered outbox entries older than the
+// retention period. A non-positive retention uses DefaultOutboxRetention.
+func (p *OutboxPublisher) CleanupLoop(ctx context.Context, retention time.Duration) {
+	if retention <= 0 {
+		retention = DefaultOutboxRetention
+	}
+
+	ticker := time.NewTicker(outboxCleanupInterval)
+	defer ticker.Stop()
+
+	p.logger.Info("outbox cleanup loop started",
+		zap.Duration("interval", outboxCleanupInterval),
+		zap.Duration("retention", retention))
+
+	for {
+		select {
+		case <-ticker.C:
+			p.cleanup(ctx, retention)
+		case <-p.stopCh:
+			p.logger.Info("outbox cleanup loop stopped")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanup purges delivered entries older than retention
+func (p *OutboxPublisher) cleanup(ctx context.Context, retention time.Duration) {
+	before := time.Now().Add(-retention)
+
+	deleted, err := p.db.CleanDeliveredOutboxEntries(ctx, before)
+	if err != nil {
+		p.logger.Error("failed to clean delivered outbox entries", zap.Error(err))
+		return
+	}
+
+	atomic.AddInt64(&p.cleanedTotal, deleted)
+
+	p.logger.Info("cleaned delivered outbox entries",
+		zap.Int64("deleted", deleted),
+		zap.Time("before", before))
+}
+
+// PartitionLoop keeps the monthly outbox partitions in step with time: it
+// creates the next month's partition a week before it is needed and drops
+// partitions older than retentionMonths. A non-positive retention uses
+// DefaultOutboxPartitionRetentionMonths.
+func (p *OutboxPublisher) PartitionLoop(ctx context.Context, retentionMonths int) {
+	if retentionMonths <= 0 {
+		retentionMonths = DefaultOutboxPartitionRetentionMonths
+	}
+
+	ticker := time.NewTicker(outboxPartitionInterval)
+	defer ticker.Stop()
+
+	p.logger.Info("outbox partition maintenance started",
+		zap.Int("retention_months", retentionMonths))
+
+	// Run once immediately so a daemon started late in the month is covered
+	p.maintainPartitions(ctx, retentionMonths)
+
+	for {
+		select {
+		case <-ticker.C:
+			p.maintainPartitions(ctx, retentionMonths)
+		case <-p.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// maintainPartitions creates upcoming partitions and drops expired ones
+func (p *OutboxPublisher) maintainPartitions(ctx context.Context, retentionMonths int) {
+	now := time.Now().UTC()
+
+	for _, month := range []time.Time{now, now.Add(outboxPartitionLead)} {
+		if err := p.db.CreateOutboxPartition(ctx, month); err != nil {
+			p.logger.Error("failed to create outbox partition", zap.Error(err))
+		}
+	}
+
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	cutoff := currentMonth.AddDate(0, -retentionMonths, 0)
+
+	dropped, err := p.db.DropOutboxPartitionsBefore(ctx, cutoff)
+	if err != nil {
+		p.logger.Error("failed to drop expired outbox partitions", zap.Error(err))
+	}
+	if len(dropped) > 0 {
+		p.logger.Info("dropped expired outbox partitions",
+			zap.Strings("partitions", dropped),
+			zap.Time("cutoff", cutoff))
+	}
+}
+
 // GetStats returns current outbox statistics
 func (p *OutboxPublisher) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	// Could query database for stats like pending count, oldest pending, etc.
 	return map[string]interface{}{
 		"interval_seconds": p.interval.Seconds(),
 		"batch_size":       p.batchSize,
+		"cleaned_total":    atomic.LoadInt64(&p.cleanedTotal),
 	}, nil
 }
@@ -0,0 +1,97 @@
+package messaging
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ConnectionState is where a Client is in its connection lifecycle:
+// connected → disconnected → reconnecting → connected, or failed once the
+// reconnection policy gives up
+type ConnectionState int
+
+const (
+	StateConnected ConnectionState = iota
+	StateDisconnected
+	StateReconnecting
+	StateFailed // terminal; the client must be recreated
+	StateClosed // closed by Close
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateFailed:
+		return "failed"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconnectionPolicy controls how a Client reconnects after its connection
+// drops. The delay before attempt n is InitialDelay * BackoffFactor^(n-1),
+// capped at MaxDelay.
+type ReconnectionPolicy struct {
+	InitialDelay  time.Duration
+	MaxDelay      time.Duration
+	MaxAttempts   int // 0 = retry forever
+	BackoffFactor float64
+	// Jitter randomises each delay between half and all of its value, so
+	// daemons that lost the broker together don't reconnect in lockstep
+	Jitter bool
+}
+
+// DefaultReconnectionPolicy retries with exponential backoff from 1s to 1m,
+// giving up after 20 attempts (about 15 minutes)
+var DefaultReconnectionPolicy = ReconnectionPolicy{
+	InitialDelay:  time.Second,
+	MaxDelay:      time.Minute,
+	MaxAttempts:   20,
+	BackoffFactor: 2,
+	Jitter:        true,
+}
+
+// withDefaults fills in unset fields from DefaultReconnectionPolicy
+func (p ReconnectionPolicy) withDefaults() ReconnectionPolicy {
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultReconnectionPolicy.InitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultReconnectionPolicy.MaxDelay
+	}
+	if p.MaxDelay < p.InitialDelay {
+		p.MaxDelay = p.InitialDelay
+	}
+	if p.BackoffFactor < 1 {
+		p.BackoffFactor = DefaultReconnectionPolicy.BackoffFactor
+	}
+	if p.MaxAttempts < 0 {
+		p.MaxAttempts = 0
+	}
+	return p
+}
+
+// delay returns how long to wait before reconnection attempt n (from 1)
+func (p ReconnectionPolicy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay) * math.Pow(p.BackoffFactor, float64(attempt-1))
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter {
+		d = d/2 + rand.Float64()*d/2
+	}
+	return time.Duration(d)
+}
+
+// exhausted reports whether attempt is beyond the policy's limit
+func (p ReconnectionPolicy) exhausted(attempt int) bool {
+	return p.MaxAttempts > 0 && attempt > p.MaxAttempts
+}
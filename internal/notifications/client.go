@@ -16,10 +16,11 @@ import (
 
 // Client sends notifications via Telegram Bot API
 type Client struct {
-	token   string
-	chatID  string
-	logger  *zap.Logger
-	client  *http.Client
+	token    string
+	chatID   string
+	logger   *zap.Logger
+	client   *http.Client
+	recorder HistoryRecorder
 }
 
 // Config for Telegram client
@@ -51,12 +52,33 @@ const (
 	PriorityUrgent  NotificationPriority = "urgent"
 )
 
-// sendText sends a text message to Telegram
+// SetRecorder enables delivery history logging
+func (c *Client) SetRecorder(r HistoryRecorder) {
+	c.recorder = r
+}
+
+// notify sends text and records the attempt in the notification history
+func (c *Client) notify(eventType, text string) {
+	err := c.sendText(text)
+	if err != nil {
+		c.logger.Error("failed to send notification",
+			zap.String("event_type", eventType),
+			zap.Error(err))
+	}
+	recordDelivery(c.recorder, c.logger, "telegram", eventType, c.chatID, text, err)
+}
+
+// sendText sends a text message to the configured Telegram chat
 func (c *Client) sendText(text string) error {
+	return c.sendTextTo(c.chatID, text)
+}
+
+// sendTextTo sends a text message to any chat the bot can post in
+func (c *Client) sendTextTo(chatID, text string) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", c.token)
 
 	payload := map[string]interface{}{
-		"chat_id":    c.chatID,
+		"chat_id":    chatID,
 		"text":       text,
 		"parse_mode": "Markdown",
 	}
@@ -151,9 +173,7 @@ func (c *Client) RunnerStarted(project, runnerID string) {
 		fmt.Sprintf("Project: `%s`\nRunner: `%s`", project, runnerID[:8]),
 		PriorityDefault)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("runner.started", text)
 }
 
 // RunnerStopped sends notification when runner stops
@@ -167,9 +187,7 @@ func (c *Client) RunnerStopped(project, runnerID string, exitCode int) {
 		fmt.Sprintf("Project: `%s`\nRunner: `%s`\nExit code: `%d`", project, runnerID[:8], exitCode),
 		PriorityLow)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("runner.stopped", text)
 }
 
 // RunnerFailed sends notification when runner fails
@@ -178,9 +196,7 @@ func (c *Client) RunnerFailed(project, runnerID string, reason error) {
 		fmt.Sprintf("Project: `%s`\nRunner: `%s`\nReason: %v", project, runnerID[:8], reason),
 		PriorityHigh)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("runner.failed", text)
 }
 
 // TokenBudgetWarning sends notification when token budget reaches threshold
@@ -196,9 +212,7 @@ func (c *Client) TokenBudgetWarning(scope string, percent int) {
 		fmt.Sprintf("Scope: `%s`\nUsage: *%d%%*", scope, percent),
 		priority)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("budget.warning", text)
 }
 
 // DaemonStarted sends notification when daemon starts
@@ -208,9 +222,7 @@ func (c *Client) DaemonStarted(version, hostname string) {
 			version, hostname, time.Now().Format("2006-01-02 15:04:05")),
 		PriorityDefault)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("daemon.started", text)
 }
 
 // DaemonStopped sends notification when daemon stops
@@ -219,18 +231,14 @@ func (c *Client) DaemonStopped(hostname string) {
 		fmt.Sprintf("Host: `%s`\nTime: %s", hostname, time.Now().Format("2006-01-02 15:04:05")),
 		PriorityDefault)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("daemon.stopped", text)
 }
 
 // SystemAlert sends a system-level alert
 func (c *Client) SystemAlert(title, message string, priority NotificationPriority) {
 	text := formatMessage("⚡", title, message, priority)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("system.alert", text)
 }
 
 // QuotaExceeded sends notification when resource quota is exceeded
@@ -239,9 +247,7 @@ func (c *Client) QuotaExceeded(project string, resource string, limit int) {
 		fmt.Sprintf("Project: `%s`\nResource: `%s`\nLimit: `%d`", project, resource, limit),
 		PriorityHigh)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send notification", zap.Error(err))
-	}
+	c.notify("quota.exceeded", text)
 }
 
 // SendMetricsSummary sends a formatted metrics summary
@@ -263,17 +269,13 @@ Time: %s`,
 		tokensUsed, tokenLimit, usagePercent,
 		time.Now().Format("2006-01-02 15:04:05"))
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send metrics summary", zap.Error(err))
-	}
+	c.notify("metrics.summary", text)
 }
 
 // SendCustomMessage sends a custom formatted message
 func (c *Client) SendCustomMessage(emoji, title, message string) {
 	text := formatMessage(emoji, title, message, PriorityDefault)
 
-	if err := c.sendText(text); err != nil {
-		c.logger.Error("failed to send custom message", zap.Error(err))
-	}
+	c.notify("custom", text)
 }
 
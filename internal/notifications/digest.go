@@ -0,0 +1,207 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxDigestDetails caps how many individual events a digest lists below
+// its summary line
+const maxDigestDetails = 20
+
+// digestBudgetUrgent is the budget usage from which warnings bypass the
+// digest, matching when Client marks them urgent
+const digestBudgetUrgent = 90
+
+var _ Notifier = (*DigestClient)(nil)
+
+// DigestClient wraps a Notifier and batches routine events into a single
+// summary message per interval, e.g. "3 runners started, 1 runner failed,
+// token budget at 78%". Urgent alerts, daemon lifecycle messages and
+// metrics summaries are passed straight through.
+type DigestClient struct {
+	next     Notifier
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	pending []digestEvent
+	budgets map[string]int // highest usage percent per scope
+}
+
+// digestEvent is one batched notification
+type digestEvent struct {
+	kind   string // "runner started", "runner failed", ...
+	detail string
+}
+
+// NewDigestClient batches notifications to next, flushing them every
+// interval once FlushLoop is running
+func NewDigestClient(next Notifier, interval time.Duration, logger *zap.Logger) *DigestClient {
+	return &DigestClient{
+		next:     next,
+		interval: interval,
+		logger:   logger,
+		budgets:  make(map[string]int),
+	}
+}
+
+// FlushLoop sends a digest every interval until ctx is cancelled, then
+// sends whatever is still pending
+func (d *DigestClient) FlushLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.logger.Info("notification digest loop started", zap.Duration("interval", d.interval))
+
+	for {
+		select {
+		case <-ticker.C:
+			d.flush()
+		case <-ctx.Done():
+			d.flush()
+			return
+		}
+	}
+}
+
+func (d *DigestClient) add(kind, detail string) {
+	d.mu.Lock()
+	d.pending = append(d.pending, digestEvent{kind: kind, detail: detail})
+	d.mu.Unlock()
+}
+
+// flush sends all pending events as one message
+func (d *DigestClient) flush() {
+	d.mu.Lock()
+	batch := d.pending
+	budgets := d.budgets
+	d.pending = nil
+	d.budgets = make(map[string]int)
+	d.mu.Unlock()
+
+	if len(batch) == 0 && len(budgets) == 0 {
+		return
+	}
+
+	d.next.SendCustomMessage("📬", "Stratavore Digest", formatDigest(batch, budgets))
+}
+
+// formatDigest summarises events by kind in order of first appearance,
+// then lists them individually
+func formatDigest(batch []digestEvent, budgets map[string]int) string {
+	var kinds []string
+	counts := make(map[string]int)
+	for _, e := range batch {
+		if counts[e.kind] == 0 {
+			kinds = append(kinds, e.kind)
+		}
+		counts[e.kind]++
+	}
+
+	var summary []string
+	for _, kind := range kinds {
+		summary = append(summary, digestCount(counts[kind], kind))
+	}
+
+	scopes := make([]string, 0, len(budgets))
+	for scope := range budgets {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		if len(scopes) == 1 {
+			summary = append(summary, fmt.Sprintf("token budget at %d%%", budgets[scope]))
+		} else {
+			summary = append(summary, fmt.Sprintf("token budget `%s` at %d%%", scope, budgets[scope]))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(summary, ", "))
+
+	if len(batch) > 0 {
+		b.WriteString("\n")
+	}
+	for i, e := range batch {
+		if i == maxDigestDetails {
+			fmt.Fprintf(&b, "\n…and %d more", len(batch)-maxDigestDetails)
+			break
+		}
+		fmt.Fprintf(&b, "\n• %s: %s", e.kind, e.detail)
+	}
+
+	return b.String()
+}
+
+// digestCount renders "1 runner started" or "3 runners started". kind's
+// first word is the noun to pluralise.
+func digestCount(n int, kind string) string {
+	if n == 1 {
+		return "1 " + kind
+	}
+	noun, rest, _ := strings.Cut(kind, " ")
+	if rest == "" {
+		return fmt.Sprintf("%d %ss", n, noun)
+	}
+	return fmt.Sprintf("%d %ss %s", n, noun, rest)
+}
+
+func (d *DigestClient) RunnerStarted(project, runnerID string) {
+	d.add("runner started", fmt.Sprintf("`%s` %s", project, shortID(runnerID)))
+}
+
+func (d *DigestClient) RunnerStopped(project, runnerID string, exitCode int) {
+	d.add("runner stopped", fmt.Sprintf("`%s` %s (exit %d)", project, shortID(runnerID), exitCode))
+}
+
+func (d *DigestClient) RunnerFailed(project, runnerID string, reason error) {
+	d.add("runner failed", fmt.Sprintf("`%s` %s: %v", project, shortID(runnerID), reason))
+}
+
+func (d *DigestClient) TokenBudgetWarning(scope string, percent int) {
+	if percent >= digestBudgetUrgent {
+		d.next.TokenBudgetWarning(scope, percent)
+		return
+	}
+
+	d.mu.Lock()
+	if percent > d.budgets[scope] {
+		d.budgets[scope] = percent
+	}
+	d.mu.Unlock()
+}
+
+func (d *DigestClient) DaemonStarted(version, hostname string) {
+	d.next.DaemonStarted(version, hostname)
+}
+
+func (d *DigestClient) DaemonStopped(hostname string) {
+	d.next.DaemonStopped(hostname)
+}
+
+func (d *DigestClient) SystemAlert(title, message string, priority NotificationPriority) {
+	if priority == PriorityUrgent {
+		d.next.SystemAlert(title, message, priority)
+		return
+	}
+	d.add("alert", fmt.Sprintf("%s: %s", title, message))
+}
+
+func (d *DigestClient) QuotaExceeded(project string, resource string, limit int) {
+	d.add("quota exceeded", fmt.Sprintf("`%s` %s (limit %d)", project, resource, limit))
+}
+
+func (d *DigestClient) SendMetricsSummary(activeRunners, activeProjects, totalSessions int, tokensUsed, tokenLimit int64) {
+	d.next.SendMetricsSummary(activeRunners, activeProjects, totalSessions, tokensUsed, tokenLimit)
+}
+
+func (d *DigestClient) SendCustomMessage(emoji, title, message string) {
+	d.add("message", fmt.Sprintf("%s %s: %s", emoji, title, message))
+}
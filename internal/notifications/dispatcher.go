@@ -0,0 +1,257 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Event types routed by the Dispatcher
+const (
+	EventRunnerFailed  = "runner.failed"
+	EventBudgetWarning = "budget.warning"
+	EventQuotaExceeded = "quota.exceeded"
+	EventSystemAlert   = "system.alert"
+)
+
+// Backends a notification route can target
+const (
+	BackendSlack    = "slack"    // target is an incoming webhook URL
+	BackendTelegram = "telegram" // target is a chat ID for the configured bot
+)
+
+// RouteStore looks up the notification routes for a project
+type RouteStore interface {
+	GetNotificationRoutes(ctx context.Context, projectName string) ([]*types.NotificationRoute, error)
+}
+
+// ValidateRoute checks a route before it is stored
+func ValidateRoute(route *types.NotificationRoute) error {
+	switch route.Backend {
+	case BackendSlack:
+		if !strings.HasPrefix(route.Target, "https://") {
+			return fmt.Errorf("slack target must be an https webhook URL")
+		}
+	case BackendTelegram:
+		if route.Target == "" {
+			return fmt.Errorf("telegram target must be a chat ID")
+		}
+	default:
+		return fmt.Errorf("unsupported notification backend: %s", route.Backend)
+	}
+	return nil
+}
+
+// Dispatcher routes project events to the channels configured for that
+// project. Projects with no matching route fall back to the global routes,
+// then to the daemon's configured notifier.
+type Dispatcher struct {
+	routes   RouteStore
+	fallback Notifier
+	telegram *Client
+	client   *http.Client
+	recorder HistoryRecorder
+	logger   *zap.Logger
+}
+
+// NewDispatcher creates a dispatcher. fallback may be nil.
+func NewDispatcher(routes RouteStore, fallback Notifier, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		routes:   routes,
+		fallback: fallback,
+		logger:   logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// SetTelegram sets the bot used to deliver telegram routes
+func (d *Dispatcher) SetTelegram(c *Client) {
+	d.telegram = c
+}
+
+// SetRecorder enables delivery history logging for routed notifications
+func (d *Dispatcher) SetRecorder(r HistoryRecorder) {
+	d.recorder = r
+}
+
+// Notify delivers an event for projectName. An empty projectName only
+// considers global routes. Safe to call on a nil Dispatcher.
+func (d *Dispatcher) Notify(projectName, eventType string, payload map[string]interface{}) {
+	if d == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	routes, err := d.routes.GetNotificationRoutes(ctx, projectName)
+	cancel()
+	if err != nil {
+		d.logger.Warn("failed to load notification routes",
+			zap.String("project", projectName),
+			zap.Error(err))
+	}
+
+	matched := selectRoutes(routes, projectName, eventType)
+	if len(matched) == 0 {
+		d.notifyFallback(projectName, eventType, payload)
+		return
+	}
+
+	text := formatEvent(projectName, eventType, payload)
+	for _, route := range matched {
+		err := d.send(route, text)
+		if err != nil {
+			d.logger.Error("failed to send routed notification",
+				zap.String("project", projectName),
+				zap.String("event_type", eventType),
+				zap.String("backend", route.Backend),
+				zap.Error(err))
+		}
+		recordDelivery(d.recorder, d.logger, route.Backend, eventType, route.Target, text, err)
+	}
+}
+
+// selectRoutes picks the project's routes for eventType, falling back to
+// the global routes when the project has none
+func selectRoutes(routes []*types.NotificationRoute, projectName, eventType string) []*types.NotificationRoute {
+	var project, global []*types.NotificationRoute
+	for _, r := range routes {
+		if !r.Matches(eventType) {
+			continue
+		}
+		if r.ProjectName == "" {
+			global = append(global, r)
+		} else if r.ProjectName == projectName {
+			project = append(project, r)
+		}
+	}
+
+	if len(project) > 0 {
+		return project
+	}
+	return global
+}
+
+// send delivers text to a single route
+func (d *Dispatcher) send(route *types.NotificationRoute, text string) error {
+	switch route.Backend {
+	case BackendSlack:
+		return d.postSlack(route.Target, text)
+	case BackendTelegram:
+		if d.telegram == nil {
+			return fmt.Errorf("no telegram bot configured")
+		}
+		return d.telegram.sendTextTo(route.Target, text)
+	}
+	return fmt.Errorf("unsupported notification backend: %s", route.Backend)
+}
+
+// postSlack posts text to a Slack incoming webhook
+func (d *Dispatcher) postSlack(webhookURL, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	resp, err := d.client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("slack webhook error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// notifyFallback hands the event to the configured notifier, keeping each
+// backend's own formatting for the events it knows about
+func (d *Dispatcher) notifyFallback(projectName, eventType string, payload map[string]interface{}) {
+	if d.fallback == nil {
+		return
+	}
+
+	switch eventType {
+	case EventRunnerFailed:
+		runnerID, _ := payload["runner_id"].(string)
+		d.fallback.RunnerFailed(projectName, runnerID, fmt.Errorf("%v", payload["reason"]))
+	case EventBudgetWarning:
+		scope, _ := payload["scope"].(string)
+		percent, _ := payload["percent"].(int)
+		d.fallback.TokenBudgetWarning(scope, percent)
+	case EventQuotaExceeded:
+		resource, _ := payload["resource"].(string)
+		limit, _ := payload["limit"].(int)
+		d.fallback.QuotaExceeded(projectName, resource, limit)
+	case EventSystemAlert:
+		title, _ := payload["title"].(string)
+		message, _ := payload["message"].(string)
+		d.fallback.SystemAlert(title, message, PriorityUrgent)
+	default:
+		d.fallback.SendCustomMessage("🔔", eventTitle(eventType), formatPayload(projectName, payload))
+	}
+}
+
+// formatEvent renders an event for a routed backend
+func formatEvent(projectName, eventType string, payload map[string]interface{}) string {
+	emoji := "🔔"
+	priority := PriorityDefault
+	switch eventType {
+	case EventRunnerFailed:
+		emoji, priority = "❌", PriorityHigh
+	case EventBudgetWarning:
+		emoji = "📊"
+		if percent, _ := payload["percent"].(int); percent >= 90 {
+			priority = PriorityUrgent
+		}
+	case EventQuotaExceeded:
+		emoji, priority = "🚫", PriorityHigh
+	case EventSystemAlert:
+		emoji, priority = "🚨", PriorityUrgent
+	}
+
+	return formatMessage(emoji, eventTitle(eventType), formatPayload(projectName, payload), priority)
+}
+
+// eventTitle turns "runner.failed" into "Runner Failed"
+func eventTitle(eventType string) string {
+	words := strings.FieldsFunc(eventType, func(r rune) bool { return r == '.' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// formatPayload lists the project and payload fields, sorted by key
+func formatPayload(projectName string, payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	if projectName != "" {
+		fmt.Fprintf(&b, "Project: `%s`", projectName)
+	}
+	for _, k := range keys {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s: `%v`", k, payload[k])
+	}
+	return b.String()
+}
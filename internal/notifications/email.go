@@ -0,0 +1,394 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EmailConfig for the SMTP email client
+type EmailConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTo       []string
+	UseTLS       bool // implicit TLS (e.g. port 465); otherwise STARTTLS when offered
+
+	// DigestInterval batches notifications into one email per interval.
+	// Zero sends one email per notification.
+	DigestInterval time.Duration
+}
+
+// EmailClient sends notifications as HTML email over SMTP
+type EmailClient struct {
+	cfg    EmailConfig
+	logger *zap.Logger
+
+	recorder HistoryRecorder
+
+	mu      sync.Mutex
+	pending []emailMessage
+}
+
+// emailMessage is one notification rendered into an email
+type emailMessage struct {
+	Event    string
+	Emoji    string
+	Title    string
+	Priority NotificationPriority
+	Rows     []emailRow
+	Lines    []string
+	Time     time.Time
+}
+
+type emailRow struct {
+	Label string
+	Value string
+}
+
+var emailTemplate = template.Must(template.New("email").Funcs(template.FuncMap{
+	"priorityPrefix": priorityPrefix,
+}).Parse(`<html>
+<body style="font-family: sans-serif;">
+{{range .}}
+<h3>{{priorityPrefix .Priority}}{{.Emoji}} {{.Title}}</h3>
+{{if .Rows}}<table border="1" cellpadding="6" cellspacing="0" style="border-collapse: collapse;">
+{{range .Rows}}<tr><th align="left">{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Lines}}<p>{{range $i, $l := .Lines}}{{if $i}}<br>{{end}}{{$l}}{{end}}</p>{{end}}
+<p style="color: #888; font-size: small;">{{.Time.Format "2006-01-02 15:04:05"}}</p>
+{{end}}
+</body>
+</html>
+`))
+
+// NewEmailClient creates a new SMTP notification client
+func NewEmailClient(cfg EmailConfig, logger *zap.Logger) *EmailClient {
+	if cfg.SMTPPort == 0 {
+		cfg.SMTPPort = 587
+	}
+	return &EmailClient{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// SetRecorder enables delivery history logging
+func (c *EmailClient) SetRecorder(r HistoryRecorder) {
+	c.recorder = r
+}
+
+// RunDigest flushes batched notifications every DigestInterval until ctx is
+// cancelled, then sends whatever is still pending. No-op without a digest
+// interval.
+func (c *EmailClient) RunDigest(ctx context.Context) {
+	if c.cfg.DigestInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.cfg.DigestInterval)
+	defer ticker.Stop()
+
+	c.logger.Info("email digest loop started", zap.Duration("interval", c.cfg.DigestInterval))
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-ctx.Done():
+			c.flush()
+			return
+		}
+	}
+}
+
+// notify sends msg immediately or queues it for the next digest
+func (c *EmailClient) notify(msg emailMessage) {
+	msg.Time = time.Now()
+
+	if c.cfg.DigestInterval > 0 {
+		c.mu.Lock()
+		c.pending = append(c.pending, msg)
+		c.mu.Unlock()
+		return
+	}
+
+	subject := fmt.Sprintf("[Stratavore] %s%s", priorityPrefix(msg.Priority), msg.Title)
+	err := c.send(subject, []emailMessage{msg})
+	if err != nil {
+		c.logger.Error("failed to send email notification", zap.Error(err))
+	}
+	c.record(msg, err)
+}
+
+// record logs a delivery attempt for one notification
+func (c *EmailClient) record(msg emailMessage, err error) {
+	lines := []string{msg.Title}
+	for _, row := range msg.Rows {
+		lines = append(lines, row.Label+": "+row.Value)
+	}
+	lines = append(lines, msg.Lines...)
+
+	recordDelivery(c.recorder, c.logger, "email", msg.Event,
+		strings.Join(c.cfg.SMTPTo, ", "), strings.Join(lines, "\n"), err)
+}
+
+// flush sends all pending notifications as a single digest email
+func (c *EmailClient) flush() {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	subject := fmt.Sprintf("[Stratavore] Digest: %d notifications", len(batch))
+	err := c.send(subject, batch)
+	if err != nil {
+		c.logger.Error("failed to send email digest",
+			zap.Int("notifications", len(batch)),
+			zap.Error(err))
+	}
+	for _, msg := range batch {
+		c.record(msg, err)
+	}
+}
+
+// send renders messages into an HTML email and delivers it over SMTP
+func (c *EmailClient) send(subject string, messages []emailMessage) error {
+	var body bytes.Buffer
+	if err := emailTemplate.Execute(&body, messages); err != nil {
+		return fmt.Errorf("render email: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", c.cfg.SMTPFrom)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(c.cfg.SMTPTo, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return c.deliver(msg.Bytes())
+}
+
+// deliver opens an SMTP session and sends a raw message to all recipients
+func (c *EmailClient) deliver(raw []byte) error {
+	addr := net.JoinHostPort(c.cfg.SMTPHost, fmt.Sprintf("%d", c.cfg.SMTPPort))
+	tlsConfig := &tls.Config{ServerName: c.cfg.SMTPHost}
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if c.cfg.UseTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", addr, err)
+	}
+
+	client, err := smtp.NewClient(conn, c.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if !c.cfg.UseTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+
+	if c.cfg.SMTPUser != "" {
+		auth := smtp.PlainAuth("", c.cfg.SMTPUser, c.cfg.SMTPPassword, c.cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(c.cfg.SMTPFrom); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, to := range c.cfg.SMTPTo {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp rcpt %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// priorityPrefix mirrors the Telegram priority indicators
+func priorityPrefix(priority NotificationPriority) string {
+	switch priority {
+	case PriorityUrgent:
+		return "🚨 "
+	case PriorityHigh:
+		return "⚠️ "
+	}
+	return ""
+}
+
+// shortID truncates a runner ID for display
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// RunnerStarted sends notification when runner starts
+func (c *EmailClient) RunnerStarted(project, runnerID string) {
+	c.notify(emailMessage{
+		Event: "runner.started",
+		Emoji: "🚀", Title: "Runner Started", Priority: PriorityDefault,
+		Rows: []emailRow{{"Project", project}, {"Runner", shortID(runnerID)}},
+	})
+}
+
+// RunnerStopped sends notification when runner stops
+func (c *EmailClient) RunnerStopped(project, runnerID string, exitCode int) {
+	emoji := "✅"
+	if exitCode != 0 {
+		emoji = "⚠️"
+	}
+
+	c.notify(emailMessage{
+		Event: "runner.stopped",
+		Emoji: emoji, Title: "Runner Stopped", Priority: PriorityLow,
+		Rows: []emailRow{
+			{"Project", project},
+			{"Runner", shortID(runnerID)},
+			{"Exit code", fmt.Sprintf("%d", exitCode)},
+		},
+	})
+}
+
+// RunnerFailed sends notification when runner fails
+func (c *EmailClient) RunnerFailed(project, runnerID string, reason error) {
+	c.notify(emailMessage{
+		Event: "runner.failed",
+		Emoji: "❌", Title: "Runner Failed", Priority: PriorityHigh,
+		Rows: []emailRow{
+			{"Project", project},
+			{"Runner", shortID(runnerID)},
+			{"Reason", fmt.Sprintf("%v", reason)},
+		},
+	})
+}
+
+// TokenBudgetWarning sends notification when token budget reaches threshold
+func (c *EmailClient) TokenBudgetWarning(scope string, percent int) {
+	priority := PriorityDefault
+	if percent >= 90 {
+		priority = PriorityUrgent
+	} else if percent >= 75 {
+		priority = PriorityHigh
+	}
+
+	c.notify(emailMessage{
+		Event: "budget.warning",
+		Emoji: "📊", Title: "Token Budget Warning", Priority: priority,
+		Rows: []emailRow{{"Scope", scope}, {"Usage", fmt.Sprintf("%d%%", percent)}},
+	})
+}
+
+// DaemonStarted sends notification when daemon starts
+func (c *EmailClient) DaemonStarted(version, hostname string) {
+	c.notify(emailMessage{
+		Event: "daemon.started",
+		Emoji: "✨", Title: "Stratavore Daemon Started", Priority: PriorityDefault,
+		Rows: []emailRow{{"Version", version}, {"Host", hostname}},
+	})
+}
+
+// DaemonStopped sends notification when daemon stops
+func (c *EmailClient) DaemonStopped(hostname string) {
+	c.notify(emailMessage{
+		Event: "daemon.stopped",
+		Emoji: "🛑", Title: "Stratavore Daemon Stopped", Priority: PriorityDefault,
+		Rows: []emailRow{{"Host", hostname}},
+	})
+}
+
+// SystemAlert sends a system-level alert
+func (c *EmailClient) SystemAlert(title, message string, priority NotificationPriority) {
+	c.notify(emailMessage{
+		Event: "system.alert",
+		Emoji: "⚡", Title: title, Priority: priority,
+		Lines: strings.Split(message, "\n"),
+	})
+}
+
+// QuotaExceeded sends notification when resource quota is exceeded
+func (c *EmailClient) QuotaExceeded(project string, resource string, limit int) {
+	c.notify(emailMessage{
+		Event: "quota.exceeded",
+		Emoji: "🚫", Title: "Resource Quota Exceeded", Priority: PriorityHigh,
+		Rows: []emailRow{
+			{"Project", project},
+			{"Resource", resource},
+			{"Limit", fmt.Sprintf("%d", limit)},
+		},
+	})
+}
+
+// SendMetricsSummary sends a formatted metrics summary
+func (c *EmailClient) SendMetricsSummary(activeRunners, activeProjects, totalSessions int, tokensUsed, tokenLimit int64) {
+	usagePercent := 0
+	if tokenLimit > 0 {
+		usagePercent = int((float64(tokensUsed) / float64(tokenLimit)) * 100)
+	}
+
+	c.notify(emailMessage{
+		Event: "metrics.summary",
+		Emoji: "📊", Title: "Stratavore Status Report", Priority: PriorityDefault,
+		Rows: []emailRow{
+			{"Active Runners", fmt.Sprintf("%d", activeRunners)},
+			{"Active Projects", fmt.Sprintf("%d", activeProjects)},
+			{"Total Sessions", fmt.Sprintf("%d", totalSessions)},
+			{"Tokens Used", fmt.Sprintf("%d / %d (%d%%)", tokensUsed, tokenLimit, usagePercent)},
+		},
+	})
+}
+
+// SendCustomMessage sends a custom formatted message
+func (c *EmailClient) SendCustomMessage(emoji, title, message string) {
+	c.notify(emailMessage{
+		Event: "custom",
+		Emoji: emoji, Title: title, Priority: PriorityDefault,
+		Lines: strings.Split(message, "\n"),
+	})
+}
@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
+)
+
+// Notifier is implemented by every notification backend
+type Notifier interface {
+	RunnerStarted(project, runnerID string)
+	RunnerStopped(project, runnerID string, exitCode int)
+	RunnerFailed(project, runnerID string, reason error)
+	TokenBudgetWarning(scope string, percent int)
+	DaemonStarted(version, hostname string)
+	DaemonStopped(hostname string)
+	SystemAlert(title, message string, priority NotificationPriority)
+	QuotaExceeded(project string, resource string, limit int)
+	SendMetricsSummary(activeRunners, activeProjects, totalSessions int, tokensUsed, tokenLimit int64)
+	SendCustomMessage(emoji, title, message string)
+}
+
+var (
+	_ Notifier = (*Client)(nil)
+	_ Notifier = (*EmailClient)(nil)
+	_ Notifier = multiNotifier(nil)
+)
+
+// HistoryRecorder persists a record of each notification delivery attempt
+type HistoryRecorder interface {
+	RecordNotification(ctx context.Context, entry *types.NotificationLogEntry) error
+}
+
+// recordDelivery writes one delivery attempt to the history. Recording
+// failures are logged and never affect delivery.
+func recordDelivery(r HistoryRecorder, logger *zap.Logger, backend, eventType, target, text string, sendErr error) {
+	if r == nil {
+		return
+	}
+
+	entry := &types.NotificationLogEntry{
+		SentAt:      time.Now(),
+		Backend:     backend,
+		EventType:   eventType,
+		Target:      target,
+		MessageText: text,
+		Success:     sendErr == nil,
+	}
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := r.RecordNotification(ctx, entry); err != nil {
+		logger.Warn("failed to record notification history", zap.Error(err))
+	}
+}
+
+// NewMulti returns a Notifier that fans out to all given backends.
+// Returns nil when no backends are given so callers can nil-check.
+func NewMulti(notifiers ...Notifier) Notifier {
+	switch len(notifiers) {
+	case 0:
+		return nil
+	case 1:
+		return notifiers[0]
+	}
+	return multiNotifier(notifiers)
+}
+
+// multiNotifier delivers each notification to every backend in turn
+type multiNotifier []Notifier
+
+func (m multiNotifier) RunnerStarted(project, runnerID string) {
+	for _, n := range m {
+		n.RunnerStarted(project, runnerID)
+	}
+}
+
+func (m multiNotifier) RunnerStopped(project, runnerID string, exitCode int) {
+	for _, n := range m {
+		n.RunnerStopped(project, runnerID, exitCode)
+	}
+}
+
+func (m multiNotifier) RunnerFailed(project, runnerID string, reason error) {
+	for _, n := range m {
+		n.RunnerFailed(project, runnerID, reason)
+	}
+}
+
+func (m multiNotifier) TokenBudgetWarning(scope string, percent int) {
+	for _, n := range m {
+		n.TokenBudgetWarning(scope, percent)
+	}
+}
+
+func (m multiNotifier) DaemonStarted(version, hostname string) {
+	for _, n := range m {
+		n.DaemonStarted(version, hostname)
+	}
+}
+
+func (m multiNotifier) DaemonStopped(hostname string) {
+	for _, n := range m {
+		n.DaemonStopped(hostname)
+	}
+}
+
+func (m multiNotifier) SystemAlert(title, message string, priority NotificationPriority) {
+	for _, n := range m {
+		n.SystemAlert(title, message, priority)
+	}
+}
+
+func (m multiNotifier) QuotaExceeded(project string, resource string, limit int) {
+	for _, n := range m {
+		n.QuotaExceeded(project, resource, limit)
+	}
+}
+
+func (m multiNotifier) SendMetricsSummary(activeRunners, activeProjects, totalSessions int, tokensUsed, tokenLimit int64) {
+	for _, n := range m {
+		n.SendMetricsSummary(activeRunners, activeProjects, totalSessions, tokensUsed, tokenLimit)
+	}
+}
+
+func (m multiNotifier) SendCustomMessage(emoji, title, message string) {
+	for _, n := range m {
+		n.SendCustomMessage(emoji, title, message)
+	}
+}
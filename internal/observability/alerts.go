@@ -0,0 +1,100 @@
+package observability
+
+import (
+	"bytes"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// PrometheusAlertRules is a Prometheus rule file
+type PrometheusAlertRules struct {
+	Groups []AlertRuleGroup `yaml:"groups"`
+}
+
+// AlertRuleGroup is a named group of alerting rules evaluated together
+type AlertRuleGroup struct {
+	Name  string      `yaml:"name"`
+	Rules []AlertRule `yaml:"rules"`
+}
+
+// AlertRule is a single Prometheus alerting rule
+type AlertRule struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// StandardAlertRules returns the alert rules for Stratavore's standard SLOs,
+// written against the metrics served by MetricsServer
+func StandardAlertRules() *PrometheusAlertRules {
+	return &PrometheusAlertRules{
+		Groups: []AlertRuleGroup{{
+			Name: "stratavore",
+			Rules: []AlertRule{
+				{
+					Alert: "StratavoreRunnerHeartbeatMissed",
+					Expr:  "stratavore_runner_heartbeat_age_seconds > 2 * stratavore_runner_heartbeat_ttl_seconds",
+					For:   "1m",
+					Labels: map[string]string{
+						"severity": "warning",
+					},
+					Annotations: map[string]string{
+						"summary":     "Runner {{ $labels.runner_id }} missed its heartbeats",
+						"description": "Runner {{ $labels.runner_id }} in project {{ $labels.project }} is running but has not sent a heartbeat for over twice its TTL.",
+					},
+				},
+				{
+					Alert: "StratavoreTokenBudgetCritical",
+					Expr:  "stratavore_budget_used_tokens / stratavore_budget_limit_tokens > 0.9",
+					Labels: map[string]string{
+						"severity": "critical",
+					},
+					Annotations: map[string]string{
+						"summary":     "Token budget for {{ $labels.scope }} {{ $labels.scope_id }} is over 90% used",
+						"description": "{{ $value | humanizePercentage }} of the {{ $labels.scope }} token budget has been used this period.",
+					},
+				},
+				{
+					Alert: "StratavoreDaemonDown",
+					Expr:  "absent_over_time(stratavore_daemon_uptime_seconds[5m])",
+					Labels: map[string]string{
+						"severity": "critical",
+					},
+					Annotations: map[string]string{
+						"summary":     "Stratavore daemon is down",
+						"description": "stratavore_daemon_uptime_seconds has not been scraped for 5 minutes.",
+					},
+				},
+				{
+					Alert: "StratavoreHighLaunchFailureRate",
+					Expr: `sum(rate(stratavore_runner_launches_total{result="failure"}[5m]))` +
+						` / sum(rate(stratavore_runner_launches_total[5m])) > 0.05`,
+					For: "5m",
+					Labels: map[string]string{
+						"severity": "warning",
+					},
+					Annotations: map[string]string{
+						"summary":     "Runner launches are failing",
+						"description": "{{ $value | humanizePercentage }} of runner launches failed over the last 5 minutes.",
+					},
+				},
+			},
+		}},
+	}
+}
+
+// AlertRulesYAML renders StandardAlertRules as a Prometheus rule file
+func AlertRulesYAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(StandardAlertRules()); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -2,9 +2,12 @@ package observability
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/meridian-lex/stratavore/internal/storage"
 	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 )
@@ -19,10 +22,26 @@ type MetricsServer struct {
 	mu                 sync.RWMutex
 	runnersByStatus    map[types.RunnerStatus]int
 	runnersByProject   map[string]int
+	runnersCPU         float64
+	runnersMemoryMB    int64
+	runnersTokens      int64
 	totalSessions      int
 	tokensUsed         int64
 	heartbeatLatencies []float64
 	daemonUptime       float64
+	heartbeats         []runnerHeartbeat
+	budgets            []*types.TokenBudget
+	launchSuccesses    int64
+	launchFailures     int64
+}
+
+// runnerHeartbeat is a running runner's heartbeat age and TTL at the last
+// metrics update
+type runnerHeartbeat struct {
+	runnerID string
+	project  string
+	age      float64
+	ttl      int
 }
 
 // NewMetricsServer creates a new metrics server
@@ -75,22 +94,35 @@ func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	// In production, use prometheus/client_golang
 
 	// Runner metrics by status
+	writeMetricHeader(w, "stratavore_runners_total", "gauge", "Active runners by status")
 	for status, count := range m.runnersByStatus {
 		fmt.Fprintf(w, "stratavore_runners_total{status=\"%s\"} %d\n", status, count)
 	}
 
+	// Combined resource usage of active runners
+	writeMetricHeader(w, "stratavore_runners_cpu_percent", "gauge", "Combined CPU usage of active runners")
+	fmt.Fprintf(w, "stratavore_runners_cpu_percent %f\n", m.runnersCPU)
+	writeMetricHeader(w, "stratavore_runners_memory_mb", "gauge", "Combined memory usage of active runners in MB")
+	fmt.Fprintf(w, "stratavore_runners_memory_mb %d\n", m.runnersMemoryMB)
+	writeMetricHeader(w, "stratavore_runners_tokens_used", "gauge", "Tokens used by active runners")
+	fmt.Fprintf(w, "stratavore_runners_tokens_used %d\n", m.runnersTokens)
+
 	// Runner metrics by project
+	writeMetricHeader(w, "stratavore_runners_by_project", "gauge", "Runners managed by this daemon by project")
 	for project, count := range m.runnersByProject {
 		fmt.Fprintf(w, "stratavore_runners_by_project{project=\"%s\"} %d\n", project, count)
 	}
 
 	// Session metrics
+	writeMetricHeader(w, "stratavore_sessions_total", "gauge", "Sessions recorded")
 	fmt.Fprintf(w, "stratavore_sessions_total %d\n", m.totalSessions)
 
 	// Token metrics
+	writeMetricHeader(w, "stratavore_tokens_used_total", "counter", "Tokens used")
 	fmt.Fprintf(w, "stratavore_tokens_used_total{scope=\"global\"} %d\n", m.tokensUsed)
 
 	// Daemon uptime
+	writeMetricHeader(w, "stratavore_daemon_uptime_seconds", "gauge", "Seconds since the daemon started")
 	fmt.Fprintf(w, "stratavore_daemon_uptime_seconds %f\n", m.daemonUptime)
 
 	// Heartbeat latency histogram (simplified)
@@ -100,10 +132,43 @@ func (m *MetricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 			sum += lat
 		}
 		avg := sum / float64(len(m.heartbeatLatencies))
+		writeMetricHeader(w, "stratavore_heartbeat_latency_seconds", "summary", "Heartbeat processing latency")
 		fmt.Fprintf(w, "stratavore_heartbeat_latency_seconds_sum %f\n", sum)
 		fmt.Fprintf(w, "stratavore_heartbeat_latency_seconds_count %d\n", len(m.heartbeatLatencies))
+		writeMetricHeader(w, "stratavore_heartbeat_latency_seconds_avg", "gauge", "Mean heartbeat processing latency")
 		fmt.Fprintf(w, "stratavore_heartbeat_latency_seconds_avg %f\n", avg)
 	}
+
+	// Per-runner heartbeat freshness, for missed-heartbeat alerts
+	writeMetricHeader(w, "stratavore_runner_heartbeat_age_seconds", "gauge", "Seconds since each running runner's last heartbeat")
+	for _, hb := range m.heartbeats {
+		fmt.Fprintf(w, "stratavore_runner_heartbeat_age_seconds{runner_id=\"%s\",project=\"%s\"} %f\n", hb.runnerID, hb.project, hb.age)
+	}
+	writeMetricHeader(w, "stratavore_runner_heartbeat_ttl_seconds", "gauge", "Heartbeat TTL of each running runner")
+	for _, hb := range m.heartbeats {
+		fmt.Fprintf(w, "stratavore_runner_heartbeat_ttl_seconds{runner_id=\"%s\",project=\"%s\"} %d\n", hb.runnerID, hb.project, hb.ttl)
+	}
+
+	// Current budget periods
+	writeMetricHeader(w, "stratavore_budget_used_tokens", "gauge", "Tokens used in each current budget period")
+	for _, b := range m.budgets {
+		fmt.Fprintf(w, "stratavore_budget_used_tokens{scope=\"%s\",scope_id=\"%s\"} %d\n", b.Scope, b.ScopeID, b.UsedTokens)
+	}
+	writeMetricHeader(w, "stratavore_budget_limit_tokens", "gauge", "Token limit of each current budget period")
+	for _, b := range m.budgets {
+		fmt.Fprintf(w, "stratavore_budget_limit_tokens{scope=\"%s\",scope_id=\"%s\"} %d\n", b.Scope, b.ScopeID, b.LimitTokens)
+	}
+
+	// Launch outcomes
+	writeMetricHeader(w, "stratavore_runner_launches_total", "counter", "Runner launch attempts by result")
+	fmt.Fprintf(w, "stratavore_runner_launches_total{result=\"success\"} %d\n", m.launchSuccesses)
+	fmt.Fprintf(w, "stratavore_runner_launches_total{result=\"failure\"} %d\n", m.launchFailures)
+}
+
+// writeMetricHeader writes the HELP and TYPE lines that precede a metric
+// family's samples
+func writeMetricHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
 }
 
 // handleHealth serves health check endpoint
@@ -112,19 +177,76 @@ func (m *MetricsServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("OK"))
 }
 
-// UpdateRunnerMetrics updates runner counts
-func (m *MetricsServer) UpdateRunnerMetrics(runners []*types.Runner) {
+// UpdateRunnerMetrics updates runner counts and resource totals from a
+// summary of every active runner in the database
+func (m *MetricsServer) UpdateRunnerMetrics(summary *storage.RunnerMetricsSummary) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Reset counters
 	m.runnersByStatus = make(map[types.RunnerStatus]int)
+	for status, sc := range summary.ByStatus {
+		m.runnersByStatus[status] = sc.Count
+	}
+
+	m.runnersCPU = summary.TotalCPU
+	m.runnersMemoryMB = summary.TotalMemoryMB
+	m.runnersTokens = summary.TotalTokensUsed
+}
+
+// UpdateLocalRunners updates per-project counts and heartbeat freshness for
+// the runners this daemon manages
+func (m *MetricsServer) UpdateLocalRunners(runners []*types.Runner) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Reset counters
 	m.runnersByProject = make(map[string]int)
 
-	// Count runners
+	m.heartbeats = m.heartbeats[:0]
+	now := time.Now()
+
 	for _, r := range runners {
-		m.runnersByStatus[r.Status]++
 		m.runnersByProject[r.ProjectName]++
+
+		if r.Status != types.StatusRunning || r.LastHeartbeat == nil || r.HeartbeatTTL <= 0 {
+			continue
+		}
+		m.heartbeats = append(m.heartbeats, runnerHeartbeat{
+			runnerID: r.ID,
+			project:  r.ProjectName,
+			age:      now.Sub(*r.LastHeartbeat).Seconds(),
+			ttl:      r.HeartbeatTTL,
+		})
+	}
+}
+
+// UpdateBudgetMetrics replaces the exported token budgets. Budgets without a
+// limit are skipped since usage can't be expressed as a fraction of them.
+func (m *MetricsServer) UpdateBudgetMetrics(budgets []*types.TokenBudget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.budgets = m.budgets[:0]
+	for _, b := range budgets {
+		if b.LimitTokens > 0 {
+			m.budgets = append(m.budgets, b)
+		}
+	}
+}
+
+// RecordLaunch counts a runner launch attempt. Safe to call on a nil
+// MetricsServer, which is what callers hold when metrics are disabled.
+func (m *MetricsServer) RecordLaunch(success bool) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if success {
+		m.launchSuccesses++
+	} else {
+		m.launchFailures++
 	}
 }
 
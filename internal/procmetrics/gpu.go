@@ -0,0 +1,15 @@
+package procmetrics
+
+import "errors"
+
+// ErrNoNvidiaSMI is returned by SampleGPU when nvidia-smi is not installed.
+// Callers should treat it as "no GPU" rather than a failure.
+var ErrNoNvidiaSMI = errors.New("procmetrics: nvidia-smi not found")
+
+// GPUSample holds one GPU's usage attributed to a process.
+type GPUSample struct {
+	GPUIndex           int
+	UtilizationPercent float64 // whole-GPU utilisation; not per process
+	MemoryUsedMB       int64   // memory used by the process on this GPU
+	MemoryTotalMB      int64
+}
@@ -0,0 +1,120 @@
+//go:build gpu
+
+package procmetrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// gpuInfo is the per-device data needed to fill in a GPUSample
+type gpuInfo struct {
+	index         int
+	utilization   float64
+	memoryTotalMB int64
+}
+
+// SampleGPU returns the GPU memory used by pid on each NVIDIA GPU it has a
+// compute context on, along with that GPU's overall utilisation.
+//
+// On macOS there is no per-process GPU accounting; system_profiler is run to
+// confirm a display GPU exists and zero samples are returned.
+func SampleGPU(pid int) ([]GPUSample, error) {
+	if runtime.GOOS == "darwin" {
+		if _, err := exec.Command("system_profiler", "SPDisplaysDataType").Output(); err != nil {
+			return nil, fmt.Errorf("procmetrics: system_profiler failed: %w", err)
+		}
+		return nil, nil
+	}
+
+	if _, err := exec.LookPath("nvidia-smi"); err != nil {
+		return nil, ErrNoNvidiaSMI
+	}
+
+	apps, err := runNvidiaSMI("--query-compute-apps=pid,gpu_uuid,used_memory")
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []GPUSample
+	var gpus map[string]gpuInfo
+	for _, fields := range apps {
+		if len(fields) < 3 || fields[0] != strconv.Itoa(pid) {
+			continue
+		}
+
+		// Only query devices once the process is known to use one
+		if gpus == nil {
+			if gpus, err = queryGPUs(); err != nil {
+				return nil, err
+			}
+		}
+
+		used, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("procmetrics: parse used_memory %q: %w", fields[2], err)
+		}
+
+		gpu := gpus[fields[1]]
+		samples = append(samples, GPUSample{
+			GPUIndex:           gpu.index,
+			UtilizationPercent: gpu.utilization,
+			MemoryUsedMB:       used,
+			MemoryTotalMB:      gpu.memoryTotalMB,
+		})
+	}
+
+	return samples, nil
+}
+
+// queryGPUs maps each GPU UUID to its index, utilisation and total memory
+func queryGPUs() (map[string]gpuInfo, error) {
+	rows, err := runNvidiaSMI("--query-gpu=uuid,index,utilization.gpu,memory.total")
+	if err != nil {
+		return nil, err
+	}
+
+	gpus := make(map[string]gpuInfo, len(rows))
+	for _, fields := range rows {
+		if len(fields) < 4 {
+			continue
+		}
+		// Fields report "[N/A]" on GPUs that don't support them; leave zero
+		index, _ := strconv.Atoi(fields[1])
+		util, _ := strconv.ParseFloat(fields[2], 64)
+		total, _ := strconv.ParseInt(fields[3], 10, 64)
+		gpus[fields[0]] = gpuInfo{index: index, utilization: util, memoryTotalMB: total}
+	}
+
+	return gpus, nil
+}
+
+// runNvidiaSMI runs an nvidia-smi query and splits its CSV output into
+// trimmed fields per line. Units are stripped so MiB and % parse as numbers.
+func runNvidiaSMI(query string) ([][]string, error) {
+	out, err := exec.Command("nvidia-smi", query, "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil, fmt.Errorf("procmetrics: nvidia-smi failed: %w", err)
+	}
+
+	var rows [][]string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		rows = append(rows, fields)
+	}
+
+	return rows, scanner.Err()
+}
@@ -0,0 +1,8 @@
+//go:build !gpu
+
+package procmetrics
+
+// SampleGPU returns no samples; build with -tags gpu to enable GPU sampling.
+func SampleGPU(pid int) ([]GPUSample, error) {
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+package procmetrics
+
+import (
+	"sync"
+	"time"
+)
+
+// PollerHistory is the number of samples a Poller keeps. At a 2s interval
+// that is the last 12 minutes.
+const PollerHistory = 360
+
+// Poller samples a process in the background on a fixed interval and keeps
+// a sliding window of recent samples, so callers can report smoothed or
+// peak usage instead of a single instantaneous reading.
+type Poller struct {
+	sampler  *Sampler
+	interval time.Duration
+
+	mu      sync.RWMutex
+	samples []Sample // oldest first
+	lastErr error
+
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPoller starts sampling pid every interval until Stop is called. The
+// first CPU reading needs a previous one to diff against, so the initial
+// sample is only used to prime the Sampler and the first recorded sample
+// arrives after one interval.
+func NewPoller(pid int, interval time.Duration) *Poller {
+	p := &Poller{
+		sampler:  NewSampler(pid),
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	p.sampler.Sample()
+	go p.run()
+
+	return p
+}
+
+func (p *Poller) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s, err := p.sampler.Sample()
+
+			p.mu.Lock()
+			p.lastErr = err
+			if err == nil {
+				if len(p.samples) == PollerHistory {
+					copy(p.samples, p.samples[1:])
+					p.samples = p.samples[:len(p.samples)-1]
+				}
+				p.samples = append(p.samples, s)
+			}
+			p.mu.Unlock()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// Stop ends background sampling and waits for the sampling goroutine to
+// exit. It is safe to call more than once.
+func (p *Poller) Stop() {
+	p.stopOnce.Do(func() { close(p.stopCh) })
+	<-p.done
+}
+
+// Err returns the error from the most recent sampling attempt, or nil if it
+// succeeded. Failed attempts are not added to the window.
+func (p *Poller) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.lastErr
+}
+
+// Latest returns the most recent sample, or a zero Sample (with a zero
+// Timestamp) if none has been taken yet.
+func (p *Poller) Latest() Sample {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.samples) == 0 {
+		return Sample{}
+	}
+	return p.samples[len(p.samples)-1]
+}
+
+// Average returns the latest sample with CPUPercent and TreeCPUPercent
+// replaced by their means over the last n samples, smoothing out short
+// spikes. n <= 0 or more than the samples held averages all of them. Memory
+// is not averaged; it is the latest reading.
+func (p *Poller) Average(n int) Sample {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.samples) == 0 {
+		return Sample{}
+	}
+	if n <= 0 || n > len(p.samples) {
+		n = len(p.samples)
+	}
+
+	recent := p.samples[len(p.samples)-n:]
+	total, treeTotal := 0.0, 0.0
+	for _, s := range recent {
+		total += s.CPUPercent
+		treeTotal += s.TreeCPUPercent
+	}
+
+	avg := recent[len(recent)-1]
+	avg.CPUPercent = total / float64(n)
+	avg.TreeCPUPercent = treeTotal / float64(n)
+	return avg
+}
+
+// Peak returns the latest sample with its CPU and memory figures, for the
+// process and for its tree, replaced by the highest values seen within
+// window of now. They may come from different samples.
+func (p *Poller) Peak(window time.Duration) Sample {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.samples) == 0 {
+		return Sample{}
+	}
+
+	peak := p.samples[len(p.samples)-1]
+	since := time.Now().Add(-window)
+	for i := len(p.samples) - 1; i >= 0 && !p.samples[i].Timestamp.Before(since); i-- {
+		peak.CPUPercent = max(peak.CPUPercent, p.samples[i].CPUPercent)
+		peak.MemoryMB = max(peak.MemoryMB, p.samples[i].MemoryMB)
+		peak.TreeCPUPercent = max(peak.TreeCPUPercent, p.samples[i].TreeCPUPercent)
+		peak.TreeMemoryMB = max(peak.TreeMemoryMB, p.samples[i].TreeMemoryMB)
+	}
+	return peak
+}
@@ -1,6 +1,7 @@
 // Package procmetrics provides lightweight CPU and memory sampling for a
-// running OS process. It reads directly from /proc on Linux and falls back to
-// a `ps` subprocess on other UNIX-like platforms (macOS, BSDs).
+// running OS process. It reads directly from /proc on Linux, queries the
+// process through the Win32 API on Windows, and falls back to a `ps`
+// subprocess on other UNIX-like platforms (macOS, BSDs).
 //
 // No third-party dependencies are required.
 package procmetrics
@@ -21,6 +22,12 @@ type Sample struct {
 	CPUPercent float64 // 0–100 (per-core; may exceed 100 on multi-core)
 	MemoryMB  int64   // resident set size in megabytes
 	Timestamp time.Time
+
+	// Usage of the process and all of its descendants, as reported by
+	// Sampler.Sample. Children spawned for shell commands and tools can use
+	// far more than the process itself.
+	TreeCPUPercent float64
+	TreeMemoryMB   int64
 }
 
 // Sampler takes repeated measurements for a single PID and computes CPU usage
@@ -29,6 +36,8 @@ type Sampler struct {
 	pid      int
 	prevTick uint64
 	prevTime time.Time
+
+	children map[int]*Sampler // by PID, for the tree totals
 }
 
 // NewSampler creates a Sampler for the given PID.
@@ -36,11 +45,22 @@ func NewSampler(pid int) *Sampler {
 	return &Sampler{pid: pid}
 }
 
-// Sample collects one metrics snapshot.
+// Sample collects one metrics snapshot, including the tree totals for the
+// process's descendants.
 //
 // On the first call, CPUPercent will always be 0 because there is no prior
 // measurement to diff against.
 func (s *Sampler) Sample() (Sample, error) {
+	sample, err := s.sampleProcess()
+	if err != nil {
+		return Sample{}, err
+	}
+	s.sampleTree(&sample)
+	return sample, nil
+}
+
+// sampleProcess collects one snapshot of the process alone
+func (s *Sampler) sampleProcess() (Sample, error) {
 	now := time.Now()
 
 	cpuPct := 0.0
@@ -63,6 +83,22 @@ func (s *Sampler) Sample() (Sample, error) {
 			}
 		}
 		s.prevTick = tick
+	} else if runtime.GOOS == "windows" {
+		cpuTime, rss, err := readProcessTimes(s.pid)
+		if err != nil {
+			return Sample{}, err
+		}
+		memMB = rss
+
+		if !s.prevTime.IsZero() {
+			elapsed := now.Sub(s.prevTime).Seconds()
+			// Windows reports CPU time in 100ns intervals
+			cpuSeconds := float64(cpuTime-s.prevTick) / 1e7
+			if elapsed > 0 {
+				cpuPct = cpuSeconds / elapsed * 100.0
+			}
+		}
+		s.prevTick = cpuTime
 	} else {
 		// macOS / other UNIX: fall back to `ps`
 		var err error
@@ -3,8 +3,14 @@
 package procmetrics
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
 )
 
 // runPS is not used on Linux (we read /proc directly), but must exist to
@@ -13,3 +19,59 @@ import (
 func runPS(_ int) (io.Reader, error) {
 	return nil, fmt.Errorf("procmetrics: runPS not supported on Linux (uses /proc instead)")
 }
+
+// readProcessTimes is only implemented on Windows; Linux reads /proc.
+func readProcessTimes(_ int) (uint64, int64, error) {
+	return 0, 0, fmt.Errorf("procmetrics: readProcessTimes not supported on Linux (uses /proc instead)")
+}
+
+// CPUAffinity returns the CPU cores pid is allowed to run on.
+func CPUAffinity(pid int) ([]int, error) {
+	var mask unix.CPUSet
+	if err := unix.SchedGetaffinity(pid, &mask); err != nil {
+		return nil, fmt.Errorf("procmetrics: sched_getaffinity %d: %w", pid, err)
+	}
+
+	var cpus []int
+	for cpu := 0; cpu < len(mask)*64; cpu++ {
+		if mask.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// readParentPIDs maps each running process to its parent, from the PPid
+// line of every /proc/<pid>/status. Processes that exit during the scan are
+// skipped.
+func readParentPIDs() (map[int]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("procmetrics: read /proc: %w", err)
+	}
+
+	parents := make(map[int]int, len(entries))
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			value, ok := bytes.CutPrefix(scanner.Bytes(), []byte("PPid:"))
+			if !ok {
+				continue
+			}
+			if ppid, err := strconv.Atoi(string(bytes.TrimSpace(value))); err == nil {
+				parents[pid] = ppid
+			}
+			break
+		}
+	}
+	return parents, nil
+}
@@ -1,4 +1,4 @@
-//go:build !linux
+//go:build !linux && !windows
 
 package procmetrics
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -18,3 +19,39 @@ func runPS(pid int) (io.Reader, error) {
 	}
 	return strings.NewReader(string(out)), nil
 }
+
+// readProcessTimes is only implemented on Windows; other UNIX platforms
+// use ps.
+func readProcessTimes(_ int) (uint64, int64, error) {
+	return 0, 0, fmt.Errorf("procmetrics: readProcessTimes not supported on this platform (uses ps instead)")
+}
+
+// CPUAffinity is not available outside Linux and always returns no cores.
+func CPUAffinity(_ int) ([]int, error) {
+	return nil, nil
+}
+
+// readParentPIDs maps each running process to its parent using
+// `ps -A -o pid=,ppid=`, which unlike pstree ships with macOS and the BSDs.
+func readParentPIDs() (map[int]int, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("procmetrics: ps failed listing processes: %w", err)
+	}
+
+	parents := make(map[int]int)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil {
+			parents[pid] = ppid
+		}
+	}
+	return parents, nil
+}
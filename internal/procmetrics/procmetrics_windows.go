@@ -0,0 +1,122 @@
+//go:build windows
+
+package procmetrics
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	procGetProcessMemoryInfo   = windows.NewLazySystemDLL("psapi.dll").NewProc("GetProcessMemoryInfo")
+	procGetProcessAffinityMask = windows.NewLazySystemDLL("kernel32.dll").NewProc("GetProcessAffinityMask")
+)
+
+// processMemoryCounters mirrors the Win32 PROCESS_MEMORY_COUNTERS struct,
+// which golang.org/x/sys/windows doesn't define.
+type processMemoryCounters struct {
+	cb                         uint32
+	pageFaultCount             uint32
+	peakworkingSetSize         uintptr
+	workingSetSize             uintptr
+	quotaPeakPagedPoolUsage    uintptr
+	quotaPagedPoolUsage        uintptr
+	quotaPeakNonPagedPoolUsage uintptr
+	quotaNonPagedPoolUsage     uintptr
+	pagefileUsage              uintptr
+	PeakpagefileUsage          uintptr
+}
+
+// runPS is not used on Windows (we query the process directly), but must
+// exist to satisfy the compiler when sampleViaPS is referenced in the
+// shared file.
+func runPS(_ int) (io.Reader, error) {
+	return nil, fmt.Errorf("procmetrics: runPS not supported on Windows (uses the Win32 API instead)")
+}
+
+// readProcessTimes returns the total kernel and user CPU time pid has used,
+// in 100ns intervals, and its working set size in MB.
+func readProcessTimes(pid int) (cpuTime uint64, rssMB int64, err error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, 0, fmt.Errorf("procmetrics: open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(h)
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0, 0, fmt.Errorf("procmetrics: get process times %d: %w", pid, err)
+	}
+	cpuTime = filetimeTicks(kernel) + filetimeTicks(user)
+
+	var mem processMemoryCounters
+	mem.cb = uint32(unsafe.Sizeof(mem))
+	r, _, callErr := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mem)), uintptr(mem.cb))
+	if r == 0 {
+		return cpuTime, 0, fmt.Errorf("procmetrics: get process memory info %d: %w", pid, callErr)
+	}
+	rssMB = int64(mem.workingSetSize) / (1024 * 1024) // bytes → MB
+
+	return cpuTime, rssMB, nil
+}
+
+// filetimeTicks converts a FILETIME duration to a count of 100ns intervals.
+// Filetime.Nanoseconds can't be used as it assumes a point in time since
+// 1601.
+func filetimeTicks(ft windows.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
+
+// CPUAffinity returns the CPU cores pid is allowed to run on. Only the
+// first 64 cores, the caller's processor group, are reported.
+func CPUAffinity(pid int) ([]int, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("procmetrics: open process %d: %w", pid, err)
+	}
+	defer windows.CloseHandle(h)
+
+	var processMask, systemMask uintptr
+	r, _, callErr := procGetProcessAffinityMask.Call(uintptr(h),
+		uintptr(unsafe.Pointer(&processMask)), uintptr(unsafe.Pointer(&systemMask)))
+	if r == 0 {
+		return nil, fmt.Errorf("procmetrics: get process affinity mask %d: %w", pid, callErr)
+	}
+
+	var cpus []int
+	for cpu := 0; cpu < bits.UintSize; cpu++ {
+		if processMask&(1<<cpu) != 0 {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// readParentPIDs maps each running process to its parent from a Toolhelp
+// snapshot of the process list.
+func readParentPIDs() (map[int]int, error) {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("procmetrics: snapshot processes: %w", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	if err := windows.Process32First(snapshot, &entry); err != nil {
+		return nil, fmt.Errorf("procmetrics: read process snapshot: %w", err)
+	}
+
+	parents := make(map[int]int)
+	for {
+		parents[int(entry.ProcessID)] = int(entry.ParentProcessID)
+		if err := windows.Process32Next(snapshot, &entry); err != nil {
+			break
+		}
+	}
+	return parents, nil
+}
@@ -0,0 +1,97 @@
+package procmetrics
+
+import "sort"
+
+// SampleTree takes one sample of pid and of each of its descendants, pid
+// first. As with a Sampler's first Sample, CPUPercent is 0 except where
+// `ps` is used; use a Sampler to track the tree's CPU usage over time.
+// Descendants that exit while the tree is being sampled are left out.
+func SampleTree(pid int) ([]Sample, error) {
+	root, err := NewSampler(pid).sampleProcess()
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := descendants(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := []Sample{root}
+	for _, child := range children {
+		if s, err := NewSampler(child).sampleProcess(); err == nil {
+			samples = append(samples, s)
+		}
+	}
+	return samples, nil
+}
+
+// sampleTree samples the Sampler's descendants and adds their usage to
+// sample's tree totals. Samplers are kept per descendant so their CPU is
+// diffed against their previous reading like the root's; a descendant's
+// first reading counts only its memory. If the process table can't be
+// read, the totals are the process's own usage.
+func (s *Sampler) sampleTree(sample *Sample) {
+	sample.TreeCPUPercent = sample.CPUPercent
+	sample.TreeMemoryMB = sample.MemoryMB
+
+	children, err := descendants(s.pid)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[int]*Sampler, len(children))
+	for _, child := range children {
+		cs := s.children[child]
+		if cs == nil {
+			cs = NewSampler(child)
+		}
+
+		// The child may have exited since the process table was read
+		cSample, err := cs.sampleProcess()
+		if err != nil {
+			continue
+		}
+		seen[child] = cs
+		sample.TreeCPUPercent += cSample.CPUPercent
+		sample.TreeMemoryMB += cSample.MemoryMB
+	}
+
+	// Forget exited children so a reused PID starts from a fresh reading
+	s.children = seen
+}
+
+// descendants returns the PIDs of pid's children, their children and so
+// on, in breadth-first order
+func descendants(pid int) ([]int, error) {
+	parents, err := readParentPIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int][]int)
+	for child, parent := range parents {
+		if child != parent {
+			children[parent] = append(children[parent], child)
+		}
+	}
+
+	var tree []int
+	queue := []int{pid}
+	seen := map[int]bool{pid: true}
+	for len(queue) > 0 {
+		next := children[queue[0]]
+		queue = queue[1:]
+		sort.Ints(next)
+		for _, child := range next {
+			// Guard against cycles from PIDs reused while the table was read
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			tree = append(tree, child)
+			queue = append(queue, child)
+		}
+	}
+	return tree, nil
+}
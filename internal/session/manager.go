@@ -1,27 +1,79 @@
 package session
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/meridian-lex/stratavore/internal/notifications"
 	"github.com/meridian-lex/stratavore/internal/storage"
 	"github.com/meridian-lex/stratavore/pkg/types"
 	"go.uber.org/zap"
 )
 
+// sessionCleanupInterval is how often expired sessions are purged
+const sessionCleanupInterval = 6 * time.Hour
+
+// indexTimeout bounds indexing an ended session for semantic search
+const indexTimeout = 30 * time.Second
+
+// Indexer stores sessions for semantic search
+type Indexer interface {
+	IndexSession(ctx context.Context, session *types.Session) error
+}
+
 // Manager handles session tracking and resumption
 type Manager struct {
-	db     *storage.PostgresClient
-	logger *zap.Logger
+	db         *storage.PostgresClient
+	store      TranscriptStore
+	indexer    Indexer
+	rates      *messageRates
+	maxRate    float64
+	dispatcher *notifications.Dispatcher
+	logger     *zap.Logger
 }
 
 // NewManager creates a new session manager
 func NewManager(db *storage.PostgresClient, logger *zap.Logger) *Manager {
 	return &Manager{
-		db:     db,
-		logger: logger,
+		db:      db,
+		rates:   newMessageRates(),
+		maxRate: DefaultMaxMessagesPerMinute,
+		logger:  logger,
+	}
+}
+
+// SetTranscriptStore sets where transcript blobs are uploaded to and
+// downloaded from. Without one, only transcript metadata is recorded.
+func (m *Manager) SetTranscriptStore(store TranscriptStore) {
+	m.store = store
+}
+
+// SetIndexer sets where ended sessions are indexed for semantic search.
+// Without one, sessions are not indexed.
+func (m *Manager) SetIndexer(indexer Indexer) {
+	m.indexer = indexer
+}
+
+// SetRedis shares message rates between daemons through Redis. Without
+// it, each daemon tracks the sessions it sees.
+func (m *Manager) SetRedis(client *redis.Client) {
+	m.rates.redis = client
+}
+
+// SetDispatcher enables runaway session alerts, raised when a session
+// sends more than maxPerMinute messages a minute for several samples in a
+// row. A maxPerMinute of zero or less keeps the default.
+func (m *Manager) SetDispatcher(d *notifications.Dispatcher, maxPerMinute int) {
+	m.dispatcher = d
+	if maxPerMinute > 0 {
+		m.maxRate = float64(maxPerMinute)
 	}
 }
 
@@ -62,10 +114,37 @@ func (m *Manager) EndSession(ctx context.Context, sessionID string) error {
 	}
 
 	m.logger.Info("session ended", zap.String("session_id", sessionID))
+	m.rates.forget(ctx, sessionID)
+
+	if m.indexer != nil {
+		go m.indexSession(sessionID)
+	}
 	return nil
 }
 
-// UpdateSessionMessage records a message in the session
+// indexSession indexes an ended session's summary. Failures are logged
+// rather than returned so search being down never blocks ending a session.
+func (m *Manager) indexSession(sessionID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), indexTimeout)
+	defer cancel()
+
+	session, err := m.db.GetSession(ctx, sessionID)
+	if err != nil {
+		m.logger.Warn("failed to load session for indexing",
+			zap.String("session_id", sessionID),
+			zap.Error(err))
+		return
+	}
+
+	if err := m.indexer.IndexSession(ctx, session); err != nil {
+		m.logger.Warn("failed to index session",
+			zap.String("session_id", sessionID),
+			zap.Error(err))
+	}
+}
+
+// UpdateSessionMessage records a message in the session and updates its
+// message rate, alerting when the session looks like a runaway loop
 func (m *Manager) UpdateSessionMessage(ctx context.Context, sessionID string, tokensUsed int64) error {
 	now := time.Now()
 
@@ -74,9 +153,98 @@ func (m *Manager) UpdateSessionMessage(ctx context.Context, sessionID string, to
 		return fmt.Errorf("update session message: %w", err)
 	}
 
+	rate := m.rates.record(ctx, sessionID, now)
+	if m.rates.sample(sessionID, rate, m.maxRate) {
+		m.alertRunaway(ctx, sessionID, rate)
+	}
+
 	return nil
 }
 
+// MessagesPerMinute returns the session's message rate over the last minute
+func (m *Manager) MessagesPerMinute(ctx context.Context, sessionID string) float64 {
+	return m.rates.rate(ctx, sessionID)
+}
+
+// alertRunaway notifies the session's project that it has stayed above the
+// message rate limit
+func (m *Manager) alertRunaway(ctx context.Context, sessionID string, rate float64) {
+	m.logger.Warn("runaway session detected",
+		zap.String("session_id", sessionID),
+		zap.Float64("messages_per_minute", rate),
+		zap.Float64("limit", m.maxRate))
+
+	projectName := ""
+	runnerID := ""
+	if sess, err := m.db.GetSession(ctx, sessionID); err == nil {
+		projectName = sess.ProjectName
+		runnerID = sess.RunnerID
+	}
+
+	m.dispatcher.Notify(projectName, notifications.EventSystemAlert, map[string]interface{}{
+		"title": "Runaway session",
+		"message": fmt.Sprintf("Session %s in %s has sent %.0f messages/min for %d samples (limit %.0f)",
+			sessionID, projectName, rate, runawaySamples, m.maxRate),
+		"session_id": sessionID,
+		"runner_id":  runnerID,
+	})
+}
+
+// PinSession protects a session from retention cleanup
+func (m *Manager) PinSession(ctx context.Context, sessionID string) error {
+	if err := m.db.PinSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("pin session: %w", err)
+	}
+
+	m.logger.Info("session pinned", zap.String("session_id", sessionID))
+	return nil
+}
+
+// UnpinSession makes a session eligible for retention cleanup again
+func (m *Manager) UnpinSession(ctx context.Context, sessionID string) error {
+	if err := m.db.UnpinSession(ctx, sessionID); err != nil {
+		return fmt.Errorf("unpin session: %w", err)
+	}
+
+	m.logger.Info("session unpinned", zap.String("session_id", sessionID))
+	return nil
+}
+
+// CleanupLoop periodically deletes ended sessions older than retention.
+// Pinned sessions are always kept. Runs until ctx is cancelled.
+func (m *Manager) CleanupLoop(ctx context.Context, retention time.Duration) {
+	ticker := time.NewTicker(sessionCleanupInterval)
+	defer ticker.Stop()
+
+	m.logger.Info("session cleanup loop started",
+		zap.Duration("interval", sessionCleanupInterval),
+		zap.Duration("retention", retention))
+
+	for {
+		select {
+		case <-ticker.C:
+			m.cleanup(ctx, retention)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanup purges unpinned sessions that ended before the retention cutoff
+func (m *Manager) cleanup(ctx context.Context, retention time.Duration) {
+	before := time.Now().Add(-retention)
+
+	deleted, err := m.db.DeleteExpiredSessions(ctx, before)
+	if err != nil {
+		m.logger.Error("failed to clean expired sessions", zap.Error(err))
+		return
+	}
+
+	m.logger.Info("cleaned expired sessions",
+		zap.Int64("deleted", deleted),
+		zap.Time("before", before))
+}
+
 // GetResumableSessions returns sessions that can be resumed for a project
 func (m *Manager) GetResumableSessions(ctx context.Context, projectName string) ([]*types.Session, error) {
 	sessions, err := m.db.GetResumableSessions(ctx, projectName)
@@ -139,29 +307,52 @@ func (m *Manager) MarkSessionNonResumable(ctx context.Context, sessionID string,
 	return nil
 }
 
-// SaveTranscript saves conversation transcript to storage
+// SaveTranscript gzip-compresses a conversation transcript and saves it to
+// storage
 func (m *Manager) SaveTranscript(ctx context.Context, sessionID string, transcript []byte) error {
-	// In production, this would upload to S3/object storage
-	// For now, just store metadata
-
-	storageKey := fmt.Sprintf("sessions/%s/transcript.json", sessionID)
-	sizeBytes := int64(len(transcript))
+	storageKey, sizeBytes, uncompressedSize, err := m.uploadTranscript(ctx, sessionID, transcript)
+	if err != nil {
+		return err
+	}
 
-	err := m.db.SaveTranscriptMetadata(ctx, sessionID, storageKey, sizeBytes)
+	err = m.db.SaveTranscriptMetadata(ctx, sessionID, storageKey, sizeBytes, uncompressedSize, true)
 	if err != nil {
 		return fmt.Errorf("save transcript metadata: %w", err)
 	}
 
+	ratio := 0.0
+	if sizeBytes > 0 {
+		ratio = float64(uncompressedSize) / float64(sizeBytes)
+	}
+
 	m.logger.Info("transcript saved",
 		zap.String("session_id", sessionID),
-		zap.Int64("size_bytes", sizeBytes))
-
-	// TODO: Actually upload to S3
-	// err = m.s3Client.Upload(storageKey, transcript)
+		zap.Int64("size_bytes", sizeBytes),
+		zap.Int64("uncompressed_size_bytes", uncompressedSize),
+		zap.Float64("compression_ratio", ratio))
 
 	return nil
 }
 
+// uploadTranscript compresses a transcript and stores it under the
+// session's key, returning the key and the stored and uncompressed sizes
+func (m *Manager) uploadTranscript(ctx context.Context, sessionID string, transcript []byte) (string, int64, int64, error) {
+	compressed, err := compressTranscript(transcript)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("compress transcript: %w", err)
+	}
+
+	storageKey := fmt.Sprintf("sessions/%s/transcript.json.gz", sessionID)
+
+	if m.store != nil {
+		if err := m.store.Upload(ctx, storageKey, compressed); err != nil {
+			return "", 0, 0, fmt.Errorf("upload transcript: %w", err)
+		}
+	}
+
+	return storageKey, int64(len(compressed)), int64(len(transcript)), nil
+}
+
 // LoadTranscript loads conversation transcript from storage
 func (m *Manager) LoadTranscript(ctx context.Context, sessionID string) ([]byte, error) {
 	session, err := m.db.GetSession(ctx, sessionID)
@@ -173,14 +364,134 @@ func (m *Manager) LoadTranscript(ctx context.Context, sessionID string) ([]byte,
 		return nil, fmt.Errorf("no transcript available for session %s", sessionID)
 	}
 
-	// TODO: Download from S3
-	// transcript, err := m.s3Client.Download(session.TranscriptS3Key)
+	if m.store == nil {
+		return nil, fmt.Errorf("no transcript store configured")
+	}
+
+	data, err := m.store.Download(ctx, session.TranscriptS3Key)
+	if err != nil {
+		return nil, fmt.Errorf("download transcript: %w", err)
+	}
+
+	// Transcripts saved before compression was introduced are stored as-is
+	transcript := data
+	if session.TranscriptCompressed {
+		if transcript, err = decompressTranscript(data); err != nil {
+			return nil, fmt.Errorf("decompress transcript: %w", err)
+		}
+	}
 
 	m.logger.Info("transcript loaded",
-		zap.String("session_id", sessionID))
+		zap.String("session_id", sessionID),
+		zap.Bool("compressed", session.TranscriptCompressed))
 
-	// Placeholder
-	return []byte{}, nil
+	return transcript, nil
+}
+
+// BranchSession forks a session after its first forkAfter messages. The
+// truncated transcript is stored as a new resumable session that records
+// the source in ResumedFrom; the source session is left untouched.
+func (m *Manager) BranchSession(ctx context.Context, sourceID string, forkAfter int) (*types.Session, error) {
+	source, err := m.db.GetSession(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	transcript, err := m.LoadTranscript(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	branched, err := forkTranscript(transcript, forkAfter)
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", sourceID, err)
+	}
+
+	now := time.Now()
+	branch := &types.Session{
+		ID:           uuid.New().String(),
+		RunnerID:     source.RunnerID,
+		ProjectName:  source.ProjectName,
+		StartedAt:    now,
+		MessageCount: forkAfter,
+		Resumable:    true,
+		ResumedFrom:  sourceID,
+		CreatedAt:    now,
+	}
+
+	// Upload before creating the row, so a failed upload never leaves a
+	// resumable session without a transcript
+	key, size, uncompressedSize, err := m.uploadTranscript(ctx, branch.ID, branched)
+	if err != nil {
+		return nil, err
+	}
+	branch.TranscriptS3Key = key
+	branch.TranscriptSizeBytes = size
+	branch.TranscriptCompressed = true
+	branch.UncompressedSizeBytes = uncompressedSize
+
+	if err := m.db.CreateSession(ctx, branch); err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	m.logger.Info("session branched",
+		zap.String("session_id", branch.ID),
+		zap.String("source_session_id", sourceID),
+		zap.Int("fork_after_message", forkAfter))
+
+	return branch, nil
+}
+
+// forkTranscript returns the first forkAfter messages of a JSON array
+// transcript
+func forkTranscript(transcript []byte, forkAfter int) ([]byte, error) {
+	if forkAfter < 1 {
+		return nil, fmt.Errorf("fork point must be at least 1")
+	}
+
+	var messages []json.RawMessage
+	if err := json.Unmarshal(transcript, &messages); err != nil {
+		return nil, fmt.Errorf("parse transcript: %w", err)
+	}
+	if forkAfter > len(messages) {
+		return nil, fmt.Errorf("transcript has only %d messages", len(messages))
+	}
+
+	branched, err := json.Marshal(messages[:forkAfter])
+	if err != nil {
+		return nil, fmt.Errorf("encode transcript: %w", err)
+	}
+	return branched, nil
+}
+
+// compressTranscript gzips a transcript, favouring speed over size
+func compressTranscript(transcript []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(transcript); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressTranscript reverses compressTranscript. An empty body (nothing
+// downloaded) decompresses to an empty transcript.
+func decompressTranscript(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return []byte{}, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
 }
 
 // GetSessionStats returns statistics for a session
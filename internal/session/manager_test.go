@@ -0,0 +1,47 @@
+package session
+
+import "testing"
+
+func TestForkTranscript(t *testing.T) {
+	transcript := []byte(`[{"role":"user","content":"a"},{"role":"assistant","content":"b"},{"role":"user","content":"c"}]`)
+
+	tests := []struct {
+		name      string
+		forkAfter int
+		want      string
+		wantErr   bool
+	}{
+		{name: "first message", forkAfter: 1, want: `[{"role":"user","content":"a"}]`},
+		{name: "middle", forkAfter: 2, want: `[{"role":"user","content":"a"},{"role":"assistant","content":"b"}]`},
+		{name: "whole transcript", forkAfter: 3, want: string(transcript)},
+		{name: "past the end", forkAfter: 4, wantErr: true},
+		{name: "zero", forkAfter: 0, wantErr: true},
+		{name: "negative", forkAfter: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := forkTranscript(transcript, tt.forkAfter)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForkTranscriptRejectsInvalidJSON(t *testing.T) {
+	for _, transcript := range []string{``, `{"role":"user"}`, `[{"role":`} {
+		if _, err := forkTranscript([]byte(transcript), 1); err == nil {
+			t.Errorf("%q: got no error", transcript)
+		}
+	}
+}
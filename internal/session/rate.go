@@ -0,0 +1,140 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// messageRatesKey is the Redis hash mapping session ID to the
+	// timestamps of its recent messages
+	messageRatesKey = "message_rates"
+
+	// messageRateWindow is the span messages are counted over
+	messageRateWindow = time.Minute
+
+	// messageRateBuffer caps the timestamps kept per session
+	messageRateBuffer = 60
+
+	// runawaySamples is how many consecutive samples over the limit raise
+	// a runaway session alert
+	runawaySamples = 3
+
+	// DefaultMaxMessagesPerMinute is the rate above which a session is
+	// considered runaway when the limit isn't configured
+	DefaultMaxMessagesPerMinute = 30
+)
+
+// messageRates tracks recent message timestamps per session in Redis so
+// every daemon sees the same rate, or in memory when Redis is unavailable
+type messageRates struct {
+	redis *redis.Client
+
+	mu    sync.Mutex
+	local map[string][]int64 // session ID → unix nanos, oldest first
+	over  map[string]int     // consecutive samples above the limit
+}
+
+func newMessageRates() *messageRates {
+	return &messageRates{
+		local: make(map[string][]int64),
+		over:  make(map[string]int),
+	}
+}
+
+// record adds a message at now and returns the session's messages per
+// minute
+func (r *messageRates) record(ctx context.Context, sessionID string, now time.Time) float64 {
+	stamps := r.load(ctx, sessionID)
+	stamps = trimStamps(append(stamps, now.UnixNano()), now)
+	r.save(ctx, sessionID, stamps)
+	return ratePerMinute(stamps)
+}
+
+// rate returns the session's current messages per minute
+func (r *messageRates) rate(ctx context.Context, sessionID string) float64 {
+	return ratePerMinute(trimStamps(r.load(ctx, sessionID), time.Now()))
+}
+
+// sample counts a rate sample against limit and reports whether the
+// session has just stayed over it for runawaySamples samples in a row
+func (r *messageRates) sample(sessionID string, rate, limit float64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rate <= limit {
+		delete(r.over, sessionID)
+		return false
+	}
+	r.over[sessionID]++
+	return r.over[sessionID] == runawaySamples
+}
+
+// forget drops an ended session's timestamps
+func (r *messageRates) forget(ctx context.Context, sessionID string) {
+	r.mu.Lock()
+	delete(r.local, sessionID)
+	delete(r.over, sessionID)
+	r.mu.Unlock()
+
+	if r.redis != nil {
+		r.redis.HDel(ctx, messageRatesKey, sessionID)
+	}
+}
+
+func (r *messageRates) load(ctx context.Context, sessionID string) []int64 {
+	if r.redis != nil {
+		data, err := r.redis.HGet(ctx, messageRatesKey, sessionID).Bytes()
+		if err == nil {
+			var stamps []int64
+			if json.Unmarshal(data, &stamps) == nil {
+				return stamps
+			}
+		} else if err == redis.Nil {
+			return nil
+		}
+		// Redis unavailable; fall back to this daemon's view
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64(nil), r.local[sessionID]...)
+}
+
+func (r *messageRates) save(ctx context.Context, sessionID string, stamps []int64) {
+	r.mu.Lock()
+	r.local[sessionID] = stamps
+	r.mu.Unlock()
+
+	if r.redis != nil {
+		// Concurrent updates from two daemons may drop a timestamp, which
+		// only makes the rate slightly low
+		if data, err := json.Marshal(stamps); err == nil {
+			r.redis.HSet(ctx, messageRatesKey, sessionID, data)
+		}
+	}
+}
+
+// trimStamps drops timestamps older than the window and keeps at most the
+// newest messageRateBuffer
+func trimStamps(stamps []int64, now time.Time) []int64 {
+	cutoff := now.Add(-messageRateWindow).UnixNano()
+	i := 0
+	for i < len(stamps) && stamps[i] < cutoff {
+		i++
+	}
+	stamps = stamps[i:]
+	if len(stamps) > messageRateBuffer {
+		stamps = stamps[len(stamps)-messageRateBuffer:]
+	}
+	return stamps
+}
+
+// ratePerMinute converts the messages in one window to a per-minute rate
+func ratePerMinute(stamps []int64) float64 {
+	return float64(len(stamps)) / messageRateWindow.Minutes()
+}
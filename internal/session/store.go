@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TranscriptStore holds transcript blobs under the keys recorded in
+// sessions.transcript_s3_key
+type TranscriptStore interface {
+	Upload(ctx context.Context, key string, data []byte) error
+	Download(ctx context.Context, key string) ([]byte, error)
+}
+
+// DirStore is a TranscriptStore backed by a local directory, used until an
+// object storage backend is configured
+type DirStore struct {
+	root string
+}
+
+// NewDirStore creates a store rooted at dir, creating it if needed
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create transcript dir: %w", err)
+	}
+	return &DirStore{root: dir}, nil
+}
+
+// Upload writes data under key, replacing any existing blob
+func (s *DirStore) Upload(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash never leaves a partial blob
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Download reads the blob stored under key
+func (s *DirStore) Download(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// path maps a storage key to a file, rejecting keys that escape the root
+func (s *DirStore) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid transcript key: %s", key)
+	}
+	return filepath.Join(s.root, key), nil
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Connection errors returned by NewPostgresClient. Each wraps the
+// underlying error so callers can match with errors.Is and still show the
+// driver's message.
+var (
+	// ErrInvalidConnString means the connection settings are incomplete or
+	// out of range
+	ErrInvalidConnString = errors.New("invalid database connection settings")
+
+	// ErrHostNotFound means the database host name did not resolve
+	ErrHostNotFound = errors.New("database host not found")
+
+	// ErrConnectionRefused means nothing is listening on the host and port
+	ErrConnectionRefused = errors.New("database connection refused")
+
+	// ErrAuthFailed means the server rejected the user or password
+	ErrAuthFailed = errors.New("database authentication failed")
+
+	// ErrDatabaseNotFound means the server is reachable but the database
+	// has not been created
+	ErrDatabaseNotFound = errors.New("database does not exist")
+)
+
+// validateConnConfig checks the fields pgx leaves unchecked when parsing
+func validateConnConfig(config *pgxpool.Config) error {
+	cc := config.ConnConfig
+	switch {
+	case cc.Host == "":
+		return fmt.Errorf("%w: host is empty", ErrInvalidConnString)
+	case cc.Port == 0:
+		// Port is a uint16, so only zero is out of the 1-65535 range
+		return fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidConnString)
+	case cc.User == "":
+		return fmt.Errorf("%w: user is empty", ErrInvalidConnString)
+	case cc.Database == "":
+		return fmt.Errorf("%w: database name is empty", ErrInvalidConnString)
+	}
+	return nil
+}
+
+// classifyConnectError wraps a failed ping in the connection error that
+// explains it, or returns it unchanged when the cause isn't recognised
+func classifyConnectError(err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Errorf("%w: %w", ErrHostNotFound, err)
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("%w: %w", ErrConnectionRefused, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "28P01", "28000": // invalid_password, invalid_authorization_specification
+			return fmt.Errorf("%w: %w", ErrAuthFailed, err)
+		case "3D000": // invalid_catalog_name
+			return fmt.Errorf("%w: %w", ErrDatabaseNotFound, err)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// advisoryLockKey maps key to a Postgres advisory lock ID. It is the 32-bit
+// FNV-1a hash, so every daemon derives the same ID for the same key.
+func advisoryLockKey(key string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int64(h.Sum32())
+}
+
+// AcquireAdvisoryLock takes a transaction-scoped advisory lock on key,
+// blocking until any other transaction holding it ends. The lock is
+// released when tx commits or rolls back. Prefix keys with what they
+// protect, e.g. "project:<name>", so unrelated locks don't collide.
+func (c *PostgresClient) AcquireAdvisoryLock(ctx context.Context, tx pgx.Tx, key string) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockKey(key)); err != nil {
+		return fmt.Errorf("acquire advisory lock %q: %w", key, err)
+	}
+	return nil
+}
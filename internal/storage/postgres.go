@@ -4,25 +4,64 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/meridian-lex/stratavore/pkg/types"
+	"go.uber.org/zap"
 )
 
+const (
+	// healthCheckInterval is how often the background monitor pings the pool
+	healthCheckInterval = 30 * time.Second
+
+	// unhealthyThreshold is the number of consecutive failed health checks
+	// after which the client reports itself unhealthy
+	unhealthyThreshold = 3
+
+	// poolStatsLogInterval is how often pool usage is logged, to catch slow
+	// connection leaks before the pool runs dry
+	poolStatsLogInterval = 60 * time.Second
+
+	// poolSaturationThreshold is the fraction of MaxConns in use above
+	// which the pool is considered saturated
+	poolSaturationThreshold = 0.9
+)
+
+// ErrQuotaExceeded is returned by CreateRunnerTx when the project already
+// has its maximum number of concurrent runners
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// ErrRunnerTerminated is returned by UpdateRunnerHeartbeat when the runner
+// has already terminated or failed, or its row has been cleaned up. Its
+// agent is orphaned and should exit.
+var ErrRunnerTerminated = errors.New("runner terminated")
+
 // PostgresClient handles PostgreSQL operations
 type PostgresClient struct {
-	pool *pgxpool.Pool
+	pool      *pgxpool.Pool
+	logger    *zap.Logger
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+	unhealthy atomic.Bool
 }
 
-// NewPostgresClient creates a new PostgreSQL client
-func NewPostgresClient(ctx context.Context, connString string, maxConns, minConns int) (*PostgresClient, error) {
+// NewPostgresClient creates a new PostgreSQL client and starts a background
+// pool health monitor. A nil logger disables monitor logging.
+func NewPostgresClient(ctx context.Context, connString string, maxConns, minConns int, logger *zap.Logger) (*PostgresClient, error) {
 	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return nil, fmt.Errorf("parse connection string: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConnString, err)
+	}
+	if err := validateConnConfig(config); err != nil {
+		return nil, err
 	}
 
 	config.MaxConns = int32(maxConns)
@@ -37,17 +76,136 @@ func NewPostgresClient(ctx context.Context, connString string, maxConns, minConn
 
 	// Test connection
 	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("ping database: %w", err)
+		pool.Close()
+		return nil, fmt.Errorf("ping database: %w", classifyConnectError(err))
+	}
+
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	c := &PostgresClient{
+		pool:   pool,
+		logger: logger,
+		stopCh: make(chan struct{}),
 	}
+	go c.monitorHealth()
 
-	return &PostgresClient{pool: pool}, nil
+	return c, nil
 }
 
-// Close closes the database connection pool
+// Close stops the health monitor and closes the database connection pool.
+// It is safe to call more than once.
 func (c *PostgresClient) Close() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
 	c.pool.Close()
 }
 
+// HealthCheck pings the database. On failure the returned error includes
+// the current pool statistics.
+func (c *PostgresClient) HealthCheck(ctx context.Context) error {
+	if err := c.pool.Ping(ctx); err != nil {
+		stat := c.pool.Stat()
+		return fmt.Errorf("ping database: %w (pool: total=%d acquired=%d idle=%d max=%d)",
+			err, stat.TotalConns(), stat.AcquiredConns(), stat.IdleConns(), stat.MaxConns())
+	}
+	return nil
+}
+
+// PoolStats is a snapshot of connection pool usage
+type PoolStats struct {
+	TotalConns    int32
+	AcquiredConns int32
+	IdleConns     int32
+	MaxConns      int32
+	AcquireCount  int64 // cumulative successful acquires
+	EmptyAcquires int64 // cumulative acquires that had to wait for a connection
+}
+
+// Utilisation returns the fraction of MaxConns currently acquired
+func (p PoolStats) Utilisation() float64 {
+	if p.MaxConns <= 0 {
+		return 0
+	}
+	return float64(p.AcquiredConns) / float64(p.MaxConns)
+}
+
+// Saturated reports whether more than 90% of the pool is in use, so new
+// queries are likely to queue for a connection
+func (p PoolStats) Saturated() bool {
+	return p.Utilisation() > poolSaturationThreshold
+}
+
+// PoolStats returns current connection pool statistics
+func (c *PostgresClient) PoolStats() PoolStats {
+	stat := c.pool.Stat()
+	return PoolStats{
+		TotalConns:    stat.TotalConns(),
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		MaxConns:      stat.MaxConns(),
+		AcquireCount:  stat.AcquireCount(),
+		EmptyAcquires: stat.EmptyAcquireCount(),
+	}
+}
+
+// IsHealthy reports whether the most recent health checks succeeded
+func (c *PostgresClient) IsHealthy() bool {
+	return !c.unhealthy.Load()
+}
+
+// monitorHealth periodically checks pool health until Close is called.
+// After unhealthyThreshold consecutive failures the client is flagged
+// unhealthy and the pool is reset so that fresh connections are dialled.
+func (c *PostgresClient) monitorHealth() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	statsTicker := time.NewTicker(poolStatsLogInterval)
+	defer statsTicker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-statsTicker.C:
+			stats := c.PoolStats()
+			c.logger.Info("postgres pool stats",
+				zap.Int32("acquired", stats.AcquiredConns),
+				zap.Int32("idle", stats.IdleConns),
+				zap.Int32("total", stats.TotalConns),
+				zap.Int32("max", stats.MaxConns),
+				zap.Int64("acquire_count", stats.AcquireCount),
+				zap.Int64("empty_acquires", stats.EmptyAcquires))
+
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := c.HealthCheck(ctx)
+			cancel()
+
+			if err == nil {
+				if c.unhealthy.Swap(false) {
+					c.logger.Info("postgres connection pool recovered")
+				}
+				failures = 0
+				continue
+			}
+
+			failures++
+			c.logger.Warn("postgres health check failed",
+				zap.Int("consecutive_failures", failures),
+				zap.Error(err))
+
+			if failures >= unhealthyThreshold && !c.unhealthy.Swap(true) {
+				c.logger.Error("postgres connection pool unhealthy, resetting connections",
+					zap.Int("consecutive_failures", failures),
+					zap.Error(err))
+				c.pool.Reset()
+			}
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
 // BeginTx starts a new transaction
 func (c *PostgresClient) BeginTx(ctx context.Context) (pgx.Tx, error) {
 	return c.pool.Begin(ctx)
@@ -73,12 +231,87 @@ func (c *PostgresClient) CreateProject(ctx context.Context, project *types.Proje
 	return err
 }
 
+// UpsertProject creates a project, or updates the path and description of
+// an existing one without touching its status, tags or usage counters.
+// created reports whether a new row was inserted.
+func (c *PostgresClient) UpsertProject(ctx context.Context, project *types.Project) (created bool, err error) {
+	query := `
+		INSERT INTO projects (name, path, status, description, tags)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			path = EXCLUDED.path,
+			description = EXCLUDED.description,
+			updated_at = NOW()
+		RETURNING (xmax = 0)
+	`
+
+	err = c.pool.QueryRow(ctx, query,
+		project.Name,
+		project.Path,
+		project.Status,
+		project.Description,
+		project.Tags,
+	).Scan(&created)
+
+	return created, err
+}
+
+// CreateProjectSetup creates a project together with its optional resource
+// quota and token budget in one transaction, so a failure leaves nothing
+// behind
+func (c *PostgresClient) CreateProjectSetup(ctx context.Context, project *types.Project, quota *types.ResourceQuota, budget *types.TokenBudget) error {
+	return c.WithRetry(func() error {
+		return c.createProjectSetup(ctx, project, quota, budget)
+	}, defaultTxRetries)
+}
+
+func (c *PostgresClient) createProjectSetup(ctx context.Context, project *types.Project, quota *types.ResourceQuota, budget *types.TokenBudget) error {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO projects (name, path, status, description, tags)
+		VALUES ($1, $2, $3, $4, $5)
+	`, project.Name, project.Path, project.Status, project.Description, project.Tags)
+	if err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+
+	if quota != nil {
+		_, err = tx.Exec(ctx, upsertResourceQuotaQuery, quota.ProjectName, quota.MaxConcurrentRunners,
+			nullInt64(quota.MaxMemoryMB), nullInt64(int64(quota.MaxCPUPercent)), nullInt64(quota.MaxTokensPerDay),
+			nullInt64(int64(quota.MaxCPUsPerRunner)), nullInt64(int64(quota.MaxRuntimeSeconds)),
+			nullInt64(int64(quota.HeartbeatTTLSeconds)))
+		if err != nil {
+			return fmt.Errorf("create quota: %w", err)
+		}
+	}
+
+	if budget != nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO token_budgets (
+				scope, scope_id, limit_tokens, used_tokens,
+				period_granularity, period_start, period_end
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, budget.Scope, nullString(budget.ScopeID), budget.LimitTokens, budget.UsedTokens,
+			budget.PeriodGranularity, budget.PeriodStart, budget.PeriodEnd)
+		if err != nil {
+			return fmt.Errorf("create budget: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetProject retrieves a project by name
 func (c *PostgresClient) GetProject(ctx context.Context, name string) (*types.Project, error) {
 	query := `
 		SELECT name, path, status, description, tags,
 		       total_runners, active_runners, total_sessions, total_tokens,
-		       created_at, last_accessed_at, archived_at, updated_at
+		       created_at, last_accessed_at, archived_at, updated_at, health_probe
 		FROM projects
 		WHERE name = $1
 	`
@@ -86,6 +319,7 @@ func (c *PostgresClient) GetProject(ctx context.Context, name string) (*types.Pr
 	var project types.Project
 	var tags []string
 	var lastAccessed, archived sql.NullTime
+	var probeJSON []byte
 
 	err := c.pool.QueryRow(ctx, query, name).Scan(
 		&project.Name,
@@ -101,6 +335,7 @@ func (c *PostgresClient) GetProject(ctx context.Context, name string) (*types.Pr
 		&lastAccessed,
 		&archived,
 		&project.UpdatedAt,
+		&probeJSON,
 	)
 
 	if err != nil {
@@ -117,6 +352,9 @@ func (c *PostgresClient) GetProject(ctx context.Context, name string) (*types.Pr
 	if archived.Valid {
 		project.ArchivedAt = &archived.Time
 	}
+	if len(probeJSON) > 0 {
+		json.Unmarshal(probeJSON, &project.HealthProbe)
+	}
 
 	return &project, nil
 }
@@ -126,7 +364,7 @@ func (c *PostgresClient) ListProjects(ctx context.Context, status string) ([]*ty
 	query := `
 		SELECT name, path, status, description, tags,
 		       total_runners, active_runners, total_sessions, total_tokens,
-		       created_at, last_accessed_at, archived_at, updated_at
+		       created_at, last_accessed_at, archived_at, updated_at, health_probe
 		FROM projects
 	`
 
@@ -146,48 +384,286 @@ func (c *PostgresClient) ListProjects(ctx context.Context, status string) ([]*ty
 
 	var projects []*types.Project
 	for rows.Next() {
-		var project types.Project
-		var tags []string
-		var lastAccessed, archived sql.NullTime
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
 
-		err := rows.Scan(
-			&project.Name,
-			&project.Path,
-			&project.Status,
-			&project.Description,
-			&tags,
-			&project.TotalRunners,
-			&project.ActiveRunners,
-			&project.TotalSessions,
-			&project.TotalTokens,
-			&project.CreatedAt,
-			&lastAccessed,
-			&archived,
-			&project.UpdatedAt,
-		)
+	return projects, rows.Err()
+}
+
+// ProjectSearchQuery narrows SearchProjects; zero fields match everything.
+// A project must carry every tag in Tags and have any status in Status.
+type ProjectSearchQuery struct {
+	NameContains     string
+	Tags             []string
+	Status           []types.ProjectStatus
+	CreatedAfter     time.Time
+	CreatedBefore    time.Time
+	HasActiveRunners bool
+}
+
+// SearchProjects lists projects matching every condition in query, most
+// recently accessed first
+func (c *PostgresClient) SearchProjects(ctx context.Context, query ProjectSearchQuery) ([]*types.Project, error) {
+	sqlQuery := `
+		SELECT name, path, status, description, tags,
+		       total_runners, active_runners, total_sessions, total_tokens,
+		       created_at, last_accessed_at, archived_at, updated_at, health_probe
+		FROM projects
+	`
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if query.NameContains != "" {
+		addCondition("name ILIKE '%%' || $%d || '%%'", query.NameContains)
+	}
+	if len(query.Tags) > 0 {
+		addCondition("tags @> $%d::text[]", query.Tags)
+	}
+	if len(query.Status) > 0 {
+		statuses := make([]string, len(query.Status))
+		for i, st := range query.Status {
+			statuses[i] = string(st)
+		}
+		addCondition("status = ANY($%d::text[])", statuses)
+	}
+	if !query.CreatedAfter.IsZero() {
+		addCondition("created_at >= $%d", query.CreatedAfter)
+	}
+	if !query.CreatedBefore.IsZero() {
+		addCondition("created_at < $%d", query.CreatedBefore)
+	}
+	if query.HasActiveRunners {
+		conditions = append(conditions, "active_runners > 0")
+	}
+
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlQuery += " ORDER BY last_accessed_at DESC NULLS LAST, name"
 
+	rows, err := c.pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*types.Project
+	for rows.Next() {
+		project, err := scanProject(rows)
 		if err != nil {
 			return nil, err
 		}
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// scanProject reads a projects row selected with the column list used by
+// ListProjects
+func scanProject(row pgx.Row) (*types.Project, error) {
+	var project types.Project
+	var tags []string
+	var lastAccessed, archived sql.NullTime
+	var probeJSON []byte
+
+	err := row.Scan(
+		&project.Name,
+		&project.Path,
+		&project.Status,
+		&project.Description,
+		&tags,
+		&project.TotalRunners,
+		&project.ActiveRunners,
+		&project.TotalSessions,
+		&project.TotalTokens,
+		&project.CreatedAt,
+		&lastAccessed,
+		&archived,
+		&project.UpdatedAt,
+		&probeJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Tags = tags
+	if lastAccessed.Valid {
+		project.LastAccessedAt = &lastAccessed.Time
+	}
+	if archived.Valid {
+		project.ArchivedAt = &archived.Time
+	}
+	if len(probeJSON) > 0 {
+		json.Unmarshal(probeJSON, &project.HealthProbe)
+	}
+
+	return &project, nil
+}
+
+// ArchiveProject marks a project archived, hiding it from default listings
+// and blocking new runner launches
+func (c *PostgresClient) ArchiveProject(ctx context.Context, name string) error {
+	query := `
+		UPDATE projects
+		SET status = 'archived', archived_at = NOW()
+		WHERE name = $1
+	`
+
+	tag, err := c.pool.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("project not found: %s", name)
+	}
+	return nil
+}
+
+// ProjectUpdates lists the project fields UpdateProject changes; nil fields
+// are left as they are
+type ProjectUpdates struct {
+	Description *string
+	Path        *string
+	Tags        *[]string
+}
 
-		project.Tags = tags
-		if lastAccessed.Valid {
-			project.LastAccessedAt = &lastAccessed.Time
+// UpdateProject changes only the fields set in updates
+func (c *PostgresClient) UpdateProject(ctx context.Context, name string, updates ProjectUpdates) error {
+	args := pgx.NamedArgs{"name": name}
+	var sets []string
+
+	if updates.Description != nil {
+		sets = append(sets, "description = @description")
+		args["description"] = *updates.Description
+	}
+	if updates.Path != nil {
+		// Keep the old path for auditing when it actually changes
+		sets = append(sets,
+			"previous_path = CASE WHEN path IS DISTINCT FROM @path THEN path ELSE previous_path END",
+			"path = @path")
+		args["path"] = *updates.Path
+	}
+	if updates.Tags != nil {
+		tags := *updates.Tags
+		if tags == nil {
+			tags = []string{}
 		}
-		if archived.Valid {
-			project.ArchivedAt = &archived.Time
+		sets = append(sets, "tags = @tags")
+		args["tags"] = tags
+	}
+
+	if len(sets) == 0 {
+		return fmt.Errorf("no project fields to update")
+	}
+	sets = append(sets, "updated_at = NOW()")
+
+	query := fmt.Sprintf("UPDATE projects SET %s WHERE name = @name", strings.Join(sets, ", "))
+
+	tag, err := c.pool.Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("project not found: %s", name)
+	}
+	return nil
+}
+
+// UnarchiveProject returns an archived project to idle
+func (c *PostgresClient) UnarchiveProject(ctx context.Context, name string) error {
+	query := `
+		UPDATE projects
+		SET status = 'idle', archived_at = NULL
+		WHERE name = $1
+	`
+
+	tag, err := c.pool.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("project not found: %s", name)
+	}
+	return nil
+}
+
+// SetProjectHealthProbe configures a project's runner health probe. A nil
+// probe removes it.
+func (c *PostgresClient) SetProjectHealthProbe(ctx context.Context, name string, probe *types.HealthProbe) error {
+	var probeJSON []byte
+	if probe != nil {
+		var err error
+		if probeJSON, err = json.Marshal(probe); err != nil {
+			return fmt.Errorf("marshal health probe: %w", err)
 		}
+	}
+
+	tag, err := c.pool.Exec(ctx, "UPDATE projects SET health_probe = $2 WHERE name = $1", name, probeJSON)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("project not found: %s", name)
+	}
+	return nil
+}
 
-		projects = append(projects, &project)
+// GetProjectStats computes live aggregates for a project rather than relying
+// on the denormalised counter columns
+func (c *PostgresClient) GetProjectStats(ctx context.Context, projectName string) (*types.ProjectStats, error) {
+	query := `
+		SELECT
+		    (SELECT COUNT(*) FROM runners
+		     WHERE project_name = $1 AND status IN ('starting', 'running', 'paused')),
+		    (SELECT COALESCE(SUM(tokens_used), 0) FROM runners
+		     WHERE project_name = $1 AND status IN ('starting', 'running', 'paused')),
+		    (SELECT COUNT(*) FROM sessions
+		     WHERE project_name = $1 AND started_at >= NOW() - INTERVAL '7 days'),
+		    (SELECT COALESCE(AVG(EXTRACT(EPOCH FROM ended_at - started_at)), 0)::float8 FROM sessions
+		     WHERE project_name = $1 AND ended_at IS NOT NULL)
+	`
+
+	stats := types.ProjectStats{ProjectName: projectName}
+	err := c.pool.QueryRow(ctx, query, projectName).Scan(
+		&stats.ActiveRunners,
+		&stats.ActiveTokens,
+		&stats.SessionsThisWeek,
+		&stats.AvgSessionSeconds,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return projects, rows.Err()
+	return &stats, nil
 }
 
 // ===== RUNNERS WITH TRANSACTIONAL OUTBOX =====
 
-// CreateRunnerTx creates a runner and outbox event in a transaction
-func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRequest, quotaMax int) (*types.Runner, error) {
+// CreateRunnerTx creates a runner owned by daemon node nodeID and its
+// outbox event in a transaction, retrying on serialisation failures and
+// deadlocks. The project's quota caps its active runners and sets the
+// runner's heartbeat TTL.
+func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRequest, quota *types.ResourceQuota, nodeID string) (*types.Runner, error) {
+	var runner *types.Runner
+	err := c.WithRetry(func() error {
+		var err error
+		runner, err = c.createRunnerTx(ctx, req, quota, nodeID)
+		return err
+	}, defaultTxRetries)
+	return runner, err
+}
+
+func (c *PostgresClient) createRunnerTx(ctx context.Context, req *types.LaunchRequest, quota *types.ResourceQuota, nodeID string) (*types.Runner, error) {
 	tx, err := c.pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("begin transaction: %w", err)
@@ -195,9 +671,8 @@ func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRe
 	defer tx.Rollback(ctx)
 
 	// Acquire advisory lock per project to avoid race conditions
-	_, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hash_project($1))", req.ProjectName)
-	if err != nil {
-		return nil, fmt.Errorf("acquire lock: %w", err)
+	if err := c.AcquireAdvisoryLock(ctx, tx, "project:"+req.ProjectName); err != nil {
+		return nil, err
 	}
 
 	// Check quota
@@ -210,8 +685,13 @@ func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRe
 		return nil, fmt.Errorf("check quota: %w", err)
 	}
 
-	if activeCount >= quotaMax {
-		return nil, fmt.Errorf("quota exceeded: %d/%d runners active", activeCount, quotaMax)
+	if activeCount >= quota.MaxConcurrentRunners {
+		return nil, fmt.Errorf("%w: %d/%d runners active", ErrQuotaExceeded, activeCount, quota.MaxConcurrentRunners)
+	}
+
+	heartbeatTTL := DefaultHeartbeatTTLSeconds
+	if quota.HeartbeatTTLSeconds > 0 {
+		heartbeatTTL = quota.HeartbeatTTLSeconds
 	}
 
 	// Create runner
@@ -219,6 +699,7 @@ func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRe
 	runner := &types.Runner{
 		ID:                 runnerID,
 		RuntimeType:        req.RuntimeType,
+		NodeID:             nodeID,
 		ProjectName:        req.ProjectName,
 		ProjectPath:        req.ProjectPath,
 		Status:             types.StatusStarting,
@@ -228,7 +709,8 @@ func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRe
 		ConversationMode:   req.ConversationMode,
 		SessionID:          req.SessionID,
 		MaxRestartAttempts: 3,
-		HeartbeatTTL:       30,
+		MaxRuntimeSeconds:  req.MaxRuntimeSeconds,
+		HeartbeatTTL:       heartbeatTTL,
 		StartedAt:          time.Now(),
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
@@ -242,12 +724,13 @@ func (c *PostgresClient) CreateRunnerTx(ctx context.Context, req *types.LaunchRe
 		INSERT INTO runners (
 			id, runtime_type, runtime_id, project_name, project_path, status,
 			flags, capabilities, environment, conversation_mode, session_id,
-			max_restart_attempts, heartbeat_ttl_seconds, started_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			max_restart_attempts, heartbeat_ttl_seconds, started_at, node_id,
+			max_runtime_seconds
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16)
 	`, runnerID, runner.RuntimeType, "", runner.ProjectName, runner.ProjectPath,
 		runner.Status, flagsJSON, capsJSON, envJSON, runner.ConversationMode,
 		runner.SessionID, runner.MaxRestartAttempts, runner.HeartbeatTTL,
-		runner.StartedAt)
+		runner.StartedAt, nodeID, runner.MaxRuntimeSeconds)
 
 	if err != nil {
 		return nil, fmt.Errorf("insert runner: %w", err)
@@ -290,6 +773,14 @@ func (c *PostgresClient) UpdateRunnerRuntimeID(ctx context.Context, runnerID, ru
 	return err
 }
 
+// SetRunnerKillReason records why the daemon stopped a runner
+func (c *PostgresClient) SetRunnerKillReason(ctx context.Context, runnerID, reason string) error {
+	_, err := c.pool.Exec(ctx, `
+		UPDATE runners SET kill_reason = $1 WHERE id = $2
+	`, reason, runnerID)
+	return err
+}
+
 // UpdateRunnerStatus updates runner status
 func (c *PostgresClient) UpdateRunnerStatus(ctx context.Context, runnerID string, status types.RunnerStatus) error {
 	_, err := c.pool.Exec(ctx, `
@@ -298,58 +789,201 @@ func (c *PostgresClient) UpdateRunnerStatus(ctx context.Context, runnerID string
 	return err
 }
 
-// UpdateRunnerHeartbeat updates runner heartbeat and metrics
+// UpdateRunnerHeartbeat updates runner heartbeat and metrics, recording a
+// token usage event when the runner's token count has increased. A late
+// heartbeat must not bring a finished runner back, so it returns
+// ErrRunnerTerminated without writing anything when the runner's status is
+// terminal or the runner no longer exists.
 func (c *PostgresClient) UpdateRunnerHeartbeat(ctx context.Context, hb *types.Heartbeat) error {
-	_, err := c.pool.Exec(ctx, `
+	return c.WithRetry(func() error {
+		return c.updateRunnerHeartbeat(ctx, hb)
+	}, defaultTxRetries)
+}
+
+func (c *PostgresClient) updateRunnerHeartbeat(ctx context.Context, hb *types.Heartbeat) error {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var projectName string
+	var status types.RunnerStatus
+	var prevTokens sql.NullInt64
+	err = tx.QueryRow(ctx, `
+		SELECT project_name, status, tokens_used FROM runners WHERE id = $1 FOR UPDATE
+	`, hb.RunnerID).Scan(&projectName, &status, &prevTokens)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w: runner %s no longer exists", ErrRunnerTerminated, hb.RunnerID)
+		}
+		return fmt.Errorf("get previous tokens: %w", err)
+	}
+	if status == types.StatusTerminated || status == types.StatusFailed {
+		return fmt.Errorf("%w: runner %s is %s", ErrRunnerTerminated, hb.RunnerID, status)
+	}
+
+	var gpuMetrics []byte
+	if len(hb.GPUSamples) > 0 {
+		if gpuMetrics, err = json.Marshal(hb.GPUSamples); err != nil {
+			return fmt.Errorf("marshal gpu metrics: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
 		UPDATE runners 
 		SET last_heartbeat = $1, cpu_percent = $2, memory_mb = $3, 
-		    tokens_used = $4, status = $5, session_id = $6
-		WHERE id = $7
-	`, hb.Timestamp, hb.CPUPercent, hb.MemoryMB, hb.TokensUsed, hb.Status, hb.SessionID, hb.RunnerID)
+		    tokens_used = $4, status = $5, session_id = $6, gpu_metrics = $7
+		WHERE id = $8
+	`, hb.Timestamp, hb.CPUPercent, hb.MemoryMB, hb.TokensUsed, hb.Status, hb.SessionID, gpuMetrics, hb.RunnerID)
+	if err != nil {
+		return fmt.Errorf("update heartbeat: %w", err)
+	}
 
-	return err
+	if delta := hb.TokensUsed - prevTokens.Int64; delta > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO token_usage_events (
+				runner_id, project_name, session_id, timestamp, tokens_delta, scope
+			) VALUES ($1, $2, $3, $4, $5, 'runner')
+		`, hb.RunnerID, projectName, nullString(hb.SessionID), hb.Timestamp, delta)
+		if err != nil {
+			return fmt.Errorf("insert token usage event: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RunnerTerminated reports whether a runner has terminated or failed, or no
+// longer exists
+func (c *PostgresClient) RunnerTerminated(ctx context.Context, runnerID string) (bool, error) {
+	var status types.RunnerStatus
+	err := c.pool.QueryRow(ctx, `
+		SELECT status FROM runners WHERE id = $1
+	`, runnerID).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return status == types.StatusTerminated || status == types.StatusFailed, nil
 }
 
 // TerminateRunner marks a runner as terminated
 func (c *PostgresClient) TerminateRunner(ctx context.Context, runnerID string, exitCode int) error {
 	now := time.Now()
+	// A runner reconciliation already failed stays failed when its process
+	// is finally reaped
 	_, err := c.pool.Exec(ctx, `
 		UPDATE runners 
-		SET status = 'terminated', terminated_at = $1, exit_code = $2
+		SET status = CASE WHEN status = 'failed' THEN status ELSE 'terminated' END,
+		    terminated_at = $1, exit_code = $2
 		WHERE id = $3
 	`, now, exitCode, runnerID)
 
 	return err
 }
 
-// GetRunner retrieves a runner by ID
-func (c *PostgresClient) GetRunner(ctx context.Context, runnerID string) (*types.Runner, error) {
-	query := `
-		SELECT id, runtime_type, runtime_id, node_id, project_name, project_path,
-		       status, flags, capabilities, environment, session_id, conversation_mode,
-		       tokens_used, cpu_percent, memory_mb, restart_attempts, max_restart_attempts,
-		       started_at, last_heartbeat, heartbeat_ttl_seconds, terminated_at, exit_code,
-		       created_at, updated_at
-		FROM runners WHERE id = $1
-	`
-
-	var runner types.Runner
-	var flagsJSON, capsJSON, envJSON []byte
-	var nodeID, sessionID sql.NullString
-	var conversationMode sql.NullString
-	var cpuPercent sql.NullFloat64
-	var memoryMB, tokensUsed sql.NullInt64
-	var lastHeartbeat, terminatedAt sql.NullTime
-	var exitCode sql.NullInt32
+// GetStuckRunners returns runners that have been starting for longer than
+// startupTimeout, e.g. because their agent crashed before its first
+// heartbeat. Reconciliation by heartbeat misses them, as they never had one.
+func (c *PostgresClient) GetStuckRunners(ctx context.Context, startupTimeout time.Duration) ([]*types.Runner, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT id, runtime_type, runtime_id, node_id, project_name, status, started_at
+		FROM runners
+		WHERE status = 'starting' AND started_at < $1
+		ORDER BY started_at
+	`, time.Now().Add(-startupTimeout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	err := c.pool.QueryRow(ctx, query, runnerID).Scan(
-		&runner.ID, &runner.RuntimeType, &runner.RuntimeID, &nodeID,
-		&runner.ProjectName, &runner.ProjectPath, &runner.Status,
-		&flagsJSON, &capsJSON, &envJSON, &sessionID, &conversationMode,
-		&tokensUsed, &cpuPercent, &memoryMB,
+	var runners []*types.Runner
+	for rows.Next() {
+		var r types.Runner
+		var nodeID sql.NullString
+		if err := rows.Scan(&r.ID, &r.RuntimeType, &r.RuntimeID, &nodeID, &r.ProjectName, &r.Status, &r.StartedAt); err != nil {
+			return nil, err
+		}
+		r.NodeID = nodeID.String
+		runners = append(runners, &r)
+	}
+
+	return runners, rows.Err()
+}
+
+// FailStartingRunner marks a runner that is still starting as failed,
+// recording killReason. It reports false when the runner has left the
+// starting state in the meantime, e.g. because another daemon failed it.
+func (c *PostgresClient) FailStartingRunner(ctx context.Context, runnerID, killReason string) (bool, error) {
+	tag, err := c.pool.Exec(ctx, `
+		UPDATE runners
+		SET status = 'failed', terminated_at = NOW(), kill_reason = $2
+		WHERE id = $1 AND status = 'starting'
+	`, runnerID, killReason)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// BulkTerminateRunners marks many runners as terminated in a single query,
+// recording each one's exit code from exitCodes, keyed by runner ID.
+// Runners that already have a termination time keep their recorded exit
+// code, and, as in TerminateRunner, runners reconciliation failed stay
+// failed.
+func (c *PostgresClient) BulkTerminateRunners(ctx context.Context, exitCodes map[string]int) error {
+	if len(exitCodes) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(exitCodes))
+	codes := make([]int32, 0, len(exitCodes))
+	for id, code := range exitCodes {
+		ids = append(ids, id)
+		codes = append(codes, int32(code))
+	}
+
+	_, err := c.pool.Exec(ctx, `
+		UPDATE runners r
+		SET status = CASE WHEN r.status = 'failed' THEN r.status ELSE 'terminated' END,
+		    terminated_at = $1, exit_code = e.exit_code
+		FROM unnest($2::uuid[], $3::int[]) AS e(id, exit_code)
+		WHERE r.id = e.id AND r.terminated_at IS NULL
+	`, time.Now(), ids, codes)
+	return err
+}
+
+// GetRunner retrieves a runner by ID
+func (c *PostgresClient) GetRunner(ctx context.Context, runnerID string) (*types.Runner, error) {
+	query := `
+		SELECT id, runtime_type, runtime_id, node_id, project_name, project_path,
+		       status, flags, capabilities, environment, session_id, conversation_mode,
+		       tokens_used, cpu_percent, memory_mb, restart_attempts, max_restart_attempts,
+		       started_at, last_heartbeat, heartbeat_ttl_seconds, terminated_at, exit_code,
+		       max_runtime_seconds, kill_reason, created_at, updated_at
+		FROM runners WHERE id = $1
+	`
+
+	var runner types.Runner
+	var flagsJSON, capsJSON, envJSON []byte
+	var nodeID, sessionID, killReason sql.NullString
+	var conversationMode sql.NullString
+	var cpuPercent sql.NullFloat64
+	var memoryMB, tokensUsed sql.NullInt64
+	var lastHeartbeat, terminatedAt sql.NullTime
+	var exitCode sql.NullInt32
+
+	err := c.pool.QueryRow(ctx, query, runnerID).Scan(
+		&runner.ID, &runner.RuntimeType, &runner.RuntimeID, &nodeID,
+		&runner.ProjectName, &runner.ProjectPath, &runner.Status,
+		&flagsJSON, &capsJSON, &envJSON, &sessionID, &conversationMode,
+		&tokensUsed, &cpuPercent, &memoryMB,
 		&runner.RestartAttempts, &runner.MaxRestartAttempts,
 		&runner.StartedAt, &lastHeartbeat, &runner.HeartbeatTTL,
-		&terminatedAt, &exitCode, &runner.CreatedAt, &runner.UpdatedAt,
+		&terminatedAt, &exitCode, &runner.MaxRuntimeSeconds, &killReason,
+		&runner.CreatedAt, &runner.UpdatedAt,
 	)
 
 	if err != nil {
@@ -391,6 +1025,9 @@ func (c *PostgresClient) GetRunner(ctx context.Context, runnerID string) (*types
 		ec := int(exitCode.Int32)
 		runner.ExitCode = &ec
 	}
+	if killReason.Valid {
+		runner.KillReason = killReason.String
+	}
 
 	return &runner, nil
 }
@@ -431,28 +1068,430 @@ func (c *PostgresClient) GetActiveRunners(ctx context.Context, projectName strin
 	return runners, rows.Err()
 }
 
-// ReconcileStaleRunners marks stale runners as failed
-func (c *PostgresClient) ReconcileStaleRunners(ctx context.Context, ttlSeconds int) ([]string, error) {
+// StatusCount is the number of runners in one status and their combined
+// resource usage
+type StatusCount struct {
+	Count      int
+	CPUPercent float64
+	MemoryMB   int64
+	TokensUsed int64
+}
+
+// RunnerMetricsSummary aggregates active runners without loading them
+type RunnerMetricsSummary struct {
+	ByStatus        map[types.RunnerStatus]StatusCount
+	TotalCPU        float64
+	TotalMemoryMB   int64
+	TotalTokensUsed int64
+}
+
+// Total returns the number of runners across all statuses
+func (s *RunnerMetricsSummary) Total() int {
+	total := 0
+	for _, sc := range s.ByStatus {
+		total += sc.Count
+	}
+	return total
+}
+
+// GetRunnerMetricsSummary counts active runners by status and sums their
+// resource usage in a single query, for dashboards that don't need the
+// runners themselves. Failed runners are excluded along with terminated
+// ones since they never leave that state.
+func (c *PostgresClient) GetRunnerMetricsSummary(ctx context.Context) (*RunnerMetricsSummary, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT status, COUNT(*),
+		       COALESCE(SUM(cpu_percent), 0)::float8,
+		       COALESCE(SUM(memory_mb), 0)::bigint,
+		       COALESCE(SUM(tokens_used), 0)::bigint
+		FROM runners
+		WHERE status NOT IN ('terminated', 'failed')
+		GROUP BY status
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &RunnerMetricsSummary{
+		ByStatus: make(map[types.RunnerStatus]StatusCount),
+	}
+	for rows.Next() {
+		var status types.RunnerStatus
+		var sc StatusCount
+		if err := rows.Scan(&status, &sc.Count, &sc.CPUPercent, &sc.MemoryMB, &sc.TokensUsed); err != nil {
+			return nil, err
+		}
+		summary.ByStatus[status] = sc
+		summary.TotalCPU += sc.CPUPercent
+		summary.TotalMemoryMB += sc.MemoryMB
+		summary.TotalTokensUsed += sc.TokensUsed
+	}
+
+	return summary, rows.Err()
+}
+
+// RunnerFilter narrows GetAllActiveRunners; empty fields match everything.
+// When Status is empty only active (starting, running, paused) runners match.
+type RunnerFilter struct {
+	ProjectName string
+	Status      types.RunnerStatus
+	NodeID      string
+	RuntimeType types.RuntimeType
+}
+
+// GetAllActiveRunners lists runners matching filter, across all projects
+// unless it names one
+func (c *PostgresClient) GetAllActiveRunners(ctx context.Context, filter RunnerFilter) ([]*types.Runner, error) {
+	query := `
+		SELECT id, runtime_type, runtime_id, node_id, project_name, project_path,
+		       status, session_id, tokens_used, cpu_percent, memory_mb,
+		       started_at, last_heartbeat
+		FROM runners
+	`
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	} else {
+		conditions = append(conditions, "status IN ('starting', 'running', 'paused')")
+	}
+	if filter.ProjectName != "" {
+		addCondition("project_name = $%d", filter.ProjectName)
+	}
+	if filter.NodeID != "" {
+		addCondition("node_id = $%d", filter.NodeID)
+	}
+	if filter.RuntimeType != "" {
+		addCondition("runtime_type = $%d", filter.RuntimeType)
+	}
+
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	query += " ORDER BY started_at DESC"
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runners []*types.Runner
+	for rows.Next() {
+		var r types.Runner
+		var nodeID, sessionID sql.NullString
+		var cpuPercent sql.NullFloat64
+		var memoryMB, tokensUsed sql.NullInt64
+		var lastHeartbeat sql.NullTime
+
+		err := rows.Scan(&r.ID, &r.RuntimeType, &r.RuntimeID, &nodeID,
+			&r.ProjectName, &r.ProjectPath, &r.Status, &sessionID,
+			&tokensUsed, &cpuPercent, &memoryMB, &r.StartedAt, &lastHeartbeat)
+		if err != nil {
+			return nil, err
+		}
+
+		r.NodeID = nodeID.String
+		r.SessionID = sessionID.String
+		r.TokensUsed = tokensUsed.Int64
+		r.CPUPercent = cpuPercent.Float64
+		r.MemoryMB = memoryMB.Int64
+		if lastHeartbeat.Valid {
+			r.LastHeartbeat = &lastHeartbeat.Time
+		}
+
+		runners = append(runners, &r)
+	}
+
+	return runners, rows.Err()
+}
+
+// RunnerHistoryFilter narrows GetRunnerHistory; zero fields match
+// everything. Since and Until bound started_at.
+type RunnerHistoryFilter struct {
+	ProjectName string
+	Status      types.RunnerStatus
+	Since       time.Time
+	Until       time.Time
+	Limit       int
+}
+
+// GetRunnerHistory lists runners in any state, most recently started first
+func (c *PostgresClient) GetRunnerHistory(ctx context.Context, filter RunnerHistoryFilter) ([]*types.Runner, error) {
+	query := `
+		SELECT id, runtime_type, runtime_id, node_id, project_name, project_path,
+		       status, session_id, tokens_used, cpu_percent, memory_mb,
+		       started_at, last_heartbeat, terminated_at, exit_code
+		FROM runners
+	`
+
+	var conditions []string
+	var args []interface{}
+	addCondition := func(clause string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(clause, len(args)))
+	}
+
+	if filter.ProjectName != "" {
+		addCondition("project_name = $%d", filter.ProjectName)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("started_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCondition("started_at < $%d", filter.Until)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at DESC"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runners []*types.Runner
+	for rows.Next() {
+		var r types.Runner
+		var nodeID, sessionID sql.NullString
+		var cpuPercent sql.NullFloat64
+		var memoryMB, tokensUsed sql.NullInt64
+		var lastHeartbeat, terminatedAt sql.NullTime
+		var exitCode sql.NullInt32
+
+		err := rows.Scan(&r.ID, &r.RuntimeType, &r.RuntimeID, &nodeID,
+			&r.ProjectName, &r.ProjectPath, &r.Status, &sessionID,
+			&tokensUsed, &cpuPercent, &memoryMB, &r.StartedAt, &lastHeartbeat,
+			&terminatedAt, &exitCode)
+		if err != nil {
+			return nil, err
+		}
+
+		r.NodeID = nodeID.String
+		r.SessionID = sessionID.String
+		r.TokensUsed = tokensUsed.Int64
+		r.CPUPercent = cpuPercent.Float64
+		r.MemoryMB = memoryMB.Int64
+		if lastHeartbeat.Valid {
+			r.LastHeartbeat = &lastHeartbeat.Time
+		}
+		if terminatedAt.Valid {
+			r.TerminatedAt = &terminatedAt.Time
+		}
+		if exitCode.Valid {
+			ec := int(exitCode.Int32)
+			r.ExitCode = &ec
+		}
+
+		runners = append(runners, &r)
+	}
+
+	return runners, rows.Err()
+}
+
+// StaleRunner is a runner marked failed for missing heartbeats
+type StaleRunner struct {
+	ID         string
+	TTLSeconds int // the heartbeat TTL it exceeded
+}
+
+// ReconcileStaleRunners marks runners failed once their last heartbeat is
+// older than their project's heartbeat TTL, or defaultTTLSeconds for
+// projects without one
+func (c *PostgresClient) ReconcileStaleRunners(ctx context.Context, defaultTTLSeconds int) ([]StaleRunner, error) {
+	query := `
+		WITH ttls AS (
+			SELECT r.id, COALESCE(rq.heartbeat_ttl_seconds, $1) AS ttl_seconds
+			FROM runners r
+			LEFT JOIN resource_quotas rq ON rq.project_name = r.project_name
+			WHERE r.status IN ('starting', 'running')
+		)
+		UPDATE runners
+		SET status = 'failed', terminated_at = NOW()
+		FROM ttls
+		WHERE runners.id = ttls.id
+		  AND runners.status IN ('starting', 'running')
+		  AND runners.last_heartbeat < NOW() - make_interval(secs => ttls.ttl_seconds)
+		RETURNING runners.id, ttls.ttl_seconds
+	`
+
+	rows, err := c.pool.Query(ctx, query, defaultTTLSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stale []StaleRunner
+	for rows.Next() {
+		var r StaleRunner
+		if err := rows.Scan(&r.ID, &r.TTLSeconds); err != nil {
+			return nil, err
+		}
+		stale = append(stale, r)
+	}
+
+	return stale, rows.Err()
+}
+
+// ===== RUNNER EVENTS =====
+
+// InsertRunnerEvent records a runner lifecycle event
+func (c *PostgresClient) InsertRunnerEvent(ctx context.Context, event *types.Event) error {
+	dataJSON, _ := json.Marshal(event.Data)
+	metadataJSON, _ := json.Marshal(event.Metadata)
+
+	entityType := event.EntityType
+	if entityType == "" {
+		entityType = "runner"
+	}
+	timestamp := event.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return c.pool.QueryRow(ctx, `
+		INSERT INTO runner_events (
+			timestamp, event_type, entity_type, entity_id, data, metadata,
+			user_id, hostname, trace_id, signature
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, event_id
+	`, timestamp, event.EventType, entityType, event.EntityID, dataJSON, metadataJSON,
+		nullString(event.UserID), nullString(event.Hostname),
+		nullString(event.TraceID), nullString(event.Signature),
+	).Scan(&event.ID, &event.EventID)
+}
+
+// RecordRunnerRestartEvent stores one restart of a runner
+func (c *PostgresClient) RecordRunnerRestartEvent(ctx context.Context, restart *types.RunnerRestart) error {
+	var exitCode sql.NullInt32
+	if restart.PreviousExitCode != nil {
+		exitCode = sql.NullInt32{Int32: int32(*restart.PreviousExitCode), Valid: true}
+	}
+
+	_, err := c.pool.Exec(ctx, `
+		INSERT INTO runner_restarts (runner_id, attempt, restarted_at, previous_exit_code, delay_seconds)
+		VALUES ($1, $2, $3, $4, $5)
+	`, restart.RunnerID, restart.Attempt, restart.RestartedAt, exitCode, restart.DelaySeconds)
+
+	return err
+}
+
+// GetRunnerRestarts returns a runner's restarts in the order they happened
+func (c *PostgresClient) GetRunnerRestarts(ctx context.Context, runnerID string) ([]types.RunnerRestart, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT runner_id, attempt, restarted_at, previous_exit_code, delay_seconds
+		FROM runner_restarts
+		WHERE runner_id = $1
+		ORDER BY restarted_at, id
+	`, runnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var restarts []types.RunnerRestart
+	for rows.Next() {
+		var r types.RunnerRestart
+		var exitCode sql.NullInt32
+
+		if err := rows.Scan(&r.RunnerID, &r.Attempt, &r.RestartedAt, &exitCode, &r.DelaySeconds); err != nil {
+			return nil, err
+		}
+		if exitCode.Valid {
+			code := int(exitCode.Int32)
+			r.PreviousExitCode = &code
+		}
+
+		restarts = append(restarts, r)
+	}
+
+	return restarts, rows.Err()
+}
+
+// ListRunnerEvents returns the lifecycle events for a runner in chronological order
+func (c *PostgresClient) ListRunnerEvents(ctx context.Context, runnerID string) ([]*types.Event, error) {
+	query := `
+		SELECT id, event_id, timestamp, event_type, entity_type, entity_id,
+		       data, metadata, user_id, hostname, trace_id, signature
+		FROM runner_events
+		WHERE entity_id = $1
+		ORDER BY timestamp, id
+	`
+
+	rows, err := c.pool.Query(ctx, query, runnerID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRunnerEvents(rows)
+}
+
+// ListProjectRunnerEvents returns the most recent lifecycle events of a
+// project's runners, newest first. Events of runners whose rows have been
+// removed are not included.
+func (c *PostgresClient) ListProjectRunnerEvents(ctx context.Context, projectName string, limit int) ([]*types.Event, error) {
 	query := `
-		SELECT reconcile_stale_runners($1)
+		SELECT e.id, e.event_id, e.timestamp, e.event_type, e.entity_type, e.entity_id,
+		       e.data, e.metadata, e.user_id, e.hostname, e.trace_id, e.signature
+		FROM runner_events e
+		JOIN runners r ON r.id::text = e.entity_id
+		WHERE r.project_name = $1
+		ORDER BY e.timestamp DESC, e.id DESC
+		LIMIT $2
 	`
 
-	rows, err := c.pool.Query(ctx, query, ttlSeconds)
+	rows, err := c.pool.Query(ctx, query, projectName, limit)
 	if err != nil {
 		return nil, err
 	}
+	return scanRunnerEvents(rows)
+}
+
+// scanRunnerEvents reads runner_events rows selected in the column order
+// used by ListRunnerEvents, closing rows
+func scanRunnerEvents(rows pgx.Rows) ([]*types.Event, error) {
 	defer rows.Close()
 
-	var failedIDs []string
+	var events []*types.Event
 	for rows.Next() {
-		var id, unused string
-		if err := rows.Scan(&id, &unused); err != nil {
+		var e types.Event
+		var dataJSON, metadataJSON []byte
+		var userID, hostname, traceID, signature sql.NullString
+
+		err := rows.Scan(
+			&e.ID, &e.EventID, &e.Timestamp, &e.EventType, &e.EntityType, &e.EntityID,
+			&dataJSON, &metadataJSON, &userID, &hostname, &traceID, &signature,
+		)
+		if err != nil {
 			return nil, err
 		}
-		failedIDs = append(failedIDs, id)
+
+		json.Unmarshal(dataJSON, &e.Data)
+		json.Unmarshal(metadataJSON, &e.Metadata)
+
+		e.UserID = userID.String
+		e.Hostname = hostname.String
+		e.TraceID = traceID.String
+		e.Signature = signature.String
+
+		events = append(events, &e)
 	}
 
-	return failedIDs, rows.Err()
+	return events, rows.Err()
 }
 
 // ===== OUTBOX =====
@@ -531,56 +1570,317 @@ func (c *PostgresClient) IncrementOutboxAttempts(ctx context.Context, id int64,
 	return err
 }
 
-// ===== RESOURCE QUOTAS =====
+// CleanDeliveredOutboxEntries deletes delivered outbox entries older than before
+// and returns the number of rows removed
+func (c *PostgresClient) CleanDeliveredOutboxEntries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := c.pool.Exec(ctx, `
+		DELETE FROM outbox
+		WHERE delivered = true AND delivered_at < $1
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
 
-// GetResourceQuota retrieves resource quota for a project
-func (c *PostgresClient) GetResourceQuota(ctx context.Context, projectName string) (*types.ResourceQuota, error) {
-	query := `
-		SELECT project_name, max_concurrent_runners, max_memory_mb, max_cpu_percent, max_tokens_per_day
-		FROM resource_quotas
-		WHERE project_name = $1
-	`
+// outboxPartitionPrefix names monthly outbox partitions, e.g. outbox_p2026_03
+const outboxPartitionPrefix = "outbox_p"
 
-	var quota types.ResourceQuota
-	var maxMemory, maxTokens sql.NullInt64
-	var maxCPU sql.NullInt32
+// CreateOutboxPartition creates the outbox partition for the month containing
+// month, if it does not already exist
+func (c *PostgresClient) CreateOutboxPartition(ctx context.Context, month time.Time) error {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	name := outboxPartitionPrefix + start.Format("2006_01")
 
-	err := c.pool.QueryRow(ctx, query, projectName).Scan(
-		&quota.ProjectName, &quota.MaxConcurrentRunners,
-		&maxMemory, &maxCPU, &maxTokens,
-	)
+	// DDL can't take bind parameters; every value here is generated above
+	_, err := c.pool.Exec(ctx, fmt.Sprintf(
+		`CREATE TABLE IF NOT EXISTS %s PARTITION OF outbox FOR VALUES FROM ('%s') TO ('%s')`,
+		name, start.Format("2006-01-02"), end.Format("2006-01-02")))
+	if err != nil {
+		return fmt.Errorf("create partition %s: %w", name, err)
+	}
+	return nil
+}
 
+// DropOutboxPartitionsBefore drops monthly outbox partitions that end on or
+// before cutoff and returns their names. Partitions still holding undelivered
+// entries are kept.
+func (c *PostgresClient) DropOutboxPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = 'outbox'
+	`)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			// Return default quota
-			return &types.ResourceQuota{
-				ProjectName:          projectName,
-				MaxConcurrentRunners: 5,
-			}, nil
-		}
-		return nil, err
+		return nil, fmt.Errorf("list partitions: %w", err)
 	}
 
-	if maxMemory.Valid {
-		quota.MaxMemoryMB = maxMemory.Int64
+	var expired []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		suffix, ok := strings.CutPrefix(name, outboxPartitionPrefix)
+		if !ok {
+			continue
+		}
+		month, err := time.Parse("2006_01", suffix)
+		if err != nil {
+			continue // not one of ours
+		}
+		if !month.AddDate(0, 1, 0).After(cutoff) {
+			expired = append(expired, name)
+		}
 	}
-	if maxCPU.Valid {
-		quota.MaxCPUPercent = int(maxCPU.Int32)
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	if maxTokens.Valid {
-		quota.MaxTokensPerDay = maxTokens.Int64
+
+	var dropped []string
+	for _, name := range expired {
+		var pending bool
+		err := c.pool.QueryRow(ctx, fmt.Sprintf(
+			`SELECT EXISTS (SELECT 1 FROM %s WHERE delivered = false)`, name)).Scan(&pending)
+		if err != nil {
+			return dropped, fmt.Errorf("check partition %s: %w", name, err)
+		}
+		if pending {
+			c.logger.Warn("keeping expired outbox partition with undelivered entries",
+				zap.String("partition", name))
+			continue
+		}
+
+		if _, err := c.pool.Exec(ctx, fmt.Sprintf(`DROP TABLE %s`, name)); err != nil {
+			return dropped, fmt.Errorf("drop partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
 	}
 
-	return &quota, nil
+	return dropped, nil
+}
+
+// ===== PRESETS =====
+
+// CreatePreset stores a reusable launch configuration
+func (c *PostgresClient) CreatePreset(ctx context.Context, preset *types.Preset) error {
+	flagsJSON, _ := json.Marshal(preset.Flags)
+	capsJSON, _ := json.Marshal(preset.Capabilities)
+	envJSON, _ := json.Marshal(preset.Environment)
+
+	query := `
+		INSERT INTO presets (name, project_name, runtime_type, flags, capabilities,
+		                     environment, conversation_mode, description)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := c.pool.Exec(ctx, query,
+		preset.Name,
+		nullString(preset.ProjectName),
+		nullString(string(preset.RuntimeType)),
+		flagsJSON,
+		capsJSON,
+		envJSON,
+		nullString(string(preset.ConversationMode)),
+		preset.Description,
+	)
+
+	return err
+}
+
+// GetPreset retrieves a preset by name
+func (c *PostgresClient) GetPreset(ctx context.Context, name string) (*types.Preset, error) {
+	query := `
+		SELECT name, project_name, runtime_type, flags, capabilities, environment,
+		       conversation_mode, description, created_at, updated_at
+		FROM presets
+		WHERE name = $1
+	`
+
+	preset, err := scanPreset(c.pool.QueryRow(ctx, query, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("preset not found: %s", name)
+		}
+		return nil, err
+	}
+
+	return preset, nil
+}
+
+// ListPresets lists presets usable by a project (its own plus global ones).
+// An empty project name lists every preset.
+func (c *PostgresClient) ListPresets(ctx context.Context, projectName string) ([]*types.Preset, error) {
+	query := `
+		SELECT name, project_name, runtime_type, flags, capabilities, environment,
+		       conversation_mode, description, created_at, updated_at
+		FROM presets
+	`
+
+	args := []interface{}{}
+	if projectName != "" {
+		query += " WHERE project_name = $1 OR project_name IS NULL"
+		args = append(args, projectName)
+	}
+
+	query += " ORDER BY name"
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var presets []*types.Preset
+	for rows.Next() {
+		preset, err := scanPreset(rows)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+
+	return presets, rows.Err()
+}
+
+// DeletePreset removes a preset by name
+func (c *PostgresClient) DeletePreset(ctx context.Context, name string) error {
+	tag, err := c.pool.Exec(ctx, `DELETE FROM presets WHERE name = $1`, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("preset not found: %s", name)
+	}
+	return nil
+}
+
+func scanPreset(row pgx.Row) (*types.Preset, error) {
+	var preset types.Preset
+	var flagsJSON, capsJSON, envJSON []byte
+	var projectName, runtimeType, conversationMode, description sql.NullString
+
+	err := row.Scan(
+		&preset.Name, &projectName, &runtimeType,
+		&flagsJSON, &capsJSON, &envJSON,
+		&conversationMode, &description,
+		&preset.CreatedAt, &preset.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal(flagsJSON, &preset.Flags)
+	json.Unmarshal(capsJSON, &preset.Capabilities)
+	json.Unmarshal(envJSON, &preset.Environment)
+
+	preset.ProjectName = projectName.String
+	preset.RuntimeType = types.RuntimeType(runtimeType.String)
+	preset.ConversationMode = types.ConversationMode(conversationMode.String)
+	preset.Description = description.String
+
+	return &preset, nil
+}
+
+// ===== RESOURCE QUOTAS =====
+
+// DefaultMaxConcurrentRunners is the runner quota of projects without one
+const DefaultMaxConcurrentRunners = 5
+
+// DefaultHeartbeatTTLSeconds is the heartbeat TTL of runners whose project
+// quota doesn't set one and no daemon default was applied
+const DefaultHeartbeatTTLSeconds = 30
+
+const upsertResourceQuotaQuery = `
+	INSERT INTO resource_quotas (
+		project_name, max_concurrent_runners, max_memory_mb, max_cpu_percent,
+		max_tokens_per_day, max_cpus_per_runner, max_runtime_seconds, heartbeat_ttl_seconds
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	ON CONFLICT (project_name) DO UPDATE SET
+		max_concurrent_runners = EXCLUDED.max_concurrent_runners,
+		max_memory_mb = EXCLUDED.max_memory_mb,
+		max_cpu_percent = EXCLUDED.max_cpu_percent,
+		max_tokens_per_day = EXCLUDED.max_tokens_per_day,
+		max_cpus_per_runner = EXCLUDED.max_cpus_per_runner,
+		max_runtime_seconds = EXCLUDED.max_runtime_seconds,
+		heartbeat_ttl_seconds = EXCLUDED.heartbeat_ttl_seconds
+`
+
+// UpsertResourceQuota creates or replaces a project's resource quota. Zero
+// optional limits are stored as NULL (unlimited).
+func (c *PostgresClient) UpsertResourceQuota(ctx context.Context, quota *types.ResourceQuota) error {
+	_, err := c.pool.Exec(ctx, upsertResourceQuotaQuery, quota.ProjectName, quota.MaxConcurrentRunners,
+		nullInt64(quota.MaxMemoryMB), nullInt64(int64(quota.MaxCPUPercent)), nullInt64(quota.MaxTokensPerDay),
+		nullInt64(int64(quota.MaxCPUsPerRunner)), nullInt64(int64(quota.MaxRuntimeSeconds)),
+		nullInt64(int64(quota.HeartbeatTTLSeconds)))
+	return err
+}
+
+// GetResourceQuota retrieves resource quota for a project
+func (c *PostgresClient) GetResourceQuota(ctx context.Context, projectName string) (*types.ResourceQuota, error) {
+	query := `
+		SELECT project_name, max_concurrent_runners, max_memory_mb, max_cpu_percent, max_tokens_per_day,
+		       max_cpus_per_runner, max_runtime_seconds, heartbeat_ttl_seconds
+		FROM resource_quotas
+		WHERE project_name = $1
+	`
+
+	var quota types.ResourceQuota
+	var maxMemory, maxTokens sql.NullInt64
+	var maxCPU, maxCPUs, maxRuntime, heartbeatTTL sql.NullInt32
+
+	err := c.pool.QueryRow(ctx, query, projectName).Scan(
+		&quota.ProjectName, &quota.MaxConcurrentRunners,
+		&maxMemory, &maxCPU, &maxTokens, &maxCPUs, &maxRuntime, &heartbeatTTL,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Return default quota
+			return &types.ResourceQuota{
+				ProjectName:          projectName,
+				MaxConcurrentRunners: DefaultMaxConcurrentRunners,
+			}, nil
+		}
+		return nil, err
+	}
+
+	if maxMemory.Valid {
+		quota.MaxMemoryMB = maxMemory.Int64
+	}
+	if maxCPU.Valid {
+		quota.MaxCPUPercent = int(maxCPU.Int32)
+	}
+	if maxTokens.Valid {
+		quota.MaxTokensPerDay = maxTokens.Int64
+	}
+	if maxCPUs.Valid {
+		quota.MaxCPUsPerRunner = int(maxCPUs.Int32)
+	}
+	if maxRuntime.Valid {
+		quota.MaxRuntimeSeconds = int(maxRuntime.Int32)
+	}
+	if heartbeatTTL.Valid {
+		quota.HeartbeatTTLSeconds = int(heartbeatTTL.Int32)
+	}
+
+	return &quota, nil
 }
 
 // ===== SESSIONS =====
 
-// CreateSession creates a new session
+// CreateSession creates a new session, with its transcript metadata if
+// the transcript is already stored
 func (c *PostgresClient) CreateSession(ctx context.Context, session *types.Session) error {
 	query := `
-		INSERT INTO sessions (id, runner_id, project_name, started_at, resumable)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO sessions (id, runner_id, project_name, started_at, resumable, resumed_from, message_count,
+		                      transcript_s3_key, transcript_size_bytes, compressed, uncompressed_size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := c.pool.Exec(ctx, query,
@@ -589,6 +1889,12 @@ func (c *PostgresClient) CreateSession(ctx context.Context, session *types.Sessi
 		session.ProjectName,
 		session.StartedAt,
 		session.Resumable,
+		nullString(session.ResumedFrom),
+		session.MessageCount,
+		nullString(session.TranscriptS3Key),
+		nullInt64(session.TranscriptSizeBytes),
+		session.TranscriptCompressed,
+		nullInt64(session.UncompressedSizeBytes),
 	)
 
 	return err
@@ -597,19 +1903,219 @@ func (c *PostgresClient) CreateSession(ctx context.Context, session *types.Sessi
 // GetSession retrieves a session by ID
 func (c *PostgresClient) GetSession(ctx context.Context, sessionID string) (*types.Session, error) {
 	query := `
-		SELECT id, runner_id, project_name, started_at, ended_at, last_message_at,
-		       message_count, tokens_used, resumable, resumed_from, summary,
-		       transcript_s3_key, transcript_size_bytes, created_at
+		SELECT ` + sessionColumns + `
 		FROM sessions
 		WHERE id = $1
 	`
 
+	session, err := scanSession(c.pool.QueryRow(ctx, query, sessionID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("session not found: %s", sessionID)
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListSessions lists the most recent sessions, optionally for one project
+func (c *PostgresClient) ListSessions(ctx context.Context, projectName string, limit int) ([]*types.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+	`
+
+	args := []interface{}{}
+	if projectName != "" {
+		query += " WHERE project_name = $1"
+		args = append(args, projectName)
+	}
+
+	query += " ORDER BY started_at DESC"
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*types.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// GetSessionsByTimeRange returns sessions started within [from, to], oldest
+// first, along with the total tokens they used. An empty projectName covers
+// all projects; includeActive adds sessions that have not ended yet.
+func (c *PostgresClient) GetSessionsByTimeRange(ctx context.Context, from, to time.Time, projectName string, includeActive bool) ([]*types.Session, int64, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE started_at BETWEEN $1 AND $2
+		  AND ($3 = '' OR project_name = $3)
+	`
+	if !includeActive {
+		query += " AND ended_at IS NOT NULL"
+	}
+	query += " ORDER BY started_at"
+
+	rows, err := c.pool.Query(ctx, query, from, to, projectName)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var sessions []*types.Session
+	var totalTokens int64
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		sessions = append(sessions, session)
+		totalTokens += session.TokensUsed
+	}
+
+	return sessions, totalTokens, rows.Err()
+}
+
+// SearchSessions runs a full-text search over session summaries, best
+// matches first. Each result's Snippet holds the matching summary excerpt
+// with matches wrapped in <b></b>.
+func (c *PostgresClient) SearchSessions(ctx context.Context, query string, projectName string, limit int) ([]*types.Session, error) {
+	sqlQuery := `
+		SELECT ` + sessionColumns + `,
+		       ts_headline('english', summary, q, 'MaxFragments=1, MaxWords=20, MinWords=8')
+		FROM sessions, websearch_to_tsquery('english', $1) q
+		WHERE to_tsvector('english', summary) @@ q
+	`
+
+	args := []interface{}{query}
+	if projectName != "" {
+		args = append(args, projectName)
+		sqlQuery += fmt.Sprintf(" AND project_name = $%d", len(args))
+	}
+
+	sqlQuery += " ORDER BY ts_rank(to_tsvector('english', summary), q) DESC, started_at DESC"
+	if limit > 0 {
+		args = append(args, limit)
+		sqlQuery += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := c.pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*types.Session
+	for rows.Next() {
+		var snippet string
+		session, err := scanSession(rows, &snippet)
+		if err != nil {
+			return nil, err
+		}
+		session.Snippet = snippet
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// PinSession protects a session from retention cleanup
+func (c *PostgresClient) PinSession(ctx context.Context, sessionID string) error {
+	return c.setSessionPinned(ctx, sessionID, true)
+}
+
+// UnpinSession makes a session eligible for retention cleanup again
+func (c *PostgresClient) UnpinSession(ctx context.Context, sessionID string) error {
+	return c.setSessionPinned(ctx, sessionID, false)
+}
+
+func (c *PostgresClient) setSessionPinned(ctx context.Context, sessionID string, pinned bool) error {
+	tag, err := c.pool.Exec(ctx, `
+		UPDATE sessions SET pinned = $1 WHERE id = $2
+	`, pinned, sessionID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("session not found: %s", sessionID)
+	}
+	return nil
+}
+
+// DeleteExpiredSessions removes ended, unpinned sessions that ended before
+// the cutoff. Returns the number of sessions removed.
+func (c *PostgresClient) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := c.pool.Exec(ctx, `
+		DELETE FROM sessions
+		WHERE ended_at IS NOT NULL AND ended_at < $1 AND pinned = false
+	`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// cleanableSessionsWhere matches ended sessions that can't be resumed and
+// have no stored transcript, for CleanSessions and CountCleanableSessions.
+// Pinned sessions are always kept.
+const cleanableSessionsWhere = `
+		WHERE resumable = false AND ended_at < $1
+		  AND transcript_s3_key IS NULL AND pinned = false
+		  AND ($2 = '' OR project_name = $2)`
+
+// CleanSessions deletes non-resumable sessions that ended before the cutoff,
+// optionally only a project's. Sessions with a stored transcript are kept.
+// Returns the number of sessions removed and the tokens they had used.
+func (c *PostgresClient) CleanSessions(ctx context.Context, before time.Time, projectName string) (deleted int64, freedTokenCount int64, err error) {
+	err = c.pool.QueryRow(ctx, `
+		WITH removed AS (
+			DELETE FROM sessions`+cleanableSessionsWhere+`
+			RETURNING tokens_used
+		)
+		SELECT COUNT(*), COALESCE(SUM(tokens_used), 0)::bigint FROM removed
+	`, before, projectName).Scan(&deleted, &freedTokenCount)
+	return deleted, freedTokenCount, err
+}
+
+// CountCleanableSessions reports what CleanSessions would remove without
+// deleting anything
+func (c *PostgresClient) CountCleanableSessions(ctx context.Context, before time.Time, projectName string) (count int64, tokens int64, err error) {
+	err = c.pool.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(tokens_used), 0)::bigint
+		FROM sessions`+cleanableSessionsWhere,
+		before, projectName).Scan(&count, &tokens)
+	return count, tokens, err
+}
+
+// sessionColumns is the column list scanned by scanSession
+const sessionColumns = `id, runner_id, project_name, started_at, ended_at, last_message_at,
+		       message_count, tokens_used, resumable, resumed_from, summary,
+		       transcript_s3_key, transcript_size_bytes, compressed, uncompressed_size_bytes,
+		       pinned, created_at`
+
+// scanSession scans sessionColumns, followed by any extra destinations
+func scanSession(row pgx.Row, extra ...interface{}) (*types.Session, error) {
 	var session types.Session
 	var endedAt, lastMessageAt sql.NullTime
 	var resumedFrom, summary, transcriptKey sql.NullString
-	var transcriptSize sql.NullInt64
+	var transcriptSize, uncompressedSize sql.NullInt64
 
-	err := c.pool.QueryRow(ctx, query, sessionID).Scan(
+	dest := []interface{}{
 		&session.ID,
 		&session.RunnerID,
 		&session.ProjectName,
@@ -623,13 +2129,14 @@ func (c *PostgresClient) GetSession(ctx context.Context, sessionID string) (*typ
 		&summary,
 		&transcriptKey,
 		&transcriptSize,
+		&session.TranscriptCompressed,
+		&uncompressedSize,
+		&session.Pinned,
 		&session.CreatedAt,
-	)
+	}
 
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("session not found: %s", sessionID)
-		}
 		return nil, err
 	}
 
@@ -651,6 +2158,9 @@ func (c *PostgresClient) GetSession(ctx context.Context, sessionID string) (*typ
 	if transcriptSize.Valid {
 		session.TranscriptSizeBytes = transcriptSize.Int64
 	}
+	if uncompressedSize.Valid {
+		session.UncompressedSizeBytes = uncompressedSize.Int64
+	}
 
 	return &session, nil
 }
@@ -734,57 +2244,676 @@ func (c *PostgresClient) MarkSessionNonResumable(ctx context.Context, sessionID
 	return err
 }
 
-// SaveTranscriptMetadata saves transcript metadata
-func (c *PostgresClient) SaveTranscriptMetadata(ctx context.Context, sessionID, s3Key string, sizeBytes int64) error {
+// SaveTranscriptMetadata saves transcript metadata. sizeBytes is the stored
+// size, which differs from uncompressedSize when compressed is set.
+func (c *PostgresClient) SaveTranscriptMetadata(ctx context.Context, sessionID, s3Key string, sizeBytes, uncompressedSize int64, compressed bool) error {
 	_, err := c.pool.Exec(ctx, `
 		UPDATE sessions 
-		SET transcript_s3_key = $1, transcript_size_bytes = $2
-		WHERE id = $3
-	`, s3Key, sizeBytes, sessionID)
+		SET transcript_s3_key = $1, transcript_size_bytes = $2,
+		    uncompressed_size_bytes = $3, compressed = $4
+		WHERE id = $5
+	`, s3Key, sizeBytes, uncompressedSize, compressed, sessionID)
 	return err
 }
 
-// ===== TOKEN BUDGETS =====
+// ===== DAEMON NODES =====
 
-// GetTokenBudget retrieves active token budget for scope
-func (c *PostgresClient) GetTokenBudget(ctx context.Context, scope, scopeID string) (*types.TokenBudget, error) {
-	query := `
-		SELECT id, scope, scope_id, limit_tokens, used_tokens, 
-		       period_granularity, period_start, period_end
-		FROM token_budgets
-		WHERE scope = $1 
-		  AND (scope_id = $2 OR ($2 = '' AND scope_id IS NULL))
-		  AND period_end > NOW()
-		ORDER BY period_start DESC
-		LIMIT 1
-	`
+// UpsertDaemonInfo registers a daemon node, or refreshes it and marks it
+// active again if the node ID is already known
+func (c *PostgresClient) UpsertDaemonInfo(ctx context.Context, info *types.DaemonInfo) error {
+	_, err := c.pool.Exec(ctx, `
+		INSERT INTO daemon_nodes (
+			node_id, hostname, version, started_at, last_heartbeat,
+			grpc_port, http_port, active_runners, status
+		) VALUES ($1, $2, $3, $4, NOW(), $5, $6, $7, 'active')
+		ON CONFLICT (node_id) DO UPDATE SET
+			hostname = EXCLUDED.hostname,
+			version = EXCLUDED.version,
+			started_at = EXCLUDED.started_at,
+			last_heartbeat = NOW(),
+			grpc_port = EXCLUDED.grpc_port,
+			http_port = EXCLUDED.http_port,
+			active_runners = EXCLUDED.active_runners,
+			status = 'active'
+	`, info.NodeID, info.Hostname, info.Version, info.StartedAt,
+		info.GRPCPort, info.HTTPPort, info.ActiveRunners)
+	return err
+}
 
-	var budget types.TokenBudget
-	var scopeIDVal sql.NullString
+// UpdateDaemonHeartbeat refreshes a node's heartbeat and runner count
+func (c *PostgresClient) UpdateDaemonHeartbeat(ctx context.Context, nodeID string, activeRunners int) error {
+	_, err := c.pool.Exec(ctx, `
+		UPDATE daemon_nodes
+		SET last_heartbeat = NOW(), active_runners = $1, status = 'active'
+		WHERE node_id = $2
+	`, activeRunners, nodeID)
+	return err
+}
 
-	err := c.pool.QueryRow(ctx, query, scope, scopeID).Scan(
-		&budget.ID,
-		&budget.Scope,
-		&scopeIDVal,
-		&budget.LimitTokens,
-		&budget.UsedTokens,
-		&budget.PeriodGranularity,
-		&budget.PeriodStart,
-		&budget.PeriodEnd,
+const daemonNodeColumns = `node_id, hostname, version, started_at, last_heartbeat,
+		       grpc_port, http_port, active_runners, status`
+
+func scanDaemonInfo(row pgx.Row) (*types.DaemonInfo, error) {
+	var info types.DaemonInfo
+	var grpcPort, httpPort sql.NullInt32
+
+	err := row.Scan(
+		&info.NodeID,
+		&info.Hostname,
+		&info.Version,
+		&info.StartedAt,
+		&info.LastHeartbeat,
+		&grpcPort,
+		&httpPort,
+		&info.ActiveRunners,
+		&info.Status,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	info.GRPCPort = int(grpcPort.Int32)
+	info.HTTPPort = int(httpPort.Int32)
 
+	return &info, nil
+}
+
+// GetDaemonInfo retrieves a single daemon node
+func (c *PostgresClient) GetDaemonInfo(ctx context.Context, nodeID string) (*types.DaemonInfo, error) {
+	info, err := scanDaemonInfo(c.pool.QueryRow(ctx, `
+		SELECT `+daemonNodeColumns+`
+		FROM daemon_nodes
+		WHERE node_id = $1
+	`, nodeID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, nil // No budget configured
+			return nil, fmt.Errorf("daemon node not found: %s", nodeID)
 		}
 		return nil, err
 	}
+	return info, nil
+}
 
-	if scopeIDVal.Valid {
-		budget.ScopeID = scopeIDVal.String
+// ListDaemonNodes returns every registered daemon node, active ones first
+func (c *PostgresClient) ListDaemonNodes(ctx context.Context) ([]*types.DaemonInfo, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT `+daemonNodeColumns+`
+		FROM daemon_nodes
+		ORDER BY status, node_id
+	`)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	return &budget, nil
+	var nodes []*types.DaemonInfo
+	for rows.Next() {
+		info, err := scanDaemonInfo(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, info)
+	}
+
+	return nodes, rows.Err()
+}
+
+// MarkStaleDaemonNodesInactive marks active nodes whose last heartbeat is
+// older than staleAfter as inactive and returns their IDs
+func (c *PostgresClient) MarkStaleDaemonNodesInactive(ctx context.Context, staleAfter time.Duration) ([]string, error) {
+	rows, err := c.pool.Query(ctx, `
+		UPDATE daemon_nodes
+		SET status = 'inactive'
+		WHERE status = 'active' AND last_heartbeat < $1
+		RETURNING node_id
+	`, time.Now().Add(-staleAfter))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodeIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, id)
+	}
+
+	return nodeIDs, rows.Err()
+}
+
+// ===== NOTIFICATION LOG =====
+
+// RecordNotification stores one notification delivery attempt
+func (c *PostgresClient) RecordNotification(ctx context.Context, entry *types.NotificationLogEntry) error {
+	_, err := c.pool.Exec(ctx, `
+		INSERT INTO notification_log (sent_at, backend, event_type, target, message_text, success, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, entry.SentAt, entry.Backend, entry.EventType, nullString(entry.Target),
+		entry.MessageText, entry.Success, nullString(entry.Error))
+
+	return err
+}
+
+// GetNotificationHistory returns the most recent delivery attempts, newest
+// first, optionally filtered by event type
+func (c *PostgresClient) GetNotificationHistory(ctx context.Context, limit int, eventType string) ([]*types.NotificationLogEntry, error) {
+	query := `
+		SELECT id, sent_at, backend, event_type, target, message_text, success, error
+		FROM notification_log
+		WHERE ($1 = '' OR event_type = $1)
+		ORDER BY sent_at DESC
+		LIMIT $2
+	`
+
+	rows, err := c.pool.Query(ctx, query, eventType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*types.NotificationLogEntry
+	for rows.Next() {
+		var entry types.NotificationLogEntry
+		var target, messageText, errText sql.NullString
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.SentAt,
+			&entry.Backend,
+			&entry.EventType,
+			&target,
+			&messageText,
+			&entry.Success,
+			&errText,
+		); err != nil {
+			return nil, err
+		}
+
+		entry.Target = target.String
+		entry.MessageText = messageText.String
+		entry.Error = errText.String
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// ===== ACTIVITY FEED =====
+
+// GetProjectActivity returns the most recent activity across all projects,
+// newest first: runners started, sessions ended and budget warnings. Budget
+// warnings come from the notification log, so only ones that were actually
+// sent appear, once per warning however many backends delivered it.
+func (c *PostgresClient) GetProjectActivity(ctx context.Context, limit int) ([]*types.ActivityEvent, error) {
+	query := `
+		SELECT ts, event_type, project_name, actor_id, description
+		FROM (
+			(SELECT started_at AS ts, 'runner.started' AS event_type,
+				project_name, id::text AS actor_id,
+				format('Runner started (%s)', runtime_type) AS description
+			FROM runners
+			ORDER BY started_at DESC
+			LIMIT $1)
+
+			UNION ALL
+
+			(SELECT ended_at, 'session.ended', project_name, id,
+				format('Session ended after %s messages, %s tokens',
+					COALESCE(message_count, 0), COALESCE(tokens_used, 0))
+			FROM sessions
+			WHERE ended_at IS NOT NULL
+			ORDER BY ended_at DESC
+			LIMIT $1)
+
+			UNION ALL
+
+			(SELECT ts, event_type, '', '', description
+			FROM (
+				SELECT DISTINCT ON (event_type, date_trunc('second', sent_at))
+					sent_at AS ts, event_type,
+					-- \x60 is a backtick, as Telegram messages quote the scope
+					format('Token budget %s at %s',
+						COALESCE(substring(message_text FROM 'Scope: \x60?([^\x60\n]+)'), 'unknown'),
+						COALESCE(substring(message_text FROM 'Usage: \*?([0-9]+%)'), '?')) AS description
+				FROM notification_log
+				WHERE event_type LIKE 'budget.%' AND success
+				ORDER BY event_type, date_trunc('second', sent_at) DESC, sent_at
+			) budget
+			ORDER BY ts DESC
+			LIMIT $1)
+		) activity
+		ORDER BY ts DESC
+		LIMIT $1
+	`
+
+	rows, err := c.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*types.ActivityEvent
+	for rows.Next() {
+		var e types.ActivityEvent
+		if err := rows.Scan(&e.Timestamp, &e.EventType, &e.ProjectName, &e.ActorID, &e.Description); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+
+	return events, rows.Err()
+}
+
+// ===== NOTIFICATION ROUTES =====
+
+// CreateNotificationRoute adds a route, replacing the event filter of an
+// existing route to the same project, backend and target
+func (c *PostgresClient) CreateNotificationRoute(ctx context.Context, route *types.NotificationRoute) error {
+	eventTypes := route.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return c.pool.QueryRow(ctx, `
+		INSERT INTO project_notification_routes (project_name, backend, target, event_types)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (COALESCE(project_name, ''), backend, target)
+		DO UPDATE SET event_types = EXCLUDED.event_types
+		RETURNING id, created_at
+	`, nullString(route.ProjectName), route.Backend, route.Target, eventTypes).
+		Scan(&route.ID, &route.CreatedAt)
+}
+
+// GetNotificationRoutes returns the routes for a project together with the
+// global routes
+func (c *PostgresClient) GetNotificationRoutes(ctx context.Context, projectName string) ([]*types.NotificationRoute, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT id, project_name, backend, target, event_types, created_at
+		FROM project_notification_routes
+		WHERE project_name = $1 OR project_name IS NULL
+		ORDER BY id
+	`, projectName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []*types.NotificationRoute
+	for rows.Next() {
+		var route types.NotificationRoute
+		var project sql.NullString
+
+		if err := rows.Scan(
+			&route.ID,
+			&project,
+			&route.Backend,
+			&route.Target,
+			&route.EventTypes,
+			&route.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		route.ProjectName = project.String
+		routes = append(routes, &route)
+	}
+
+	return routes, rows.Err()
+}
+
+// ===== DEVICE CODES =====
+
+// CreateDeviceCode stores a new pending device authorization request
+func (c *PostgresClient) CreateDeviceCode(ctx context.Context, dc *types.DeviceCode) error {
+	_, err := c.pool.Exec(ctx, `
+		INSERT INTO device_codes (device_code, user_code, status, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, dc.DeviceCode, dc.UserCode, string(types.DeviceCodePending), dc.ExpiresAt)
+
+	return err
+}
+
+// GetDeviceCode retrieves an unexpired device authorization request
+func (c *PostgresClient) GetDeviceCode(ctx context.Context, deviceCode string) (*types.DeviceCode, error) {
+	query := `
+		SELECT device_code, user_code, status, subject, created_at, expires_at
+		FROM device_codes
+		WHERE device_code = $1 AND expires_at > NOW()
+	`
+
+	var dc types.DeviceCode
+	var status string
+	var subject sql.NullString
+
+	err := c.pool.QueryRow(ctx, query, deviceCode).Scan(
+		&dc.DeviceCode,
+		&dc.UserCode,
+		&status,
+		&subject,
+		&dc.CreatedAt,
+		&dc.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("device code not found or expired")
+		}
+		return nil, err
+	}
+
+	dc.Status = types.DeviceCodeStatus(status)
+	if subject.Valid {
+		dc.Subject = subject.String
+	}
+
+	return &dc, nil
+}
+
+// ResolveDeviceCode approves or denies a pending request by its user code
+func (c *PostgresClient) ResolveDeviceCode(ctx context.Context, userCode string, status types.DeviceCodeStatus, subject string) error {
+	result, err := c.pool.Exec(ctx, `
+		UPDATE device_codes
+		SET status = $2, subject = $3
+		WHERE user_code = $1 AND status = 'pending' AND expires_at > NOW()
+	`, userCode, string(status), nullString(subject))
+
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user code not found or expired: %s", userCode)
+	}
+
+	return nil
+}
+
+// ClaimApprovedDeviceCode deletes an unexpired, approved device
+// authorization request and returns it. It returns nil without an error if
+// there is no such request, e.g. because a concurrent poll claimed it first.
+func (c *PostgresClient) ClaimApprovedDeviceCode(ctx context.Context, deviceCode string) (*types.DeviceCode, error) {
+	var dc types.DeviceCode
+	var status string
+	var subject sql.NullString
+
+	err := c.pool.QueryRow(ctx, `
+		DELETE FROM device_codes
+		WHERE device_code = $1 AND status = 'approved' AND expires_at > NOW()
+		RETURNING device_code, user_code, status, subject, created_at, expires_at
+	`, deviceCode).Scan(
+		&dc.DeviceCode,
+		&dc.UserCode,
+		&status,
+		&subject,
+		&dc.CreatedAt,
+		&dc.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	dc.Status = types.DeviceCodeStatus(status)
+	if subject.Valid {
+		dc.Subject = subject.String
+	}
+
+	return &dc, nil
+}
+
+// DeleteDeviceCode removes a device authorization request once it is used
+func (c *PostgresClient) DeleteDeviceCode(ctx context.Context, deviceCode string) error {
+	_, err := c.pool.Exec(ctx, "DELETE FROM device_codes WHERE device_code = $1", deviceCode)
+	return err
+}
+
+// DeleteExpiredDeviceCodes removes device authorization requests past their TTL
+func (c *PostgresClient) DeleteExpiredDeviceCodes(ctx context.Context) (int64, error) {
+	result, err := c.pool.Exec(ctx, "DELETE FROM device_codes WHERE expires_at <= NOW()")
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// ===== API TOKENS =====
+
+// CreateAPIToken stores a token's metadata, filling in its generated ID and
+// creation time
+func (c *PostgresClient) CreateAPIToken(ctx context.Context, t *types.APIToken) error {
+	return c.pool.QueryRow(ctx, `
+		INSERT INTO api_tokens (name, scope, subject, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, t.Name, t.Scope, t.Subject, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+}
+
+// ListAPITokens returns unrevoked, unexpired tokens, newest first
+func (c *PostgresClient) ListAPITokens(ctx context.Context) ([]*types.APIToken, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT id, name, scope, subject, created_at, last_used_at, expires_at, revoked_at
+		FROM api_tokens
+		WHERE revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*types.APIToken
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
+}
+
+// GetAPIToken returns an active API token by ID
+func (c *PostgresClient) GetAPIToken(ctx context.Context, id string) (*types.APIToken, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT id, name, scope, subject, created_at, last_used_at, expires_at, revoked_at
+		FROM api_tokens
+		WHERE id::text = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("token not found or already revoked: %s", id)
+	}
+	return scanAPIToken(rows)
+}
+
+// ListRevokedAPITokens returns revoked tokens that have not yet expired, so
+// their revocation can be restored after a restart
+func (c *PostgresClient) ListRevokedAPITokens(ctx context.Context) ([]*types.APIToken, error) {
+	rows, err := c.pool.Query(ctx, `
+		SELECT id, name, scope, subject, created_at, last_used_at, expires_at, revoked_at
+		FROM api_tokens
+		WHERE revoked_at IS NOT NULL AND expires_at > NOW()
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*types.APIToken
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
+}
+
+func scanAPIToken(rows pgx.Rows) (*types.APIToken, error) {
+	var t types.APIToken
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := rows.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Scope,
+		&t.Subject,
+		&t.CreatedAt,
+		&lastUsedAt,
+		&t.ExpiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+
+	return &t, nil
+}
+
+// RevokeAPIToken marks a token revoked and returns it. Revoking an already
+// revoked token is an error.
+func (c *PostgresClient) RevokeAPIToken(ctx context.Context, id string) (*types.APIToken, error) {
+	var t types.APIToken
+	err := c.pool.QueryRow(ctx, `
+		UPDATE api_tokens
+		SET revoked_at = NOW()
+		WHERE id::text = $1 AND revoked_at IS NULL
+		RETURNING id, name, expires_at
+	`, id).Scan(&t.ID, &t.Name, &t.ExpiresAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("token not found or already revoked: %s", id)
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// SetAPITokenExpiry moves a token's expiry, e.g. when it is rotated, so
+// that listing it and restoring its revocation use the new expiry
+func (c *PostgresClient) SetAPITokenExpiry(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := c.pool.Exec(ctx, "UPDATE api_tokens SET expires_at = $2 WHERE id::text = $1", id, expiresAt)
+	return err
+}
+
+// TouchAPIToken records that a token was just used
+func (c *PostgresClient) TouchAPIToken(ctx context.Context, id string) error {
+	_, err := c.pool.Exec(ctx, "UPDATE api_tokens SET last_used_at = NOW() WHERE id::text = $1", id)
+	return err
+}
+
+// ===== TOKEN BUDGETS =====
+
+// GetTokenBudget retrieves active token budget for scope
+func (c *PostgresClient) GetTokenBudget(ctx context.Context, scope, scopeID string) (*types.TokenBudget, error) {
+	query := `
+		SELECT id, scope, scope_id, limit_tokens, used_tokens, 
+		       period_granularity, period_start, period_end
+		FROM token_budgets
+		WHERE scope = $1 
+		  AND (scope_id = $2 OR ($2 = '' AND scope_id IS NULL))
+		  AND period_end > NOW()
+		ORDER BY period_start DESC
+		LIMIT 1
+	`
+
+	var budget types.TokenBudget
+	var scopeIDVal sql.NullString
+
+	err := c.pool.QueryRow(ctx, query, scope, scopeID).Scan(
+		&budget.ID,
+		&budget.Scope,
+		&scopeIDVal,
+		&budget.LimitTokens,
+		&budget.UsedTokens,
+		&budget.PeriodGranularity,
+		&budget.PeriodStart,
+		&budget.PeriodEnd,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No budget configured
+		}
+		return nil, err
+	}
+
+	if scopeIDVal.Valid {
+		budget.ScopeID = scopeIDVal.String
+	}
+
+	return &budget, nil
+}
+
+// GetBudgetHistory returns up to limit budget periods for scope, newest
+// first, including expired ones
+func (c *PostgresClient) GetBudgetHistory(ctx context.Context, scope, scopeID string, limit int) ([]*types.TokenBudget, error) {
+	query := `
+		SELECT id, scope, scope_id, limit_tokens, used_tokens,
+		       period_granularity, period_start, period_end
+		FROM token_budgets
+		WHERE scope = $1
+		  AND (scope_id = $2 OR ($2 = '' AND scope_id IS NULL))
+		ORDER BY period_start DESC
+		LIMIT $3
+	`
+
+	rows, err := c.pool.Query(ctx, query, scope, scopeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []*types.TokenBudget
+	for rows.Next() {
+		var budget types.TokenBudget
+		var scopeIDVal sql.NullString
+
+		err := rows.Scan(
+			&budget.ID,
+			&budget.Scope,
+			&scopeIDVal,
+			&budget.LimitTokens,
+			&budget.UsedTokens,
+			&budget.PeriodGranularity,
+			&budget.PeriodStart,
+			&budget.PeriodEnd,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		budget.ScopeID = scopeIDVal.String
+		budgets = append(budgets, &budget)
+	}
+
+	return budgets, rows.Err()
 }
 
 // CreateTokenBudget creates a new token budget
@@ -796,15 +2925,16 @@ func (c *PostgresClient) CreateTokenBudget(ctx context.Context, budget *types.To
 		scopeID = budget.ScopeID
 	}
 
-	_, err := c.pool.Exec(ctx, `
-		INSERT INTO token_budgets (
-			scope, scope_id, limit_tokens, used_tokens,
-			period_granularity, period_start, period_end
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, budget.Scope, scopeID, budget.LimitTokens, budget.UsedTokens,
-		budget.PeriodGranularity, budget.PeriodStart, budget.PeriodEnd)
-
-	return err
+	return c.WithRetry(func() error {
+		_, err := c.pool.Exec(ctx, `
+			INSERT INTO token_budgets (
+				scope, scope_id, limit_tokens, used_tokens,
+				period_granularity, period_start, period_end
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, budget.Scope, scopeID, budget.LimitTokens, budget.UsedTokens,
+			budget.PeriodGranularity, budget.PeriodStart, budget.PeriodEnd)
+		return err
+	}, defaultTxRetries)
 }
 
 // IncrementTokenUsage increments token usage for a budget
@@ -816,15 +2946,144 @@ func (c *PostgresClient) IncrementTokenUsage(ctx context.Context, scope, scopeID
 		scopeIDVal = scopeID
 	}
 
-	_, err := c.pool.Exec(ctx, `
-		UPDATE token_budgets
-		SET used_tokens = used_tokens + $1
-		WHERE scope = $2
-		  AND (scope_id = $3 OR ($3 IS NULL AND scope_id IS NULL))
-		  AND period_end > NOW()
-	`, tokens, scope, scopeIDVal)
+	return c.WithRetry(func() error {
+		_, err := c.pool.Exec(ctx, `
+			UPDATE token_budgets
+			SET used_tokens = used_tokens + $1
+			WHERE scope = $2
+			  AND (scope_id = $3 OR ($3 IS NULL AND scope_id IS NULL))
+			  AND period_end > NOW()
+		`, tokens, scope, scopeIDVal)
+		return err
+	}, defaultTxRetries)
+}
 
-	return err
+// GetTokenUsageTimeSeries aggregates token usage into buckets of the given
+// granularity (minute, hour, day, week, month or the budget forms hourly,
+// daily, weekly, monthly). An empty projectName aggregates all projects.
+func (c *PostgresClient) GetTokenUsageTimeSeries(ctx context.Context, projectName string, from, to time.Time, granularity string) ([]types.TokenUsagePoint, error) {
+	unit, err := truncUnit(granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT DATE_TRUNC($1, timestamp) AS bucket, SUM(tokens_delta)
+		FROM token_usage_events
+		WHERE timestamp >= $2 AND timestamp < $3
+		  AND ($4 = '' OR project_name = $4)
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	rows, err := c.pool.Query(ctx, query, unit, from, to, projectName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []types.TokenUsagePoint
+	for rows.Next() {
+		var p types.TokenUsagePoint
+		if err := rows.Scan(&p.Bucket, &p.Tokens); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
+// GetTokensUsedSince returns the total tokens recorded across all projects since the given time
+func (c *PostgresClient) GetTokensUsedSince(ctx context.Context, since time.Time) (int64, error) {
+	var total int64
+	err := c.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(tokens_delta), 0) FROM token_usage_events WHERE timestamp >= $1
+	`, since).Scan(&total)
+	return total, err
+}
+
+// TokenCostPerMillionUSD is the blended input/output price used to
+// estimate spend from token counts. It is a rough guide, not billing data.
+const TokenCostPerMillionUSD = 15.0
+
+// TokenLeaderboardEntry is one project's position in the token usage ranking
+type TokenLeaderboardEntry struct {
+	ProjectName      string
+	TokensUsed       int64
+	EstimatedCostUSD float64
+	Rank             int
+}
+
+// GetTokenUsageLeaderboard ranks projects by tokens used during period:
+// today, week or month (calendar periods starting at DATE_TRUNC of now) or
+// all (lifetime totals from projects.total_tokens). Projects with no usage
+// in the period are omitted.
+func (c *PostgresClient) GetTokenUsageLeaderboard(ctx context.Context, limit int, period string) ([]TokenLeaderboardEntry, error) {
+	var query string
+	switch period {
+	case "today", "week", "month":
+		unit := period
+		if unit == "today" {
+			unit = "day"
+		}
+		query = fmt.Sprintf(`
+			SELECT project_name, SUM(tokens_delta)::bigint AS tokens
+			FROM token_usage_events
+			WHERE timestamp >= DATE_TRUNC('%s', NOW())
+			GROUP BY project_name
+			HAVING SUM(tokens_delta) > 0
+			ORDER BY tokens DESC, project_name
+			LIMIT $1
+		`, unit)
+	case "all":
+		query = `
+			SELECT name, total_tokens
+			FROM projects
+			WHERE total_tokens > 0
+			ORDER BY total_tokens DESC, name
+			LIMIT $1
+		`
+	default:
+		return nil, fmt.Errorf("unsupported period: %s", period)
+	}
+
+	rows, err := c.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []TokenLeaderboardEntry
+	for rows.Next() {
+		var e TokenLeaderboardEntry
+		if err := rows.Scan(&e.ProjectName, &e.TokensUsed); err != nil {
+			return nil, err
+		}
+		e.Rank = len(entries) + 1
+		e.EstimatedCostUSD = float64(e.TokensUsed) * TokenCostPerMillionUSD / 1e6
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// truncUnit maps a granularity name to a DATE_TRUNC field
+func truncUnit(granularity string) (string, error) {
+	switch granularity {
+	case "minute":
+		return "minute", nil
+	case "hour", "hourly":
+		return "hour", nil
+	case "", "day", "daily":
+		return "day", nil
+	case "week", "weekly":
+		return "week", nil
+	case "month", "monthly":
+		return "month", nil
+	default:
+		return "", fmt.Errorf("unsupported granularity: %s", granularity)
+	}
 }
 
 // GetExpiredBudgets returns budgets that need rollover
@@ -871,3 +3130,60 @@ func (c *PostgresClient) GetExpiredBudgets(ctx context.Context, now time.Time) (
 
 	return budgets, rows.Err()
 }
+
+// GetCurrentBudgets returns budgets whose period contains now
+func (c *PostgresClient) GetCurrentBudgets(ctx context.Context, now time.Time) ([]*types.TokenBudget, error) {
+	query := `
+		SELECT id, scope, scope_id, limit_tokens, used_tokens,
+		       period_granularity, period_start, period_end
+		FROM token_budgets
+		WHERE period_start <= $1 AND period_end > $1
+		ORDER BY scope, scope_id
+	`
+
+	rows, err := c.pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []*types.TokenBudget
+	for rows.Next() {
+		var budget types.TokenBudget
+		var scopeIDVal sql.NullString
+
+		err := rows.Scan(
+			&budget.ID,
+			&budget.Scope,
+			&scopeIDVal,
+			&budget.LimitTokens,
+			&budget.UsedTokens,
+			&budget.PeriodGranularity,
+			&budget.PeriodStart,
+			&budget.PeriodEnd,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		budget.ScopeID = scopeIDVal.String
+		budgets = append(budgets, &budget)
+	}
+
+	return budgets, rows.Err()
+}
+
+// nullString converts an empty string to SQL NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullInt64(n int64) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
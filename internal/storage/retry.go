@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultTxRetries is how many times a transaction is retried after a
+	// serialisation failure or deadlock
+	defaultTxRetries = 3
+
+	// txRetryBaseDelay is multiplied by the attempt number between retries
+	txRetryBaseDelay = 50 * time.Millisecond
+)
+
+// isRetryableTxError reports whether err is a serialisation failure (40001)
+// or deadlock (40P01) that is safe to retry from the start of the transaction
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// WithRetry runs fn, re-running it up to maxRetries more times while it fails
+// with a serialisation failure or deadlock. Retries wait 50ms × attempt plus
+// jitter. fn must run a complete transaction so each attempt starts fresh.
+// The last error is returned once retries are exhausted.
+func (c *PostgresClient) WithRetry(fn func() error, maxRetries int) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !isRetryableTxError(err) || attempt >= maxRetries {
+			return err
+		}
+
+		delay := txRetryBaseDelay*time.Duration(attempt+1) + rand.N(txRetryBaseDelay/2)
+		c.logger.Debug("retrying transaction",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		time.Sleep(delay)
+	}
+}
@@ -3,206 +3,353 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/meridian-lex/stratavore/internal/storage"
-	"github.com/meridian-lex/stratavore/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/client"
 )
 
-// LiveMonitor displays live runner status in terminal
+// fetchTimeout bounds each poll of the daemon API
+const fetchTimeout = 5 * time.Second
+
+// minBarWidth is the narrowest runner bar drawn in the projects view
+const minBarWidth = 4
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("8"))
+	helpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	barStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	boxStyle    = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("8")).
+			Padding(0, 1)
+
+	statusColors = map[string]lipgloss.Color{
+		"active":     "10",
+		"running":    "10",
+		"starting":   "11",
+		"paused":     "11",
+		"idle":       "7",
+		"stopping":   "11",
+		"archived":   "8",
+		"terminated": "8",
+		"failed":     "9",
+	}
+)
+
+// LiveMonitor displays live project and runner status in the terminal
 type LiveMonitor struct {
-	db       *storage.PostgresClient
+	client   *client.Client
 	interval time.Duration
 }
 
-// NewLiveMonitor creates a new live monitor
-func NewLiveMonitor(db *storage.PostgresClient, interval time.Duration) *LiveMonitor {
+// NewLiveMonitor creates a new live monitor polling the daemon every
+// interval
+func NewLiveMonitor(c *client.Client, interval time.Duration) *LiveMonitor {
 	return &LiveMonitor{
-		db:       db,
+		client:   c,
 		interval: interval,
 	}
 }
 
-// Display shows live runner status with refresh
-func (m *LiveMonitor) Display(ctx context.Context) error {
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
+// Run shows the monitor until the user quits or ctx is cancelled. With a
+// project name it opens on that project's runners.
+func (m *LiveMonitor) Run(ctx context.Context, projectName string) error {
+	model := &monitorModel{
+		client:   m.client,
+		interval: m.interval,
+		project:  projectName,
+	}
+	if projectName != "" {
+		model.view = viewRunners
+	}
+
+	_, err := tea.NewProgram(model, tea.WithContext(ctx), tea.WithAltScreen()).Run()
+	if err == tea.ErrProgramKilled {
+		// Context cancelled
+		return nil
+	}
+	return err
+}
+
+type monitorView int
 
-	// Clear screen and display initial
-	fmt.Print("\033[2J\033[H")
-	m.renderStatus(ctx)
+const (
+	viewProjects monitorView = iota
+	viewRunners
+)
 
-	for {
-		select {
-		case <-ticker.C:
-			// Move cursor to top and redraw
-			fmt.Print("\033[H")
-			m.renderStatus(ctx)
-		case <-ctx.Done():
-			return nil
+// monitorModel is the Bubble Tea model behind LiveMonitor
+type monitorModel struct {
+	client   *client.Client
+	interval time.Duration
+	view     monitorView
+	project  string // runners view filter; empty shows every project
+
+	projects []*api.Project
+	runners  []*api.Runner
+	err      error
+	updated  time.Time
+
+	width int
+}
+
+// snapshotMsg carries one poll of the daemon
+type snapshotMsg struct {
+	projects []*api.Project
+	runners  []*api.Runner
+	err      error
+	at       time.Time
+}
+
+type tickMsg time.Time
+
+func (m *monitorModel) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *monitorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "tab":
+			if m.view == viewProjects {
+				m.view = viewRunners
+			} else {
+				m.view = viewProjects
+			}
 		}
+
+	case tickMsg:
+		return m, m.fetch()
+
+	case snapshotMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.projects = msg.projects
+			m.runners = msg.runners
+			m.updated = msg.at
+		}
+		return m, tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
 	}
+
+	return m, nil
 }
 
-func (m *LiveMonitor) renderStatus(ctx context.Context) {
-	// Get all projects
-	projects, err := m.db.ListProjects(ctx, "")
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
-	}
+// fetch polls projects and runners in the background
+func (m *monitorModel) fetch() tea.Cmd {
+	c, project := m.client, m.project
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		defer cancel()
 
-	// Header
-	fmt.Println("═══════════════════════════════════════════════════════════════════════")
-	fmt.Printf("  STRATAVORE LIVE MONITOR - %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Println("═══════════════════════════════════════════════════════════════════════")
-	fmt.Println()
+		projects, err := c.ListProjects(ctx, "", false)
+		if err == nil && projects.Error != "" {
+			err = fmt.Errorf("%s", projects.Error)
+		}
+		if err != nil {
+			return snapshotMsg{err: err}
+		}
 
-	if len(projects) == 0 {
-		fmt.Println("  No projects found.")
-		fmt.Println()
-		return
+		runners, err := c.ListRunners(ctx, project)
+		if err == nil && runners.Error != "" {
+			err = fmt.Errorf("%s", runners.Error)
+		}
+		if err != nil {
+			return snapshotMsg{err: err}
+		}
+
+		return snapshotMsg{
+			projects: projects.Projects,
+			runners:  runners.Runners,
+			at:       time.Now(),
+		}
 	}
+}
 
-	// Stats
-	totalActiveRunners := 0
-	totalSessions := 0
-	var totalTokens int64
+func (m *monitorModel) View() string {
+	var b strings.Builder
 
-	for _, p := range projects {
-		totalActiveRunners += p.ActiveRunners
-		totalSessions += p.TotalSessions
-		totalTokens += p.TotalTokens
+	title := "STRATAVORE LIVE MONITOR"
+	if m.view == viewRunners {
+		title = "ACTIVE RUNNERS"
+		if m.project != "" {
+			title += " - " + m.project
+		}
 	}
+	b.WriteString(titleStyle.Render(title))
+	if !m.updated.IsZero() {
+		b.WriteString(helpStyle.Render("  " + m.updated.Format("15:04:05")))
+	}
+	b.WriteString("\n\n")
 
-	fmt.Printf("  📊 Summary: %d Projects | %d Active Runners | %d Sessions | %s Tokens\n",
-		len(projects), totalActiveRunners, totalSessions, formatNumber(totalTokens))
-	fmt.Println()
-
-	// Projects table
-	fmt.Println("  PROJECT              STATUS    RUNNERS  SESSIONS  TOKENS")
-	fmt.Println("  ─────────────────────────────────────────────────────────────────────")
+	switch {
+	case m.updated.IsZero() && m.err == nil:
+		b.WriteString("Loading...")
+	case m.view == viewProjects:
+		b.WriteString(m.projectsView())
+	default:
+		b.WriteString(m.runnersView())
+	}
 
-	for _, p := range projects {
-		statusIcon := getStatusIcon(p.Status)
-		name := truncate(p.Name, 20)
+	if m.err != nil {
+		b.WriteString("\n\n" + errorStyle.Render("Error: "+m.err.Error()))
+	}
 
-		fmt.Printf("  %-20s %s %-7s  %2d       %4d      %s\n",
-			name,
-			statusIcon,
-			p.Status,
-			p.ActiveRunners,
-			p.TotalSessions,
-			formatNumber(p.TotalTokens))
+	box := boxStyle
+	if m.width > 0 {
+		// Width excludes the border, so leave room for it
+		box = box.Width(m.width - 2)
 	}
 
-	fmt.Println()
-	fmt.Println("  Press Ctrl+C to exit")
-	fmt.Print("  ")
-}
+	help := helpStyle.Render(" tab: switch view • q: quit")
+	view := box.Render(b.String()) + "\n" + help
 
-func getStatusIcon(status types.ProjectStatus) string {
-	switch status {
-	case types.ProjectActive:
-		return "🟢"
-	case types.ProjectIdle:
-		return "⚪"
-	case types.ProjectArchived:
-		return "📦"
-	default:
-		return "⚫"
+	// Never let a long line wrap and push the display out of place
+	if m.width > 0 {
+		view = lipgloss.NewStyle().MaxWidth(m.width).Render(view)
 	}
+	return view
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+// contentWidth is the usable width inside the border and padding
+func (m *monitorModel) contentWidth() int {
+	if m.width == 0 {
+		return 80
 	}
-	return s[:max-3] + "..."
+	return m.width - 6
 }
 
-func formatNumber(n int64) string {
-	if n < 1000 {
-		return fmt.Sprintf("%d", n)
-	} else if n < 1000000 {
-		return fmt.Sprintf("%.1fK", float64(n)/1000)
-	} else {
-		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+func (m *monitorModel) projectsView() string {
+	if len(m.projects) == 0 {
+		return "No projects found."
 	}
-}
 
-// DisplayRunners shows detailed runner information
-func (m *LiveMonitor) DisplayRunners(ctx context.Context, projectName string) error {
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
+	var activeRunners, sessions int32
+	var tokens int64
+	var maxRunners int32
+	for _, p := range m.projects {
+		activeRunners += p.ActiveRunners
+		sessions += p.TotalSessions
+		tokens += p.TotalTokens
+		if p.ActiveRunners > maxRunners {
+			maxRunners = p.ActiveRunners
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d projects │ %d active runners │ %d sessions │ %s tokens\n\n",
+		len(m.projects), activeRunners, sessions, formatNumber(tokens))
+
+	// Fixed columns: name 20, status 9, count 3, tokens 8, plus gaps
+	barWidth := m.contentWidth() - 20 - 9 - 3 - 8 - 5
+	showBar := barWidth >= minBarWidth
 
-	fmt.Print("\033[2J\033[H")
-	m.renderRunners(ctx, projectName)
+	header := fmt.Sprintf("%-20s %-9s %3s %8s", "PROJECT", "STATUS", "RUN", "TOKENS")
+	if showBar {
+		header += " RUNNERS"
+	}
+	b.WriteString(headerStyle.Render(header) + "\n")
 
-	for {
-		select {
-		case <-ticker.C:
-			fmt.Print("\033[H")
-			m.renderRunners(ctx, projectName)
-		case <-ctx.Done():
-			return nil
+	for _, p := range m.projects {
+		line := fmt.Sprintf("%-20s %s %3d %8s",
+			truncate(p.Name, 20),
+			statusCell(p.Status, 9),
+			p.ActiveRunners,
+			formatNumber(p.TotalTokens))
+		if showBar {
+			line += " " + runnerBar(p.ActiveRunners, maxRunners, barWidth)
 		}
+		b.WriteString(line + "\n")
 	}
-}
 
-func (m *LiveMonitor) renderRunners(ctx context.Context, projectName string) {
-	var runners []*types.Runner
-	var err error
+	return strings.TrimSuffix(b.String(), "\n")
+}
 
-	if projectName != "" {
-		runners, err = m.db.GetActiveRunners(ctx, projectName)
-	} else {
-		// Get all runners (would need new query)
-		runners = []*types.Runner{}
+func (m *monitorModel) runnersView() string {
+	if len(m.runners) == 0 {
+		return "No active runners."
 	}
 
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+	// Drop the resource columns on narrow terminals rather than wrapping
+	wide := m.contentWidth() >= 72
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d active runners\n\n", len(m.runners))
+
+	header := fmt.Sprintf("%-8s  %-16s %-9s %-8s", "RUNNER", "PROJECT", "STATUS", "UPTIME")
+	if wide {
+		header += fmt.Sprintf(" %6s %8s %8s", "CPU%", "MEM(MB)", "TOKENS")
 	}
+	b.WriteString(headerStyle.Render(header) + "\n")
 
-	// Header
-	fmt.Println("═══════════════════════════════════════════════════════════════════════")
-	fmt.Printf("  ACTIVE RUNNERS - %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Println("═══════════════════════════════════════════════════════════════════════")
-	fmt.Println()
+	for _, r := range m.runners {
+		uptime := "-"
+		if started, err := api.ParseTime(r.StartedAt); err == nil && !started.IsZero() {
+			uptime = formatDuration(time.Since(started))
+		}
 
-	if len(runners) == 0 {
-		fmt.Println("  No active runners.")
-		fmt.Println()
-		return
+		line := fmt.Sprintf("%-8s  %-16s %s %-8s",
+			truncate(r.ID, 8),
+			truncate(r.ProjectName, 16),
+			statusCell(r.Status, 9),
+			uptime)
+		if wide {
+			line += fmt.Sprintf(" %6.1f %8d %8s", r.CPUPercent, r.MemoryMB, formatNumber(r.TokensUsed))
+		}
+		b.WriteString(line + "\n")
 	}
 
-	fmt.Printf("  Total: %d active runners\n", len(runners))
-	fmt.Println()
+	return strings.TrimSuffix(b.String(), "\n")
+}
 
-	// Runners table
-	fmt.Println("  RUNNER    PROJECT          STATUS    UPTIME    CPU%   MEM(MB)  TOKENS")
-	fmt.Println("  ─────────────────────────────────────────────────────────────────────")
+// statusCell pads a status to width and colours it. Padding happens before
+// styling so escape codes don't throw off the column.
+func statusCell(status string, width int) string {
+	cell := fmt.Sprintf("%-*s", width, truncate(status, width))
+	if color, ok := statusColors[status]; ok {
+		return lipgloss.NewStyle().Foreground(color).Render(cell)
+	}
+	return cell
+}
 
-	for _, r := range runners {
-		id := truncate(r.ID, 8)
-		project := truncate(r.ProjectName, 15)
-		uptime := formatDuration(time.Since(r.StartedAt))
+// runnerBar draws n relative to max as a bar of the given width
+func runnerBar(n, max int32, width int) string {
+	filled := 0
+	if max > 0 {
+		filled = int(n) * width / int(max)
+	}
+	if filled == 0 && n > 0 {
+		filled = 1
+	}
+	return barStyle.Render(strings.Repeat("█", filled)) + strings.Repeat("░", width-filled)
+}
 
-		fmt.Printf("  %-8s  %-15s  %-8s  %-8s  %5.1f  %7d  %s\n",
-			id,
-			project,
-			r.Status,
-			uptime,
-			r.CPUPercent,
-			r.MemoryMB,
-			formatNumber(r.TokensUsed))
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
 	}
+	return s[:max-3] + "..."
+}
 
-	fmt.Println()
-	fmt.Println("  Press Ctrl+C to exit")
-	fmt.Print("  ")
+func formatNumber(n int64) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	} else if n < 1000000 {
+		return fmt.Sprintf("%.1fK", float64(n)/1000)
+	} else {
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	}
 }
 
 func formatDuration(d time.Duration) string {
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"github.com/meridian-lex/stratavore/pkg/client"
+)
+
+const (
+	// historySize is the number of samples kept per metric
+	historySize = 60
+
+	// sparklineWidth is the rendered width of a sparkline in characters
+	sparklineWidth = 8
+)
+
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+// metricHistory is a fixed-size circular buffer of metric samples
+type metricHistory struct {
+	values [historySize]float64
+	next   int
+	count  int
+}
+
+func (h *metricHistory) add(v float64) {
+	h.values[h.next] = v
+	h.next = (h.next + 1) % historySize
+	if h.count < historySize {
+		h.count++
+	}
+}
+
+// samples returns buffered values from oldest to newest
+func (h *metricHistory) samples() []float64 {
+	out := make([]float64, 0, h.count)
+	start := (h.next - h.count + historySize) % historySize
+	for i := 0; i < h.count; i++ {
+		out = append(out, h.values[(start+i)%historySize])
+	}
+	return out
+}
+
+// sparkline renders the buffered samples averaged into sparklineWidth buckets
+func (h *metricHistory) sparkline() string {
+	samples := h.samples()
+	if len(samples) == 0 {
+		return strings.Repeat(" ", sparklineWidth)
+	}
+
+	width := sparklineWidth
+	if len(samples) < width {
+		width = len(samples)
+	}
+
+	buckets := make([]float64, width)
+	for i := range buckets {
+		lo := i * len(samples) / width
+		hi := (i + 1) * len(samples) / width
+		var sum float64
+		for _, v := range samples[lo:hi] {
+			sum += v
+		}
+		buckets[i] = sum / float64(hi-lo)
+	}
+
+	low, high := buckets[0], buckets[0]
+	for _, v := range buckets {
+		if v < low {
+			low = v
+		}
+		if v > high {
+			high = v
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range buckets {
+		idx := 0
+		if high > low {
+			idx = int((v - low) / (high - low) * float64(len(sparkChars)-1))
+		}
+		sb.WriteRune(sparkChars[idx])
+	}
+	sb.WriteString(strings.Repeat(" ", sparklineWidth-width))
+	return sb.String()
+}
+
+// RunnerMonitor displays live metrics for a single runner via the daemon API
+type RunnerMonitor struct {
+	client   *client.Client
+	runnerID string
+	interval time.Duration
+
+	cpu    metricHistory
+	memory metricHistory
+	tokens metricHistory
+}
+
+// NewRunnerMonitor creates a new single-runner monitor
+func NewRunnerMonitor(c *client.Client, runnerID string, interval time.Duration) *RunnerMonitor {
+	return &RunnerMonitor{
+		client:   c,
+		runnerID: runnerID,
+		interval: interval,
+	}
+}
+
+// Display shows live runner metrics with refresh
+func (m *RunnerMonitor) Display(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	fmt.Print("\033[2J\033[H")
+	m.render(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			// Clear to end of screen so shorter frames leave no residue
+			fmt.Print("\033[H\033[J")
+			m.render(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (m *RunnerMonitor) render(ctx context.Context) {
+	resp, err := m.client.GetRunner(ctx, m.runnerID)
+	if err == nil && resp.Error != "" {
+		err = fmt.Errorf("%s", resp.Error)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	r := resp.Runner
+	m.cpu.add(r.CPUPercent)
+	m.memory.add(float64(r.MemoryMB))
+	m.tokens.add(float64(r.TokensUsed))
+
+	uptime := "-"
+	if started, err := api.ParseTime(r.StartedAt); err == nil && !started.IsZero() {
+		uptime = formatDuration(time.Since(started))
+	}
+
+	sessionID := r.SessionID
+	if sessionID == "" {
+		sessionID = "-"
+	}
+
+	flags := strings.Join(r.Flags, " ")
+	if flags == "" {
+		flags = "-"
+	}
+
+	fmt.Println("═══════════════════════════════════════════════════════════════════════")
+	fmt.Printf("  RUNNER %s - %s\n", r.ID, time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Println("═══════════════════════════════════════════════════════════════════════")
+	fmt.Println()
+	fmt.Printf("  Project:     %s\n", r.ProjectName)
+	fmt.Printf("  Status:      %s\n", r.Status)
+	fmt.Printf("  Uptime:      %s\n", uptime)
+	fmt.Printf("  Session:     %s\n", sessionID)
+	fmt.Printf("  Mode:        %s\n", r.ConversationMode)
+	fmt.Printf("  Flags:       %s\n", flags)
+	fmt.Printf("  Restarts:    %d/%d\n", r.RestartAttempts, r.MaxRestartAttempts)
+	fmt.Println()
+	fmt.Printf("  CPU:         %s  %5.1f%%\n", m.cpu.sparkline(), r.CPUPercent)
+	fmt.Printf("  Memory:      %s  %d MB\n", m.memory.sparkline(), r.MemoryMB)
+	fmt.Printf("  Tokens:      %s  %s (%.0f/min)\n",
+		m.tokens.sparkline(), formatNumber(r.TokensUsed), m.tokenRate())
+	fmt.Println()
+	fmt.Println("  Press Ctrl+C to exit")
+	fmt.Print("  ")
+}
+
+// tokenRate returns tokens per minute across the buffered window
+func (m *RunnerMonitor) tokenRate() float64 {
+	samples := m.tokens.samples()
+	if len(samples) < 2 {
+		return 0
+	}
+
+	elapsed := time.Duration(len(samples)-1) * m.interval
+	delta := samples[len(samples)-1] - samples[0]
+	if delta < 0 {
+		return 0
+	}
+	return delta / elapsed.Minutes()
+}
@@ -0,0 +1,179 @@
+// Package vectorstore indexes session summaries in Qdrant for semantic
+// search
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/meridian-lex/stratavore/pkg/types"
+	"github.com/qdrant/go-client/qdrant"
+	"go.uber.org/zap"
+)
+
+// DefaultCollection is the Qdrant collection session vectors are stored in
+const DefaultCollection = "stratavore_sessions"
+
+// SessionMatch is a session found by semantic search
+type SessionMatch struct {
+	SessionID string
+	Score     float32
+}
+
+// QdrantClient stores session summary embeddings in Qdrant
+type QdrantClient struct {
+	client     *qdrant.Client
+	embedder   Embedder
+	collection string
+	logger     *zap.Logger
+
+	// The collection is created on first use, once the embedding size is
+	// known
+	ensureOnce sync.Mutex
+	ensured    bool
+}
+
+// NewQdrantClient connects to Qdrant's gRPC API
+func NewQdrantClient(host string, port int, collection string, embedder Embedder, logger *zap.Logger) (*QdrantClient, error) {
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host: host,
+		Port: port,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connect to qdrant: %w", err)
+	}
+
+	if collection == "" {
+		collection = DefaultCollection
+	}
+
+	return &QdrantClient{
+		client:     client,
+		embedder:   embedder,
+		collection: collection,
+		logger:     logger,
+	}, nil
+}
+
+// Close closes the Qdrant connection
+func (q *QdrantClient) Close() error {
+	return q.client.Close()
+}
+
+// IndexSession embeds a session's summary and upserts it, keyed by session
+// ID. Sessions without a summary are skipped.
+func (q *QdrantClient) IndexSession(ctx context.Context, sess *types.Session) error {
+	if sess.Summary == "" {
+		return nil
+	}
+
+	vector, err := q.embedder.Embed(ctx, sess.Summary)
+	if err != nil {
+		return fmt.Errorf("embed summary: %w", err)
+	}
+
+	if err := q.ensureCollection(ctx, len(vector)); err != nil {
+		return err
+	}
+
+	_, err = q.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: q.collection,
+		Points: []*qdrant.PointStruct{{
+			Id:      qdrant.NewID(sess.ID),
+			Vectors: qdrant.NewVectors(vector...),
+			Payload: qdrant.NewValueMap(map[string]any{
+				"session_id":   sess.ID,
+				"project_name": sess.ProjectName,
+				"started_at":   sess.StartedAt.UTC().Format(time.RFC3339),
+			}),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("upsert session vector: %w", err)
+	}
+
+	q.logger.Debug("session indexed", zap.String("session_id", sess.ID))
+	return nil
+}
+
+// SearchSessions returns the sessions whose summaries are closest to query,
+// best first. An empty projectName searches every project.
+func (q *QdrantClient) SearchSessions(ctx context.Context, query, projectName string, limit int) ([]SessionMatch, error) {
+	vector, err := q.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	exists, err := q.client.CollectionExists(ctx, q.collection)
+	if err != nil {
+		return nil, fmt.Errorf("check collection: %w", err)
+	}
+	if !exists {
+		// Nothing indexed yet
+		return nil, nil
+	}
+
+	n := uint64(limit)
+	req := &qdrant.QueryPoints{
+		CollectionName: q.collection,
+		Query:          qdrant.NewQuery(vector...),
+		Limit:          &n,
+		WithPayload:    qdrant.NewWithPayload(true),
+	}
+	if projectName != "" {
+		req.Filter = &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch("project_name", projectName)},
+		}
+	}
+
+	points, err := q.client.Query(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("query qdrant: %w", err)
+	}
+
+	matches := make([]SessionMatch, 0, len(points))
+	for _, p := range points {
+		matches = append(matches, SessionMatch{
+			SessionID: p.GetPayload()["session_id"].GetStringValue(),
+			Score:     p.GetScore(),
+		})
+	}
+	return matches, nil
+}
+
+// ensureCollection creates the collection for vectors of size dims if it
+// doesn't exist yet
+func (q *QdrantClient) ensureCollection(ctx context.Context, dims int) error {
+	q.ensureOnce.Lock()
+	defer q.ensureOnce.Unlock()
+
+	if q.ensured {
+		return nil
+	}
+
+	exists, err := q.client.CollectionExists(ctx, q.collection)
+	if err != nil {
+		return fmt.Errorf("check collection: %w", err)
+	}
+
+	if !exists {
+		err := q.client.CreateCollection(ctx, &qdrant.CreateCollection{
+			CollectionName: q.collection,
+			VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+				Size:     uint64(dims),
+				Distance: qdrant.Distance_Cosine,
+			}),
+		})
+		if err != nil {
+			return fmt.Errorf("create collection: %w", err)
+		}
+		q.logger.Info("created qdrant collection",
+			zap.String("collection", q.collection),
+			zap.Int("dims", dims))
+	}
+
+	q.ensured = true
+	return nil
+}
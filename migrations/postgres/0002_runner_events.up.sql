@@ -0,0 +1,22 @@
+-- Runner lifecycle events (audit trail + timeline view)
+CREATE TABLE runner_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    
+    event_type TEXT NOT NULL,  -- 'runner.started', 'runner.stopped', ...
+    entity_type TEXT NOT NULL DEFAULT 'runner',
+    entity_id TEXT NOT NULL,  -- runner ID
+    
+    data JSONB DEFAULT '{}',
+    metadata JSONB DEFAULT '{}',
+    
+    -- Context
+    user_id TEXT,
+    hostname TEXT,
+    trace_id TEXT,
+    signature TEXT
+);
+
+CREATE INDEX idx_runner_events_entity ON runner_events(entity_id, timestamp);
+CREATE INDEX idx_runner_events_type ON runner_events(event_type);
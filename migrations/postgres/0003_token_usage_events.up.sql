@@ -0,0 +1,16 @@
+-- Fine-grained token usage tracking (time series)
+CREATE TABLE token_usage_events (
+    id BIGSERIAL PRIMARY KEY,
+    runner_id UUID NOT NULL,
+    project_name TEXT NOT NULL,
+    session_id TEXT,
+    timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    tokens_delta BIGINT NOT NULL,
+    scope TEXT NOT NULL DEFAULT 'runner',  -- 'runner', 'project', 'global'
+    
+    FOREIGN KEY (runner_id) REFERENCES runners(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_name) REFERENCES projects(name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_token_usage_events_project_time ON token_usage_events(project_name, timestamp);
+CREATE INDEX idx_token_usage_events_timestamp ON token_usage_events(timestamp);
@@ -0,0 +1,21 @@
+-- Reusable launch configurations
+CREATE TABLE presets (
+    name TEXT PRIMARY KEY,
+    project_name TEXT,  -- NULL for presets usable by any project
+    runtime_type runtime_type,
+    flags JSONB DEFAULT '[]',
+    capabilities JSONB DEFAULT '[]',
+    environment JSONB DEFAULT '{}',
+    conversation_mode conversation_mode,
+    description TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (project_name) REFERENCES projects(name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_presets_project ON presets(project_name);
+
+CREATE TRIGGER presets_updated_at BEFORE UPDATE ON presets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sessions_retention;
+ALTER TABLE sessions DROP COLUMN IF EXISTS pinned;
@@ -0,0 +1,4 @@
+-- Pinned sessions are never removed by retention cleanup
+ALTER TABLE sessions ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_sessions_retention ON sessions(ended_at) WHERE pinned = false;
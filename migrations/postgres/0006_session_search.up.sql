@@ -0,0 +1,2 @@
+-- Full-text search over session summaries
+CREATE INDEX idx_sessions_summary_fts ON sessions USING GIN (to_tsvector('english', summary));
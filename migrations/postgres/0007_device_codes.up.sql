@@ -0,0 +1,12 @@
+-- Pending OAuth2 device authorization requests (RFC 8628)
+CREATE TABLE device_codes (
+    device_code TEXT PRIMARY KEY,
+    user_code TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'pending',  -- pending, approved, denied
+    subject TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_device_codes_expires ON device_codes(expires_at);
@@ -0,0 +1,2 @@
+-- Supports time-window session queries for analytics and billing
+CREATE INDEX idx_sessions_project_started ON sessions(project_name, started_at);
@@ -0,0 +1,4 @@
+-- Optional per-project runner health check:
+-- {"type": "http|tcp|exec", "target": "...", "interval_seconds": 30,
+--  "timeout_seconds": 5, "failure_threshold": 3}
+ALTER TABLE projects ADD COLUMN health_probe JSONB;
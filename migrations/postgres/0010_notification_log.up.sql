@@ -0,0 +1,14 @@
+-- Record of every notification delivery attempt, for debugging missed alerts
+CREATE TABLE notification_log (
+    id BIGSERIAL PRIMARY KEY,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    backend TEXT NOT NULL,  -- telegram, email
+    event_type TEXT NOT NULL,
+    target TEXT,
+    message_text TEXT,
+    success BOOLEAN NOT NULL,
+    error TEXT
+);
+
+CREATE INDEX idx_notification_log_sent ON notification_log(sent_at DESC);
+CREATE INDEX idx_notification_log_event ON notification_log(event_type, sent_at DESC);
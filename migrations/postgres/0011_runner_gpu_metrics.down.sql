@@ -0,0 +1 @@
+ALTER TABLE runners DROP COLUMN IF EXISTS gpu_metrics;
@@ -0,0 +1,4 @@
+-- Latest GPU samples reported by the runner's agent:
+-- [{"gpu_index": 0, "utilization_percent": 87, "memory_used_mb": 14336,
+--   "memory_total_mb": 24576}]
+ALTER TABLE runners ADD COLUMN gpu_metrics JSONB;
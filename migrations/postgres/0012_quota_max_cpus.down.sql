@@ -0,0 +1 @@
+ALTER TABLE resource_quotas DROP COLUMN IF EXISTS max_cpus_per_runner;
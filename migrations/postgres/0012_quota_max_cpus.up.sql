@@ -0,0 +1,2 @@
+-- Cap on the number of CPU cores a runner may be pinned to (NULL = no cap)
+ALTER TABLE resource_quotas ADD COLUMN max_cpus_per_runner INTEGER;
@@ -0,0 +1,20 @@
+-- Collapse the monthly partitions back into a single outbox table
+
+ALTER TABLE outbox RENAME TO outbox_partitioned;
+ALTER SEQUENCE outbox_id_seq OWNED BY NONE;
+DROP INDEX IF EXISTS idx_outbox_undelivered;
+DROP INDEX IF EXISTS idx_outbox_event_id;
+DROP INDEX IF EXISTS idx_outbox_created;
+
+CREATE TABLE outbox (LIKE outbox_partitioned INCLUDING DEFAULTS);
+ALTER TABLE outbox ADD PRIMARY KEY (id);
+ALTER SEQUENCE outbox_id_seq OWNED BY outbox.id;
+
+CREATE INDEX idx_outbox_undelivered ON outbox(delivered, next_retry_at)
+    WHERE delivered = false;
+CREATE INDEX idx_outbox_event_id ON outbox(event_id);
+CREATE INDEX idx_outbox_created ON outbox(created_at);
+
+INSERT INTO outbox SELECT * FROM outbox_partitioned;
+
+DROP TABLE outbox_partitioned;
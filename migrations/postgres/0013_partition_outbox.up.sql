@@ -0,0 +1,75 @@
+-- Range-partition the outbox by month on created_at so old months can be
+-- dropped whole instead of deleted row by row. The publisher creates future
+-- partitions ahead of time and drops expired ones
+-- (daemon.outbox_partition_retention_months).
+--
+-- Partitions are named outbox_pYYYY_MM. The primary key must include the
+-- partition key, so it becomes (id, created_at).
+
+ALTER TABLE outbox RENAME TO outbox_unpartitioned;
+ALTER INDEX outbox_pkey RENAME TO outbox_unpartitioned_pkey;
+DROP INDEX IF EXISTS idx_outbox_undelivered;
+DROP INDEX IF EXISTS idx_outbox_event_id;
+DROP INDEX IF EXISTS idx_outbox_created;
+
+CREATE TABLE outbox (
+    id BIGINT NOT NULL DEFAULT nextval('outbox_id_seq'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered BOOLEAN NOT NULL DEFAULT FALSE,
+    delivered_at TIMESTAMPTZ,
+
+    -- Event identification
+    event_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    service_name TEXT NOT NULL DEFAULT 'stratavore',
+    aggregate_type TEXT,
+    aggregate_id TEXT,
+    event_type TEXT NOT NULL,
+
+    -- Event data
+    payload JSONB NOT NULL,
+    metadata JSONB DEFAULT '{}',
+
+    -- Routing
+    routing_key TEXT NOT NULL,
+
+    -- Retry management
+    attempts INTEGER DEFAULT 0,
+    max_attempts INTEGER DEFAULT 5,
+    last_attempt_at TIMESTAMPTZ,
+    next_retry_at TIMESTAMPTZ,
+    error TEXT,
+
+    -- Trace context
+    trace_id TEXT,
+    span_id TEXT,
+
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE outbox_id_seq OWNED BY outbox.id;
+
+CREATE INDEX idx_outbox_undelivered ON outbox(delivered, next_retry_at)
+    WHERE delivered = false;
+CREATE INDEX idx_outbox_event_id ON outbox(event_id);
+CREATE INDEX idx_outbox_created ON outbox(created_at);
+
+-- One partition per month from the oldest existing entry through next month
+DO $$
+DECLARE
+    month DATE := date_trunc('month', COALESCE(
+        (SELECT MIN(created_at) FROM outbox_unpartitioned), NOW()))::DATE;
+    last_month DATE := (date_trunc('month', NOW()) + INTERVAL '1 month')::DATE;
+BEGIN
+    WHILE month <= last_month LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF outbox FOR VALUES FROM (%L) TO (%L)',
+            'outbox_p' || to_char(month, 'YYYY_MM'),
+            month,
+            (month + INTERVAL '1 month')::DATE);
+        month := (month + INTERVAL '1 month')::DATE;
+    END LOOP;
+END $$;
+
+INSERT INTO outbox SELECT * FROM outbox_unpartitioned;
+
+DROP TABLE outbox_unpartitioned;
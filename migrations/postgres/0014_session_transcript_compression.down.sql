@@ -0,0 +1,2 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS uncompressed_size_bytes;
+ALTER TABLE sessions DROP COLUMN IF EXISTS compressed;
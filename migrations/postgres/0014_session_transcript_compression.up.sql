@@ -0,0 +1,4 @@
+-- Transcripts are gzip-compressed before upload; transcript_size_bytes holds
+-- the stored (compressed) size and uncompressed_size_bytes the original
+ALTER TABLE sessions ADD COLUMN compressed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE sessions ADD COLUMN uncompressed_size_bytes BIGINT;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS daemon_nodes;
@@ -0,0 +1,16 @@
+-- One row per daemon sharing this database. Each daemon refreshes its own
+-- last_heartbeat; any daemon's reconciliation loop marks nodes that stop
+-- heartbeating as inactive.
+CREATE TABLE daemon_nodes (
+    node_id TEXT PRIMARY KEY,
+    hostname TEXT NOT NULL,
+    version TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_heartbeat TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    grpc_port INTEGER,
+    http_port INTEGER,
+    active_runners INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active'  -- 'active', 'inactive'
+);
+
+CREATE INDEX idx_daemon_nodes_heartbeat ON daemon_nodes(last_heartbeat) WHERE status = 'active';
@@ -0,0 +1,14 @@
+-- Per-project notification destinations. Rows with a NULL project_name are
+-- global routes, used for projects that have no route for an event. An
+-- empty event_types array matches every event.
+CREATE TABLE project_notification_routes (
+    id BIGSERIAL PRIMARY KEY,
+    project_name TEXT REFERENCES projects(name) ON DELETE CASCADE,
+    backend TEXT NOT NULL,  -- slack, telegram
+    target TEXT NOT NULL,   -- webhook URL or chat ID
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_notification_routes_unique
+    ON project_notification_routes(COALESCE(project_name, ''), backend, target);
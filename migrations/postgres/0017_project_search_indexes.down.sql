@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_projects_status_last_accessed;
+DROP INDEX IF EXISTS idx_projects_tags;
@@ -0,0 +1,4 @@
+-- Support SearchProjects: tag subset matches use the GIN index, status
+-- filters ordered by recency use the composite B-tree index.
+CREATE INDEX idx_projects_tags ON projects USING GIN (tags);
+CREATE INDEX idx_projects_status_last_accessed ON projects(status, last_accessed_at);
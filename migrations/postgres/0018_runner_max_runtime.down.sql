@@ -0,0 +1,3 @@
+ALTER TABLE resource_quotas DROP COLUMN IF EXISTS max_runtime_seconds;
+ALTER TABLE runners DROP COLUMN IF EXISTS kill_reason;
+ALTER TABLE runners DROP COLUMN IF EXISTS max_runtime_seconds;
@@ -0,0 +1,7 @@
+-- Runners are stopped once they have run for max_runtime_seconds (0 = no
+-- limit); kill_reason records why the daemon stopped a runner
+ALTER TABLE runners ADD COLUMN max_runtime_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE runners ADD COLUMN kill_reason TEXT;
+
+-- Default runtime limit for a project's runners (NULL = unlimited)
+ALTER TABLE resource_quotas ADD COLUMN max_runtime_seconds INTEGER;
@@ -0,0 +1,14 @@
+-- Long-lived API tokens for scripts and CI. The token itself is never
+-- stored; its id is embedded in the token (jti) so it can be revoked.
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    scope TEXT[] NOT NULL DEFAULT '{}',
+    subject TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_api_tokens_active ON api_tokens(expires_at) WHERE revoked_at IS NULL;
@@ -0,0 +1,12 @@
+-- One row per runner restart, to show the pattern of crashes and delays
+-- behind a flapping runner's restart_attempts count
+CREATE TABLE runner_restarts (
+    id BIGSERIAL PRIMARY KEY,
+    runner_id UUID NOT NULL REFERENCES runners(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    restarted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    previous_exit_code INTEGER,
+    delay_seconds DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_runner_restarts_runner ON runner_restarts(runner_id, restarted_at);
@@ -0,0 +1,2 @@
+-- The path a project had before its last move, kept for auditing
+ALTER TABLE projects ADD COLUMN previous_path TEXT;
@@ -0,0 +1 @@
+ALTER TABLE resource_quotas DROP COLUMN IF EXISTS heartbeat_ttl_seconds;
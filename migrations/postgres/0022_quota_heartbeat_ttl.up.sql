@@ -0,0 +1,4 @@
+-- Heartbeat TTL for a project's runners (NULL = the daemon's default, three
+-- heartbeat intervals), for projects whose runners go quiet during long
+-- operations
+ALTER TABLE resource_quotas ADD COLUMN heartbeat_ttl_seconds INTEGER;
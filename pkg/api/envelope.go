@@ -0,0 +1,54 @@
+package api
+
+import (
+	"reflect"
+	"time"
+)
+
+// APIVersion identifies the HTTP API's response format. Clients compare it
+// with the version in each envelope to detect a mismatched daemon.
+const APIVersion = "v1"
+
+// Response is the envelope every JSON response from the HTTP API is wrapped
+// in. RequestID matches the X-Request-ID response header.
+type Response[T any] struct {
+	Data       T         `json:"data"`
+	Error      string    `json:"error,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	APIVersion string    `json:"api_version"`
+}
+
+// NewResponse wraps data in an envelope stamped with the current time. The
+// envelope's Error is taken from data's Error field, if it has one, so
+// clients can check errors without knowing the response type.
+func NewResponse[T any](data T, requestID string) *Response[T] {
+	return &Response[T]{
+		Data:       data,
+		Error:      errorField(data),
+		RequestID:  requestID,
+		Timestamp:  time.Now().UTC(),
+		APIVersion: APIVersion,
+	}
+}
+
+// errorField returns the Error string field of a struct or struct pointer,
+// or "" if it has none.
+func errorField(data any) string {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	f := v.FieldByName("Error")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// The daemon's gRPC service is described here by hand rather than generated
+// from stratavore.proto. Messages travel as JSON using the codec below, so
+// the plain structs in this package are the wire types for both HTTP and
+// gRPC.
+
+// JSONCodecName is the gRPC content-subtype for JSON-encoded messages
+const JSONCodecName = "json"
+
+// StratavoreServiceName is the fully-qualified gRPC service name
+const StratavoreServiceName = "stratavore.StratavoreService"
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return JSONCodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// StratavoreServiceServer is the subset of the daemon API served over gRPC
+type StratavoreServiceServer interface {
+	LaunchRunner(ctx context.Context, req *LaunchRunnerRequest) (*LaunchRunnerResponse, error)
+	StopRunner(ctx context.Context, req *StopRunnerRequest) (*StopRunnerResponse, error)
+	GetRunner(ctx context.Context, req *GetRunnerRequest) (*GetRunnerResponse, error)
+	ListRunners(ctx context.Context, req *ListRunnersRequest) (*ListRunnersResponse, error)
+	CreateProject(ctx context.Context, req *CreateProjectRequest) (*CreateProjectResponse, error)
+	GetProject(ctx context.Context, req *GetProjectRequest) (*GetProjectResponse, error)
+	ListProjects(ctx context.Context, req *ListProjectsRequest) (*ListProjectsResponse, error)
+	GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error)
+	TriggerReconciliation(ctx context.Context, req *TriggerReconciliationRequest) (*TriggerReconciliationResponse, error)
+	StreamLogs(req *RunnerLogsRequest, stream StratavoreService_StreamLogsServer) error
+}
+
+var stratavoreServiceDesc = grpc.ServiceDesc{
+	ServiceName: StratavoreServiceName,
+	HandlerType: (*StratavoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("LaunchRunner", StratavoreServiceServer.LaunchRunner),
+		unaryMethod("StopRunner", StratavoreServiceServer.StopRunner),
+		unaryMethod("GetRunner", StratavoreServiceServer.GetRunner),
+		unaryMethod("ListRunners", StratavoreServiceServer.ListRunners),
+		unaryMethod("CreateProject", StratavoreServiceServer.CreateProject),
+		unaryMethod("GetProject", StratavoreServiceServer.GetProject),
+		unaryMethod("ListProjects", StratavoreServiceServer.ListProjects),
+		unaryMethod("GetStatus", StratavoreServiceServer.GetStatus),
+		unaryMethod("TriggerReconciliation", StratavoreServiceServer.TriggerReconciliation),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogs",
+			Handler:       streamLogsHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stratavore.proto",
+}
+
+// RegisterStratavoreServiceServer registers srv with a gRPC server
+func RegisterStratavoreServiceServer(s grpc.ServiceRegistrar, srv StratavoreServiceServer) {
+	s.RegisterService(&stratavoreServiceDesc, srv)
+}
+
+// StratavoreMethod returns the full gRPC method path for a service method
+func StratavoreMethod(name string) string {
+	return "/" + StratavoreServiceName + "/" + name
+}
+
+// unaryMethod adapts a StratavoreServiceServer method to a gRPC handler,
+// running it through the server's interceptor chain
+func unaryMethod[Req, Resp any](name string, call func(StratavoreServiceServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+
+			s := srv.(StratavoreServiceServer)
+			if interceptor == nil {
+				return call(s, ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: StratavoreMethod(name),
+			}
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return call(s, ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// streamLogsHandler reads the StreamLogs request, the only message the
+// client sends, and hands the stream to the server
+func streamLogsHandler(srv any, stream grpc.ServerStream) error {
+	req := new(RunnerLogsRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(StratavoreServiceServer).StreamLogs(req, &streamLogsServer{stream})
+}
+
+// streamLogsServer adapts a grpc.ServerStream to
+// StratavoreService_StreamLogsServer
+type streamLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamLogsServer) Send(line *LogLine) error {
+	return x.SendMsg(line)
+}
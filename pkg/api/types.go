@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"time"
 )
 
@@ -18,6 +19,42 @@ type LaunchRunnerRequest struct {
 	ConversationMode string
 	SessionID        string
 	RuntimeType      string
+	PresetName       string
+	CPUAffinity      []int32
+
+	// Seconds after which the runner is stopped; 0 uses the project quota
+	MaxRuntimeSeconds int32
+}
+
+type CloneRunnerRequest struct {
+	SourceRunnerID string
+	NewProjectName string
+	Overrides      *LaunchRunnerRequest
+	ResumeSession  bool
+}
+
+type PollDeviceAuthRequest struct {
+	DeviceCode string
+}
+
+// CreateAPITokenRequest asks for a long-lived API token. An empty Scope
+// copies the caller's own scopes.
+type CreateAPITokenRequest struct {
+	Name             string
+	Scope            []string
+	ExpiresInSeconds int64
+}
+
+type ListAPITokensRequest struct{}
+
+type RevokeAPITokenRequest struct {
+	ID string
+}
+
+// RotateTokenRequest exchanges Token for a fresh one. Over HTTP an empty
+// Token rotates the token the request was authenticated with.
+type RotateTokenRequest struct {
+	Token string
 }
 
 type StopRunnerRequest struct {
@@ -26,6 +63,16 @@ type StopRunnerRequest struct {
 	TimeoutSeconds int32
 }
 
+// GetRunnerHistoryRequest lists runners in any state. Since and Until
+// bound the start time.
+type GetRunnerHistoryRequest struct {
+	ProjectName string
+	Status      string
+	Since       string // RFC3339
+	Until       string // RFC3339
+	Limit       int32
+}
+
 type GetRunnerRequest struct {
 	RunnerID string
 }
@@ -44,34 +91,263 @@ type CreateProjectRequest struct {
 	Tags        []string
 }
 
+// DuplicateProjectRequest copies a project's configuration to a new name
+// and path. NewPath must differ from the source project's path.
+type DuplicateProjectRequest struct {
+	SourceName string
+	NewName    string
+	NewPath    string
+}
+
+// MoveProjectRequest points a project at the directory it was moved to.
+// NewPath must be an existing directory on the daemon's host.
+type MoveProjectRequest struct {
+	Name    string
+	NewPath string
+}
+
+// UpdateProjectRequest changes some of a project's fields; nil fields are
+// left unchanged
+type UpdateProjectRequest struct {
+	Name        string
+	Description *string
+	Path        *string
+	Tags        *[]string
+}
+
+// CreateProjectSetupRequest creates a project and, when MaxRunners or
+// TokenBudget are set, its resource quota and token budget
+type CreateProjectSetupRequest struct {
+	Name         string
+	Path         string
+	Description  string
+	Tags         []string
+	MaxRunners   int32
+	TokenBudget  int64
+	BudgetPeriod string // hourly, daily, weekly or monthly (default daily)
+
+	// Default runtime limit for the project's runners (0 = unlimited)
+	MaxRuntimeSeconds int32
+}
+
 type GetProjectRequest struct {
 	Name string
 }
 
 type ListProjectsRequest struct {
-	Status string
+	Status          string
+	IncludeArchived bool
+}
+
+// SearchProjectsRequest narrows projects by name, tags and status. Empty
+// fields match everything; a project must carry every listed tag.
+type SearchProjectsRequest struct {
+	Query            string // substring of the project name
+	Tags             []string
+	Status           []string
+	CreatedAfter     string // RFC3339
+	CreatedBefore    string // RFC3339
+	HasActiveRunners bool
+}
+
+type ArchiveProjectRequest struct {
+	Name string
+}
+
+type DrainProjectRequest struct {
+	Name           string
+	TimeoutSeconds int32 // grace period before remaining runners are killed
+}
+
+// StopAllRunnersRequest stops every active runner, or only ProjectName's
+type StopAllRunnersRequest struct {
+	ProjectName    string
+	TimeoutSeconds int32 // grace period for project runners before they are killed
 }
 
 type HeartbeatRequest struct {
-	RunnerID     string
-	Status       string
-	CPUPercent   float64
-	MemoryMB     int64
-	TokensUsed   int64
-	SessionID    string
-	AgentVersion string
-	Hostname     string
+	RunnerID        string
+	Status          string
+	CPUPercent      float64
+	MemoryMB        int64
+	TokensUsed      int64
+	SessionID       string
+	AgentVersion    string
+	Hostname        string
+	GPUSamples      []*GPUSample
+	CPUAffinity     []int32
+	ErrorContext    string
+	PreflightResult *PreflightResult
+}
+
+// PreflightResult reports the checks an agent ran before starting claude
+// code
+type PreflightResult struct {
+	Passed bool
+	Checks []*PreflightCheck
+}
+
+type PreflightCheck struct {
+	Name     string
+	Passed   bool
+	Critical bool
+	Message  string
+}
+
+type GPUSample struct {
+	GPUIndex           int32
+	UtilizationPercent float64
+	MemoryUsedMB       int64
+	MemoryTotalMB      int64
 }
 
 type GetStatusRequest struct{}
 
+type GetReadinessRequest struct{}
+
+type CheckBudgetRequest struct {
+	ProjectName     string
+	EstimatedTokens int64
+}
+
+type GetUsageReportRequest struct {
+	Scope   string // "global" or "project"
+	ScopeID string
+	Days    int32
+}
+
+type GetBudgetHistoryRequest struct {
+	Scope   string // "global" or "project"
+	ScopeID string
+	Limit   int32
+}
+
+type GetTokenLeaderboardRequest struct {
+	Period string // "today", "week", "month" or "all"
+	Limit  int32
+}
+
+type GetDaemonVersionRequest struct{}
+
+type GetNotificationHistoryRequest struct {
+	Limit     int32
+	EventType string
+}
+
+type GetActivityRequest struct {
+	Limit int32
+}
+
+type CreateNotificationRouteRequest struct {
+	Route *NotificationRoute
+}
+
+type GetRunnerEventsRequest struct {
+	RunnerID string
+}
+
+type GetRunnerRestartsRequest struct {
+	RunnerID string
+}
+
+type GetProjectEventsRequest struct {
+	ProjectName string
+	Limit       int32
+}
+
+type GetProjectQuotaRequest struct {
+	ProjectName string
+}
+
+// SetProjectQuotaRequest changes some of a project's quota limits; nil
+// fields are left unchanged. Zero runtime and heartbeat TTL restore the
+// defaults.
+type SetProjectQuotaRequest struct {
+	ProjectName          string
+	MaxConcurrentRunners *int32
+	MaxRuntimeSeconds    *int64
+	HeartbeatTTLSeconds  *int64
+}
+
+type RunnerLogsRequest struct {
+	RunnerID  string
+	TailLines int32 // 0 = whole buffer
+	Follow    bool
+}
+
 type TriggerReconciliationRequest struct{}
 
+type ListSessionsRequest struct {
+	ProjectName string
+	Limit       int32
+}
+
+type SearchSessionsRequest struct {
+	Query       string
+	ProjectName string
+	Limit       int32
+}
+
+type SemanticSearchSessionsRequest struct {
+	Query       string
+	ProjectName string
+	Limit       int32
+}
+
+type GetSessionsByTimeRangeRequest struct {
+	From          string // RFC3339
+	To            string // RFC3339
+	ProjectName   string
+	IncludeActive bool
+}
+
+type PinSessionRequest struct {
+	SessionID string
+}
+
+// CleanupSessionsRequest selects non-resumable sessions that ended before
+// Before (RFC3339) for deletion. DryRun only counts them.
+type CleanupSessionsRequest struct {
+	Before      string
+	ProjectName string
+	DryRun      bool
+}
+
+type ResumeSessionRequest struct {
+	SessionID string
+}
+
+type BranchSessionRequest struct {
+	SourceSessionID  string
+	ForkAfterMessage int32
+}
+
+type CreatePresetRequest struct {
+	Preset *Preset
+}
+
+type ListPresetsRequest struct {
+	ProjectName string
+}
+
+type DeletePresetRequest struct {
+	Name string
+}
+
 // ===== RESPONSE TYPES =====
 
+// Error codes accompany Error on responses whose failures clients handle
+// differently, so they don't have to match on the message
+const (
+	ErrorCodeProjectArchived = "project_archived"
+	ErrorCodeProjectDraining = "project_draining"
+	ErrorCodeNotClonable     = "not_clonable"
+)
+
 type LaunchRunnerResponse struct {
-	Runner *Runner
-	Error  string
+	Runner    *Runner
+	Error     string
+	ErrorCode string // one of the ErrorCode constants, when Error has one
 }
 
 type StopRunnerResponse struct {
@@ -92,9 +368,17 @@ type ListRunnersResponse struct {
 
 type CreateProjectResponse struct {
 	Project *Project
+	Created bool // false when an existing project was updated
 	Error   string
 }
 
+type MoveProjectResponse struct {
+	Project          *Project
+	OldPath          string
+	SignalledRunners int32 // active runners sent the directory changed signal
+	Error            string
+}
+
 type GetProjectResponse struct {
 	Project *Project
 	Error   string
@@ -107,10 +391,14 @@ type ListProjectsResponse struct {
 
 type HeartbeatResponse struct {
 	Success bool
-	Command string
+	Command string // HeartbeatCommandExit, or empty
 	Error   string
 }
 
+// HeartbeatCommandExit tells an agent its runner has already terminated, so
+// it should stop Claude Code and exit instead of sending more heartbeats
+const HeartbeatCommandExit = "exit"
+
 type GetStatusResponse struct {
 	Daemon  *DaemonStatus
 	Metrics *GlobalMetrics
@@ -123,8 +411,241 @@ type TriggerReconciliationResponse struct {
 	Error            string
 }
 
+type GetReadinessResponse struct {
+	Ready      bool
+	Degraded   bool   // up but shedding load, e.g. the database pool is saturated
+	Reason     string // why Degraded is set, e.g. "pool_saturation"
+	Components []*ComponentHealth
+	Postgres   *PoolStats
+	Error      string
+}
+
+type CheckBudgetResponse struct {
+	Allowed         bool
+	RemainingTokens int64
+	Unlimited       bool
+	Error           string
+}
+
+type GetUsageReportResponse struct {
+	Scope                 string
+	ScopeID               string
+	DailyUsage            []*DailyUsagePoint
+	TotalUsage            int64
+	AverageDailyUsage     float64
+	PeakDayUsage          int64
+	PeakDayDate           string
+	ProjectedMonthlyUsage int64
+	Error                 string
+}
+
+type GetBudgetHistoryResponse struct {
+	Periods []*BudgetPeriod
+	Error   string
+}
+
+type GetTokenLeaderboardResponse struct {
+	Period  string
+	Entries []*TokenLeaderboardEntry
+	Error   string
+}
+
+// BudgetPeriod is one period of a token budget. Times are RFC3339.
+type BudgetPeriod struct {
+	PeriodGranularity string
+	PeriodStart       string
+	PeriodEnd         string
+	LimitTokens       int64
+	UsedTokens        int64
+	Current           bool
+}
+
+type GetDaemonVersionResponse struct {
+	CurrentVersion             string
+	BuildTime                  string
+	Commit                     string
+	MinCompatibleClientVersion string
+	LatestVersion              string
+	UpdateAvailable            bool
+	BinaryPath                 string
+	DownloadURL                string
+	ChecksumURL                string
+	Error                      string
+}
+
+type ListNodesResponse struct {
+	Nodes []*DaemonNode
+	Error string
+}
+
+type GetNotificationHistoryResponse struct {
+	Entries []*NotificationLogEntry
+	Error   string
+}
+
+type GetActivityResponse struct {
+	Events []*ActivityEvent
+	Error  string
+}
+
+type CreateNotificationRouteResponse struct {
+	Route *NotificationRoute
+	Error string
+}
+
+type GetRunnerEventsResponse struct {
+	Events []*RunnerEvent
+	Error  string
+}
+
+type GetRunnerRestartsResponse struct {
+	Restarts []*RunnerRestart
+	Error    string
+}
+
+type GetProjectQuotaResponse struct {
+	Quota *ResourceQuota
+	Error string
+}
+
+type ArchiveProjectResponse struct {
+	Success bool
+	Error   string
+}
+
+type StopAllRunnersResponse struct {
+	Stopped []string
+	Failed  map[string]string // runner ID to error
+	Error   string
+}
+
+type DrainProjectResponse struct {
+	Graceful  int32 // runners that exited after SIGTERM
+	Forced    int32 // runners killed after the timeout
+	Success   bool
+	Error     string
+	ErrorCode string // one of the ErrorCode constants, when Error has one
+}
+
+type ListSessionsResponse struct {
+	Sessions []*Session
+	Error    string
+}
+
+type GetSessionsByTimeRangeResponse struct {
+	Sessions      []*Session
+	TotalSessions int32
+	TotalTokens   int64
+	Error         string
+}
+
+type PinSessionResponse struct {
+	Success bool
+	Error   string
+}
+
+type CleanupSessionsResponse struct {
+	Deleted     int64
+	TokensFreed int64
+	DryRun      bool
+	Error       string
+}
+
+type ResumeSessionResponse struct {
+	Session      *Session
+	Runner       *Runner
+	RunnerActive bool // the session's original runner is still running
+	Error        string
+}
+
+type BranchSessionResponse struct {
+	Session *Session
+	Runner  *Runner // resumes the new session
+	Error   string
+}
+
+type CreatePresetResponse struct {
+	Preset *Preset
+	Error  string
+}
+
+type ListPresetsResponse struct {
+	Presets []*Preset
+	Error   string
+}
+
+type DeletePresetResponse struct {
+	Success bool
+	Error   string
+}
+
+type StartDeviceAuthResponse struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	ExpiresIn       int32 // seconds
+	Interval        int32 // minimum seconds between polls
+	Error           string
+}
+
+// PollDeviceAuthResponse carries the token once approved. Until then Error
+// holds an RFC 8628 code: authorization_pending, access_denied or
+// expired_token.
+type PollDeviceAuthResponse struct {
+	Token     string
+	ExpiresAt string
+	Error     string
+}
+
+// CreateAPITokenResponse carries the signed token, which the daemon does not
+// keep and cannot show again
+type CreateAPITokenResponse struct {
+	Token    string
+	APIToken *APIToken
+	Error    string
+}
+
+type ListAPITokensResponse struct {
+	Tokens []*APIToken
+	Error  string
+}
+
+type RevokeAPITokenResponse struct {
+	Success bool
+	Error   string
+}
+
+// RotateTokenResponse carries the replacement token; the old one no longer
+// works
+type RotateTokenResponse struct {
+	Token     string
+	ExpiresAt string
+	Error     string
+}
+
+// ===== STREAM TYPES =====
+
+// StratavoreService_StreamLogsServer is the server side of the StreamLogs
+// stream, matching what protoc-gen-go-grpc would generate
+type StratavoreService_StreamLogsServer interface {
+	Send(*LogLine) error
+	Context() context.Context
+}
+
 // ===== MODEL TYPES =====
 
+// APIToken is a long-lived API token's metadata. Times are RFC3339;
+// LastUsedAt is empty until the token is first used.
+type APIToken struct {
+	ID         string
+	Name       string
+	Scope      []string
+	Subject    string
+	CreatedAt  string
+	LastUsedAt string
+	ExpiresAt  string
+}
+
 type Runner struct {
 	ID                 string
 	RuntimeType        string
@@ -148,6 +669,8 @@ type Runner struct {
 	HeartbeatTTL       int32
 	TerminatedAt       string
 	ExitCode           int32
+	MaxRuntimeSeconds  int32
+	KillReason         string
 	CreatedAt          string
 	UpdatedAt          string
 }
@@ -166,6 +689,131 @@ type Project struct {
 	LastAccessedAt string
 	ArchivedAt     string
 	UpdatedAt      string
+
+	// Live aggregates, populated by GetProject only
+	ActiveTokens      int64
+	SessionsThisWeek  int32
+	AvgSessionSeconds float64
+}
+
+type Session struct {
+	ID            string
+	RunnerID      string
+	ProjectName   string
+	StartedAt     string
+	EndedAt       string
+	LastMessageAt string
+	MessageCount  int32
+	TokensUsed    int64
+	Resumable     bool
+	ResumedFrom   string
+	Summary       string
+	Pinned        bool
+	Snippet       string
+	Score         float32 // semantic search similarity, 0 otherwise
+}
+
+type Preset struct {
+	Name             string
+	ProjectName      string
+	RuntimeType      string
+	Flags            []string
+	Capabilities     []string
+	Environment      map[string]string
+	ConversationMode string
+	Description      string
+	CreatedAt        string
+	UpdatedAt        string
+}
+
+type DailyUsagePoint struct {
+	Date   string // YYYY-MM-DD
+	Tokens int64
+}
+
+type TokenLeaderboardEntry struct {
+	Rank             int32
+	ProjectName      string
+	TokensUsed       int64
+	EstimatedCostUSD float64
+}
+
+type DaemonNode struct {
+	NodeID        string
+	Hostname      string
+	Version       string
+	StartedAt     string
+	LastHeartbeat string
+	GRPCPort      int32
+	HTTPPort      int32
+	ActiveRunners int32
+	Status        string
+}
+
+type NotificationLogEntry struct {
+	ID          int64
+	SentAt      string
+	Backend     string
+	EventType   string
+	Target      string
+	MessageText string
+	Success     bool
+	Error       string
+}
+
+type ActivityEvent struct {
+	Timestamp   string
+	EventType   string
+	ProjectName string
+	ActorID     string // runner or session ID
+	Description string
+}
+
+type NotificationRoute struct {
+	ID          int64
+	ProjectName string // empty for a global route
+	Backend     string
+	Target      string
+	EventTypes  []string // empty matches every event
+	CreatedAt   string
+}
+
+type LogLine struct {
+	Timestamp string // RFC3339Nano
+	Content   string
+	Stream    string // "stdout" or "stderr"
+}
+
+type RunnerEvent struct {
+	ID        int64
+	EventID   string
+	Timestamp string
+	EventType string
+	RunnerID  string
+	Data      map[string]interface{}
+	Hostname  string
+}
+
+// RunnerRestart is one restart of a runner. PreviousExitCode is nil when
+// the earlier exit code wasn't recorded.
+type RunnerRestart struct {
+	Attempt          int32
+	RestartedAt      string
+	PreviousExitCode *int32
+	DelaySeconds     float64
+}
+
+// ResourceQuota is a project's runner limits; zero means unlimited, except
+// MaxConcurrentRunners which always has a value
+type ResourceQuota struct {
+	ProjectName          string
+	MaxConcurrentRunners int32
+	MaxMemoryMB          int64
+	MaxCPUPercent        int32
+	MaxTokensPerDay      int64
+	MaxCPUsPerRunner     int32
+	MaxRuntimeSeconds    int64
+	HeartbeatTTLSeconds  int64 // 0 = daemon default
 }
 
 type DaemonStatus struct {
@@ -175,14 +823,34 @@ type DaemonStatus struct {
 	StartedAt     string
 	LastHeartbeat string
 	Healthy       bool
+	PID           int32
+	UptimeSeconds int64
+	Goroutines    int32
 }
 
 type GlobalMetrics struct {
-	ActiveRunners  int32
-	ActiveProjects int32
-	TotalSessions  int32
-	TokensUsed     int64
-	TokenLimit     int64
+	ActiveRunners   int32
+	ActiveProjects  int32
+	TotalSessions   int32
+	TokensUsed      int64
+	TokenLimit      int64
+	RunnersByStatus map[string]int32
+	TokensToday     int64
+	QueuedLaunches  int32 // launches waiting for a free launch slot on this node
+}
+
+type ComponentHealth struct {
+	Name   string
+	Status string // "ok", "down" or "disabled"
+	Detail string
+}
+
+type PoolStats struct {
+	TotalConns    int32
+	AcquiredConns int32
+	IdleConns     int32
+	MaxConns      int32
+	AcquireCount  int64
 }
 
 // ===== CONVERSION HELPERS =====
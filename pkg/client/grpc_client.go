@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/meridian-lex/stratavore/internal/auth"
+	"github.com/meridian-lex/stratavore/pkg/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// grpcServiceConfig retries calls that fail before reaching the daemon,
+// e.g. while it is restarting
+const grpcServiceConfig = `{
+	"methodConfig": [{
+		"name": [{"service": "stratavore.StratavoreService"}],
+		"retryPolicy": {
+			"maxAttempts": 4,
+			"initialBackoff": "0.2s",
+			"maxBackoff": "2s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// GRPCOptions configures a GRPCClient
+type GRPCOptions struct {
+	TLS        *tls.Config // nil dials without transport security
+	AuthSecret string      // signs calls for the daemon's HMAC check
+}
+
+// GRPCClient communicates with the stratavore daemon over gRPC. It offers
+// the same methods as Client for the calls the gRPC service serves.
+type GRPCClient struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCClient creates a gRPC client for the daemon at host:port. The
+// connection is established lazily on the first call.
+func NewGRPCClient(host string, port int, opts GRPCOptions) (*GRPCClient, error) {
+	creds := insecure.NewCredentials()
+	if opts.TLS != nil {
+		creds = credentials.NewTLS(opts.TLS)
+	}
+
+	conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", host, port),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(grpcServiceConfig),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(api.JSONCodecName)),
+		grpc.WithUnaryInterceptor(auth.HMACUnaryClientInterceptor(opts.AuthSecret)),
+		grpc.WithStreamInterceptor(auth.HMACStreamClientInterceptor(opts.AuthSecret)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create grpc client: %w", err)
+	}
+
+	return &GRPCClient{conn: conn}, nil
+}
+
+// Close releases the connection
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *GRPCClient) invoke(ctx context.Context, method string, req, resp any) error {
+	return c.conn.Invoke(ctx, api.StratavoreMethod(method), req, resp)
+}
+
+// LaunchRunner launches a new runner
+func (c *GRPCClient) LaunchRunner(ctx context.Context, req *api.LaunchRunnerRequest) (*api.LaunchRunnerResponse, error) {
+	var resp api.LaunchRunnerResponse
+	err := c.invoke(ctx, "LaunchRunner", req, &resp)
+	return &resp, err
+}
+
+// StopRunner stops a runner
+func (c *GRPCClient) StopRunner(ctx context.Context, runnerID string, force bool) (*api.StopRunnerResponse, error) {
+	var resp api.StopRunnerResponse
+	err := c.invoke(ctx, "StopRunner", &api.StopRunnerRequest{RunnerID: runnerID, Force: force}, &resp)
+	return &resp, err
+}
+
+// GetRunner retrieves runner details
+func (c *GRPCClient) GetRunner(ctx context.Context, runnerID string) (*api.GetRunnerResponse, error) {
+	var resp api.GetRunnerResponse
+	err := c.invoke(ctx, "GetRunner", &api.GetRunnerRequest{RunnerID: runnerID}, &resp)
+	return &resp, err
+}
+
+// ListRunners lists runners, optionally for one project
+func (c *GRPCClient) ListRunners(ctx context.Context, projectName string) (*api.ListRunnersResponse, error) {
+	var resp api.ListRunnersResponse
+	err := c.invoke(ctx, "ListRunners", &api.ListRunnersRequest{ProjectName: projectName}, &resp)
+	return &resp, err
+}
+
+// CreateProject creates a new project
+func (c *GRPCClient) CreateProject(ctx context.Context, req *api.CreateProjectRequest) (*api.CreateProjectResponse, error) {
+	var resp api.CreateProjectResponse
+	err := c.invoke(ctx, "CreateProject", req, &resp)
+	return &resp, err
+}
+
+// GetProject retrieves a single project
+func (c *GRPCClient) GetProject(ctx context.Context, name string) (*api.GetProjectResponse, error) {
+	var resp api.GetProjectResponse
+	err := c.invoke(ctx, "GetProject", &api.GetProjectRequest{Name: name}, &resp)
+	return &resp, err
+}
+
+// ListProjects lists projects; archived ones are omitted unless
+// includeArchived is set or status asks for them
+func (c *GRPCClient) ListProjects(ctx context.Context, status string, includeArchived bool) (*api.ListProjectsResponse, error) {
+	var resp api.ListProjectsResponse
+	err := c.invoke(ctx, "ListProjects", &api.ListProjectsRequest{
+		Status:          status,
+		IncludeArchived: includeArchived,
+	}, &resp)
+	return &resp, err
+}
+
+// GetStatus retrieves daemon status
+func (c *GRPCClient) GetStatus(ctx context.Context) (*api.GetStatusResponse, error) {
+	var resp api.GetStatusResponse
+	err := c.invoke(ctx, "GetStatus", &api.GetStatusRequest{}, &resp)
+	return &resp, err
+}
+
+// TriggerReconciliation asks the daemon to reconcile runner state now
+func (c *GRPCClient) TriggerReconciliation(ctx context.Context) (*api.TriggerReconciliationResponse, error) {
+	var resp api.TriggerReconciliationResponse
+	err := c.invoke(ctx, "TriggerReconciliation", &api.TriggerReconciliationRequest{}, &resp)
+	return &resp, err
+}
+
+// streamLogsDesc describes the daemon's server-streaming StreamLogs call
+var streamLogsDesc = grpc.StreamDesc{
+	StreamName:    "StreamLogs",
+	ServerStreams: true,
+}
+
+// StreamLogs reads a runner's log lines, calling fn for each one. With
+// follow it blocks until the runner exits or ctx is cancelled.
+func (c *GRPCClient) StreamLogs(ctx context.Context, runnerID string, tailLines int, follow bool, fn func(*api.LogLine)) error {
+	stream, err := c.conn.NewStream(ctx, &streamLogsDesc, api.StratavoreMethod("StreamLogs"))
+	if err != nil {
+		return err
+	}
+
+	req := &api.RunnerLogsRequest{
+		RunnerID:  runnerID,
+		TailLines: int32(tailLines),
+		Follow:    follow,
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		var line api.LogLine
+		if err := stream.RecvMsg(&line); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		fn(&line)
+	}
+}
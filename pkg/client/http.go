@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/meridian-lex/stratavore/pkg/api"
@@ -17,8 +20,14 @@ import (
 type Client struct {
 	baseURL string
 	version int
+	token   string
 	client  *http.Client
+	grpc    *GRPCClient
 	logger  *zap.Logger
+
+	// versionWarning logs an API version mismatch once; it is shared by
+	// the copies made for long-running requests
+	versionWarning *sync.Once
 }
 
 // NewClient creates a new API client
@@ -30,19 +39,90 @@ func NewClient(host string, port int, version int) *Client {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		logger: logger,
+		logger:         logger,
+		versionWarning: &sync.Once{},
 	}
 }
 
+// UseGRPC sends the calls the daemon serves over gRPC through g; all other
+// calls keep using HTTP
+func (c *Client) UseGRPC(g *GRPCClient) {
+	c.grpc = g
+}
+
+// SetToken sets the bearer token sent with every request
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// StartDeviceAuth begins a device flow login
+func (c *Client) StartDeviceAuth(ctx context.Context) (*api.StartDeviceAuthResponse, error) {
+	var resp api.StartDeviceAuthResponse
+	err := c.post(ctx, "/auth/device/start", nil, &resp)
+	return &resp, err
+}
+
+// PollDeviceAuth checks whether a device login has been approved
+func (c *Client) PollDeviceAuth(ctx context.Context, deviceCode string) (*api.PollDeviceAuthResponse, error) {
+	var resp api.PollDeviceAuthResponse
+	err := c.post(ctx, "/auth/device/poll?device_code="+url.QueryEscape(deviceCode), nil, &resp)
+	return &resp, err
+}
+
+// CreateAPIToken creates a long-lived API token. The token is only returned
+// here; the daemon keeps just its metadata.
+func (c *Client) CreateAPIToken(ctx context.Context, req *api.CreateAPITokenRequest) (*api.CreateAPITokenResponse, error) {
+	var resp api.CreateAPITokenResponse
+	err := c.post(ctx, "/auth/tokens", req, &resp)
+	return &resp, err
+}
+
+// ListAPITokens lists API tokens that are neither revoked nor expired
+func (c *Client) ListAPITokens(ctx context.Context) (*api.ListAPITokensResponse, error) {
+	var resp api.ListAPITokensResponse
+	err := c.get(ctx, c.baseURL+"/auth/tokens", &resp)
+	return &resp, err
+}
+
+// RotateToken exchanges token for a fresh one with a refreshed expiry. An
+// empty token rotates the one set with SetToken.
+func (c *Client) RotateToken(ctx context.Context, token string) (*api.RotateTokenResponse, error) {
+	var resp api.RotateTokenResponse
+	err := c.post(ctx, "/auth/rotate", &api.RotateTokenRequest{Token: token}, &resp)
+	return &resp, err
+}
+
+// RevokeAPIToken revokes an API token by ID
+func (c *Client) RevokeAPIToken(ctx context.Context, tokenID string) (*api.RevokeAPITokenResponse, error) {
+	var resp api.RevokeAPITokenResponse
+	err := c.delete(ctx, "/auth/tokens/"+url.PathEscape(tokenID), &resp)
+	return &resp, err
+}
+
 // LaunchRunner launches a new runner
 func (c *Client) LaunchRunner(ctx context.Context, req *api.LaunchRunnerRequest) (*api.LaunchRunnerResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.LaunchRunner(ctx, req)
+	}
+
 	var resp api.LaunchRunnerResponse
 	err := c.post(ctx, "/runners/launch", req, &resp)
 	return &resp, err
 }
 
+// CloneRunner launches a new runner from an existing runner's configuration
+func (c *Client) CloneRunner(ctx context.Context, req *api.CloneRunnerRequest) (*api.LaunchRunnerResponse, error) {
+	var resp api.LaunchRunnerResponse
+	err := c.post(ctx, "/runners/clone", req, &resp)
+	return &resp, err
+}
+
 // StopRunner stops a running runner
 func (c *Client) StopRunner(ctx context.Context, runnerID string, force bool) (*api.StopRunnerResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.StopRunner(ctx, runnerID, force)
+	}
+
 	req := &api.StopRunnerRequest{
 		RunnerID: runnerID,
 		Force:    force,
@@ -54,14 +134,133 @@ func (c *Client) StopRunner(ctx context.Context, runnerID string, force bool) (*
 
 // GetRunner retrieves runner details
 func (c *Client) GetRunner(ctx context.Context, runnerID string) (*api.GetRunnerResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.GetRunner(ctx, runnerID)
+	}
+
 	var resp api.GetRunnerResponse
 	url := fmt.Sprintf("%s/runners/get?id=%s", c.baseURL, runnerID)
 	err := c.get(ctx, url, &resp)
 	return &resp, err
 }
 
+// GetRunnerEvents retrieves the lifecycle event timeline for a runner
+func (c *Client) GetRunnerEvents(ctx context.Context, runnerID string) (*api.GetRunnerEventsResponse, error) {
+	var resp api.GetRunnerEventsResponse
+	url := fmt.Sprintf("%s/runners/%s/events", c.baseURL, runnerID)
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// GetRunnerRestarts retrieves a runner's restart history
+func (c *Client) GetRunnerRestarts(ctx context.Context, runnerID string) (*api.GetRunnerRestartsResponse, error) {
+	var resp api.GetRunnerRestartsResponse
+	url := fmt.Sprintf("%s/runners/%s/restarts", c.baseURL, runnerID)
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// GetProjectEvents retrieves the most recent lifecycle events of a
+// project's runners, newest first
+func (c *Client) GetProjectEvents(ctx context.Context, projectName string, limit int) (*api.GetRunnerEventsResponse, error) {
+	var resp api.GetRunnerEventsResponse
+	url := fmt.Sprintf("%s/projects/%s/events?limit=%d", c.baseURL, url.PathEscape(projectName), limit)
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// GetProjectQuota retrieves a project's resource quota
+func (c *Client) GetProjectQuota(ctx context.Context, projectName string) (*api.GetProjectQuotaResponse, error) {
+	var resp api.GetProjectQuotaResponse
+	url := fmt.Sprintf("%s/projects/%s/quota", c.baseURL, url.PathEscape(projectName))
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// SetProjectQuota changes the quota limits set in req and returns the
+// updated quota
+func (c *Client) SetProjectQuota(ctx context.Context, req *api.SetProjectQuotaRequest) (*api.GetProjectQuotaResponse, error) {
+	var resp api.GetProjectQuotaResponse
+	err := c.patch(ctx, "/projects/"+url.PathEscape(req.ProjectName)+"/quota", req, &resp)
+	return &resp, err
+}
+
+// GetRunnerHistory lists runners in any state matching req
+func (c *Client) GetRunnerHistory(ctx context.Context, req *api.GetRunnerHistoryRequest) (*api.ListRunnersResponse, error) {
+	var resp api.ListRunnersResponse
+	params := url.Values{}
+	if req.ProjectName != "" {
+		params.Set("project", req.ProjectName)
+	}
+	if req.Status != "" {
+		params.Set("status", req.Status)
+	}
+	if req.Since != "" {
+		params.Set("since", req.Since)
+	}
+	if req.Until != "" {
+		params.Set("until", req.Until)
+	}
+	if req.Limit > 0 {
+		params.Set("limit", strconv.Itoa(int(req.Limit)))
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/runners/history?%s", c.baseURL, params.Encode()), &resp)
+	return &resp, err
+}
+
+// StreamLogs reads a runner's log lines, calling fn for each one. With
+// follow it blocks until the runner exits or ctx is cancelled.
+func (c *Client) StreamLogs(ctx context.Context, runnerID string, tailLines int, follow bool, fn func(*api.LogLine)) error {
+	if c.grpc != nil {
+		return c.grpc.StreamLogs(ctx, runnerID, tailLines, follow, fn)
+	}
+
+	q := url.Values{}
+	q.Set("id", runnerID)
+	q.Set("tail", strconv.Itoa(tailLines))
+	q.Set("follow", strconv.FormatBool(follow))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/runners/logs?"+q.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	c.authorize(req)
+
+	// Followed streams run indefinitely, so skip the client timeout
+	httpClient := c.client
+	if follow {
+		httpClient = &http.Client{Transport: c.client.Transport}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line api.LogLine
+		if err := dec.Decode(&line); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("decode log line: %w", err)
+		}
+		fn(&line)
+	}
+}
+
 // ListRunners lists active runners
 func (c *Client) ListRunners(ctx context.Context, projectName string) (*api.ListRunnersResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.ListRunners(ctx, projectName)
+	}
+
 	var resp api.ListRunnersResponse
 	url := fmt.Sprintf("%s/runners/list", c.baseURL)
 	if projectName != "" {
@@ -73,22 +272,353 @@ func (c *Client) ListRunners(ctx context.Context, projectName string) (*api.List
 
 // CreateProject creates a new project
 func (c *Client) CreateProject(ctx context.Context, req *api.CreateProjectRequest) (*api.CreateProjectResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.CreateProject(ctx, req)
+	}
+
 	var resp api.CreateProjectResponse
 	err := c.post(ctx, "/projects/create", req, &resp)
 	return &resp, err
 }
 
-// ListProjects lists all projects
-func (c *Client) ListProjects(ctx context.Context, status string) (*api.ListProjectsResponse, error) {
+// CreateProjectSetup creates a project with its quota and token budget in
+// one request
+func (c *Client) CreateProjectSetup(ctx context.Context, req *api.CreateProjectSetupRequest) (*api.CreateProjectResponse, error) {
+	var resp api.CreateProjectResponse
+	err := c.post(ctx, "/projects/setup", req, &resp)
+	return &resp, err
+}
+
+// DuplicateProject copies a project's configuration to a new project
+func (c *Client) DuplicateProject(ctx context.Context, req *api.DuplicateProjectRequest) (*api.CreateProjectResponse, error) {
+	var resp api.CreateProjectResponse
+	err := c.post(ctx, "/projects/duplicate", req, &resp)
+	return &resp, err
+}
+
+// MoveProject points a project at the directory it was moved to
+func (c *Client) MoveProject(ctx context.Context, req *api.MoveProjectRequest) (*api.MoveProjectResponse, error) {
+	var resp api.MoveProjectResponse
+	err := c.post(ctx, "/projects/move", req, &resp)
+	return &resp, err
+}
+
+// UpdateProject changes the fields set in req and returns the updated
+// project
+func (c *Client) UpdateProject(ctx context.Context, req *api.UpdateProjectRequest) (*api.GetProjectResponse, error) {
+	var resp api.GetProjectResponse
+	err := c.patch(ctx, "/projects/"+url.PathEscape(req.Name), req, &resp)
+	return &resp, err
+}
+
+// ArchiveProject archives a project
+func (c *Client) ArchiveProject(ctx context.Context, name string) (*api.ArchiveProjectResponse, error) {
+	var resp api.ArchiveProjectResponse
+	err := c.post(ctx, "/projects/archive", &api.ArchiveProjectRequest{Name: name}, &resp)
+	return &resp, err
+}
+
+// DrainProject stops a project's runners, waiting up to timeout for them to
+// exit before they are killed, then archives the project
+func (c *Client) DrainProject(ctx context.Context, name string, timeout time.Duration) (*api.DrainProjectResponse, error) {
+	// The daemon holds the request open while runners exit
+	drainClient := *c
+	drainClient.client = &http.Client{
+		Transport: c.client.Transport,
+		Timeout:   timeout + c.client.Timeout,
+	}
+
+	var resp api.DrainProjectResponse
+	err := drainClient.post(ctx, "/projects/drain", &api.DrainProjectRequest{
+		Name:           name,
+		TimeoutSeconds: int32(timeout / time.Second),
+	}, &resp)
+	return &resp, err
+}
+
+// StopAllRunners stops every active runner, or only projectName's when set.
+// Project runners get timeout to exit before they are killed; zero uses the
+// daemon default.
+func (c *Client) StopAllRunners(ctx context.Context, projectName string, timeout time.Duration) (*api.StopAllRunnersResponse, error) {
+	// The daemon holds the request open until every runner has stopped
+	stopClient := *c
+	stopClient.client = &http.Client{Transport: c.client.Transport}
+
+	var resp api.StopAllRunnersResponse
+	err := stopClient.post(ctx, "/runners/stop-all", &api.StopAllRunnersRequest{
+		ProjectName:    projectName,
+		TimeoutSeconds: int32(timeout / time.Second),
+	}, &resp)
+	return &resp, err
+}
+
+// UnarchiveProject restores an archived project
+func (c *Client) UnarchiveProject(ctx context.Context, name string) (*api.ArchiveProjectResponse, error) {
+	var resp api.ArchiveProjectResponse
+	err := c.post(ctx, "/projects/unarchive", &api.ArchiveProjectRequest{Name: name}, &resp)
+	return &resp, err
+}
+
+// ListSessions lists recent sessions, optionally for one project
+func (c *Client) ListSessions(ctx context.Context, projectName string, limit int) (*api.ListSessionsResponse, error) {
+	var resp api.ListSessionsResponse
+	url := fmt.Sprintf("%s/sessions/list?limit=%d", c.baseURL, limit)
+	if projectName != "" {
+		url += fmt.Sprintf("&project=%s", projectName)
+	}
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// GetSessionsByTimeRange lists sessions started between from and to with
+// their total token usage
+func (c *Client) GetSessionsByTimeRange(ctx context.Context, from, to time.Time, projectName string, includeActive bool) (*api.GetSessionsByTimeRangeResponse, error) {
+	q := url.Values{}
+	q.Set("from", api.FormatTime(from))
+	q.Set("to", api.FormatTime(to))
+	if projectName != "" {
+		q.Set("project", projectName)
+	}
+	q.Set("include_active", strconv.FormatBool(includeActive))
+
+	var resp api.GetSessionsByTimeRangeResponse
+	err := c.get(ctx, c.baseURL+"/sessions?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// SearchSessions runs a full-text search over session summaries
+func (c *Client) SearchSessions(ctx context.Context, query, projectName string, limit int) (*api.ListSessionsResponse, error) {
+	var resp api.ListSessionsResponse
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	if projectName != "" {
+		params.Set("project", projectName)
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/sessions/search?%s", c.baseURL, params.Encode()), &resp)
+	return &resp, err
+}
+
+// SemanticSearchSessions finds sessions whose summaries are closest in
+// meaning to query
+func (c *Client) SemanticSearchSessions(ctx context.Context, query, projectName string, limit int) (*api.ListSessionsResponse, error) {
+	var resp api.ListSessionsResponse
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	if projectName != "" {
+		params.Set("project", projectName)
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/sessions/semantic-search?%s", c.baseURL, params.Encode()), &resp)
+	return &resp, err
+}
+
+// PinSession protects a session from retention cleanup
+func (c *Client) PinSession(ctx context.Context, sessionID string) (*api.PinSessionResponse, error) {
+	var resp api.PinSessionResponse
+	err := c.post(ctx, "/sessions/pin", &api.PinSessionRequest{SessionID: sessionID}, &resp)
+	return &resp, err
+}
+
+// UnpinSession makes a session eligible for retention cleanup again
+func (c *Client) UnpinSession(ctx context.Context, sessionID string) (*api.PinSessionResponse, error) {
+	var resp api.PinSessionResponse
+	err := c.post(ctx, "/sessions/unpin", &api.PinSessionRequest{SessionID: sessionID}, &resp)
+	return &resp, err
+}
+
+// CleanupSessions deletes non-resumable sessions that ended before the
+// cutoff, or only counts them when dryRun is set
+func (c *Client) CleanupSessions(ctx context.Context, before time.Time, projectName string, dryRun bool) (*api.CleanupSessionsResponse, error) {
+	var resp api.CleanupSessionsResponse
+	err := c.post(ctx, "/sessions/cleanup", &api.CleanupSessionsRequest{
+		Before:      api.FormatTime(before),
+		ProjectName: projectName,
+		DryRun:      dryRun,
+	}, &resp)
+	return &resp, err
+}
+
+// ResumeSession reattaches to or relaunches a session's runner
+func (c *Client) ResumeSession(ctx context.Context, sessionID string) (*api.ResumeSessionResponse, error) {
+	var resp api.ResumeSessionResponse
+	err := c.post(ctx, "/sessions/resume", &api.ResumeSessionRequest{SessionID: sessionID}, &resp)
+	return &resp, err
+}
+
+// BranchSession forks a session after forkAfter messages and launches a
+// runner resuming the new branch
+func (c *Client) BranchSession(ctx context.Context, sourceSessionID string, forkAfter int) (*api.BranchSessionResponse, error) {
+	var resp api.BranchSessionResponse
+	err := c.post(ctx, "/sessions/branch", &api.BranchSessionRequest{
+		SourceSessionID:  sourceSessionID,
+		ForkAfterMessage: int32(forkAfter),
+	}, &resp)
+	return &resp, err
+}
+
+// CreatePreset stores a reusable launch configuration
+func (c *Client) CreatePreset(ctx context.Context, preset *api.Preset) (*api.CreatePresetResponse, error) {
+	var resp api.CreatePresetResponse
+	err := c.post(ctx, "/presets/create", &api.CreatePresetRequest{Preset: preset}, &resp)
+	return &resp, err
+}
+
+// ListPresets lists presets usable by a project (all presets when empty)
+func (c *Client) ListPresets(ctx context.Context, projectName string) (*api.ListPresetsResponse, error) {
+	var resp api.ListPresetsResponse
+	url := fmt.Sprintf("%s/presets/list", c.baseURL)
+	if projectName != "" {
+		url += fmt.Sprintf("?project=%s", projectName)
+	}
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// DeletePreset removes a preset
+func (c *Client) DeletePreset(ctx context.Context, name string) (*api.DeletePresetResponse, error) {
+	var resp api.DeletePresetResponse
+	err := c.post(ctx, "/presets/delete", &api.DeletePresetRequest{Name: name}, &resp)
+	return &resp, err
+}
+
+// GetProject gets project details including live aggregates
+func (c *Client) GetProject(ctx context.Context, name string) (*api.GetProjectResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.GetProject(ctx, name)
+	}
+
+	var resp api.GetProjectResponse
+	url := fmt.Sprintf("%s/projects/get?name=%s", c.baseURL, name)
+	err := c.get(ctx, url, &resp)
+	return &resp, err
+}
+
+// ListProjects lists projects; archived ones are omitted unless
+// includeArchived is set or status asks for them
+func (c *Client) ListProjects(ctx context.Context, status string, includeArchived bool) (*api.ListProjectsResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.ListProjects(ctx, status, includeArchived)
+	}
+
 	var resp api.ListProjectsResponse
-	url := fmt.Sprintf("%s/projects/list", c.baseURL)
+	url := fmt.Sprintf("%s/projects/list?include_archived=%t", c.baseURL, includeArchived)
 	if status != "" {
-		url += fmt.Sprintf("?status=%s", status)
+		url += fmt.Sprintf("&status=%s", status)
 	}
 	err := c.get(ctx, url, &resp)
 	return &resp, err
 }
 
+// SearchProjects lists projects matching req
+func (c *Client) SearchProjects(ctx context.Context, req *api.SearchProjectsRequest) (*api.ListProjectsResponse, error) {
+	var resp api.ListProjectsResponse
+	params := url.Values{}
+	if req.Query != "" {
+		params.Set("q", req.Query)
+	}
+	for _, tag := range req.Tags {
+		params.Add("tag", tag)
+	}
+	for _, st := range req.Status {
+		params.Add("status", st)
+	}
+	if req.CreatedAfter != "" {
+		params.Set("created_after", req.CreatedAfter)
+	}
+	if req.CreatedBefore != "" {
+		params.Set("created_before", req.CreatedBefore)
+	}
+	if req.HasActiveRunners {
+		params.Set("has_runners", "true")
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/projects/search?%s", c.baseURL, params.Encode()), &resp)
+	return &resp, err
+}
+
+// GetUsageReport retrieves daily token usage for a budget scope over the
+// last days days
+func (c *Client) GetUsageReport(ctx context.Context, scope, scopeID string, days int) (*api.GetUsageReportResponse, error) {
+	q := url.Values{}
+	q.Set("scope", scope)
+	if scopeID != "" {
+		q.Set("scope_id", scopeID)
+	}
+	q.Set("days", strconv.Itoa(days))
+
+	var resp api.GetUsageReportResponse
+	err := c.get(ctx, c.baseURL+"/budget/report?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// GetBudgetHistory lists up to limit periods of a budget scope, newest
+// first
+func (c *Client) GetBudgetHistory(ctx context.Context, scope, scopeID string, limit int) (*api.GetBudgetHistoryResponse, error) {
+	q := url.Values{}
+	q.Set("scope", scope)
+	if scopeID != "" {
+		q.Set("scope_id", scopeID)
+	}
+	q.Set("limit", strconv.Itoa(limit))
+
+	var resp api.GetBudgetHistoryResponse
+	err := c.get(ctx, c.baseURL+"/budget/history?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// GetTokenLeaderboard ranks up to limit projects by token usage over
+// period (today, week, month or all)
+func (c *Client) GetTokenLeaderboard(ctx context.Context, period string, limit int) (*api.GetTokenLeaderboardResponse, error) {
+	q := url.Values{}
+	q.Set("period", period)
+	q.Set("limit", strconv.Itoa(limit))
+
+	var resp api.GetTokenLeaderboardResponse
+	err := c.get(ctx, c.baseURL+"/stats/leaderboard?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// GetNotificationHistory lists recent notification delivery attempts,
+// optionally only those for one event type
+func (c *Client) GetNotificationHistory(ctx context.Context, limit int, eventType string) (*api.GetNotificationHistoryResponse, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	if eventType != "" {
+		q.Set("event", eventType)
+	}
+
+	var resp api.GetNotificationHistoryResponse
+	err := c.get(ctx, c.baseURL+"/notifications/history?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// GetActivity returns the most recent activity across all projects
+func (c *Client) GetActivity(ctx context.Context, limit int) (*api.GetActivityResponse, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+
+	var resp api.GetActivityResponse
+	err := c.get(ctx, c.baseURL+"/activity?"+q.Encode(), &resp)
+	return &resp, err
+}
+
+// CreateNotificationRoute adds a per-project (or global) notification route
+func (c *Client) CreateNotificationRoute(ctx context.Context, route *api.NotificationRoute) (*api.CreateNotificationRouteResponse, error) {
+	var resp api.CreateNotificationRouteResponse
+	err := c.post(ctx, "/notifications/routes", &api.CreateNotificationRouteRequest{Route: route}, &resp)
+	return &resp, err
+}
+
+// CheckBudget reports whether a launch of estimatedTokens fits the token budget
+func (c *Client) CheckBudget(ctx context.Context, projectName string, estimatedTokens int64) (*api.CheckBudgetResponse, error) {
+	var resp api.CheckBudgetResponse
+	q := url.Values{}
+	q.Set("project", projectName)
+	q.Set("estimated_tokens", strconv.FormatInt(estimatedTokens, 10))
+	err := c.get(ctx, c.baseURL+"/budget/check?"+q.Encode(), &resp)
+	return &resp, err
+}
+
 // SendHeartbeat sends heartbeat from agent
 func (c *Client) SendHeartbeat(ctx context.Context, req *api.HeartbeatRequest) (*api.HeartbeatResponse, error) {
 	var resp api.HeartbeatResponse
@@ -98,14 +628,87 @@ func (c *Client) SendHeartbeat(ctx context.Context, req *api.HeartbeatRequest) (
 
 // GetStatus retrieves daemon status
 func (c *Client) GetStatus(ctx context.Context) (*api.GetStatusResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.GetStatus(ctx)
+	}
+
 	var resp api.GetStatusResponse
 	url := fmt.Sprintf("%s/status", c.baseURL)
 	err := c.get(ctx, url, &resp)
 	return &resp, err
 }
 
+// GetDaemonVersion retrieves the running and latest released daemon versions
+func (c *Client) GetDaemonVersion(ctx context.Context) (*api.GetDaemonVersionResponse, error) {
+	var resp api.GetDaemonVersionResponse
+	err := c.get(ctx, c.baseURL+"/daemon/version", &resp)
+	return &resp, err
+}
+
+// ListNodes lists the daemons registered against the shared database
+func (c *Client) ListNodes(ctx context.Context) (*api.ListNodesResponse, error) {
+	var resp api.ListNodesResponse
+	err := c.get(ctx, c.baseURL+"/nodes", &resp)
+	return &resp, err
+}
+
+// GetReadiness retrieves dependency health. A not-ready daemon responds with
+// 503, which is decoded rather than treated as an error.
+func (c *Client) GetReadiness(ctx context.Context) (*api.GetReadinessResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/health/ready", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, apiError(resp)
+	}
+
+	var ready api.GetReadinessResponse
+	if err := c.decodeResponse(resp, &ready); err != nil {
+		return nil, err
+	}
+	return &ready, nil
+}
+
+// GetAlertRules retrieves the daemon's Prometheus alert rule file
+func (c *Client) GetAlertRules(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/observability/alert-rules", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+
+	rules, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return rules, nil
+}
+
 // TriggerReconciliation manually triggers reconciliation
 func (c *Client) TriggerReconciliation(ctx context.Context) (*api.TriggerReconciliationResponse, error) {
+	if c.grpc != nil {
+		return c.grpc.TriggerReconciliation(ctx)
+	}
+
 	var resp api.TriggerReconciliationResponse
 	err := c.post(ctx, "/reconcile", nil, &resp)
 	return &resp, err
@@ -114,6 +717,19 @@ func (c *Client) TriggerReconciliation(ctx context.Context) (*api.TriggerReconci
 // Helper methods
 
 func (c *Client) post(ctx context.Context, path string, reqBody, respBody interface{}) error {
+	return c.send(ctx, "POST", path, reqBody, respBody)
+}
+
+func (c *Client) patch(ctx context.Context, path string, reqBody, respBody interface{}) error {
+	return c.send(ctx, "PATCH", path, reqBody, respBody)
+}
+
+func (c *Client) delete(ctx context.Context, path string, respBody interface{}) error {
+	return c.send(ctx, "DELETE", path, nil, respBody)
+}
+
+// send makes a request with a JSON body to path
+func (c *Client) send(ctx context.Context, method, path string, reqBody, respBody interface{}) error {
 	var body io.Reader
 	if reqBody != nil {
 		data, err := json.Marshal(reqBody)
@@ -123,10 +739,11 @@ func (c *Client) post(ctx context.Context, path string, reqBody, respBody interf
 		body = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, body)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
+	c.authorize(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.client.Do(req)
@@ -136,17 +753,10 @@ func (c *Client) post(ctx context.Context, path string, reqBody, respBody interf
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		errBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(errBody))
-	}
-
-	if respBody != nil {
-		if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
-			return fmt.Errorf("decode response: %w", err)
-		}
+		return apiError(resp)
 	}
 
-	return nil
+	return c.decodeResponse(resp, respBody)
 }
 
 func (c *Client) get(ctx context.Context, url string, respBody interface{}) error {
@@ -154,6 +764,7 @@ func (c *Client) get(ctx context.Context, url string, respBody interface{}) erro
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
+	c.authorize(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -162,17 +773,55 @@ func (c *Client) get(ctx context.Context, url string, respBody interface{}) erro
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		errBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(errBody))
+		return apiError(resp)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+	return c.decodeResponse(resp, respBody)
+}
+
+// decodeResponse unwraps the API response envelope into respBody, which
+// may be nil to discard the data
+func (c *Client) decodeResponse(resp *http.Response, respBody interface{}) error {
+	var env api.Response[json.RawMessage]
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
 		return fmt.Errorf("decode response: %w", err)
 	}
 
+	if env.APIVersion != api.APIVersion {
+		c.versionWarning.Do(func() {
+			c.logger.Warn("daemon API version differs from client",
+				zap.String("daemon", env.APIVersion),
+				zap.String("client", api.APIVersion))
+		})
+	}
+
+	if respBody == nil || len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, respBody); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
 	return nil
 }
 
+// apiError describes a non-200 response, using the envelope's error message
+// when the body is one and including the request ID for correlation with
+// daemon logs
+func apiError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	message := string(body)
+	var env api.Response[json.RawMessage]
+	if json.Unmarshal(body, &env) == nil && env.Error != "" {
+		message = env.Error
+	}
+
+	if id := resp.Header.Get("X-Request-ID"); id != "" {
+		return fmt.Errorf("API error (%d, request %s): %s", resp.StatusCode, id, message)
+	}
+	return fmt.Errorf("API error (%d): %s", resp.StatusCode, message)
+}
+
 // Ping checks if daemon is reachable
 func (c *Client) Ping(ctx context.Context) error {
 	c.logger.Info("Pinging daemon", zap.String("url", c.baseURL+"/health"))
@@ -182,6 +831,7 @@ func (c *Client) Ping(ctx context.Context) error {
 		c.logger.Error("Failed to create HTTP request", zap.Error(err))
 		return err
 	}
+	c.authorize(req)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -198,3 +848,10 @@ func (c *Client) Ping(ctx context.Context) error {
 	c.logger.Info("Daemon healthy")
 	return nil
 }
+
+// authorize adds the bearer token, if any, to an outgoing request
+func (c *Client) authorize(req *http.Request) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,6 +18,15 @@ type Config struct {
 	Daemon        DaemonConfig        `mapstructure:"daemon"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
 	Security      SecurityConfig      `mapstructure:"security"`
+	Upgrade       UpgradeConfig       `mapstructure:"upgrade"`
+
+	file string // config file read, if any
+}
+
+// File returns the config file the configuration was read from, or "" when
+// only defaults and environment variables were used
+func (c *Config) File() string {
+	return c.file
 }
 
 // DatabaseConfig holds database connection settings
@@ -46,8 +58,11 @@ type DockerConfig struct {
 	RabbitMQ   RabbitMQConfig   `mapstructure:"rabbitmq"`
 	Ntfy       NtfyConfig       `mapstructure:"ntfy"` // Deprecated
 	Telegram   TelegramConfig   `mapstructure:"telegram"`
+	Email      EmailConfig      `mapstructure:"email"`
+	Notify     NotifyConfig     `mapstructure:"notifications"`
 	Prometheus PrometheusConfig `mapstructure:"prometheus"`
 	Qdrant     QdrantConfig     `mapstructure:"qdrant"`
+	Redis      RedisConfig      `mapstructure:"redis"`
 }
 
 // APIGatewayConfig for lex-docker API gateway
@@ -65,6 +80,18 @@ type RabbitMQConfig struct {
 	Password          string `mapstructure:"password"`
 	Exchange          string `mapstructure:"exchange"`
 	PublisherConfirms bool   `mapstructure:"publisher_confirms"`
+
+	Reconnect RabbitMQReconnectConfig `mapstructure:"reconnect"`
+}
+
+// RabbitMQReconnectConfig controls reconnection after the broker connection
+// drops. Delays grow by BackoffFactor from InitialDelay up to MaxDelay.
+type RabbitMQReconnectConfig struct {
+	InitialDelay  time.Duration `mapstructure:"initial_delay"`
+	MaxDelay      time.Duration `mapstructure:"max_delay"`
+	MaxAttempts   int           `mapstructure:"max_attempts"` // 0 = retry forever
+	BackoffFactor float64       `mapstructure:"backoff_factor"`
+	Jitter        bool          `mapstructure:"jitter"`
 }
 
 // NtfyConfig for notifications (deprecated - using Telegram)
@@ -80,6 +107,26 @@ type TelegramConfig struct {
 	ChatID string `mapstructure:"chat_id"`
 }
 
+// EmailConfig for SMTP notifications
+type EmailConfig struct {
+	SMTPHost       string        `mapstructure:"smtp_host"`
+	SMTPPort       int           `mapstructure:"smtp_port"`
+	SMTPUser       string        `mapstructure:"smtp_user"`
+	SMTPPassword   string        `mapstructure:"smtp_password"`
+	SMTPFrom       string        `mapstructure:"smtp_from"`
+	SMTPTo         []string      `mapstructure:"smtp_to"`
+	UseTLS         bool          `mapstructure:"use_tls"`
+	DigestInterval time.Duration `mapstructure:"digest_interval"` // 0 = one email per event; ignored when Notify.DigestInterval is set
+}
+
+// NotifyConfig applies to every notification backend
+type NotifyConfig struct {
+	// DigestInterval batches routine events into one summary message per
+	// interval. Urgent alerts are still sent immediately. 0 = disabled.
+	// When set it replaces the email-only digest.
+	DigestInterval time.Duration `mapstructure:"digest_interval"`
+}
+
 // PrometheusConfig for metrics
 type PrometheusConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
@@ -87,22 +134,65 @@ type PrometheusConfig struct {
 	Path    string `mapstructure:"path"`
 }
 
-// QdrantConfig for vector storage (future)
+// QdrantConfig for session semantic search
 type QdrantConfig struct {
-	Host    string `mapstructure:"host"`
-	Port    int    `mapstructure:"port"`
-	Enabled bool   `mapstructure:"enabled"`
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`      // REST port
+	GRPCPort   int    `mapstructure:"grpc_port"` // used by the daemon
+	Collection string `mapstructure:"collection"`
+	Enabled    bool   `mapstructure:"enabled"`
+}
+
+// RedisConfig for the optional read cache
+type RedisConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+	Enabled  bool   `mapstructure:"enabled"`
 }
 
 // DaemonConfig for daemon-specific settings
 type DaemonConfig struct {
-	Port_GRPC          int    `mapstructure:"grpc_port"`
-	Port_HTTP          int    `mapstructure:"http_port"`
-	HeartbeatInterval  int    `mapstructure:"heartbeat_interval_seconds"`
-	ReconcileInterval  int    `mapstructure:"reconcile_interval_seconds"`
-	OutboxPollInterval int    `mapstructure:"outbox_poll_interval_seconds"`
-	ShutdownTimeout    int    `mapstructure:"shutdown_timeout_seconds"`
-	DataDir            string `mapstructure:"data_dir"`
+	Port_GRPC                int    `mapstructure:"grpc_port"`
+	Port_HTTP                int    `mapstructure:"http_port"`
+	HeartbeatInterval        int    `mapstructure:"heartbeat_interval_seconds"`
+	ReconcileInterval        int    `mapstructure:"reconcile_interval_seconds"`
+	OutboxPollInterval       int    `mapstructure:"outbox_poll_interval_seconds"`
+	OutboxRetention          int    `mapstructure:"outbox_retention_days"`
+	OutboxPartitionRetention int    `mapstructure:"outbox_partition_retention_months"`
+	SessionRetention         int    `mapstructure:"session_retention_days"`
+	ShutdownTimeout          int    `mapstructure:"shutdown_timeout_seconds"`
+	MaxMessagesPerMinute     int    `mapstructure:"max_messages_per_minute"`
+	RunnerDrainTimeout       int    `mapstructure:"runner_drain_timeout_seconds"` // 0 = no drain
+	RunnerDirChangedSignal   string `mapstructure:"runner_dir_changed_signal"`    // sent when a project moves; "" = none
+	MaxConcurrentLaunches    int    `mapstructure:"max_concurrent_launches"`      // agent processes starting at once
+	StartupTimeout           int    `mapstructure:"startup_timeout_seconds"`      // before a starting runner is failed
+	DataDir                  string `mapstructure:"data_dir"`
+	NodeID                   string `mapstructure:"node_id"` // default: <hostname>-<grpc_port>
+}
+
+// PIDFile returns the path of the daemon's PID file inside DataDir
+func (c *DaemonConfig) PIDFile() string {
+	return c.DataPath("stratavored.pid")
+}
+
+// DataPath returns name joined to DataDir, with a leading "~/" expanded
+func (c *DaemonConfig) DataPath(name string) string {
+	dataDir := c.DataDir
+	if rest, ok := strings.CutPrefix(dataDir, "~/"); ok {
+		homeDir, _ := os.UserHomeDir()
+		dataDir = filepath.Join(homeDir, rest)
+	}
+	return filepath.Join(dataDir, name)
+}
+
+// UpgradeConfig points 'stratavore daemon upgrade' at release artifacts.
+// "{version}" in either URL is replaced with the latest version.
+type UpgradeConfig struct {
+	VersionURL  string `mapstructure:"version_url"`  // plain-text latest version
+	ReleaseURL  string `mapstructure:"release_url"`  // daemon binary
+	ChecksumURL string `mapstructure:"checksum_url"` // SHA256 of the binary
 }
 
 // ObservabilityConfig for logging and tracing
@@ -110,6 +200,11 @@ type ObservabilityConfig struct {
 	LogLevel       string `mapstructure:"log_level"`
 	LogFormat      string `mapstructure:"log_format"` // json or console
 	TracingEnabled bool   `mapstructure:"tracing_enabled"`
+
+	// EmbeddingEndpoint is an OpenAI-compatible embeddings URL used to
+	// index session summaries when Qdrant is enabled
+	EmbeddingEndpoint string `mapstructure:"embedding_endpoint"`
+	EmbeddingModel    string `mapstructure:"embedding_model"`
 }
 
 // SecurityConfig for authentication and encryption
@@ -122,16 +217,36 @@ type SecurityConfig struct {
 	JoinTokenTTL    int             `mapstructure:"join_token_ttl_seconds"`
 	AuthSecret      string          `mapstructure:"auth_secret"`
 	RateLimit       RateLimitConfig `mapstructure:"rate_limit"`
+	AdminRateLimit  RateLimitConfig `mapstructure:"admin_rate_limit"` // tokens with the admin scope
+
+	// DisableSecurityHeaders stops the HTTP API setting X-Frame-Options,
+	// CSP and similar headers, for deployments behind a proxy that sets them
+	DisableSecurityHeaders bool `mapstructure:"disable_security_headers"`
+
+	// CORSAllowedOrigins lists the origins browser clients may call the HTTP
+	// API from, e.g. https://dash.example.com. "*" allows any origin. Empty
+	// disables CORS.
+	CORSAllowedOrigins []string `mapstructure:"cors_allowed_origins"`
 }
 
 // RateLimitConfig controls per-client request throttling
 type RateLimitConfig struct {
 	RequestsPerMinute int `mapstructure:"requests_per_minute"`
-	Burst             int `mapstructure:"burst"`
+	Burst             int `mapstructure:"burst"` // in-memory limiter only; ignored with Redis
 }
 
 // LoadConfig loads configuration from file and environment
 func LoadConfig() (*Config, error) {
+	return loadConfig("")
+}
+
+// LoadConfigFile loads configuration from path instead of searching the
+// default locations. Environment variables still override the file.
+func LoadConfigFile(path string) (*Config, error) {
+	return loadConfig(path)
+}
+
+func loadConfig(path string) (*Config, error) {
 	v := viper.New()
 
 	// Set config name and paths
@@ -140,29 +255,63 @@ func LoadConfig() (*Config, error) {
 
 	// Search paths
 	homeDir, _ := os.UserHomeDir()
-	v.AddConfigPath(filepath.Join(homeDir, ".config", "stratavore"))
-	v.AddConfigPath("/etc/stratavore")
-	v.AddConfigPath(".")
+	searchPaths := []string{
+		filepath.Join(homeDir, ".config", "stratavore"),
+		"/etc/stratavore",
+		".",
+	}
+	for _, p := range searchPaths {
+		v.AddConfigPath(p)
+	}
 
-	// Environment variables
+	// Environment variables, e.g. STRATAVORE_DATABASE_POSTGRESQL_HOST
 	v.SetEnvPrefix("STRATAVORE")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
 	// Set defaults
 	setDefaults(v)
 
-	// Read config file (optional)
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+	var file string
+	switch {
+	case strings.HasSuffix(path, EncryptedConfigExt):
+		if err := readEncryptedConfig(v, path); err != nil {
+			return nil, err
+		}
+		file = path
+
+	case path != "":
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
 			return nil, fmt.Errorf("error reading config: %w", err)
 		}
-		// Config file not found is OK, use defaults
+		file = v.ConfigFileUsed()
+
+	default:
+		// Read config file (optional)
+		if err := v.ReadInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				return nil, fmt.Errorf("error reading config: %w", err)
+			}
+
+			// No plaintext config; fall back to an age-encrypted one
+			if path := findEncryptedConfig(searchPaths); path != "" {
+				if err := readEncryptedConfig(v, path); err != nil {
+					return nil, err
+				}
+				file = path
+			}
+			// Config file not found is OK, use defaults
+		} else {
+			file = v.ConfigFileUsed()
+		}
 	}
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	cfg.file = file
 
 	// Override with secrets from files if specified
 	if cfg.Security.TokenSecretPath != "" {
@@ -173,6 +322,44 @@ func LoadConfig() (*Config, error) {
 	return &cfg, nil
 }
 
+// DefaultConfig returns the built-in defaults without reading any config
+// file or environment variables
+func DefaultConfig() (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// UserConfigPath returns ~/.config/stratavore/stratavore.yaml, the first
+// location LoadConfig searches
+func UserConfigPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "stratavore", "stratavore.yaml")
+}
+
+// readEncryptedConfig decrypts an age-encrypted config file into v
+func readEncryptedConfig(v *viper.Viper, path string) error {
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+
+	plaintext, err := DecryptConfig(ciphertext, DefaultIdentityPath())
+	if err != nil {
+		return fmt.Errorf("error decrypting config %s: %w", path, err)
+	}
+
+	if err := v.ReadConfig(bytes.NewReader(plaintext)); err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	return nil
+}
+
 func setDefaults(v *viper.Viper) {
 	// Database defaults
 	v.SetDefault("database.postgresql.host", "localhost")
@@ -197,6 +384,11 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("docker.rabbitmq.password", "guest")
 	v.SetDefault("docker.rabbitmq.exchange", "stratavore.events")
 	v.SetDefault("docker.rabbitmq.publisher_confirms", true)
+	v.SetDefault("docker.rabbitmq.reconnect.initial_delay", "1s")
+	v.SetDefault("docker.rabbitmq.reconnect.max_delay", "1m")
+	v.SetDefault("docker.rabbitmq.reconnect.max_attempts", 20)
+	v.SetDefault("docker.rabbitmq.reconnect.backoff_factor", 2.0)
+	v.SetDefault("docker.rabbitmq.reconnect.jitter", true)
 
 	v.SetDefault("docker.ntfy.host", "localhost")
 	v.SetDefault("docker.ntfy.port", 2586)
@@ -207,26 +399,50 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("docker.telegram.token", "")
 	v.SetDefault("docker.telegram.chat_id", "")
 
+	// Email defaults (disabled until smtp_host and smtp_to are set)
+	v.SetDefault("docker.email.smtp_host", "")
+	v.SetDefault("docker.email.smtp_port", 587)
+	v.SetDefault("docker.email.use_tls", false)
+	v.SetDefault("docker.email.digest_interval", "0s")
+	v.SetDefault("docker.notifications.digest_interval", "0s")
+
 	v.SetDefault("docker.prometheus.enabled", true)
 	v.SetDefault("docker.prometheus.port", 9091)
 	v.SetDefault("docker.prometheus.path", "/metrics")
 
 	v.SetDefault("docker.qdrant.host", "localhost")
 	v.SetDefault("docker.qdrant.port", 6333)
+	v.SetDefault("docker.qdrant.grpc_port", 6334)
+	v.SetDefault("docker.qdrant.collection", "stratavore_sessions")
 	v.SetDefault("docker.qdrant.enabled", false)
 
+	v.SetDefault("docker.redis.host", "localhost")
+	v.SetDefault("docker.redis.port", 6379)
+	v.SetDefault("docker.redis.db", 0)
+	v.SetDefault("docker.redis.enabled", false)
+
 	// Daemon defaults
 	v.SetDefault("daemon.grpc_port", 50051)
 	v.SetDefault("daemon.heartbeat_interval_seconds", 10)
 	v.SetDefault("daemon.reconcile_interval_seconds", 30)
 	v.SetDefault("daemon.outbox_poll_interval_seconds", 2)
+	v.SetDefault("daemon.outbox_retention_days", 7)
+	v.SetDefault("daemon.outbox_partition_retention_months", 3)
+	v.SetDefault("daemon.session_retention_days", 0)
 	v.SetDefault("daemon.shutdown_timeout_seconds", 30)
+	v.SetDefault("daemon.runner_drain_timeout_seconds", 60)
+	v.SetDefault("daemon.runner_dir_changed_signal", "SIGUSR2")
+	v.SetDefault("daemon.max_concurrent_launches", 10)
+	v.SetDefault("daemon.startup_timeout_seconds", 60)
+	v.SetDefault("daemon.max_messages_per_minute", 30)
 	v.SetDefault("daemon.data_dir", filepath.Join(homeDir, ".local", "share", "stratavore"))
 
 	// Observability defaults
 	v.SetDefault("observability.log_level", "info")
 	v.SetDefault("observability.log_format", "json")
 	v.SetDefault("observability.tracing_enabled", false)
+	v.SetDefault("observability.embedding_endpoint", "")
+	v.SetDefault("observability.embedding_model", "")
 
 	// Security defaults
 	v.SetDefault("security.enable_mtls", false)
@@ -234,6 +450,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("security.auth_secret", "") // empty = auth disabled
 	v.SetDefault("security.rate_limit.requests_per_minute", 300)
 	v.SetDefault("security.rate_limit.burst", 50)
+	v.SetDefault("security.admin_rate_limit.requests_per_minute", 1200)
+	v.SetDefault("security.admin_rate_limit.burst", 200)
+	v.SetDefault("security.disable_security_headers", false)
+	v.SetDefault("security.cors_allowed_origins", []string{})
+
+	// Upgrade defaults (disabled until release_url is set)
+	v.SetDefault("upgrade.version_url", "")
+	v.SetDefault("upgrade.release_url", "")
+	v.SetDefault("upgrade.checksum_url", "")
 }
 
 // GetConnectionString returns PostgreSQL connection string
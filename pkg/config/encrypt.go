@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// EncryptedConfigExt is the file extension of age-encrypted config files
+const EncryptedConfigExt = ".age"
+
+// IdentityFileEnv names the environment variable pointing at the age
+// identity used to decrypt config files
+const IdentityFileEnv = "STRATAVORE_IDENTITY_FILE"
+
+// EncryptConfig encrypts plaintext config to an age recipient public key.
+// The output is ASCII-armored so it diffs and reviews cleanly in VCS.
+func EncryptConfig(plaintext []byte, recipientPublicKey string) ([]byte, error) {
+	recipient, err := age.ParseX25519Recipient(recipientPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse recipient: %w", err)
+	}
+
+	var buf bytes.Buffer
+	armorWriter := armor.NewWriter(&buf)
+
+	w, err := age.Encrypt(armorWriter, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+	if err := armorWriter.Close(); err != nil {
+		return nil, fmt.Errorf("encrypt: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecryptConfig decrypts an age-encrypted config (armored or binary) using
+// the identities in identityPath.
+func DecryptConfig(ciphertext []byte, identityPath string) ([]byte, error) {
+	f, err := os.Open(identityPath)
+	if err != nil {
+		return nil, fmt.Errorf("open identity file: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse identity file: %w", err)
+	}
+
+	var src io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(ciphertext))
+	}
+
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// DefaultIdentityPath returns the age identity file used for config
+// decryption: $STRATAVORE_IDENTITY_FILE, else ~/.config/stratavore/identity.
+func DefaultIdentityPath() string {
+	if path := os.Getenv(IdentityFileEnv); path != "" {
+		return path
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "stratavore", "identity")
+}
+
+// findEncryptedConfig returns the first stratavore.yaml.age or stratavore.age
+// found in paths, or "" when none exists.
+func findEncryptedConfig(paths []string) string {
+	for _, dir := range paths {
+		for _, name := range []string{"stratavore.yaml" + EncryptedConfigExt, "stratavore" + EncryptedConfigExt} {
+			candidate := filepath.Join(dir, name)
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces sensitive values in displayed configuration
+const RedactedValue = "[REDACTED]"
+
+// EnvPrefix is the prefix of environment variables that override settings
+const EnvPrefix = "STRATAVORE_"
+
+// Settings returns the configuration as nested maps keyed by the same names
+// used in stratavore.yaml. Durations are rendered as strings.
+func (c *Config) Settings() map[string]any {
+	return settingsOf(reflect.ValueOf(*c)).(map[string]any)
+}
+
+func settingsOf(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key := field.Tag.Get("mapstructure")
+			if key == "" || key == "-" {
+				continue
+			}
+			out[key] = settingsOf(v.Field(i))
+		}
+		return out
+
+	case reflect.Map:
+		out := make(map[string]any)
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = settingsOf(iter.Value())
+		}
+		return out
+	}
+
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	return v.Interface()
+}
+
+// IsSensitiveKey reports whether a setting holds a credential. key may be a
+// full dotted path; only its last segment is checked.
+func IsSensitiveKey(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	switch name {
+	case "password", "token", "secret", "auth_secret":
+		return true
+	}
+	return strings.HasSuffix(name, "_password") ||
+		strings.HasSuffix(name, "_secret") ||
+		strings.HasSuffix(name, "_token")
+}
+
+// RedactSettings replaces every non-empty sensitive value in settings with
+// RedactedValue, in place, and returns settings
+func RedactSettings(settings map[string]any) map[string]any {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			RedactSettings(nested)
+			continue
+		}
+		if IsSensitiveKey(key) && !isZero(value) {
+			settings[key] = RedactedValue
+		}
+	}
+	return settings
+}
+
+// FlattenSettings returns settings keyed by dotted path, e.g.
+// "database.postgresql.host"
+func FlattenSettings(settings map[string]any) map[string]any {
+	flat := make(map[string]any)
+	flattenInto(flat, "", settings)
+	return flat
+}
+
+func flattenInto(flat map[string]any, prefix string, settings map[string]any) {
+	for key, value := range settings {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenInto(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
+
+// EnvName returns the environment variable that overrides a dotted setting
+// key, e.g. STRATAVORE_DATABASE_POSTGRESQL_HOST
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvValue formats a setting value the way LoadConfig parses it back from
+// the environment; lists are comma-separated
+func EnvValue(value any) string {
+	if list, ok := value.([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+func isZero(value any) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
@@ -62,17 +62,29 @@ type Runner struct {
 	
 	RestartAttempts    int `json:"restart_attempts"`
 	MaxRestartAttempts int `json:"max_restart_attempts"`
+	MaxRuntimeSeconds  int `json:"max_runtime_seconds,omitempty"`
 	
 	StartedAt      time.Time  `json:"started_at"`
 	LastHeartbeat  *time.Time `json:"last_heartbeat,omitempty"`
 	HeartbeatTTL   int        `json:"heartbeat_ttl_seconds"`
 	TerminatedAt   *time.Time `json:"terminated_at,omitempty"`
 	ExitCode       *int       `json:"exit_code,omitempty"`
+	KillReason    string     `json:"kill_reason,omitempty"`
 	
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// RunnerRestart records one restart of a runner after it exited.
+// PreviousExitCode is nil when the earlier exit code wasn't recorded.
+type RunnerRestart struct {
+	RunnerID         string    `json:"runner_id"`
+	Attempt          int       `json:"attempt"`
+	RestartedAt      time.Time `json:"restarted_at"`
+	PreviousExitCode *int      `json:"previous_exit_code,omitempty"`
+	DelaySeconds     float64   `json:"delay_seconds"`
+}
+
 // Project represents a development project
 type Project struct {
 	Name        string        `json:"name"`
@@ -80,6 +92,7 @@ type Project struct {
 	Status      ProjectStatus `json:"status"`
 	Description string        `json:"description,omitempty"`
 	Tags        []string      `json:"tags"`
+	HealthProbe *HealthProbe  `json:"health_probe,omitempty"`
 	
 	TotalRunners  int   `json:"total_runners"`
 	ActiveRunners int   `json:"active_runners"`
@@ -92,6 +105,25 @@ type Project struct {
 	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
+// HealthProbeType selects how a runner health probe checks its target
+type HealthProbeType string
+
+const (
+	HealthProbeHTTP HealthProbeType = "http" // GET a URL, 2xx/3xx is healthy
+	HealthProbeTCP  HealthProbeType = "tcp"  // dial host:port
+	HealthProbeExec HealthProbeType = "exec" // run a command in the project dir, exit 0 is healthy
+)
+
+// HealthProbe is a per-project runner health check run in addition to the
+// heartbeat TTL
+type HealthProbe struct {
+	Type             HealthProbeType `json:"type"`
+	Target           string          `json:"target"`
+	IntervalSeconds  int             `json:"interval_seconds"`
+	TimeoutSeconds   int             `json:"timeout_seconds"`
+	FailureThreshold int             `json:"failure_threshold"`
+}
+
 // Session represents a conversation session
 type Session struct {
 	ID          string    `json:"id"`
@@ -107,9 +139,15 @@ type Session struct {
 	Resumable    bool   `json:"resumable"`
 	ResumedFrom  string `json:"resumed_from,omitempty"`
 	Summary      string `json:"summary,omitempty"`
+	Pinned       bool   `json:"pinned"`
+	Snippet      string `json:"snippet,omitempty"` // search results only
 	
 	TranscriptS3Key   string `json:"transcript_s3_key,omitempty"`
-	TranscriptSizeBytes int64 `json:"transcript_size_bytes,omitempty"`
+	TranscriptSizeBytes int64 `json:"transcript_size_bytes,omitempty"` // stored size
+
+	// Set when the stored transcript is gzip-compressed
+	TranscriptCompressed  bool  `json:"compressed,omitempty"`
+	UncompressedSizeBytes int64 `json:"uncompressed_size_bytes,omitempty"`
 	
 	CreatedAt time.Time `json:"created_at"`
 }
@@ -127,6 +165,47 @@ type Heartbeat struct {
 	// Agent metadata
 	AgentVersion string `json:"agent_version"`
 	Hostname     string `json:"hostname"`
+
+	// GPU usage, only reported by agents built with the gpu tag
+	GPUSamples []GPUSample `json:"gpu_samples,omitempty"`
+
+	// CPU cores the agent is pinned to (Linux only)
+	CPUAffinity []int `json:"cpu_affinity,omitempty"`
+
+	// Latest stderr line matching a known error pattern since the previous
+	// heartbeat, e.g. "out of memory"
+	ErrorContext string `json:"error_context,omitempty"`
+
+	// Message rate of the runner's session over the last minute, filled in
+	// by the daemon
+	MessagesPerMinute float64 `json:"messages_per_minute,omitempty"`
+
+	// Pre-flight check results, sent with the agent's first heartbeat
+	PreflightResult *PreflightResult `json:"preflight_result,omitempty"`
+}
+
+// PreflightResult is the outcome of the checks an agent runs before
+// starting claude code
+type PreflightResult struct {
+	Passed bool             `json:"passed"` // every critical check passed
+	Checks []PreflightCheck `json:"checks"`
+}
+
+// PreflightCheck is one pre-flight check. Critical checks stop the launch
+// when they fail; the others only warn.
+type PreflightCheck struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Critical bool   `json:"critical"`
+	Message  string `json:"message,omitempty"`
+}
+
+// GPUSample is one GPU's usage attributed to a runner process
+type GPUSample struct {
+	GPUIndex           int     `json:"gpu_index"`
+	UtilizationPercent float64 `json:"utilization_percent"`
+	MemoryUsedMB       int64   `json:"memory_used_mb"`
+	MemoryTotalMB      int64   `json:"memory_total_mb"`
 }
 
 // Event represents a system event for audit/event sourcing
@@ -182,6 +261,60 @@ type LaunchRequest struct {
 	ConversationMode ConversationMode `json:"conversation_mode"`
 	SessionID        string           `json:"session_id,omitempty"`
 	RuntimeType      RuntimeType      `json:"runtime_type"`
+	PresetName       string           `json:"preset_name,omitempty"`
+
+	// CPU cores to pin the runner to (Linux only); empty means no pinning
+	CPUAffinity []int `json:"cpu_affinity,omitempty"`
+
+	// Seconds after which the runner is stopped; 0 uses the project quota
+	MaxRuntimeSeconds int `json:"max_runtime_seconds,omitempty"`
+}
+
+// Preset is a reusable launch configuration. Presets without a project name
+// may be used by any project.
+type Preset struct {
+	Name             string            `json:"name"`
+	ProjectName      string            `json:"project_name,omitempty"`
+	RuntimeType      RuntimeType       `json:"runtime_type,omitempty"`
+	Flags            []string          `json:"flags"`
+	Capabilities     []string          `json:"capabilities"`
+	Environment      map[string]string `json:"environment"`
+	ConversationMode ConversationMode  `json:"conversation_mode,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// DeviceCodeStatus is the approval state of a device authorization request
+type DeviceCodeStatus string
+
+const (
+	DeviceCodePending  DeviceCodeStatus = "pending"
+	DeviceCodeApproved DeviceCodeStatus = "approved"
+	DeviceCodeDenied   DeviceCodeStatus = "denied"
+)
+
+// DeviceCode is a pending OAuth2 device flow login
+type DeviceCode struct {
+	DeviceCode string           `json:"device_code"`
+	UserCode   string           `json:"user_code"`
+	Status     DeviceCodeStatus `json:"status"`
+	Subject    string           `json:"subject,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	ExpiresAt  time.Time        `json:"expires_at"`
+}
+
+// APIToken is a long-lived API token. Only its metadata is stored; the
+// signed token is shown once when it is created.
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scope      []string   `json:"scope"`
+	Subject    string     `json:"subject"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
 }
 
 // ResourceQuota represents project resource limits
@@ -191,6 +324,16 @@ type ResourceQuota struct {
 	MaxMemoryMB         int64  `json:"max_memory_mb,omitempty"`
 	MaxCPUPercent       int    `json:"max_cpu_percent,omitempty"`
 	MaxTokensPerDay     int64  `json:"max_tokens_per_day,omitempty"`
+
+	// Most CPU cores a runner may request via CPUAffinity (0 = no cap)
+	MaxCPUsPerRunner int `json:"max_cpus_per_runner,omitempty"`
+
+	// Default runtime limit for runners launched without one (0 = unlimited)
+	MaxRuntimeSeconds int `json:"max_runtime_seconds,omitempty"`
+
+	// Seconds without a heartbeat before a runner is marked failed (0 = the
+	// daemon's default)
+	HeartbeatTTLSeconds int `json:"heartbeat_ttl_seconds,omitempty"`
 }
 
 // TokenBudget represents token usage limits
@@ -205,16 +348,89 @@ type TokenBudget struct {
 	PeriodEnd         time.Time `json:"period_end"`
 }
 
-// DaemonInfo represents daemon state
+// ProjectStats holds live aggregates computed from runners and sessions
+type ProjectStats struct {
+	ProjectName       string  `json:"project_name"`
+	ActiveRunners     int     `json:"active_runners"`
+	ActiveTokens      int64   `json:"active_tokens"`
+	SessionsThisWeek  int     `json:"sessions_this_week"`
+	AvgSessionSeconds float64 `json:"avg_session_seconds"`
+}
+
+// TokenUsagePoint is one bucket of a token usage time series
+type TokenUsagePoint struct {
+	Bucket time.Time `json:"bucket"`
+	Tokens int64     `json:"tokens"`
+}
+
+// NotificationLogEntry records one notification delivery attempt
+type NotificationLogEntry struct {
+	ID          int64     `json:"id"`
+	SentAt      time.Time `json:"sent_at"`
+	Backend     string    `json:"backend"`
+	EventType   string    `json:"event_type"`
+	Target      string    `json:"target,omitempty"`
+	MessageText string    `json:"message_text"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ActivityEvent is one entry in the cross-project recent activity feed
+type ActivityEvent struct {
+	Timestamp   time.Time `json:"timestamp"`
+	EventType   string    `json:"event_type"` // runner.started, session.ended, budget.*
+	ProjectName string    `json:"project_name,omitempty"`
+	ActorID     string    `json:"actor_id,omitempty"` // runner or session ID
+	Description string    `json:"description"`
+}
+
+// NotificationRoute sends a project's notifications to a backend target.
+// An empty ProjectName makes the route global; empty EventTypes matches
+// every event.
+type NotificationRoute struct {
+	ID          int64     `json:"id"`
+	ProjectName string    `json:"project_name,omitempty"`
+	Backend     string    `json:"backend"`
+	Target      string    `json:"target"`
+	EventTypes  []string  `json:"event_types,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Matches reports whether the route should receive eventType
+func (r *NotificationRoute) Matches(eventType string) bool {
+	if len(r.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range r.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DaemonInfo represents one registered daemon node
 type DaemonInfo struct {
-	DaemonID      string                 `json:"daemon_id"`
+	NodeID        string                 `json:"node_id"`
 	Hostname      string                 `json:"hostname"`
 	Version       string                 `json:"version"`
 	StartedAt     time.Time              `json:"started_at"`
 	LastHeartbeat time.Time              `json:"last_heartbeat"`
-	Config        map[string]interface{} `json:"config"`
+	GRPCPort      int                    `json:"grpc_port"`
+	HTTPPort      int                    `json:"http_port"`
+	ActiveRunners int                    `json:"active_runners"`
+	Status        DaemonNodeStatus       `json:"status"`
+	Config        map[string]interface{} `json:"config,omitempty"`
 }
 
+// DaemonNodeStatus is whether a daemon node is still heartbeating
+type DaemonNodeStatus string
+
+const (
+	DaemonNodeActive   DaemonNodeStatus = "active"
+	DaemonNodeInactive DaemonNodeStatus = "inactive"
+)
+
 // Metrics represents global metrics
 type Metrics struct {
 	ActiveRunners  int   `json:"active_runners"`
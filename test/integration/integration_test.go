@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/meridian-lex/stratavore/internal/storage"
 	"github.com/meridian-lex/stratavore/pkg/api"
 	"github.com/meridian-lex/stratavore/pkg/client"
@@ -52,7 +53,7 @@ func TestProjectLifecycle(t *testing.T) {
 	assert.Equal(t, req.Name, resp.Project.Name)
 
 	// List projects
-	listResp, err := apiClient.ListProjects(ctx, "")
+	listResp, err := apiClient.ListProjects(ctx, "", false)
 	require.NoError(t, err)
 	assert.NotEmpty(t, listResp.Projects)
 
@@ -162,7 +163,7 @@ func TestTokenBudget(t *testing.T) {
 	db, err := storage.NewPostgresClient(
 		ctx,
 		cfg.Database.PostgreSQL.GetConnectionString(),
-		5, 1,
+		5, 1, nil,
 	)
 	require.NoError(t, err)
 	defer db.Close()
@@ -233,7 +234,7 @@ func BenchmarkDatabaseQuery(b *testing.B) {
 	db, err := storage.NewPostgresClient(
 		ctx,
 		cfg.Database.PostgreSQL.GetConnectionString(),
-		5, 1,
+		5, 1, nil,
 	)
 	if err != nil {
 		b.Fatal(err)
@@ -245,3 +246,113 @@ func BenchmarkDatabaseQuery(b *testing.B) {
 		db.ListProjects(ctx, "")
 	}
 }
+
+// benchmarkRunnerIDs returns n random runner IDs for termination benchmarks
+func benchmarkRunnerIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = uuid.New().String()
+	}
+	return ids
+}
+
+// BenchmarkTerminateRunnersLoop benchmarks terminating 50 runners one query
+// at a time, as shutdown used to
+func BenchmarkTerminateRunnersLoop(b *testing.B) {
+	ctx := context.Background()
+	cfg, _ := config.LoadConfig()
+
+	db, err := storage.NewPostgresClient(
+		ctx,
+		cfg.Database.PostgreSQL.GetConnectionString(),
+		5, 1, nil,
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	ids := benchmarkRunnerIDs(50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range ids {
+			db.TerminateRunner(ctx, id, 0)
+		}
+	}
+}
+
+// BenchmarkBulkTerminateRunners benchmarks terminating 50 runners in a
+// single query
+func BenchmarkBulkTerminateRunners(b *testing.B) {
+	ctx := context.Background()
+	cfg, _ := config.LoadConfig()
+
+	db, err := storage.NewPostgresClient(
+		ctx,
+		cfg.Database.PostgreSQL.GetConnectionString(),
+		5, 1, nil,
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	exitCodes := make(map[string]int)
+	for _, id := range benchmarkRunnerIDs(50) {
+		exitCodes[id] = 0
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		db.BulkTerminateRunners(ctx, exitCodes)
+	}
+}
+
+// BenchmarkListActiveRunnersForStatus benchmarks counting runners by loading
+// every active runner
+func BenchmarkListActiveRunnersForStatus(b *testing.B) {
+	ctx := context.Background()
+	cfg, _ := config.LoadConfig()
+
+	db, err := storage.NewPostgresClient(
+		ctx,
+		cfg.Database.PostgreSQL.GetConnectionString(),
+		5, 1, nil,
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runners, _ := db.GetAllActiveRunners(ctx, storage.RunnerFilter{})
+		byStatus := make(map[types.RunnerStatus]int)
+		for _, r := range runners {
+			byStatus[r.Status]++
+		}
+	}
+}
+
+// BenchmarkRunnerMetricsSummary benchmarks counting runners with a single
+// aggregate query
+func BenchmarkRunnerMetricsSummary(b *testing.B) {
+	ctx := context.Background()
+	cfg, _ := config.LoadConfig()
+
+	db, err := storage.NewPostgresClient(
+		ctx,
+		cfg.Database.PostgreSQL.GetConnectionString(),
+		5, 1, nil,
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		db.GetRunnerMetricsSummary(ctx)
+	}
+}